@@ -3,14 +3,21 @@ package main
 import (
 	"api-gateway/internal/i18n"
 	"api-gateway/internal/middleware"
+	"api-gateway/internal/preference"
 	"api-gateway/internal/routes"
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"log"
 	"os"
+	"packages/preferences"
+	"packages/servicetoken"
+	"packages/session"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func initEnv() {
@@ -32,8 +39,20 @@ func initI18n() {
 func initRouter() *gin.Engine {
 	r := gin.Default()
 
+	// Languages picked by users in user-service
+	prefs := preference.NewStore()
+	prefTopic := os.Getenv(preferences.EnvTopic)
+	if prefTopic == "" {
+		prefTopic = preferences.DefaultTopic
+	}
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		brokers = "localhost:9092"
+	}
+	go prefs.Run(context.Background(), strings.Split(brokers, ","), prefTopic)
+
 	r.Use(middleware.LoggingMiddleware)
-	r.Use(middleware.I18nMiddleware())
+	r.Use(middleware.I18nMiddleware(prefs))
 	r.Use(middleware.TranslateMiddleware())
 
 	// Rate limit
@@ -44,7 +63,19 @@ func initRouter() *gin.Engine {
 		}
 	}
 	r.Use(middleware.RateLimitMiddleware(rateLimit))
-	r.Use(middleware.I18nMiddleware())
+	r.Use(middleware.I18nMiddleware(prefs))
+
+	// Calls to the internal routes of auth-service carry a service token
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	authClient := servicetoken.NewIssuer(servicetoken.APIGateway, serviceSecret).Client(servicetoken.AuthService, 5*time.Second)
+
+	// Reject tokens of sessions revoked in auth-service
+	revocations := session.NewRevocationStore(session.HTTPSource(os.Getenv("AUTH_SERVICE_URL"), authClient))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	r.Use(middleware.SessionRevocationMiddleware(revocations))
 
 	// Test route
 	r.GET("/hello", func(c *gin.Context) {
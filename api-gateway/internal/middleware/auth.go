@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"packages/policy"
 	"api-gateway/utils"
 	"strings"
 )
@@ -37,7 +38,8 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-func RequireRole(requiredRole string) gin.HandlerFunc {
+// RequirePermission only lets through roles holding every listed permission.
+func RequirePermission(perms ...policy.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleVal, exists := c.Get("role")
 		if !exists {
@@ -47,7 +49,7 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 		}
 
 		role, ok := roleVal.(string)
-		if !ok || role != requiredRole {
+		if !ok || !policy.CanAll(role, perms...) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 			c.Abort()
 			return
@@ -3,9 +3,11 @@ package middleware
 import (
 	"log"
 	"api-gateway/internal/i18n"
+	"api-gateway/internal/preference"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 var supportedLangs = []string{"en", "vi"}
@@ -34,10 +36,37 @@ func parseAcceptLanguageHeader(h string) string {
 	return strings.ToLower(langTag)
 }
 
-func I18nMiddleware() gin.HandlerFunc {
+// preferredLanguage is the language the signed-in user picked in user-service. The token is
+// only read here; services still verify it.
+func preferredLanguage(c *gin.Context, prefs *preference.Store) string {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return ""
+	}
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(strings.TrimPrefix(authHeader, "Bearer "), claims); err != nil {
+		return ""
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return ""
+	}
+	pref, ok := prefs.Get(uint(userID))
+	if !ok {
+		return ""
+	}
+	return pref.Language
+}
+
+// I18nMiddleware picks the language of the response: the lang query parameter, then the
+// preference of the signed-in user, then Accept-Language.
+func I18nMiddleware(prefs *preference.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		lang := strings.ToLower(c.Query("lang"))
 
+		if lang == "" {
+			lang = preferredLanguage(c, prefs)
+		}
 		if lang == "" {
 			lang = parseAcceptLanguageHeader(c.GetHeader("Accept-Language"))
 		}
@@ -0,0 +1,110 @@
+package preference
+
+import (
+	"context"
+	"log"
+	"packages/preferences"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Store mirrors the preferences user-service publishes, so responses can be written in the
+// language each user picked rather than the one their browser asks for.
+type Store struct {
+	mu    sync.RWMutex
+	users map[uint]preferences.Preferences
+}
+
+func NewStore() *Store {
+	return &Store{users: make(map[uint]preferences.Preferences)}
+}
+
+// Get reports the preferences of the user, if user-service published any.
+func (s *Store) Get(userID uint) (preferences.Preferences, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pref, ok := s.users[userID]
+	return pref, ok
+}
+
+func (s *Store) set(pref preferences.Preferences) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[pref.UserID] = pref
+}
+
+// Run reads every partition of the preferences topic from the start until ctx is cancelled.
+// No consumer group is used, so every gateway instance sees every user. Partitions added to the
+// topic later are read after a restart.
+func (s *Store) Run(ctx context.Context, brokers []string, topic string) {
+	partitions, err := listPartitions(ctx, brokers, topic)
+	if err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range partitions {
+		wg.Add(1)
+		go func(partition int) {
+			defer wg.Done()
+			s.readPartition(ctx, brokers, topic, partition)
+		}(p.ID)
+	}
+	wg.Wait()
+}
+
+func (s *Store) readPartition(ctx context.Context, brokers []string, topic string, partition int) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		Partition:   partition,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer r.Close()
+
+	for {
+		m, err := r.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Println("read user preferences failed:", err)
+			continue
+		}
+
+		pref, err := preferences.Decode(m.Value)
+		if err != nil {
+			log.Println("invalid user preferences:", err)
+			continue
+		}
+		s.set(pref)
+	}
+}
+
+// listPartitions asks the brokers in turn for the partitions of the topic, retrying until one
+// answers or ctx is cancelled.
+func listPartitions(ctx context.Context, brokers []string, topic string) ([]kafka.Partition, error) {
+	for {
+		for _, broker := range brokers {
+			conn, err := kafka.DialContext(ctx, "tcp", broker)
+			if err != nil {
+				log.Println("connect to kafka failed:", err)
+				continue
+			}
+			partitions, err := conn.ReadPartitions(topic)
+			conn.Close()
+			if err == nil && len(partitions) > 0 {
+				return partitions, nil
+			}
+			log.Printf("list partitions of %s failed: %v", topic, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
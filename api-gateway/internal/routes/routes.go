@@ -4,6 +4,7 @@ import (
 	"api-gateway/internal/middleware"
 	"api-gateway/internal/proxy"
 	"os"
+	"packages/policy"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,9 +15,14 @@ func RegisterRoutes(r *gin.Engine) {
 	r.Any("/api/v1/users/*path",
 		proxy.NewReverseProxy(os.Getenv("USER_SERVICE_URL")),
 	)
+	r.Any("/api/v1/organizations", proxy.NewReverseProxy(os.Getenv("USER_SERVICE_URL")))
+	r.Any("/api/v1/organizations/*path", proxy.NewReverseProxy(os.Getenv("USER_SERVICE_URL")))
 
 	r.Any("/api/venues", proxy.NewReverseProxy(os.Getenv("VENUE_SERVICE_URL")))
 	r.Any("/api/venues/*path", proxy.NewReverseProxy(os.Getenv("VENUE_SERVICE_URL")))
+	r.Any("/api/v1/catalog/*path", proxy.NewReverseProxy(os.Getenv("VENUE_SERVICE_URL")))
+	r.Any("/api/v1/images/*path", proxy.NewReverseProxy(os.Getenv("VENUE_SERVICE_URL")))
+	r.Any("/api/v1/holidays", proxy.NewReverseProxy(os.Getenv("VENUE_SERVICE_URL")))
 
 	r.Any("/api/booking/*path", middleware.AuthMiddleware(), proxy.NewReverseProxy(os.Getenv("BOOKING_SERVICE_URL")))
 
@@ -30,7 +36,7 @@ func RegisterRoutes(r *gin.Engine) {
 
 	r.Any("/api/stats/*path",
 		middleware.AuthMiddleware(),
-		middleware.RequireRole("admin"),
+		middleware.RequirePermission(policy.StatsRead),
 		proxy.NewReverseProxy(os.Getenv("STATISTIC_SERVICE_URL")),
 	)
 }
@@ -0,0 +1,5 @@
+module packages
+
+go 1.24.4
+
+require github.com/golang-jwt/jwt/v5 v5.3.0
@@ -0,0 +1,47 @@
+// Package imagingtest holds the pictures the tests of image uploads are made of.
+package imagingtest
+
+import (
+	"image"
+	"image/color"
+)
+
+var (
+	Red  = color.RGBA{R: 255, A: 255}
+	Blue = color.RGBA{B: 255, A: 255}
+)
+
+// Halves draws a picture whose left half is red and right half blue.
+func Halves(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, Red)
+			} else {
+				img.Set(x, y, Blue)
+			}
+		}
+	}
+	return img
+}
+
+// IsRed reports whether c is close to the red of Halves.
+func IsRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xC000 && b < 0x4000
+}
+
+// WithOrientation inserts an EXIF segment holding the orientation right after the SOI marker
+// of the JPEG.
+func WithOrientation(jpg []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0, 42, 0, 0, 0, 8, // big endian, IFD0 at offset 8
+		0, 1, // one entry
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, // Orientation, SHORT, count 1
+		0, 0, 0, 0, // no next IFD
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := append([]byte{0xFF, 0xE1, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}, segment...)
+	return append(append([]byte{0xFF, 0xD8}, app1...), jpg[2:]...)
+}
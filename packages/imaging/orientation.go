@@ -0,0 +1,100 @@
+// Package imaging holds the image helpers shared by the services that take picture uploads.
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// Orient turns a picture upright according to its EXIF orientation (1 to 8).
+func Orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-dx, dy
+			case 3: // upside down
+				sx, sy = w-1-dx, h-1-dy
+			case 4: // mirrored upside down
+				sx, sy = dx, h-1-dy
+			case 5: // mirrored, rotated 90° counter-clockwise
+				sx, sy = dy, dx
+			case 6: // rotated 90° counter-clockwise
+				sx, sy = dy, h-1-dx
+			case 7: // mirrored, rotated 90° clockwise
+				sx, sy = w-1-dy, h-1-dx
+			case 8: // rotated 90° clockwise
+				sx, sy = w-1-dy, dx
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// JPEGOrientation reads the orientation tag from the EXIF segment of a JPEG, or returns 1.
+func JPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // image data starts, no more metadata
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 { // Orientation, a SHORT stored in place
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
@@ -0,0 +1,65 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// exifJPEG is the start of a JPEG holding only an EXIF segment with the orientation.
+func exifJPEG(orientation byte, bigEndian bool) []byte {
+	tiff := []byte{
+		'I', 'I', 42, 0, 8, 0, 0, 0, // little endian, first IFD at 8
+		1, 0, // one entry
+		0x12, 0x01, 3, 0, 1, 0, 0, 0, orientation, 0, 0, 0, // Orientation, SHORT, count 1
+	}
+	if bigEndian {
+		tiff = []byte{
+			'M', 'M', 0, 42, 0, 0, 0, 8,
+			0, 1,
+			0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0,
+		}
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(length >> 8), byte(length)}
+	return append(append(data, segment...), 0xFF, 0xDA)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"little endian", exifJPEG(6, false), 6},
+		{"big endian", exifJPEG(3, true), 3},
+		{"no exif", []byte{0xFF, 0xD8, 0xFF, 0xDA}, 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"truncated", exifJPEG(6, false)[:20], 1},
+	}
+	for _, tc := range cases {
+		if got := JPEGOrientation(tc.data); got != tc.want {
+			t.Errorf("%s: JPEGOrientation = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOrient_RotatesClockwise(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	left, right := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	src.Set(0, 0, left)
+	src.Set(1, 0, right)
+
+	dst := Orient(src, 6)
+
+	if b := dst.Bounds(); b.Dx() != 1 || b.Dy() != 2 {
+		t.Fatalf("bounds = %v, want 1x2", b)
+	}
+	if dst.RGBAAt(0, 0) != left || dst.RGBAAt(0, 1) != right {
+		t.Errorf("pixels = %v, %v; want the left one on top", dst.RGBAAt(0, 0), dst.RGBAAt(0, 1))
+	}
+	if Orient(src, 1) != src {
+		t.Error("orientation 1 should keep the picture as is")
+	}
+}
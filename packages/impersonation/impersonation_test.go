@@ -0,0 +1,55 @@
+package impersonation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	var got []Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []Request `json:"requests"`
+		}
+		if r.URL.Path != Path || json.NewDecoder(r.Body).Decode(&body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, body.Requests...)
+		recorded := 1
+		if body.Requests[0].Token == "forged" {
+			recorded = 0
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]int{"recorded": recorded, "rejected": 1 - recorded}})
+	}))
+	defer server.Close()
+	recorder := NewRecorder(server.URL, server.Client())
+
+	req := Request{Token: "tok", Method: http.MethodDelete, Path: "/api/v1/bookings/3", IP: "10.0.0.1", OccurredAt: time.Now()}
+	if err := recorder.Record(context.Background(), req); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if len(got) != 1 || got[0].Path != req.Path || got[0].Token != "tok" {
+		t.Errorf("auth-service got %+v, want the request", got)
+	}
+
+	if err := recorder.Record(context.Background(), Request{Token: "forged"}); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Record of a rejected token = %v, want ErrNotRecorded", err)
+	}
+}
+
+func TestRecorder_AuthServiceDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewRecorder(server.URL, server.Client()).Record(context.Background(), Request{Token: "tok"}); err == nil {
+		t.Error("Record succeeded on a 500")
+	}
+}
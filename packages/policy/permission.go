@@ -0,0 +1,129 @@
+package policy
+
+// Permission is a named capability checked by middlewares and usecases instead of raw role names.
+type Permission string
+
+const (
+	VenueCreate        Permission = "venue:create"
+	VenueUpdate        Permission = "venue:update"
+	VenueDelete        Permission = "venue:delete"
+	VenueApprove       Permission = "venue:approve"
+	VenueReadAll       Permission = "venue:read_all"
+	VenueManageMembers Permission = "venue:manage_members"
+
+	SpaceCreate        Permission = "space:create"
+	SpaceUpdate        Permission = "space:update"
+	SpaceDelete        Permission = "space:delete"
+	SpaceAssignManager Permission = "space:assign_manager"
+
+	AmenityManage Permission = "amenity:manage"
+	HolidayManage Permission = "holiday:manage"
+
+	BookingCreate  Permission = "booking:create"
+	BookingRead    Permission = "booking:read"
+	BookingReadAll Permission = "booking:read_all"
+
+	PaymentCreate Permission = "payment:create"
+
+	ProfileManage   Permission = "profile:manage"
+	UserReadAll     Permission = "user:read_all"
+	UserManage      Permission = "user:manage"
+	UserImpersonate Permission = "user:impersonate"
+
+	StatsRead Permission = "stats:read"
+)
+
+// Global roles, carried in the JWT "role" claim.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// RoleSystem is carried by service tokens only. It holds no user permission and cannot be
+// assigned to a user, see IsGlobalRole.
+const RoleSystem = "system"
+
+// Scoped roles, granted on a single venue or space.
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleStaff   = "staff"
+)
+
+var userPermissions = []Permission{
+	VenueCreate,
+	BookingCreate,
+	PaymentCreate,
+	ProfileManage,
+}
+
+var moderatorPermissions = []Permission{
+	VenueApprove,
+	VenueReadAll,
+	AmenityManage,
+	HolidayManage,
+	BookingReadAll,
+	UserReadAll,
+	ProfileManage,
+}
+
+// rolePermissions applies everywhere: a global permission is never restricted to a scope.
+var rolePermissions = map[string][]Permission{
+	RoleUser:      userPermissions,
+	RoleModerator: moderatorPermissions,
+	RoleAdmin: append(append([]Permission{}, moderatorPermissions...),
+		VenueCreate,
+		VenueUpdate,
+		VenueDelete,
+		VenueManageMembers,
+		SpaceCreate,
+		SpaceUpdate,
+		SpaceDelete,
+		SpaceAssignManager,
+		BookingRead,
+		UserManage,
+		UserImpersonate,
+		StatsRead,
+	),
+}
+
+// scopedRolePermissions only apply to the scope the grant was given on.
+var scopedRolePermissions = map[string][]Permission{
+	RoleOwner: {
+		VenueUpdate,
+		VenueDelete,
+		VenueManageMembers,
+		SpaceCreate,
+		SpaceUpdate,
+		SpaceDelete,
+		SpaceAssignManager,
+		BookingRead,
+	},
+	RoleManager: {
+		SpaceUpdate,
+		SpaceDelete,
+		BookingRead,
+	},
+	RoleStaff: {
+		BookingRead,
+	},
+}
+
+// impersonationDenied lists permissions an impersonation token never carries, whatever the
+// impersonated role holds: support staff may look, but must not move money on a customer's behalf.
+var impersonationDenied = map[Permission]bool{
+	PaymentCreate: true,
+}
+
+// IsGlobalRole reports whether role is a valid value for the JWT role claim.
+func IsGlobalRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsScopedRole reports whether role can be granted on a venue.
+func IsScopedRole(role string) bool {
+	_, ok := scopedRolePermissions[role]
+	return ok
+}
@@ -0,0 +1,107 @@
+// Package policy is the single place where roles are turned into permissions.
+// Services evaluate a global role with Can, and scoped grants (owner/manager/staff
+// of a venue or space) with Subject.Can.
+package policy
+
+import "fmt"
+
+// Scope identifies the resource a grant was given on, e.g. venue 7 or space 12.
+type Scope struct {
+	Kind string
+	ID   uint
+}
+
+const (
+	ScopeVenue = "venue"
+	ScopeSpace = "space"
+)
+
+func VenueScope(id uint) Scope {
+	return Scope{Kind: ScopeVenue, ID: id}
+}
+
+func SpaceScope(id uint) Scope {
+	return Scope{Kind: ScopeSpace, ID: id}
+}
+
+func (s Scope) String() string {
+	return fmt.Sprintf("%s:%d", s.Kind, s.ID)
+}
+
+// Grant gives a scoped role on one resource.
+type Grant struct {
+	Role  string
+	Scope Scope
+}
+
+// Actor is the "act" claim of an impersonation token: the admin really making the request.
+type Actor struct {
+	UserID uint   `json:"sub"`
+	Email  string `json:"email,omitempty"`
+}
+
+// Subject is the caller being authorised: its global role plus any grants the service resolved.
+// Actor is set when an admin is impersonating the user.
+type Subject struct {
+	UserID uint
+	Role   string
+	Grants []Grant
+	Actor  *Actor
+}
+
+// Can reports whether the global role holds perm.
+func Can(role string, perm Permission) bool {
+	return contains(rolePermissions[role], perm)
+}
+
+// Can reports whether the subject holds perm either globally or through a grant on one of scopes.
+// Callers pass every scope that encloses the resource, e.g. a space and its venue, so that a
+// venue owner is allowed on the venue's spaces.
+func (s Subject) Can(perm Permission, scopes ...Scope) bool {
+	if s.Actor != nil && impersonationDenied[perm] {
+		return false
+	}
+	if Can(s.Role, perm) {
+		return true
+	}
+	for _, g := range s.Grants {
+		if !contains(scopedRolePermissions[g.Role], perm) {
+			continue
+		}
+		for _, scope := range scopes {
+			if g.Scope == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanAll reports whether the global role holds every permission in perms.
+func CanAll(role string, perms ...Permission) bool {
+	for _, p := range perms {
+		if !Can(role, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedWhileImpersonating reports whether none of perms is withheld from impersonation tokens.
+func AllowedWhileImpersonating(perms ...Permission) bool {
+	for _, p := range perms {
+		if impersonationDenied[p] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(perms []Permission, perm Permission) bool {
+	for _, p := range perms {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
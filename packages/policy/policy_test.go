@@ -0,0 +1,90 @@
+package policy
+
+import "testing"
+
+func TestCan_GlobalRoles(t *testing.T) {
+	cases := []struct {
+		role string
+		perm Permission
+		want bool
+	}{
+		{RoleUser, VenueCreate, true},
+		{RoleAdmin, VenueCreate, true},
+		{RoleModerator, VenueCreate, false},
+		{RoleModerator, VenueApprove, true},
+		{RoleUser, VenueApprove, false},
+		{RoleModerator, BookingReadAll, true},
+		{RoleUser, BookingReadAll, false},
+		{RoleAdmin, UserManage, true},
+		{RoleModerator, HolidayManage, true},
+		{RoleUser, HolidayManage, false},
+		{RoleModerator, UserManage, false},
+		{"unknown", ProfileManage, false},
+	}
+	for _, tc := range cases {
+		if got := Can(tc.role, tc.perm); got != tc.want {
+			t.Errorf("Can(%q, %q) = %v, want %v", tc.role, tc.perm, got, tc.want)
+		}
+	}
+}
+
+func TestSubjectCan_ScopedGrants(t *testing.T) {
+	owner := Subject{UserID: 1, Role: RoleUser, Grants: []Grant{{Role: RoleOwner, Scope: VenueScope(7)}}}
+	manager := Subject{UserID: 2, Role: RoleUser, Grants: []Grant{{Role: RoleManager, Scope: SpaceScope(12)}}}
+	staff := Subject{UserID: 3, Role: RoleUser, Grants: []Grant{{Role: RoleStaff, Scope: VenueScope(7)}}}
+
+	if !owner.Can(SpaceUpdate, SpaceScope(12), VenueScope(7)) {
+		t.Error("owner should update spaces of their venue")
+	}
+	if owner.Can(VenueUpdate, VenueScope(8)) {
+		t.Error("owner grant must not leak to another venue")
+	}
+	if !manager.Can(SpaceUpdate, SpaceScope(12), VenueScope(7)) {
+		t.Error("manager should update their space")
+	}
+	if manager.Can(SpaceAssignManager, SpaceScope(12), VenueScope(7)) {
+		t.Error("manager must not reassign the manager")
+	}
+	if !staff.Can(BookingRead, VenueScope(7)) {
+		t.Error("staff should read bookings of their venue")
+	}
+	if staff.Can(VenueUpdate, VenueScope(7)) {
+		t.Error("staff must not update the venue")
+	}
+}
+
+func TestSubjectCan_GlobalWinsWithoutGrants(t *testing.T) {
+	admin := Subject{UserID: 9, Role: RoleAdmin}
+	if !admin.Can(VenueDelete, VenueScope(7)) {
+		t.Error("admin should delete any venue")
+	}
+	user := Subject{UserID: 10, Role: RoleUser}
+	if user.Can(VenueDelete, VenueScope(7)) {
+		t.Error("user without grant must not delete a venue")
+	}
+}
+
+func TestCanAll(t *testing.T) {
+	if !CanAll(RoleAdmin, VenueApprove, UserManage) {
+		t.Error("admin should hold both permissions")
+	}
+	if CanAll(RoleModerator, VenueApprove, UserManage) {
+		t.Error("moderator lacks user:manage")
+	}
+}
+
+func TestImpersonationWithholdsPayments(t *testing.T) {
+	sub := Subject{UserID: 1, Role: RoleUser, Actor: &Actor{UserID: 9}}
+	if sub.Can(PaymentCreate) {
+		t.Error("impersonation must not create payments")
+	}
+	if !sub.Can(BookingCreate) {
+		t.Error("impersonation should keep the other user permissions")
+	}
+	if AllowedWhileImpersonating(BookingCreate, PaymentCreate) {
+		t.Error("payment:create must be withheld")
+	}
+	if !AllowedWhileImpersonating() {
+		t.Error("routes without permissions stay reachable")
+	}
+}
@@ -0,0 +1,131 @@
+// Package preferences is the contract for the preferences users keep in user-service: language,
+// timezone, currency display and the channels each notification event is delivered on.
+//
+// user-service publishes the whole set of a user as JSON to a compacted Kafka topic, keyed by
+// user ID, every time it changes. Consumers keeping them in memory read the topic from the
+// start on boot, so they always end up with the latest preferences of every user.
+package preferences
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+	// timezones have to resolve on hosts without a zoneinfo database too
+	_ "time/tzdata"
+)
+
+const (
+	EnvTopic     = "KAFKA_TOPIC_USER_PREFERENCES"
+	DefaultTopic = "user-preferences"
+)
+
+const (
+	LanguageEnglish    = "en"
+	LanguageVietnamese = "vi"
+	DefaultLanguage    = LanguageEnglish
+	DefaultTimezone    = "UTC"
+	DefaultCurrency    = "VND"
+)
+
+var (
+	Languages  = []string{LanguageEnglish, LanguageVietnamese}
+	Currencies = []string{"VND", "USD", "EUR"}
+)
+
+// Channels a notification can be delivered on. Each is honoured by the service delivering on it.
+// SMS only ever goes to Phone, the number the user verified.
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+var Channels = []string{ChannelInApp, ChannelEmail, ChannelSMS}
+
+// Events users can choose the channels of, named after the type of their notification event.
+const (
+	EventBookingCreated       = "BOOKING_CREATED"
+	EventBookingStatusUpdated = "BOOKING_STATUS_UPDATED"
+)
+
+var Events = []string{EventBookingCreated, EventBookingStatusUpdated}
+
+// DefaultChannels are the channels of an event the user did not choose any for.
+var DefaultChannels = []string{ChannelInApp, ChannelEmail}
+
+// Preferences is the message published for one user.
+type Preferences struct {
+	UserID    uint                `json:"user_id"`
+	Language  string              `json:"language"`
+	Timezone  string              `json:"timezone"`
+	Currency  string              `json:"currency"`
+	Channels  map[string][]string `json:"channels"`
+	Phone     string              `json:"phone,omitempty"` // verified number in E.164 format, if any
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Default is what a user who never changed anything gets.
+func Default(userID uint) Preferences {
+	return Preferences{
+		UserID:   userID,
+		Language: DefaultLanguage,
+		Timezone: DefaultTimezone,
+		Currency: DefaultCurrency,
+		Channels: map[string][]string{},
+	}
+}
+
+// Decode reads a published message, filling in defaults for anything missing.
+func Decode(data []byte) (Preferences, error) {
+	var p Preferences
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Preferences{}, err
+	}
+	if p.Language == "" {
+		p.Language = DefaultLanguage
+	}
+	if p.Timezone == "" {
+		p.Timezone = DefaultTimezone
+	}
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	return p, nil
+}
+
+// Wants reports whether the user gets event on channel.
+func (p Preferences) Wants(event, channel string) bool {
+	channels, ok := p.Channels[event]
+	if !ok {
+		channels = DefaultChannels
+	}
+	return slices.Contains(channels, channel)
+}
+
+// WantsSMS reports whether the user gets event by SMS, which needs a verified number.
+func (p Preferences) WantsSMS(event string) bool {
+	return p.Phone != "" && p.Wants(event, ChannelSMS)
+}
+
+// Location is the timezone of the user, UTC if it is unknown to this host.
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+var timeLayouts = map[string]string{
+	LanguageEnglish:    "Jan 2, 2006 15:04 MST",
+	LanguageVietnamese: "15:04 02/01/2006 (MST)",
+}
+
+// FormatTime writes t in the timezone of the user, the way their language usually does.
+func (p Preferences) FormatTime(t time.Time) string {
+	layout, ok := timeLayouts[p.Language]
+	if !ok {
+		layout = timeLayouts[DefaultLanguage]
+	}
+	return t.In(p.Location()).Format(layout)
+}
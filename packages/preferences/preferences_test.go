@@ -0,0 +1,70 @@
+package preferences
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecode_FillsDefaults(t *testing.T) {
+	p, err := Decode([]byte(`{"user_id":7,"language":"vi"}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if p.UserID != 7 || p.Language != LanguageVietnamese {
+		t.Errorf("Decode() = %+v", p)
+	}
+	if p.Timezone != DefaultTimezone || p.Currency != DefaultCurrency {
+		t.Errorf("defaults not filled in: %+v", p)
+	}
+}
+
+func TestWants(t *testing.T) {
+	p := Default(7)
+	p.Channels[EventBookingCreated] = []string{ChannelSMS}
+
+	if p.Wants(EventBookingCreated, ChannelInApp) {
+		t.Error("in-app wanted although only sms was chosen")
+	}
+	if !p.Wants(EventBookingCreated, ChannelSMS) {
+		t.Error("sms not wanted although chosen")
+	}
+	if !p.Wants(EventBookingStatusUpdated, ChannelEmail) || p.Wants(EventBookingStatusUpdated, ChannelSMS) {
+		t.Error("event without a choice does not use the default channels")
+	}
+}
+
+func TestWantsSMS_NeedsVerifiedPhone(t *testing.T) {
+	p := Default(7)
+	p.Channels[EventBookingCreated] = []string{ChannelSMS}
+
+	if p.WantsSMS(EventBookingCreated) {
+		t.Error("sms wanted without a verified phone")
+	}
+	p.Phone = "+84912345678"
+	if !p.WantsSMS(EventBookingCreated) {
+		t.Error("sms not wanted although chosen with a verified phone")
+	}
+	if p.WantsSMS(EventBookingStatusUpdated) {
+		t.Error("sms wanted for an event using the default channels")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	at := time.Date(2026, 3, 5, 8, 30, 0, 0, time.UTC)
+
+	p := Default(7)
+	p.Timezone = "Asia/Ho_Chi_Minh"
+	if got, want := p.FormatTime(at), "Mar 5, 2026 15:30 +07"; got != want {
+		t.Errorf("FormatTime() = %q, want %q", got, want)
+	}
+
+	p.Language = LanguageVietnamese
+	if got, want := p.FormatTime(at), "15:30 05/03/2026 (+07)"; got != want {
+		t.Errorf("FormatTime() = %q, want %q", got, want)
+	}
+
+	p.Timezone = "Mars/Olympus_Mons"
+	if got, want := p.FormatTime(at), "08:30 05/03/2026 (UTC)"; got != want {
+		t.Errorf("FormatTime() with unknown timezone = %q, want %q", got, want)
+	}
+}
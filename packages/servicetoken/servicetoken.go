@@ -0,0 +1,179 @@
+// Package servicetoken authenticates calls between services. The caller signs a short-lived
+// token naming itself as subject and the target service as audience, with the "system" role;
+// the target only accepts tokens addressed to it. Tokens are signed with SERVICE_TOKEN_SECRET,
+// a key distinct from the one of user tokens, so neither kind can pass for the other.
+package servicetoken
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"packages/policy"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Header carries the service token. It is separate from Authorization so a request can never
+// mix up a user token and a service token.
+const Header = "X-Service-Token"
+
+const EnvSecret = "SERVICE_TOKEN_SECRET"
+
+// Service names, used as token subject and audience.
+const (
+	AuthService         = "auth-service"
+	UserService         = "user-service"
+	VenueService        = "venue-service"
+	BookingService      = "booking-service"
+	PaymentService      = "payment-service"
+	ChatService         = "chat-service"
+	NotificationService = "notification-service"
+	APIGateway          = "api-gateway"
+)
+
+const (
+	TTL = 5 * time.Minute
+	// a cached token is replaced this long before it expires, so it never expires in flight
+	renewBefore = time.Minute
+	// tolerated clock skew between services
+	leeway = 30 * time.Second
+
+	minSecretLength = 32
+)
+
+var (
+	ErrMissingSecret = fmt.Errorf("%s must be set to at least %d bytes", EnvSecret, minSecretLength)
+	ErrInvalidToken  = errors.New("invalid service token")
+)
+
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// SecretFromEnv reads the shared signing key.
+func SecretFromEnv() ([]byte, error) {
+	secret := os.Getenv(EnvSecret)
+	if len(secret) < minSecretLength {
+		return nil, ErrMissingSecret
+	}
+	return []byte(secret), nil
+}
+
+// Issuer signs the tokens of one calling service and reuses them until shortly before expiry.
+type Issuer struct {
+	service string
+	secret  []byte
+	now     func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken // audience -> token
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+func NewIssuer(service string, secret []byte) *Issuer {
+	return &Issuer{
+		service: service,
+		secret:  secret,
+		now:     time.Now,
+		tokens:  make(map[string]cachedToken),
+	}
+}
+
+// Token returns a token for calling the audience service.
+func (i *Issuer) Token(audience string) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	if cached, ok := i.tokens[audience]; ok && now.Add(renewBefore).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	expiresAt := now.Add(TTL)
+	claims := &Claims{
+		Role: policy.RoleSystem,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.service,
+			Subject:   i.service,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", err
+	}
+	i.tokens[audience] = cachedToken{token: token, expiresAt: expiresAt}
+	return token, nil
+}
+
+// Client returns an HTTP client that attaches a token for audience to every request.
+func (i *Issuer) Client(audience string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &transport{issuer: i, audience: audience, base: http.DefaultTransport},
+	}
+}
+
+type transport struct {
+	issuer   *Issuer
+	audience string
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.issuer.Token(t.audience)
+	if err != nil {
+		return nil, fmt.Errorf("sign service token: %w", err)
+	}
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, token)
+	return t.base.RoundTrip(req)
+}
+
+// Verifier checks the tokens sent to one service.
+type Verifier struct {
+	service string
+	secret  []byte
+	now     func() time.Time
+}
+
+func NewVerifier(service string, secret []byte) *Verifier {
+	return &Verifier{service: service, secret: secret, now: time.Now}
+}
+
+// Verify returns the claims of a valid token addressed to this service. The calling service
+// is the subject; callers decide which services they accept.
+func (v *Verifier) Verify(token string, callers ...string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return v.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(v.service),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Role != policy.RoleSystem || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if len(callers) > 0 && !slices.Contains(callers, claims.Subject) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,106 @@
+package servicetoken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestVerify_AcceptsTokenForThisService(t *testing.T) {
+	token, err := NewIssuer(PaymentService, secret).Token(BookingService)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := NewVerifier(BookingService, secret).Verify(token, PaymentService)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.Subject != PaymentService {
+		t.Errorf("subject = %q, want %q", claims.Subject, PaymentService)
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	issuer := NewIssuer(PaymentService, secret)
+	forBooking, _ := issuer.Token(BookingService)
+	forUser, _ := issuer.Token(UserService)
+	otherKey, _ := NewIssuer(PaymentService, []byte("another-secret-another-secret-xx")).Token(BookingService)
+
+	expiredIssuer := NewIssuer(PaymentService, secret)
+	expiredIssuer.now = func() time.Time { return time.Now().Add(-TTL - time.Minute) }
+	expired, _ := expiredIssuer.Token(BookingService)
+
+	// a token signed with the right key but without the system role, like a user token
+	userToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   PaymentService,
+			Audience:  jwt.ClaimStrings{BookingService},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}).SignedString(secret)
+
+	cases := map[string]struct {
+		token   string
+		callers []string
+	}{
+		"other audience":    {forUser, nil},
+		"other key":         {otherKey, nil},
+		"expired":           {expired, nil},
+		"not system role":   {userToken, nil},
+		"caller not listed": {forBooking, []string{VenueService}},
+		"garbage":           {"not-a-token", nil},
+	}
+	verifier := NewVerifier(BookingService, secret)
+	for name, tc := range cases {
+		if _, err := verifier.Verify(tc.token, tc.callers...); err == nil {
+			t.Errorf("%s: Verify() accepted the token", name)
+		}
+	}
+}
+
+func TestIssuer_ReusesTokenUntilRenewal(t *testing.T) {
+	now := time.Now()
+	issuer := NewIssuer(VenueService, secret)
+	issuer.now = func() time.Time { return now }
+
+	first, _ := issuer.Token(BookingService)
+	now = now.Add(TTL - renewBefore - time.Second)
+	second, _ := issuer.Token(BookingService)
+	now = now.Add(2 * time.Second)
+	third, _ := issuer.Token(BookingService)
+
+	if first != second {
+		t.Error("token should be reused while far from expiry")
+	}
+	if third == second {
+		t.Error("token should be renewed shortly before expiry")
+	}
+}
+
+func TestClient_AttachesToken(t *testing.T) {
+	verifier := NewVerifier(UserService, secret)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r.Header.Get(Header), AuthService); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp, err := NewIssuer(AuthService, secret).Client(UserService, time.Second).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,150 @@
+// Package session lets every service reject the tokens of sessions revoked in auth-service.
+// Access and refresh tokens are stateless, so auth-service publishes revocations on an internal
+// feed that each service mirrors in memory.
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Types of the user tokens issued by auth-service that authenticate requests to the services.
+// Refresh tokens and single-purpose tokens (MFA, email links, ...) must never be accepted.
+const (
+	TokenTypeAccess        = "access"
+	TokenTypeImpersonation = "impersonation"
+)
+
+const (
+	// Retention is how long a revoked session has to be remembered: the lifetime of the refresh
+	// tokens issued by auth-service, the longest lived tokens of a session. After that no token
+	// of the session can still be valid.
+	Retention = 7 * 24 * time.Hour
+
+	// RevokedPath is the internal route of auth-service listing revoked sessions.
+	RevokedPath = "/api/v1/internal/sessions/revoked"
+	// SyncInterval is how often a store asks for new revocations.
+	SyncInterval = 5 * time.Second
+)
+
+// Bearer reports whether a token of that type may authenticate a request.
+func Bearer(tokenType string) bool {
+	return tokenType == TokenTypeAccess || tokenType == TokenTypeImpersonation
+}
+
+// Revoked is a session ended in auth-service.
+type Revoked struct {
+	ID        string    `json:"id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// Source returns the sessions revoked since the unix time since, and the server time to ask
+// from next.
+type Source func(ctx context.Context, since int64) ([]Revoked, int64, error)
+
+// HTTPSource reads the revocation feed of auth-service with client, which must authenticate
+// the calling service.
+func HTTPSource(authURL string, client *http.Client) Source {
+	return func(ctx context.Context, since int64) ([]Revoked, int64, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?since=%d", authURL, RevokedPath, since), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+
+		var body struct {
+			Data struct {
+				Sessions   []Revoked `json:"sessions"`
+				ServerTime int64     `json:"server_time"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return nil, 0, err
+		}
+		return body.Data.Sessions, body.Data.ServerTime, nil
+	}
+}
+
+// RevocationStore mirrors the sessions revoked in the last Retention.
+type RevocationStore struct {
+	source Source
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time // sid -> revoked at
+	since   int64
+}
+
+func NewRevocationStore(source Source) *RevocationStore {
+	return &RevocationStore{
+		source:  source,
+		revoked: make(map[string]time.Time),
+		since:   time.Now().Add(-Retention).Unix(),
+	}
+}
+
+// IsRevoked reports whether the session was revoked. Tokens without a session are never.
+func (s *RevocationStore) IsRevoked(sid string) bool {
+	if sid == "" {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.revoked[sid]
+	return ok
+}
+
+// Run keeps the store in sync with auth-service until ctx is cancelled.
+func (s *RevocationStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx); err != nil {
+			log.Println("sync revoked sessions failed:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync fetches the revocations made since the last call.
+func (s *RevocationStore) Sync(ctx context.Context) error {
+	s.mu.RLock()
+	since := s.since
+	s.mu.RUnlock()
+
+	sessions, serverTime, err := s.source(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rs := range sessions {
+		s.revoked[rs.ID] = rs.RevokedAt
+	}
+	for sid, revokedAt := range s.revoked {
+		if now.Sub(revokedAt) > Retention {
+			delete(s.revoked, sid)
+		}
+	}
+	// overlap one second so revocations committed in the same second are not missed
+	s.since = serverTime - 1
+	return nil
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBearer(t *testing.T) {
+	cases := map[string]bool{
+		TokenTypeAccess:        true,
+		TokenTypeImpersonation: true,
+		"refresh":              false,
+		"mfa_challenge":        false,
+		"":                     false,
+	}
+	for tokenType, want := range cases {
+		if got := Bearer(tokenType); got != want {
+			t.Errorf("Bearer(%q) = %v, want %v", tokenType, got, want)
+		}
+	}
+}
+
+func TestRevocationStore_SyncsFromAuthService(t *testing.T) {
+	var asked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = append(asked, r.URL.Query().Get("since"))
+		if r.URL.Path != RevokedPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"sessions": []Revoked{
+				{ID: "recent", RevokedAt: time.Now().Add(-time.Hour)},
+				// refresh tokens of a session live a week, so a day-old revocation still counts
+				{ID: "yesterday", RevokedAt: time.Now().Add(-24 * time.Hour)},
+				{ID: "expired", RevokedAt: time.Now().Add(-Retention - time.Hour)},
+			},
+			"server_time": 1000,
+		}})
+	}))
+	defer server.Close()
+
+	store := NewRevocationStore(HTTPSource(server.URL, server.Client()))
+	if err := store.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for sid, want := range map[string]bool{"recent": true, "yesterday": true, "expired": false, "other": false, "": false} {
+		if got := store.IsRevoked(sid); got != want {
+			t.Errorf("IsRevoked(%q) = %v, want %v", sid, got, want)
+		}
+	}
+	if len(asked) != 2 || asked[1] != "999" {
+		t.Errorf("since asked = %v, want the server time less a second on the second call", asked)
+	}
+}
+
+func TestRevocationStore_SyncFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	store := NewRevocationStore(HTTPSource(server.URL, server.Client()))
+	if err := store.Sync(context.Background()); err == nil {
+		t.Error("Sync succeeded on a 401")
+	}
+}
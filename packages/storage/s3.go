@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigAlgorithm     = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	headerDate       = "X-Amz-Date"
+	headerContentSHA = "X-Amz-Content-Sha256"
+)
+
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL, when set, is the base URL of a publicly readable bucket; URL then returns
+	// plain links instead of signed ones.
+	PublicURL string
+}
+
+// S3Storage talks to any S3-compatible store (AWS, MinIO, ...) with path-style requests signed
+// with Signature Version 4.
+type S3Storage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	http     *http.Client
+	now      func() time.Time
+}
+
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" || cfg.Region == "" {
+		return nil, errors.New("s3 storage needs an endpoint, region, bucket and credentials")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	return &S3Storage{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, data)
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil)
+}
+
+// URL returns a public link when the bucket is public, else a GET link signed for an hour.
+func (s *S3Storage) URL(_ context.Context, key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	if s.cfg.PublicURL != "" {
+		return s.cfg.PublicURL + "/" + escapePath(key), nil
+	}
+
+	u := s.objectURL(key)
+	t := s.now().UTC()
+	query := url.Values{
+		"X-Amz-Algorithm":     {sigAlgorithm},
+		"X-Amz-Credential":    {s.cfg.AccessKey + "/" + s.scope(t)},
+		"X-Amz-Date":          {t.Format(amzDateFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(signedURLTTL.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	headers := http.Header{}
+	signature := s.signature(t, http.MethodGet, u, headers, []string{"host"}, unsignedPayload)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	return req, nil
+}
+
+func (s *S3Storage) do(req *http.Request, body []byte) error {
+	s.sign(req, body)
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.cfg.Bucket + "/" + key
+	u.RawPath = strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + "/" + escapePath(s.cfg.Bucket) + "/" + escapePath(key)
+	return &u
+}
+
+// sign adds the Authorization header of Signature Version 4, covering the host, the x-amz-*
+// headers and the content type.
+func (s *S3Storage) sign(req *http.Request, body []byte) {
+	t := s.now().UTC()
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set(headerDate, t.Format(amzDateFormat))
+	req.Header.Set(headerContentSHA, payloadHash)
+
+	signed := []string{"host"}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "range" {
+			signed = append(signed, lower)
+		}
+	}
+	sort.Strings(signed)
+
+	signature := s.signature(t, req.Method, req.URL, req.Header, signed, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigAlgorithm, s.cfg.AccessKey, s.scope(t), strings.Join(signed, ";"), signature))
+}
+
+func (s *S3Storage) signature(t time.Time, method string, u *url.URL, headers http.Header, signed []string, payloadHash string) string {
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		value := u.Host
+		if name != "host" {
+			value = strings.Join(headers.Values(name), ",")
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		u.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigAlgorithm,
+		t.Format(amzDateFormat),
+		s.scope(t),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *S3Storage) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath encodes each segment of a key the way Signature Version 4 expects: everything
+// but unreserved characters, keeping the slashes.
+func escapePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = uriEncode(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		for _, v := range values[k] {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
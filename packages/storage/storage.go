@@ -0,0 +1,87 @@
+// Package storage keeps uploaded files, such as avatars and venue photos, on the local
+// filesystem or in an S3-compatible object store, and hands out URLs to read them back.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Storage stores objects under slash-separated keys such as "avatars/1/ab12.jpg".
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL returns where clients can fetch the object. It may be signed and expire.
+	URL(ctx context.Context, key string) (string, error)
+}
+
+const (
+	// The storage of a service is configured by <prefix>_STORAGE, "local" (default) or "s3",
+	// <prefix>_LOCAL_DIR and <prefix>_PUBLIC_URL, e.g. AVATAR_STORAGE for user-service.
+	EnvDriverSuffix    = "_STORAGE"
+	EnvLocalDirSuffix  = "_LOCAL_DIR"
+	EnvPublicURLSuffix = "_PUBLIC_URL"
+
+	EnvS3Endpoint  = "S3_ENDPOINT"
+	EnvS3Region    = "S3_REGION"
+	EnvS3Bucket    = "S3_BUCKET"
+	EnvS3AccessKey = "S3_ACCESS_KEY_ID"
+	EnvS3SecretKey = "S3_SECRET_ACCESS_KEY"
+
+	DefaultLocalDir = "uploads"
+
+	defaultS3Region = "us-east-1"
+	// lifetime of signed URLs, when the bucket is not public
+	signedURLTTL = time.Hour
+)
+
+// NewFromEnv builds the storage configured by the variables starting with prefix. localRoute
+// is where the service serves a local storage directory, and the default public URL.
+func NewFromEnv(prefix, localRoute string) (Storage, error) {
+	switch driver := os.Getenv(prefix + EnvDriverSuffix); driver {
+	case "", "local":
+		dir := os.Getenv(prefix + EnvLocalDirSuffix)
+		if dir == "" {
+			dir = DefaultLocalDir
+		}
+		publicURL := os.Getenv(prefix + EnvPublicURLSuffix)
+		if publicURL == "" {
+			publicURL = localRoute
+		}
+		return NewLocalStorage(dir, publicURL)
+	case "s3":
+		region := os.Getenv(EnvS3Region)
+		if region == "" {
+			region = defaultS3Region
+		}
+		return NewS3Storage(S3Config{
+			Endpoint:  os.Getenv(EnvS3Endpoint),
+			Region:    region,
+			Bucket:    os.Getenv(EnvS3Bucket),
+			AccessKey: os.Getenv(EnvS3AccessKey),
+			SecretKey: os.Getenv(EnvS3SecretKey),
+			PublicURL: os.Getenv(prefix + EnvPublicURLSuffix),
+		})
+	default:
+		return nil, fmt.Errorf("unknown %s%s %q", prefix, EnvDriverSuffix, driver)
+	}
+}
+
+// validKey rejects keys that could escape the storage root or the bucket.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
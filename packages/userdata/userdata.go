@@ -0,0 +1,28 @@
+// Package userdata is the contract between auth-service, which runs personal data exports and
+// account deletions, and the services holding data about users. Each of them serves, for
+// auth-service only:
+//
+//	GET    Route  the data of the user, as {"data": ...}; it becomes one JSON file of the export
+//	DELETE Route  erase or anonymise that data; repeating it must be harmless
+//
+// Financial records are kept on deletion. They only reference the user by ID, which is a
+// pseudonym once the account itself is anonymised.
+package userdata
+
+import "fmt"
+
+const Route = "/api/v1/internal/user-data/:id"
+
+// Path is Route for one user.
+func Path(userID uint) string {
+	return fmt.Sprintf("/api/v1/internal/user-data/%d", userID)
+}
+
+// DeletedName replaces the name of a deleted account.
+const DeletedName = "Deleted user"
+
+// DeletedEmail is the address a deleted account is left with. It is unique per user, so the
+// unique indexes on email keep holding, and can never receive mail.
+func DeletedEmail(userID uint) string {
+	return fmt.Sprintf("deleted-%d@deleted.invalid", userID)
+}
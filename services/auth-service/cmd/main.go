@@ -5,8 +5,10 @@ import (
 	"auth-service/internal/db"
 	"auth-service/internal/kafka"
 	"auth-service/router"
+	"context"
 	"log"
 	"os"
+	"packages/session"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +28,7 @@ func main() {
 	}
 	db.InitDB()
 	db.AutoMigrate()
+	db.BackfillProfileVerified()
 	r := gin.Default()
 
 	kafkaBrokers := os.Getenv("KAFKA_BROKERS") // format: "broker1:9092,broker2:9092"
@@ -41,7 +44,16 @@ func main() {
 	producer := kafka.New(brokerList, kafkaTopic)
 	defer producer.Close()
 
-	router.SetupRouter(r, db.DB, producer)
+	// Deliver events queued in the outbox (sign-up verification mails, verified profiles, ...),
+	// carry out data exports and account deletions once due, and create imported users
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	outbox, dataRequests, userImports, revocations := router.SetupRouter(r, db.DB, producer)
+	go outbox.Run(relayCtx)
+	go dataRequests.Run(relayCtx)
+	go userImports.Run(relayCtx)
+	go revocations.Run(relayCtx, session.SyncInterval)
+
 	err = r.Run(":8081")
 	if err != nil {
 		log.Fatal("Server failed:", err)
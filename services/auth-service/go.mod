@@ -11,6 +11,7 @@ require (
 	golang.org/x/crypto v0.42.0
 	gorm.io/driver/mysql v1.6.0
 	gorm.io/gorm v1.30.1
+	packages v0.0.0
 )
 
 require (
@@ -83,3 +84,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	sigs.k8s.io/yaml v1.6.0 // indirect
 )
+
+replace packages => ../../packages
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 const (
 	ErrInvalidInput                 = "invalid input data"
 	ErrEmailAlreadyExists           = "email already exists"
@@ -33,6 +35,36 @@ const (
 	ErrPublishEvent                 = "error.failed_to_publish_event"
 	ErrSendResetPasswordEmail       = "error.send_reset_password_email"
 	ErrSendMailFailed               = "error.send_mail_failed"
+	ErrMFAAlreadyEnabled            = "error.mfa_already_enabled"
+	ErrMFANotEnabled                = "error.mfa_not_enabled"
+	ErrMFASetupRequired             = "error.mfa_setup_required"
+	ErrMFARequiredForRole           = "error.mfa_required_for_role"
+	ErrInvalidMFACode               = "error.invalid_mfa_code"
+	ErrInvalidMFAToken              = "error.invalid_mfa_token"
+	ErrGenerateMFASecret            = "error.generate_mfa_secret_failed"
+	ErrGenerateRecoveryCodes        = "error.generate_recovery_codes_failed"
+	ErrAccountLocked                = "error.account_locked"
+	ErrTooManyAttempts              = "error.too_many_attempts"
+	ErrInvalidUserID                = "error.invalid_user_id"
+	ErrPermissionDenied             = "error.permission_denied"
+	ErrSessionNotFound              = "error.session_not_found"
+	ErrSessionRevoked               = "error.session_revoked"
+	ErrCreateSession                = "error.create_session_failed"
+	ErrCannotImpersonate            = "error.cannot_impersonate"
+	ErrSameEmail                    = "error.same_email"
+	ErrInvalidServiceToken          = "error.invalid_service_token"
+	ErrDataRequestPending           = "error.data_request_already_pending"
+	ErrDataRequestNotFound          = "error.data_request_not_found"
+	ErrExportNotReady               = "error.export_not_ready"
+	ErrImportFormat                 = "error.unsupported_import_format"
+	ErrImportInvalid                = "error.invalid_import_file"
+	ErrImportEmpty                  = "error.import_empty"
+	ErrImportTooLarge               = "error.import_too_large"
+	ErrImportNotFound               = "error.import_not_found"
+	ErrInvalidEmail                 = "error.invalid_email"
+	ErrInvalidName                  = "error.invalid_name"
+	ErrInvalidRole                  = "error.invalid_role"
+	ErrDuplicateRow                 = "error.duplicate_row"
 )
 
 const (
@@ -41,18 +73,191 @@ const (
 	SuccessLogin             = "success.login"
 	SuccessRefreshToken      = "success.refresh_token"
 	SuccessResetPasswordSent = "success.reset_password_sent"
+	SuccessMFARequired       = "success.mfa_required"
+	SuccessMFASetup          = "success.mfa_setup"
+	SuccessMFAEnabled        = "success.mfa_enabled"
+	SuccessMFADisabled       = "success.mfa_disabled"
+	SuccessRecoveryCodes     = "success.recovery_codes_generated"
+	SuccessAccountUnlocked   = "success.account_unlocked"
+	SuccessVerificationSent  = "success.verification_email_sent"
+	SuccessSessionsFetched   = "success.sessions_fetched"
+	SuccessSessionRevoked    = "success.session_revoked"
+	SuccessImpersonation     = "success.impersonation_started"
+	SuccessAuditFetched      = "success.audit_fetched"
+	SuccessEmailChangeSent   = "success.email_change_requested"
+	SuccessEmailChanged      = "success.email_changed"
+	SuccessEmailRestored     = "success.email_change_reverted"
+	SuccessMagicLinkSent     = "success.magic_link_sent"
+	SuccessExportRequested   = "success.data_export_requested"
+	SuccessDeletionScheduled = "success.account_deletion_scheduled"
+	SuccessDeletionCancelled = "success.account_deletion_cancelled"
+	SuccessDataRequests      = "success.data_requests_fetched"
+	SuccessImportQueued      = "success.user_import_queued"
+	SuccessImportsFetched    = "success.user_imports_fetched"
+	SuccessImportFetched     = "success.user_import_fetched"
+	SuccessInvitationUsed    = "success.invitation_accepted"
 )
 
 const (
-	USER_ROLE     = "user"
-	CreateUserUrl = "/api/v1/users/"
+	USER_ROLE       = "user"
+	MODERATOR_ROLE  = "moderator"
+	ADMIN_ROLE      = "admin"
+	CreateUserUrl   = "/api/v1/users/"
+	InternalUserUrl = "/api/v1/internal/users"
+	UserEmailPath   = "email"
+	UserVerifyPath  = "verified"
 )
 
 const (
 	EventTypeVerifyEmail   = "VERIFY_EMAIL"
 	EventTypeResetPassword = "RESET_PASSWORD"
+	EventTypeAccountLocked = "ACCOUNT_LOCKED"
+	EventTypeEmailChange   = "EMAIL_CHANGE_CONFIRM"
+	EventTypeEmailNotice   = "EMAIL_CHANGE_NOTICE"
+	EventTypeMagicLink     = "MAGIC_LINK"
+	EventTypeInvitation    = "USER_INVITATION"
+	// not a mail: the outbox relay marks the profile verified in user-service
+	EventTypeProfileVerified = "PROFILE_VERIFIED"
 )
 
 const (
 	PasswordLength = 12
 )
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+const (
+	TokenTypeAccess        = "access"
+	TokenTypeRefresh       = "refresh"
+	TokenTypeMFAChallenge  = "mfa_challenge"
+	TokenTypeMFAEnrollment = "mfa_enrollment"
+	TokenTypeEmailVerify   = "email_verification"
+	TokenTypeImpersonation = "impersonation"
+	TokenTypeEmailChange   = "email_change"
+	TokenTypeEmailRevert   = "email_change_revert"
+	TokenTypeInvitation    = "invitation"
+)
+
+const (
+	TOTPIssuer         = "Coworking Booking"
+	MFATokenTTL        = 5 * time.Minute
+	RecoveryCodeCount  = 10
+	EnvMFAEnforceRoles = "MFA_ENFORCE_PRIVILEGED_ROLES"
+)
+
+const (
+	AttemptActionLogin         = "login"
+	AttemptActionResetPassword = "reset_password"
+	AttemptActionUnlock        = "unlock"
+	AttemptActionResendVerify  = "resend_verification"
+	AttemptActionMagicLink     = "magic_link"
+	AttemptActionMagicRedeem   = "magic_link_redeem"
+	AttemptActionMFAVerify     = "mfa_verify"
+)
+
+const (
+	DefaultEmailVerifyTTL = 24 * time.Hour
+	EnvEmailVerifyTTL     = "EMAIL_VERIFICATION_TOKEN_TTL"
+)
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+
+	OutboxPollInterval = 2 * time.Second
+	OutboxBatchSize    = 50
+	OutboxLease        = time.Minute // how long a claimed event is hidden from other relays
+	OutboxMaxAttempts  = 10
+	OutboxMaxBackoff   = 5 * time.Minute
+)
+
+const (
+	EnvGeoIPDBPath = "GEOIP_DB_PATH"
+)
+
+const (
+	ImpersonationTokenTTL = 15 * time.Minute
+	// requests are reported by the api-gateway in batches, so a token may have expired by then
+	ImpersonationAuditLeeway = 5 * time.Minute
+	ImpersonationAuditLimit  = 200
+
+	ImpersonationActionStart   = "start"
+	ImpersonationActionRequest = "request"
+)
+
+const (
+	EmailChangeStatusPending   = "pending"
+	EmailChangeStatusConfirmed = "confirmed"
+	EmailChangeStatusCancelled = "cancelled"
+	EmailChangeStatusReverted  = "reverted"
+
+	EmailChangeTTL = 24 * time.Hour
+	// how long the old address can undo a confirmed change
+	EmailChangeRevertWindow = 7 * 24 * time.Hour
+)
+
+const (
+	MagicLinkTTL = 15 * time.Minute
+	// the device secret travels in this cookie, or in the header for clients without cookies
+	MagicLinkDeviceCookie = "magic_link_device"
+	MagicLinkDeviceHeader = "X-Device-Token"
+	MagicLinkCookiePath   = "/api/v1/auth/magic-link"
+)
+
+const (
+	DataRequestKindExport   = "export"
+	DataRequestKindDeletion = "deletion"
+
+	DataRequestStatusPending    = "pending" // queued, or a deletion in its grace period
+	DataRequestStatusProcessing = "processing"
+	DataRequestStatusCompleted  = "completed"
+	DataRequestStatusFailed     = "failed" // some step gave up
+	DataRequestStatusCancelled  = "cancelled"
+	DataRequestStatusExpired    = "expired" // the export archive was removed
+
+	DataStepStatusPending = "pending"
+	DataStepStatusDone    = "done"
+	DataStepStatusFailed  = "failed"
+
+	AccountDeletionGracePeriod = 14 * 24 * time.Hour
+	DataExportTTL              = 7 * 24 * time.Hour
+
+	DataRequestPollInterval = 30 * time.Second
+	DataRequestBatchSize    = 5
+	DataRequestLease        = 10 * time.Minute // how long a claimed request is hidden from other processors
+	DataRequestMaxAttempts  = 10
+	DataRequestMaxBackoff   = time.Hour
+	DataRequestListLimit    = 20
+
+	EnvDataExportDir      = "DATA_EXPORT_DIR"
+	DefaultDataExportDir  = "exports"
+	DataExportDownloadURL = "/api/v1/auth/account/export/%s/download"
+)
+
+const (
+	UserImportFormatCSV  = "csv"
+	UserImportFormatJSON = "json"
+
+	UserImportStatusPending    = "pending"
+	UserImportStatusProcessing = "processing"
+	UserImportStatusCompleted  = "completed"
+
+	UserImportRowPending      = "pending"
+	UserImportRowInvited      = "invited"           // new account, invitation mailed
+	UserImportRowVerification = "verification_sent" // unverified account already there, verification mailed again
+	UserImportRowFailed       = "failed"
+
+	UserImportMaxBytes     = 2 << 20
+	UserImportMaxRows      = 1000
+	UserImportPollInterval = 10 * time.Second
+	UserImportBatchSize    = 2
+	UserImportLease        = 10 * time.Minute // how long a claimed import is hidden from other processors
+	UserImportListLimit    = 20
+
+	// invited users set their password with the link, so it lives longer than a verification
+	InvitationTTL = 7 * 24 * time.Hour
+)
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"auth-service/internal/constant"
 	"auth-service/internal/model"
 	"fmt"
 	"log"
@@ -30,8 +31,31 @@ func InitDB() {
 }
 
 func AutoMigrate() {
-	err := DB.AutoMigrate(&model.AuthUser{})
+	err := DB.AutoMigrate(&model.AuthUser{}, &model.RecoveryCode{}, &model.AuthAttempt{}, &model.AuthThrottle{}, &model.OutboxEvent{}, &model.Session{}, &model.ImpersonationAudit{}, &model.EmailChange{}, &model.MagicLink{}, &model.DataRequest{}, &model.DataRequestStep{}, &model.UserImport{}, &model.UserImportRow{})
 	if err != nil {
 		log.Fatal("AutoMigrate failed:", err)
 	}
 }
+
+// BackfillProfileVerified queues a PROFILE_VERIFIED outbox event for every verified account that
+// never had one, so profiles left unverified in user-service when it could not be reached are
+// caught up by the outbox relay. Accounts with an event are skipped, which makes it safe to run
+// on every start.
+func BackfillProfileVerified() {
+	result := DB.Exec(`
+		INSERT INTO outbox_events (created_at, updated_at, event_type, `+"`key`"+`, payload, status, next_attempt_at, attempts)
+		SELECT NOW(3), NOW(3), ?, CAST(a.user_id AS CHAR), CONCAT('{"user_id":', a.user_id, '}'), ?, NOW(3), 0
+		FROM auth_users a
+		WHERE a.is_verified = TRUE AND a.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events o
+				WHERE o.event_type = ? AND o.`+"`key`"+` = CAST(a.user_id AS CHAR)
+			)`,
+		constant.EventTypeProfileVerified, constant.OutboxStatusPending, constant.EventTypeProfileVerified)
+	if result.Error != nil {
+		log.Fatal("backfill of verified profiles failed:", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("queued %d verified profiles for user-service", result.RowsAffected)
+	}
+}
@@ -0,0 +1,10 @@
+package dto
+
+// AttemptInfo describes who is trying to authenticate, used for throttling and the audit trail.
+type AttemptInfo struct {
+	Action    string
+	Email     string
+	UserID    uint // the account when the attempt carries no email, e.g. the second factor
+	IP        string
+	UserAgent string
+}
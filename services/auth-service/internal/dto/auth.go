@@ -21,10 +21,18 @@ type CreateUserResponse struct {
 }
 
 type MailEvent struct {
-	Email string            `json:"email"`
-	Type  string            `json:"type"` // "VERIFY_EMAIL"
-	Data  map[string]string `json:"data,omitempty"`
+	// set on mails that show times, so mail-service can write them in the timezone of the user
+	UserID uint              `json:"user_id,omitempty"`
+	Email  string            `json:"email"`
+	Type   string            `json:"type"` // "VERIFY_EMAIL"
+	Data   map[string]string `json:"data,omitempty"`
 }
+
+// ProfileVerifiedEvent is the payload of a PROFILE_VERIFIED outbox event.
+type ProfileVerifiedEvent struct {
+	UserID uint `json:"user_id"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -35,6 +43,16 @@ type RefreshTokenInput struct {
 type ResetPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
 
 func IsStrongPassword(pw string) bool {
 	if len(pw) < 8 {
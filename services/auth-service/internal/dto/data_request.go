@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type DataRequestStepResponse struct {
+	Service     string     `json:"service"`
+	Status      string     `json:"status"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// DataRequestResponse is a data export or account deletion with the progress of each service.
+type DataRequestResponse struct {
+	ID           string                    `json:"id"`
+	Kind         string                    `json:"kind"`
+	Status       string                    `json:"status"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	ScheduledFor *time.Time                `json:"scheduled_for,omitempty"` // end of the grace period of a deletion
+	ExpiresAt    *time.Time                `json:"expires_at,omitempty"`    // last day the export can be downloaded
+	CompletedAt  *time.Time                `json:"completed_at,omitempty"`
+	DownloadURL  string                    `json:"download_url,omitempty"`
+	Steps        []DataRequestStepResponse `json:"steps"`
+}
+
+// AuthDataExport is what auth-service adds to a personal data export.
+type AuthDataExport struct {
+	Account        AuthAccountExport         `json:"account"`
+	Sessions       []SessionExport           `json:"sessions"`
+	Impersonations []ImpersonationAuditEntry `json:"impersonations"`
+}
+
+type AuthAccountExport struct {
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	IsVerified       bool      `json:"is_verified"`
+	IsActive         bool      `json:"is_active"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type SessionExport struct {
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	Location   string     `json:"location"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
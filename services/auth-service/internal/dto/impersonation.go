@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type ImpersonationResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	UserID      uint   `json:"user_id"`
+	Email       string `json:"email"`
+}
+
+// ImpersonatedRequest is one request a service received carrying an impersonation token.
+// The token itself is sent so that auth-service, not the caller, decides who was involved.
+type ImpersonatedRequest struct {
+	Token      string    `json:"token" binding:"required"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type ImpersonatedRequestsRequest struct {
+	Requests []ImpersonatedRequest `json:"requests" binding:"required,dive"`
+}
+
+type ImpersonationAuditEntry struct {
+	Action     string    `json:"action"`
+	ActorID    uint      `json:"actor_id"`
+	ActorEmail string    `json:"actor_email"`
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	IP         string    `json:"ip"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+type MFAChallengeResponse struct {
+	MFAToken      string `json:"mfa_token"`
+	SetupRequired bool   `json:"setup_required"`
+	ExpiresIn     int    `json:"expires_in"` // seconds
+}
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
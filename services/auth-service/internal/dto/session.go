@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Location   string    `json:"location"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type RevokedSession struct {
+	ID        string    `json:"id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevokedSessionsResponse is polled by the api-gateway and the services to reject tokens of
+// revoked sessions.
+type RevokedSessionsResponse struct {
+	Sessions   []RevokedSession `json:"sessions"`
+	ServerTime int64            `json:"server_time"`
+}
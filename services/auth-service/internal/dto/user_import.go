@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// ImportedUser is one entry of a JSON import. CSV files have the same columns, named on their
+// first line.
+type ImportedUser struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+type UserImportRowResponse struct {
+	Line   int    `json:"line"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	UserID uint   `json:"user_id,omitempty"`
+}
+
+// UserImportResponse is an import with its counts. Rows, the report of each line, is only filled
+// in when a single import is asked for.
+type UserImportResponse struct {
+	ID          string                  `json:"id"`
+	Format      string                  `json:"format"`
+	Status      string                  `json:"status"`
+	Total       int                     `json:"total"`
+	Succeeded   int                     `json:"succeeded"`
+	Failed      int                     `json:"failed"`
+	CreatedBy   uint                    `json:"created_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	CompletedAt *time.Time              `json:"completed_at,omitempty"`
+	Rows        []UserImportRowResponse `json:"rows,omitempty"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
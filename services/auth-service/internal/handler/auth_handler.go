@@ -3,20 +3,27 @@ package handler
 import (
 	"auth-service/internal/constant"
 	"auth-service/internal/dto"
+	"auth-service/internal/model"
 	"auth-service/internal/usecase"
 	"auth-service/internal/utils"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-	uc usecase.AuthUsecase
+	uc          usecase.AuthUsecase
+	mfaUC       *usecase.MFAUsecase
+	attemptUC   *usecase.AttemptUsecase
+	sessionUC   *usecase.SessionUsecase
+	magicLinkUC *usecase.MagicLinkUsecase
 }
 
-func NewAuthHandler(uc usecase.AuthUsecase) *AuthHandler {
-	return &AuthHandler{uc: uc}
+func NewAuthHandler(uc usecase.AuthUsecase, mfaUC *usecase.MFAUsecase, attemptUC *usecase.AttemptUsecase, sessionUC *usecase.SessionUsecase, magicLinkUC *usecase.MagicLinkUsecase) *AuthHandler {
+	return &AuthHandler{uc: uc, mfaUC: mfaUC, attemptUC: attemptUC, sessionUC: sessionUC, magicLinkUC: magicLinkUC}
 }
 
 // SignUp godoc
@@ -106,9 +113,87 @@ func (h *AuthHandler) VerifyAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessAccountVerified})
 }
 
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Choose the password of an account created by an admin import, with the token mailed in the
+// @Description invitation. This also verifies the account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptInvitationRequest true "Invitation token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/invitation/accept [post]
+func (h *AuthHandler) AcceptInvitation(c *gin.Context) {
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidInput})
+		return
+	}
+	if !dto.IsStrongPassword(req.Password) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrStrongPassword})
+		return
+	}
+
+	if err := h.uc.AcceptInvitation(c.Request.Context(), req.Token, req.Password); err != nil {
+		switch err.Error() {
+		case constant.ErrInvalidToken:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessInvitationUsed})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new account verification link to an unverified user. The answer is the same
+// @Description whether or not the email belongs to an unverified account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "Resend verification request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/verify-account/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	attempt := newAttemptInfo(c, constant.AttemptActionResendVerify, req.Email)
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
+	err := h.uc.ResendVerification(c.Request.Context(), req.Email)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound, constant.ErrUserAlreadyVerified:
+			// answered like a sent link so the endpoint does not reveal which emails have an account
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessVerificationSent})
+}
+
 // Login godoc
 // @Summary Login
-// @Description Authenticate user with email and password, return JWT tokens
+// @Description Authenticate user with email and password, return JWT tokens.
+// @Description When two-factor authentication is on (or required for the role) an mfa_token is returned instead.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -116,6 +201,7 @@ func (h *AuthHandler) VerifyAccount(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -125,7 +211,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	attempt := newAttemptInfo(c, constant.AttemptActionLogin, loginRequest.Email)
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
 	user, err := h.uc.Authenticate(c.Request.Context(), &loginRequest)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
 	if err != nil {
 		switch err.Error() {
 		case constant.ErrInvalidCredentials:
@@ -136,24 +228,162 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	accessToken, errAT := utils.GenerateAccessToken(user)
-	refreshToken, errRT := utils.GenerateRefreshToken(user)
+	h.completeLogin(c, user)
+}
 
-	if errAT != nil || errRT != nil {
+// completeLogin finishes a first-factor login: it asks for the second factor when the user
+// needs one and otherwise writes the access/refresh pair.
+func (h *AuthHandler) completeLogin(c *gin.Context, user *model.AuthUser) {
+	challenge, err := h.mfaUC.BeginChallenge(c.Request.Context(), user)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrGenerateTokenFailed})
 		return
 	}
+	if challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": constant.SuccessMFARequired,
+			"data":    challenge,
+		})
+		return
+	}
+
+	respondWithTokens(c, h.sessionUC, user)
+}
+
+// RequestMagicLink godoc
+// @Summary Request a magic login link
+// @Description Mail a single-use login link valid for 15 minutes. The link only works on the device that asked
+// @Description for it: that device receives a secret as a cookie and as device_token, to send back as the
+// @Description X-Device-Token header when it cannot keep cookies. The answer is the same whether or not the
+// @Description email belongs to an account. No link is sent while the login of the account is locked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.MagicLinkRequest true "Magic link request"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	attempt := newAttemptInfo(c, constant.AttemptActionMagicLink, req.Email)
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
+	deviceSecret, err := h.magicLinkUC.Request(c.Request.Context(), req.Email)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound, constant.ErrUserNotActive, constant.ErrUserNotVerified, constant.ErrAccountLocked:
+			// answered like a sent link so the endpoint does not reveal which emails have an account
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+			return
+		}
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(constant.MagicLinkDeviceCookie, deviceSecret, int(constant.MagicLinkTTL.Seconds()),
+		constant.MagicLinkCookiePath, "", c.Request.TLS != nil, true)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": constant.SuccessMagicLinkSent,
+		"data":    gin.H{"device_token": deviceSecret},
+	})
+}
+
+// RedeemMagicLink godoc
+// @Summary Log in with a magic link
+// @Description Redeem the token of a magic link on the device that requested it and return JWT tokens,
+// @Description or an mfa_token when two-factor authentication is on. Refused with 429 while the login of the
+// @Description account is locked; the link can still be used once the lock is over.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Magic link token"
+// @Param X-Device-Token header string false "Device secret, when not sent as cookie"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/magic-link/redeem [get]
+func (h *AuthHandler) RedeemMagicLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrTokenRequired})
+		return
+	}
+	deviceSecret := c.GetHeader(constant.MagicLinkDeviceHeader)
+	if deviceSecret == "" {
+		deviceSecret, _ = c.Cookie(constant.MagicLinkDeviceCookie)
+	}
+
+	attempt := newAttemptInfo(c, constant.AttemptActionMagicRedeem, "")
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
+	user, err := h.magicLinkUC.Redeem(c.Request.Context(), token, deviceSecret)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrInvalidToken, constant.ErrUserNotActive, constant.ErrUserNotVerified:
+			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		case constant.ErrAccountLocked:
+			c.JSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.SetCookie(constant.MagicLinkDeviceCookie, "", -1, constant.MagicLinkCookiePath, "", c.Request.TLS != nil, true)
+	h.completeLogin(c, user)
+}
+
+// respondWithTokens writes the access/refresh pair that finishes a successful login.
+func respondWithTokens(c *gin.Context, sessionUC *usecase.SessionUsecase, user *model.AuthUser) {
+	data, ok := startSession(c, sessionUC, user)
+	if !ok {
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": constant.SuccessLogin,
-		"data": gin.H{
-			"access_token":  accessToken,
-			"refresh_token": refreshToken,
-			"user_id":       user.ID,
-		},
+		"data":    data,
 	})
 }
 
+// startSession records the login session and issues tokens bound to it.
+// It writes the error response itself and returns false when that fails.
+func startSession(c *gin.Context, sessionUC *usecase.SessionUsecase, user *model.AuthUser) (gin.H, bool) {
+	session, err := sessionUC.Start(c.Request.Context(), user, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	accessToken, errAT := utils.GenerateAccessToken(user, session.SessionID)
+	refreshToken, errRT := utils.GenerateRefreshToken(user, session.SessionID)
+	if errAT != nil || errRT != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrGenerateTokenFailed})
+		return nil, false
+	}
+
+	return gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"user_id":       user.ID,
+	}, true
+}
+
 // RefreshToken godoc
 // @Summary Refresh access token
 // @Description Generate new access token from refresh token
@@ -179,7 +409,13 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := utils.GenerateAccessToken(user)
+	sessionID, err := h.sessionUC.Resume(c.Request.Context(), input.RefreshToken, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
+	newAccessToken, err := utils.GenerateAccessToken(user, sessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrGenerateTokenFailed})
 		return
@@ -203,6 +439,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
@@ -212,7 +449,13 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	attempt := newAttemptInfo(c, constant.AttemptActionResetPassword, req.Email)
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
 	err := h.uc.SendResetPassword(c.Request.Context(), req)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
 	if err != nil {
 		switch err.Error() {
 		case constant.ErrUserNotFound:
@@ -226,6 +469,68 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessResetPasswordSent})
 }
 
+// UnlockAccount godoc
+// @Summary Unlock account
+// @Description Clear the failed attempt counters and lockout of a user (admin only)
+// @Tags auth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	attempt := newAttemptInfo(c, constant.AttemptActionUnlock, "")
+	if err := h.attemptUC.Unlock(c.Request.Context(), userID, attempt, adminID); err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessAccountUnlocked})
+}
+
+func newAttemptInfo(c *gin.Context, action, email string) dto.AttemptInfo {
+	return dto.AttemptInfo{
+		Action:    action,
+		Email:     email,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// allowAttempt answers 429 with a Retry-After header while the email or IP is backing off or locked.
+func allowAttempt(c *gin.Context, attemptUC *usecase.AttemptUsecase, attempt dto.AttemptInfo) bool {
+	retryAfter, err := attemptUC.Allow(c.Request.Context(), attempt)
+	if err == nil {
+		return true
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"message": err.Error(),
+		"data":    gin.H{"retry_after": seconds},
+	})
+	return false
+}
+
 func (h *AuthHandler) UpdateAuthUser(c *gin.Context) {
 	var req dto.UpdateAuthUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -239,6 +544,14 @@ func (h *AuthHandler) UpdateAuthUser(c *gin.Context) {
 		return
 	}
 
+	// a deactivated user must be signed out everywhere, not only when the refresh token expires
+	if req.IsActive != nil && !*req.IsActive {
+		if err := h.sessionUC.RevokeAll(c.Request.Context(), authUser.UserID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "update success",
 		"data":    authUser,
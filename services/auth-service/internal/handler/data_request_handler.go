@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataRequestHandler struct {
+	uc *usecase.DataRequestUsecase
+}
+
+func NewDataRequestHandler(uc *usecase.DataRequestUsecase) *DataRequestHandler {
+	return &DataRequestHandler{uc: uc}
+}
+
+// RequestExport godoc
+// @Summary Export my data
+// @Description Collect the data every service keeps about the current user into a ZIP of JSON files.
+// @Description Follow its progress with GET /auth/account/requests.
+// @Tags account
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/account/export [post]
+func (h *DataRequestHandler) RequestExport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req, err := h.uc.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		writeDataRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": constant.SuccessExportRequested,
+		"data":    req,
+	})
+}
+
+// RequestDeletion godoc
+// @Summary Delete my account
+// @Description Schedule the deletion of the account once a grace period is over. The profile and chat messages
+// @Description are anonymised and every session revoked; bookings and payments are kept under the user ID only.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body dto.DeleteAccountRequest true "Current password"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/account/delete [post]
+func (h *DataRequestHandler) RequestDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var body dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	req, err := h.uc.RequestDeletion(c.Request.Context(), userID, body.Password)
+	if err != nil {
+		writeDataRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": constant.SuccessDeletionScheduled,
+		"data":    req,
+	})
+}
+
+// CancelDeletion godoc
+// @Summary Cancel the deletion of my account
+// @Description Keep the account while its deletion is still in the grace period
+// @Tags account
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/account/delete [delete]
+func (h *DataRequestHandler) CancelDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.CancelDeletion(c.Request.Context(), userID); err != nil {
+		writeDataRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessDeletionCancelled})
+}
+
+// ListRequests godoc
+// @Summary List my data requests
+// @Description List the data exports and account deletions of the current user with the status of each service
+// @Tags account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/account/requests [get]
+func (h *DataRequestHandler) ListRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	reqs, err := h.uc.List(c.Request.Context(), userID)
+	if err != nil {
+		writeDataRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessDataRequests,
+		"data":    reqs,
+	})
+}
+
+// DownloadExport godoc
+// @Summary Download my data
+// @Description Download the ZIP of a completed data export
+// @Tags account
+// @Produce application/zip
+// @Param id path string true "Request ID"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/account/export/{id}/download [get]
+func (h *DataRequestHandler) DownloadExport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	path, err := h.uc.ExportArchive(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		writeDataRequestError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, "data-export.zip")
+}
+
+func writeDataRequestError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrInvalidCredentials:
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case constant.ErrUserNotFound, constant.ErrDataRequestNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrDataRequestPending, constant.ErrExportNotReady:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
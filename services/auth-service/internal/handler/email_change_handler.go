@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/usecase"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailChangeHandler struct {
+	uc *usecase.EmailChangeUsecase
+}
+
+func NewEmailChangeHandler(uc *usecase.EmailChangeUsecase) *EmailChangeHandler {
+	return &EmailChangeHandler{uc: uc}
+}
+
+// RequestEmailChange godoc
+// @Summary Change email address
+// @Description Start moving the account to a new email. A confirmation link is sent to the new address
+// @Description and a notice with a revert link to the current one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ChangeEmailRequest true "Change email request"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/email-change [post]
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+	req.NewEmail = strings.ToLower(strings.TrimSpace(req.NewEmail))
+
+	if err := h.uc.Request(c.Request.Context(), userID, req.NewEmail, req.Password); err != nil {
+		switch err.Error() {
+		case constant.ErrInvalidCredentials:
+			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		case constant.ErrSameEmail, constant.ErrEmailAlreadyExists:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": constant.SuccessEmailChangeSent})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Switch the account to the new email with the token sent to it. Every session is signed out.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/email-change/confirm [get]
+func (h *EmailChangeHandler) ConfirmEmailChange(c *gin.Context) {
+	h.handleToken(c, h.uc.Confirm, constant.SuccessEmailChanged)
+}
+
+// RevertEmailChange godoc
+// @Summary Revert email change
+// @Description Cancel a pending email change, or switch back to the old email, with the token sent to the old
+// @Description address. Every session is signed out when the email is switched back.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Revert token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/email-change/revert [get]
+func (h *EmailChangeHandler) RevertEmailChange(c *gin.Context) {
+	h.handleToken(c, h.uc.Revert, constant.SuccessEmailRestored)
+}
+
+func (h *EmailChangeHandler) handleToken(c *gin.Context, apply func(ctx context.Context, token string) error, success string) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrTokenRequired})
+		return
+	}
+
+	if err := apply(c.Request.Context(), token); err != nil {
+		switch err.Error() {
+		case constant.ErrInvalidToken, constant.ErrEmailAlreadyExists:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": success})
+}
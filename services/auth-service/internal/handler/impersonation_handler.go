@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	uc *usecase.ImpersonationUsecase
+}
+
+func NewImpersonationHandler(uc *usecase.ImpersonationUsecase) *ImpersonationHandler {
+	return &ImpersonationHandler{uc: uc}
+}
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token to act as a user (admin only). The token carries an "act"
+// @Description claim naming the admin, cannot be refreshed, is refused for payments and every request
+// @Description made with it is written to an audit log the user can see.
+// @Tags impersonation
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.Start(c.Request.Context(), adminID, userID, newAttemptInfo(c, "", ""))
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case constant.ErrCannotImpersonate, constant.ErrUserNotActive, constant.ErrUserNotVerified:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		case constant.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessImpersonation,
+		"data":    res,
+	})
+}
+
+// ListImpersonations godoc
+// @Summary List impersonations of my account
+// @Description List when support staff acted as the current user and which requests they made
+// @Tags impersonation
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/impersonations [get]
+func (h *ImpersonationHandler) ListImpersonations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	h.list(c, userID)
+}
+
+// AdminListImpersonations godoc
+// @Summary List impersonations of a user
+// @Description List the impersonation audit log of any user (admin only)
+// @Tags impersonation
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/impersonations [get]
+func (h *ImpersonationHandler) AdminListImpersonations(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+
+	h.list(c, userID)
+}
+
+// RecordImpersonatedRequests stores the requests made with impersonation tokens.
+// Internal endpoint called by the services before they serve such a request.
+func (h *ImpersonationHandler) RecordImpersonatedRequests(c *gin.Context) {
+	var req dto.ImpersonatedRequestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	rejected, err := h.uc.RecordRequests(c.Request.Context(), req.Requests)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"recorded": len(req.Requests) - rejected,
+		"rejected": rejected,
+	}})
+}
+
+func (h *ImpersonationHandler) list(c *gin.Context, userID uint) {
+	entries, err := h.uc.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessAuditFetched,
+		"data":    entries,
+	})
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	uc        *usecase.MFAUsecase
+	attemptUC *usecase.AttemptUsecase
+	sessionUC *usecase.SessionUsecase
+}
+
+func NewMFAHandler(uc *usecase.MFAUsecase, attemptUC *usecase.AttemptUsecase, sessionUC *usecase.SessionUsecase) *MFAHandler {
+	return &MFAHandler{uc: uc, attemptUC: attemptUC, sessionUC: sessionUC}
+}
+
+// SetupMFA godoc
+// @Summary Start two-factor enrolment
+// @Description Generate a TOTP secret and otpauth URI for the current user. Accepts an access token or the enrollment mfa_token from login.
+// @Tags 2fa
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/2fa/setup [post]
+func (h *MFAHandler) SetupMFA(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	setup, err := h.uc.Setup(c.Request.Context(), userID)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessMFASetup,
+		"data":    setup,
+	})
+}
+
+// EnableMFA godoc
+// @Summary Confirm two-factor enrolment
+// @Description Confirm the pending TOTP secret with a code and receive one-time recovery codes.
+// @Description When called with the enrollment mfa_token the login is completed and tokens are returned as well.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "TOTP code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/2fa/enable [post]
+func (h *MFAHandler) EnableMFA(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	attempt, ok := h.allowCode(c, userID)
+	if !ok {
+		return
+	}
+	user, codes, err := h.uc.Enable(c.Request.Context(), userID, req.Code)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	data := gin.H{"recovery_codes": codes}
+	if c.GetString("tokenType") == constant.TokenTypeMFAEnrollment {
+		tokens, ok := startSession(c, h.sessionUC, user)
+		if !ok {
+			return
+		}
+		for k, v := range tokens {
+			data[k] = v
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessMFAEnabled,
+		"data":    data,
+	})
+}
+
+// VerifyMFA godoc
+// @Summary Complete login with a second factor
+// @Description Exchange the mfa_token returned by login plus a TOTP or recovery code for JWT tokens.
+// @Description Wrong codes are throttled per user and IP; after 5 the account is locked for 15 minutes.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAVerifyRequest true "MFA verification"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/2fa/verify [post]
+func (h *MFAHandler) VerifyMFA(c *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	attempt := newAttemptInfo(c, constant.AttemptActionMFAVerify, "")
+	attempt.UserID = h.uc.ChallengeUserID(req.MFAToken)
+	if !allowAttempt(c, h.attemptUC, attempt) {
+		return
+	}
+
+	user, err := h.uc.Verify(c.Request.Context(), req)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	respondWithTokens(c, h.sessionUC, user)
+}
+
+// DisableMFA godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off TOTP for the current user. Not allowed for roles the policy requires 2FA for.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "TOTP code"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/2fa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	attempt, ok := h.allowCode(c, userID)
+	if !ok {
+		return
+	}
+	err := h.uc.Disable(c.Request.Context(), userID, req.Code)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessMFADisabled})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Invalidate all previous recovery codes and return a new set
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "TOTP code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/2fa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	attempt, ok := h.allowCode(c, userID)
+	if !ok {
+		return
+	}
+	codes, err := h.uc.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	h.attemptUC.Record(c.Request.Context(), attempt, err)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessRecoveryCodes,
+		"data":    dto.RecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+// allowCode throttles the TOTP codes of a signed-in user on the same counter as the login
+// challenge, so a stolen access token cannot guess its way to the second factor either.
+func (h *MFAHandler) allowCode(c *gin.Context, userID uint) (dto.AttemptInfo, bool) {
+	attempt := newAttemptInfo(c, constant.AttemptActionMFAVerify, "")
+	attempt.UserID = userID
+	return attempt, allowAttempt(c, h.attemptUC, attempt)
+}
+
+func currentUserID(c *gin.Context) (uint, bool) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidToken})
+		return 0, false
+	}
+	userID, ok := userIDVal.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidToken})
+		return 0, false
+	}
+	return userID, true
+}
+
+func writeMFAError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrInvalidMFACode, constant.ErrInvalidMFAToken:
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case constant.ErrMFAAlreadyEnabled, constant.ErrMFANotEnabled, constant.ErrMFASetupRequired:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case constant.ErrMFARequiredForRole:
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
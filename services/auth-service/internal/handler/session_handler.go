@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/usecase"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	uc *usecase.SessionUsecase
+}
+
+func NewSessionHandler(uc *usecase.SessionUsecase) *SessionHandler {
+	return &SessionHandler{uc: uc}
+}
+
+// ListSessions godoc
+// @Summary List my sessions
+// @Description List the devices the current user is logged in on
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.uc.List(c.Request.Context(), userID, c.GetString("sessionID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessSessionsFetched,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession godoc
+// @Summary Log out a session
+// @Description Revoke one of the current user's sessions; its tokens stop working in every service
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	h.revoke(c, userID, c.Param("id"))
+}
+
+// AdminListSessions godoc
+// @Summary List sessions of a user
+// @Description List the active sessions of any user (admin only)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/sessions [get]
+func (h *SessionHandler) AdminListSessions(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.uc.List(c.Request.Context(), userID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessSessionsFetched,
+		"data":    sessions,
+	})
+}
+
+// AdminRevokeSession godoc
+// @Summary Kill a session of a user
+// @Description Revoke one session of any user (admin only)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "User ID"
+// @Param sid path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/sessions/{sid} [delete]
+func (h *SessionHandler) AdminRevokeSession(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+
+	h.revoke(c, userID, c.Param("sid"))
+}
+
+// AdminRevokeAllSessions godoc
+// @Summary Kill all sessions of a user
+// @Description Revoke every session of any user (admin only)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/sessions [delete]
+func (h *SessionHandler) AdminRevokeAllSessions(c *gin.Context) {
+	userID, ok := pathUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.RevokeAll(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessSessionRevoked})
+}
+
+// RevokedSessions returns the sessions revoked since the "since" unix timestamp.
+// Internal endpoint polled by the api-gateway and the services, not exposed through the gateway.
+func (h *SessionHandler) RevokedSessions(c *gin.Context) {
+	var since time.Time
+	if v := c.Query("since"); v != "" {
+		unix, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+			return
+		}
+		since = time.Unix(unix, 0)
+	}
+
+	res, err := h.uc.RevokedSince(c.Request.Context(), since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *SessionHandler) revoke(c *gin.Context, userID uint, sessionID string) {
+	if err := h.uc.Revoke(c.Request.Context(), userID, sessionID); err != nil {
+		switch err.Error() {
+		case constant.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessSessionRevoked})
+}
+
+func pathUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return 0, false
+	}
+	return uint(userID), true
+}
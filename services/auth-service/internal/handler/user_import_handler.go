@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/usecase"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserImportHandler struct {
+	uc *usecase.UserImportUsecase
+}
+
+func NewUserImportHandler(uc *usecase.UserImportUsecase) *UserImportHandler {
+	return &UserImportHandler{uc: uc}
+}
+
+// CreateImport godoc
+// @Summary Import users
+// @Description Create users in bulk from a CSV file with an email, name and role column, or a JSON array of
+// @Description {email, name, role}. Send the file as the body with its content type, or as the "file" field of a
+// @Description form. New users are mailed an invitation to choose a password; unverified accounts get a new
+// @Description verification link. The import runs in the background, follow it with GET /auth/admin/users/imports/{id}.
+// @Tags admin
+// @Accept text/csv,application/json,multipart/form-data
+// @Produce json
+// @Param file formData file false "CSV or JSON file"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/import [post]
+func (h *UserImportHandler) CreateImport(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var (
+		body   io.Reader = c.Request.Body
+		format string
+	)
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	switch mediaType {
+	case "multipart/form-data":
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+			return
+		}
+		defer file.Close()
+		body = file
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	case "text/csv":
+		format = constant.UserImportFormatCSV
+	case "application/json":
+		format = constant.UserImportFormatJSON
+	}
+
+	res, err := h.uc.Create(c.Request.Context(), adminID, format, body)
+	if err != nil {
+		writeUserImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": constant.SuccessImportQueued,
+		"data":    res,
+	})
+}
+
+// ListImports godoc
+// @Summary List user imports
+// @Description List the latest user imports with how many rows succeeded and failed
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/imports [get]
+func (h *UserImportHandler) ListImports(c *gin.Context) {
+	res, err := h.uc.List(c.Request.Context())
+	if err != nil {
+		writeUserImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessImportsFetched,
+		"data":    res,
+	})
+}
+
+// GetImport godoc
+// @Summary Get a user import
+// @Description Get an import with the outcome of each row and, for failed rows, why
+// @Tags admin
+// @Produce json
+// @Param id path string true "Import ID"
+// @Param status query string false "Only rows with this status" Enums(pending, invited, verification_sent, failed)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/admin/users/imports/{id} [get]
+func (h *UserImportHandler) GetImport(c *gin.Context) {
+	res, err := h.uc.Get(c.Request.Context(), c.Param("id"), c.Query("status"))
+	if err != nil {
+		writeUserImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": constant.SuccessImportFetched,
+		"data":    res,
+	})
+}
+
+func writeUserImportError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrImportInvalid, constant.ErrImportEmpty:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case constant.ErrImportFormat:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"message": err.Error()})
+	case constant.ErrImportTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": err.Error()})
+	case constant.ErrImportNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -14,6 +15,7 @@ type Producer interface {
 	PublishMailEvent(ctx context.Context, event dto.MailEvent) error
 	PublishVerificationEvent(ctx context.Context, email string, token string) error
 	PublishResetPasswordEvent(ctx context.Context, email string, newPassword string) error
+	PublishAccountLockedEvent(ctx context.Context, userID uint, email string, lockedUntil time.Time) error
 	Close() error
 }
 
@@ -70,6 +72,18 @@ func (p *producer) PublishResetPasswordEvent(ctx context.Context, email string,
 	return p.PublishMailEvent(ctx, event)
 }
 
+func (p *producer) PublishAccountLockedEvent(ctx context.Context, userID uint, email string, lockedUntil time.Time) error {
+	event := dto.MailEvent{
+		UserID: userID,
+		Email:  email,
+		Data: map[string]string{
+			"lockedUntil": lockedUntil.UTC().Format(time.RFC3339),
+		},
+		Type: constant.EventTypeAccountLocked,
+	}
+	return p.PublishMailEvent(ctx, event)
+}
+
 func (p *producer) Close() error {
 	return p.writer.Close()
 }
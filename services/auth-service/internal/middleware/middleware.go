@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/utils"
+	"net/http"
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/session"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// revocations mirrors the sessions revoked in this service; RequireAuth refuses their tokens
+// once TrackRevocations has been called.
+var revocations *session.RevocationStore
+
+func TrackRevocations(store *session.RevocationStore) {
+	revocations = store
+}
+
+// RequireAuth accepts bearer tokens issued by this service. Access tokens are always allowed;
+// extraTokenTypes lets a route also accept e.g. the MFA enrollment token handed out by login.
+func RequireAuth(extraTokenTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "error.missing_token"})
+			c.Abort()
+			return
+		}
+		tokenParts := strings.Split(authHeader, " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidToken})
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ValidateToken(tokenParts[1])
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidToken})
+			c.Abort()
+			return
+		}
+
+		allowed := claims.TokenType == constant.TokenTypeAccess
+		for _, tokenType := range extraTokenTypes {
+			if claims.TokenType == tokenType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidToken})
+			c.Abort()
+			return
+		}
+		if revocations != nil && revocations.IsRevoked(claims.SessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrSessionRevoked})
+			c.Abort()
+			return
+		}
+
+		c.Set("userEmail", claims.Email)
+		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("tokenType", claims.TokenType)
+		c.Set("sessionID", claims.SessionID)
+		c.Next()
+	}
+}
+
+// RequirePermission must run after RequireAuth and only lets through roles holding every listed permission.
+func RequirePermission(perms ...policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !policy.CanAll(c.GetString("role"), perms...) {
+			c.JSON(http.StatusForbidden, gin.H{"message": constant.ErrPermissionDenied})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireService only lets through other services holding a token addressed to this one,
+// and only the listed callers when any are given. It guards the service-to-service routes.
+func RequireService(verifier *servicetoken.Verifier, callers ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := verifier.Verify(c.GetHeader(servicetoken.Header), callers...)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrInvalidServiceToken})
+			c.Abort()
+			return
+		}
+		c.Set("service", claims.Subject)
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuthAttempt is the audit trail of every login, second factor, reset password and unlock request.
+type AuthAttempt struct {
+	gorm.Model
+	Action    string `gorm:"type:varchar(32);not null;index"` // login, mfa_verify, reset_password, unlock
+	Email     string `gorm:"type:varchar(255);index"`
+	UserID    uint   `gorm:"index"` // set when the attempt carries no email
+	IP        string `gorm:"type:varchar(45);index"`
+	UserAgent string `gorm:"type:varchar(255)"`
+	Success   bool
+	Reason    string `gorm:"type:varchar(100)"` // error key when the attempt failed
+	ActorID   *uint  // admin performing the action, if any
+}
+
+// AuthThrottle counts recent failures for one key, e.g. "login:email:foo@bar.com" or "login:ip:1.2.3.4".
+type AuthThrottle struct {
+	gorm.Model
+	Key          string `gorm:"type:varchar(320);not null;uniqueIndex"`
+	FailedCount  int    `gorm:"not null;default:0"`
+	LastFailedAt *time.Time
+	LockedUntil  *time.Time
+}
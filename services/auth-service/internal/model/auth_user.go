@@ -4,10 +4,13 @@ import "gorm.io/gorm"
 
 type AuthUser struct {
 	gorm.Model
-	Email        string `gorm:"type:varchar(255);not null;uniqueIndex"`
-	UserID       uint   `gorm:"not null;uniqueIndex"`      //
-	Role         string `gorm:"type:varchar(50);not null"` // e.g. USER, MODERATOR, ADMIN
-	IsActive     bool   `gorm:"default:true"`
-	PasswordHash string `gorm:"type:varchar(255);not null"`
-	IsVerified   bool   `gorm:"default:false"`
+	Email            string `gorm:"type:varchar(255);not null;uniqueIndex"`
+	UserID           uint   `gorm:"not null;uniqueIndex"`      //
+	Role             string `gorm:"type:varchar(50);not null"` // e.g. USER, MODERATOR, ADMIN
+	IsActive         bool   `gorm:"default:true"`
+	PasswordHash     string `gorm:"type:varchar(255);not null"`
+	IsVerified       bool   `gorm:"default:false"`
+	TwoFactorEnabled bool   `gorm:"default:false"`
+	TOTPSecret       string `gorm:"type:varchar(64)" json:"-"` // base32, pending until TwoFactorEnabled
+	TOTPLastStep     int64  `json:"-"`                         // last accepted time step, blocks code replay
 }
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataRequest is a personal data export or an account deletion asked for by a user. Every
+// service holding data about users is one step of it; the request is over once each step
+// completed or gave up. A deletion waits until DueAt, the end of its grace period, and can be
+// cancelled until then.
+type DataRequest struct {
+	gorm.Model
+	RequestID   string     `gorm:"type:char(32);not null;uniqueIndex"`
+	UserID      uint       `gorm:"not null;index"`
+	Kind        string     `gorm:"type:varchar(20);not null"` // export, deletion
+	Status      string     `gorm:"type:varchar(20);not null;index:idx_data_request_due,priority:1"`
+	DueAt       time.Time  `gorm:"not null;index:idx_data_request_due,priority:2"`
+	ArchivePath string     `gorm:"type:varchar(255)"` // ZIP of a completed export
+	ExpiresAt   *time.Time // the archive is removed after that
+	CompletedAt *time.Time
+	Steps       []DataRequestStep `gorm:"foreignKey:RequestID;references:RequestID"`
+}
+
+// DataRequestStep is the part of a request one service carries out. Exported data is kept in
+// Data until the archive is written.
+type DataRequestStep struct {
+	gorm.Model
+	RequestID   string `gorm:"type:char(32);not null;uniqueIndex:idx_data_request_step,priority:1"`
+	Service     string `gorm:"type:varchar(50);not null;uniqueIndex:idx_data_request_step,priority:2"`
+	Status      string `gorm:"type:varchar(20);not null"` // pending, done, failed
+	Attempts    int    `gorm:"not null;default:0"`
+	LastError   string `gorm:"type:varchar(255)"`
+	Data        string `gorm:"type:longtext"`
+	CompletedAt *time.Time
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailChange is a request to move an account to a new email address. The new address
+// confirms it, and the old address can cancel it or, within the revert window, undo it.
+// Both mailed links reference it by ChangeID.
+type EmailChange struct {
+	gorm.Model
+	ChangeID        string    `gorm:"type:char(32);not null;uniqueIndex"`
+	UserID          uint      `gorm:"not null;index"`
+	OldEmail        string    `gorm:"type:varchar(255);not null"`
+	NewEmail        string    `gorm:"type:varchar(255);not null"`
+	Status          string    `gorm:"type:varchar(20);not null"`
+	ExpiresAt       time.Time `gorm:"not null"` // confirmation deadline
+	RevertibleUntil time.Time `gorm:"not null"`
+	ConfirmedAt     *time.Time
+	RevertedAt      *time.Time
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImpersonationAudit records an admin acting as a user: the token being issued ("start") and
+// every request made with it ("request"). The impersonated user can read their own entries.
+type ImpersonationAudit struct {
+	gorm.Model
+	UserID     uint      `gorm:"not null;index:idx_impersonation_user,priority:1"`
+	ActorID    uint      `gorm:"not null;index"`
+	ActorEmail string    `gorm:"type:varchar(255)"`
+	Action     string    `gorm:"type:varchar(20);not null"` // start, request
+	Method     string    `gorm:"type:varchar(10)"`
+	Path       string    `gorm:"type:varchar(512)"`
+	Status     int       `gorm:"default:0"`
+	IP         string    `gorm:"type:varchar(45)"`
+	UserAgent  string    `gorm:"type:varchar(255)"`
+	OccurredAt time.Time `gorm:"not null;index:idx_impersonation_user,priority:2"`
+}
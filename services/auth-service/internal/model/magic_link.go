@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MagicLink is a one-time login link. Only digests are stored: TokenHash of the token mailed to
+// the user and DeviceHash of the secret given to the device that asked for the link, which has
+// to present it again when redeeming.
+type MagicLink struct {
+	gorm.Model
+	UserID     uint      `gorm:"not null;index"`
+	TokenHash  string    `gorm:"type:char(64);not null;uniqueIndex"`
+	DeviceHash string    `gorm:"type:char(64);not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	UsedAt     *time.Time
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a mail event written in the same transaction as the data it belongs to.
+// The outbox relay publishes it to Kafka afterwards, so the event is never lost or sent for
+// data that was rolled back.
+type OutboxEvent struct {
+	gorm.Model
+	EventType     string    `gorm:"type:varchar(50);not null"`
+	Key           string    `gorm:"type:varchar(255)"`
+	Payload       string    `gorm:"type:text;not null"`
+	Status        string    `gorm:"type:varchar(20);not null;default:pending;index:idx_outbox_due,priority:1"`
+	NextAttemptAt time.Time `gorm:"not null;index:idx_outbox_due,priority:2"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:varchar(255)"`
+	SentAt        *time.Time
+}
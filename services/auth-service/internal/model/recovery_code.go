@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`
+	CodeHash string `gorm:"type:char(64);not null;uniqueIndex"` // sha256 hex of the plain code
+	UsedAt   *time.Time
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is one login of a user on a device. Its SessionID is embedded in every
+// access and refresh token issued for it as the "sid" claim.
+type Session struct {
+	gorm.Model
+	SessionID  string     `gorm:"type:char(32);not null;uniqueIndex"`
+	UserID     uint       `gorm:"not null;index"`
+	UserAgent  string     `gorm:"type:varchar(255)"`
+	IP         string     `gorm:"type:varchar(45)"`
+	Location   string     `gorm:"type:varchar(150)"`
+	LastUsedAt time.Time  `gorm:"not null"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	RevokedAt  *time.Time `gorm:"index"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserImport is a file of users uploaded by an admin. Rows failing validation are stored failed
+// straight away; the others are created in the background once DueAt is reached.
+type UserImport struct {
+	gorm.Model
+	ImportID    string    `gorm:"type:char(32);not null;uniqueIndex"`
+	CreatedBy   uint      `gorm:"not null;index"`
+	Format      string    `gorm:"type:varchar(10);not null"` // csv, json
+	Status      string    `gorm:"type:varchar(20);not null;index:idx_user_import_due,priority:1"`
+	DueAt       time.Time `gorm:"not null;index:idx_user_import_due,priority:2"`
+	Total       int       `gorm:"not null;default:0"`
+	Succeeded   int       `gorm:"not null;default:0"`
+	Failed      int       `gorm:"not null;default:0"`
+	CompletedAt *time.Time
+	Rows        []UserImportRow `gorm:"foreignKey:ImportID;references:ImportID"`
+}
+
+// UserImportRow is one user of an import. Line is the line of the CSV file, or the position in
+// the JSON array, so admins can find it in what they uploaded.
+type UserImportRow struct {
+	gorm.Model
+	ImportID string `gorm:"type:char(32);not null;index"`
+	Line     int    `gorm:"not null"`
+	Email    string `gorm:"type:varchar(255);not null"`
+	Name     string `gorm:"type:varchar(255)"`
+	Role     string `gorm:"type:varchar(50)"`
+	Status   string `gorm:"type:varchar(20);not null"` // pending, invited, verification_sent, failed
+	Error    string `gorm:"type:varchar(255)"`
+	UserID   uint
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttemptRepository interface {
+	CreateAttempt(ctx context.Context, attempt *model.AuthAttempt) error
+	GetThrottles(ctx context.Context, keys []string) ([]model.AuthThrottle, error)
+	IncrementFailure(ctx context.Context, key string, window time.Duration, now time.Time) (*model.AuthThrottle, error)
+	LockUntil(ctx context.Context, key string, until time.Time) error
+	DeleteThrottles(ctx context.Context, keys []string) error
+}
+
+type attemptRepository struct {
+	db *gorm.DB
+}
+
+func NewAttemptRepository(db *gorm.DB) AttemptRepository {
+	return &attemptRepository{db}
+}
+
+func (r *attemptRepository) CreateAttempt(ctx context.Context, attempt *model.AuthAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *attemptRepository) GetThrottles(ctx context.Context, keys []string) ([]model.AuthThrottle, error) {
+	var throttles []model.AuthThrottle
+	if err := r.db.WithContext(ctx).Where("`key` IN ?", keys).Find(&throttles).Error; err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
+
+// IncrementFailure bumps the failure counter of the key, restarting it when the last failure
+// is older than window. The row is locked so parallel requests cannot lose increments.
+func (r *attemptRepository) IncrementFailure(ctx context.Context, key string, window time.Duration, now time.Time) (*model.AuthThrottle, error) {
+	var throttle model.AuthThrottle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.AuthThrottle{Key: key}).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).First(&throttle).Error; err != nil {
+			return err
+		}
+
+		if throttle.LastFailedAt == nil || now.Sub(*throttle.LastFailedAt) > window {
+			throttle.FailedCount = 0
+		}
+		throttle.FailedCount++
+		throttle.LastFailedAt = &now
+		return tx.Save(&throttle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *attemptRepository) LockUntil(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.AuthThrottle{}).
+		Where("`key` = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r *attemptRepository) DeleteThrottles(ctx context.Context, keys []string) error {
+	return r.db.WithContext(ctx).Unscoped().Where("`key` IN ?", keys).Delete(&model.AuthThrottle{}).Error
+}
@@ -11,9 +11,10 @@ import (
 type AuthRepository interface {
 	Create(ctx context.Context, user *model.AuthUser) error
 	GetByEmail(ctx context.Context, email string) (*model.AuthUser, error)
-	VerifyUser(ctx context.Context, email string) error
 	UpdateUser(ctx context.Context, user *model.AuthUser) error
 	GetByUserID(ctx context.Context, userID uint) (*model.AuthUser, error)
+	CreateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error
+	UpdateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error
 }
 
 type authRepository struct {
@@ -39,15 +40,9 @@ func (r *authRepository) GetByEmail(ctx context.Context, email string) (*model.A
 	return &user, nil
 }
 
-func (r *authRepository) VerifyUser(ctx context.Context, email string) error {
-	result := r.db.WithContext(ctx).Model(&model.AuthUser{}).
-		Where("email = ?", email).
-		Update("is_verified", true)
-	return result.Error
-}
-
 func (r *authRepository) UpdateUser(ctx context.Context, user *model.AuthUser) error {
-	return r.db.WithContext(ctx).Where("email = ?", user.Email).Save(user).Error
+	// by primary key: filtering on the email would miss the row once the address changed
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
 func (r *authRepository) GetByUserID(ctx context.Context, userID uint) (*model.AuthUser, error) {
@@ -61,3 +56,22 @@ func (r *authRepository) GetByUserID(ctx context.Context, userID uint) (*model.A
 	return &authUser, nil
 }
 
+// CreateWithOutbox stores the user and its outbox event atomically: either both exist or neither.
+func (r *authRepository) CreateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+// UpdateWithOutbox saves the user and stores its outbox event atomically.
+func (r *authRepository) UpdateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataRequestRepository interface {
+	Create(ctx context.Context, req *model.DataRequest) error
+	GetActive(ctx context.Context, userID uint, kind string) (*model.DataRequest, error)
+	GetByRequestID(ctx context.Context, requestID string) (*model.DataRequest, error)
+	ListByUser(ctx context.Context, userID uint, limit int) ([]model.DataRequest, error)
+	Cancel(ctx context.Context, requestID string) error
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.DataRequest, error)
+	UpdateStep(ctx context.Context, step *model.DataRequestStep) error
+	Reschedule(ctx context.Context, id uint, dueAt time.Time) error
+	Finish(ctx context.Context, req *model.DataRequest) error
+	ListExpiredExports(ctx context.Context, now time.Time) ([]model.DataRequest, error)
+	MarkExpired(ctx context.Context, id uint) error
+	EraseAuthData(ctx context.Context, userID uint, email string, at time.Time) error
+}
+
+type dataRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewDataRequestRepository(db *gorm.DB) DataRequestRepository {
+	return &dataRequestRepository{db}
+}
+
+// Create stores the request together with its steps.
+func (r *dataRequestRepository) Create(ctx context.Context, req *model.DataRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+// GetActive returns the request of the kind that the user still has running, if any.
+func (r *dataRequestRepository) GetActive(ctx context.Context, userID uint, kind string) (*model.DataRequest, error) {
+	var req model.DataRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND kind = ? AND status IN ?", userID, kind,
+			[]string{constant.DataRequestStatusPending, constant.DataRequestStatusProcessing}).
+		First(&req).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *dataRequestRepository) GetByRequestID(ctx context.Context, requestID string) (*model.DataRequest, error) {
+	var req model.DataRequest
+	if err := r.db.WithContext(ctx).Where("request_id = ?", requestID).First(&req).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ListByUser returns the latest requests of the user with the state of each step.
+func (r *dataRequestRepository) ListByUser(ctx context.Context, userID uint, limit int) ([]model.DataRequest, error) {
+	var reqs []model.DataRequest
+	err := r.db.WithContext(ctx).
+		Preload("Steps", withoutStepData).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&reqs).Error
+	return reqs, err
+}
+
+// Cancel stops a request that has not started yet. It fails with gorm.ErrRecordNotFound once
+// processing began.
+func (r *dataRequestRepository) Cancel(ctx context.Context, requestID string) error {
+	res := r.db.WithContext(ctx).Model(&model.DataRequest{}).
+		Where("request_id = ? AND status = ?", requestID, constant.DataRequestStatusPending).
+		Update("status", constant.DataRequestStatusCancelled)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClaimDue picks the requests that are due, moves them to processing and pushes their DueAt one
+// lease ahead, the way the outbox relay claims events. Once processing, a request can no
+// longer be cancelled. A request claimed by a processor that crashes is picked up again once
+// the lease runs out.
+func (r *dataRequestRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.DataRequest, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.DataRequest{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND due_at <= ?",
+				[]string{constant.DataRequestStatusPending, constant.DataRequestStatusProcessing}, now).
+			Order("due_at").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&model.DataRequest{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status": constant.DataRequestStatusProcessing,
+				"due_at": now.Add(constant.DataRequestLease),
+			}).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var reqs []model.DataRequest
+	err = r.db.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id IN ?", ids).
+		Find(&reqs).Error
+	return reqs, err
+}
+
+func (r *dataRequestRepository) UpdateStep(ctx context.Context, step *model.DataRequestStep) error {
+	return r.db.WithContext(ctx).Model(&model.DataRequestStep{}).
+		Where("id = ?", step.ID).
+		Updates(map[string]interface{}{
+			"status":       step.Status,
+			"attempts":     step.Attempts,
+			"last_error":   step.LastError,
+			"data":         step.Data,
+			"completed_at": step.CompletedAt,
+		}).Error
+}
+
+func (r *dataRequestRepository) Reschedule(ctx context.Context, id uint, dueAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.DataRequest{}).
+		Where("id = ?", id).
+		Update("due_at", dueAt).Error
+}
+
+// Finish stores the outcome of a request and drops the exported data held by its steps, which
+// now lives in the archive if anywhere.
+func (r *dataRequestRepository) Finish(ctx context.Context, req *model.DataRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.DataRequest{}).
+			Where("id = ?", req.ID).
+			Updates(map[string]interface{}{
+				"status":       req.Status,
+				"archive_path": req.ArchivePath,
+				"expires_at":   req.ExpiresAt,
+				"completed_at": req.CompletedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.DataRequestStep{}).
+			Where("request_id = ?", req.RequestID).
+			Update("data", "").Error
+	})
+}
+
+func (r *dataRequestRepository) ListExpiredExports(ctx context.Context, now time.Time) ([]model.DataRequest, error) {
+	var reqs []model.DataRequest
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND status = ? AND expires_at <= ?",
+			constant.DataRequestKindExport, constant.DataRequestStatusCompleted, now).
+		Find(&reqs).Error
+	return reqs, err
+}
+
+func (r *dataRequestRepository) MarkExpired(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.DataRequest{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       constant.DataRequestStatusExpired,
+			"archive_path": "",
+		}).Error
+}
+
+// EraseAuthData anonymises everything auth-service keeps about a deleted account in one
+// transaction: the account is locked under a pseudonymous email without any credentials, its
+// sessions are revoked and stripped of where they came from, and the login trail, pending links
+// and mails of the address are deleted for good. Impersonation audits stay: they record what
+// admins did.
+func (r *dataRequestRepository) EraseAuthData(ctx context.Context, userID uint, email string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.AuthUser
+		if err := tx.Where("user_id = ?", userID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		oldEmail := user.Email
+
+		if err := tx.Model(&model.AuthUser{}).
+			Where("id = ?", user.ID).
+			Updates(map[string]interface{}{
+				"email":              email,
+				"password_hash":      "",
+				"is_active":          false,
+				"two_factor_enabled": false,
+				"totp_secret":        "",
+				"totp_last_step":     0,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Session{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"user_agent": "", "ip": "", "location": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.MagicLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.EmailChange{}).Error; err != nil {
+			return err
+		}
+		if oldEmail == email {
+			return nil
+		}
+		if err := tx.Unscoped().Where("email = ?", oldEmail).Delete(&model.AuthAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("`key` = ?", oldEmail).Delete(&model.OutboxEvent{}).Error
+	})
+}
+
+func withoutStepData(db *gorm.DB) *gorm.DB {
+	return db.Omit("data").Order("id")
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailChangeRepository interface {
+	CreatePending(ctx context.Context, change *model.EmailChange, events []model.OutboxEvent) error
+	GetByChangeID(ctx context.Context, changeID string) (*model.EmailChange, error)
+	Cancel(ctx context.Context, changeID string) error
+	Apply(ctx context.Context, change *model.EmailChange, fromStatus, fromEmail, toEmail string, at time.Time) error
+}
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) EmailChangeRepository {
+	return &emailChangeRepository{db}
+}
+
+// CreatePending stores the change together with its mails. Earlier pending changes of the
+// user are cancelled so that only the latest links work.
+func (r *emailChangeRepository) CreatePending(ctx context.Context, change *model.EmailChange, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.EmailChange{}).
+			Where("user_id = ? AND status = ?", change.UserID, constant.EmailChangeStatusPending).
+			Update("status", constant.EmailChangeStatusCancelled).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return tx.Create(&events).Error
+	})
+}
+
+func (r *emailChangeRepository) GetByChangeID(ctx context.Context, changeID string) (*model.EmailChange, error) {
+	var change model.EmailChange
+	if err := r.db.WithContext(ctx).Where("change_id = ?", changeID).First(&change).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *emailChangeRepository) Cancel(ctx context.Context, changeID string) error {
+	res := r.db.WithContext(ctx).Model(&model.EmailChange{}).
+		Where("change_id = ? AND status = ?", changeID, constant.EmailChangeStatusPending).
+		Update("status", constant.EmailChangeStatusCancelled)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Apply moves the auth user from fromEmail to toEmail, stores the new state of the change and
+// revokes every session of the user in one transaction, so no token with the previous email
+// claim outlives the switch. It fails with gorm.ErrRecordNotFound when the change left
+// fromStatus or the user left fromEmail in the meantime.
+func (r *emailChangeRepository) Apply(ctx context.Context, change *model.EmailChange, fromStatus, fromEmail, toEmail string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.EmailChange{}).
+			Where("id = ? AND status = ?", change.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":       change.Status,
+				"confirmed_at": change.ConfirmedAt,
+				"reverted_at":  change.RevertedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		res = tx.Model(&model.AuthUser{}).
+			Where("user_id = ? AND email = ?", change.UserID, fromEmail).
+			Update("email", toEmail)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&model.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", change.UserID).
+			Update("revoked_at", at).Error
+	})
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, entries []model.ImpersonationAudit) error
+	ListByUser(ctx context.Context, userID uint, limit int) ([]model.ImpersonationAudit, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db}
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, entries []model.ImpersonationAudit) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&entries).Error
+}
+
+func (r *impersonationRepository) ListByUser(ctx context.Context, userID uint, limit int) ([]model.ImpersonationAudit, error) {
+	var entries []model.ImpersonationAudit
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("occurred_at DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
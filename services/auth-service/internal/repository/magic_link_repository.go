@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MagicLinkRepository interface {
+	CreateWithOutbox(ctx context.Context, link *model.MagicLink, event *model.OutboxEvent) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.MagicLink, error)
+	Use(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+type magicLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkRepository(db *gorm.DB) MagicLinkRepository {
+	return &magicLinkRepository{db}
+}
+
+// CreateWithOutbox stores the link together with the mail carrying it.
+func (r *magicLinkRepository) CreateWithOutbox(ctx context.Context, link *model.MagicLink, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(link).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+func (r *magicLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.MagicLink, error) {
+	var link model.MagicLink
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Use marks the link as used and reports whether it was still unused, so that two concurrent
+// redemptions of the same link cannot both succeed.
+func (r *magicLinkRepository) Use(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.MagicLink{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MFARepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, userID uint) error
+	UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db}
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]model.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks the code as used and reports whether an unused code matched.
+// The conditional update makes concurrent redemption of the same code impossible.
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UseTOTPStep records step as the last accepted TOTP time step and reports whether it is newer
+// than the one stored. Like UseRecoveryCode, the conditional update lets a code through once
+// even when the same code is sent in parallel.
+func (r *mfaRepository) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.AuthUser{}).
+		Where("user_id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *mfaRepository) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error)
+	MarkSent(ctx context.Context, id uint, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time, lastErr string) error
+	MarkFailed(ctx context.Context, id uint, attempts int, lastErr string) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db}
+}
+
+// ClaimDue picks pending events that are due and pushes their NextAttemptAt one lease ahead,
+// so several relays can run side by side without sending the same event twice in a row.
+// An event claimed by a relay that crashes is picked up again once the lease runs out.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", constant.OutboxStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(constant.OutboxLease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id uint, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":  constant.OutboxStatusSent,
+			"sent_at": sentAt,
+		}).Error
+}
+
+func (r *outboxRepository) MarkRetry(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastErr,
+		}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uint, attempts int, lastErr string) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     constant.OutboxStatusFailed,
+			"attempts":   attempts,
+			"last_error": lastErr,
+		}).Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	GetBySessionID(ctx context.Context, sessionID string) (*model.Session, error)
+	ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]model.Session, error)
+	ListByUser(ctx context.Context, userID uint) ([]model.Session, error)
+	Touch(ctx context.Context, sessionID, ip, location string, at time.Time) error
+	Revoke(ctx context.Context, sessionID string, at time.Time) error
+	RevokeAllByUser(ctx context.Context, userID uint, at time.Time) error
+	ListRevokedSince(ctx context.Context, since time.Time) ([]model.Session, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *model.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *sessionRepository) GetBySessionID(ctx context.Context, sessionID string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.WithContext(ctx).Where("session_id = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// ListByUser returns every session of the user, revoked and expired ones included.
+func (r *sessionRepository) ListByUser(ctx context.Context, userID uint) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, sessionID, ip, location string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("session_id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"ip":           ip,
+			"location":     location,
+			"last_used_at": at,
+		}).Error
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, sessionID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", at).Error
+}
+
+func (r *sessionRepository) RevokeAllByUser(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *sessionRepository) ListRevokedSince(ctx context.Context, since time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Select("session_id", "revoked_at").
+		Where("revoked_at >= ?", since).
+		Order("revoked_at").
+		Find(&sessions).Error
+	return sessions, err
+}
@@ -7,13 +7,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"packages/servicetoken"
 	"time"
 )
 
 type UserClient interface {
 	CreateUser(ctx context.Context, email, name, role string) (*dto.CreateUserResponse, error)
+	GetUserByEmail(ctx context.Context, email string) (*dto.CreateUserResponse, error)
+	DeleteUser(ctx context.Context, userID uint) error
+	UpdateEmail(ctx context.Context, userID uint, email string) error
+	MarkVerified(ctx context.Context, userID uint) error
 }
 
 type userClient struct {
@@ -21,12 +27,11 @@ type userClient struct {
 	http    *http.Client
 }
 
-func NewUserClient(baseURL string) UserClient {
+// NewUserClient calls the internal routes of user-service, authenticated with a service token.
+func NewUserClient(baseURL string, issuer *servicetoken.Issuer) UserClient {
 	return &userClient{
 		baseURL: baseURL,
-		http: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		http:    issuer.Client(servicetoken.UserService, 5*time.Second),
 	}
 }
 
@@ -62,6 +67,15 @@ func (c *userClient) CreateUser(ctx context.Context, email, name, role string) (
 	defer resp.Body.Close()
 
 	// Handle non-201 status codes
+	if resp.StatusCode == http.StatusBadRequest {
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errBody) == nil && errBody.Message == constant.ErrEmailAlreadyExists {
+			return nil, errors.New(constant.ErrEmailAlreadyExists)
+		}
+		return nil, errors.New(constant.ErrInternalServer)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return nil, errors.New(constant.ErrInternalServer)
 	}
@@ -77,3 +91,123 @@ func (c *userClient) CreateUser(ctx context.Context, email, name, role string) (
 
 	return &wrapper.Data, nil
 }
+
+func (c *userClient) GetUserByEmail(ctx context.Context, email string) (*dto.CreateUserResponse, error) {
+	fullURL, err := url.JoinPath(c.baseURL, constant.InternalUserUrl)
+	if err != nil {
+		return nil, errors.New(constant.ErrCreateHTTPRequest)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL+"?email="+url.QueryEscape(email), nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrCreateHTTPRequest)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrSendHTTPRequest)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	var wrapper struct {
+		Message string                 `json:"message"`
+		Data    dto.CreateUserResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, errors.New(constant.ErrUnmarshalResponse)
+	}
+
+	return &wrapper.Data, nil
+}
+
+func (c *userClient) DeleteUser(ctx context.Context, userID uint) error {
+	fullURL, err := url.JoinPath(c.baseURL, constant.InternalUserUrl, fmt.Sprint(userID))
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fullURL, nil)
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrSendHTTPRequest)
+	}
+	defer resp.Body.Close()
+
+	// already gone counts as compensated
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+func (c *userClient) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	jsonBody, err := json.Marshal(map[string]string{"email": email})
+	if err != nil {
+		return errors.New(constant.ErrMarshalRequest)
+	}
+
+	fullURL, err := url.JoinPath(c.baseURL, constant.InternalUserUrl, fmt.Sprint(userID), constant.UserEmailPath)
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrSendHTTPRequest)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errors.New(constant.ErrUserNotFound)
+	case http.StatusBadRequest:
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errBody) == nil && errBody.Message == constant.ErrEmailAlreadyExists {
+			return errors.New(constant.ErrEmailAlreadyExists)
+		}
+	}
+	return errors.New(constant.ErrInternalServer)
+}
+
+func (c *userClient) MarkVerified(ctx context.Context, userID uint) error {
+	fullURL, err := url.JoinPath(c.baseURL, constant.InternalUserUrl, fmt.Sprint(userID), constant.UserVerifyPath)
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, nil)
+	if err != nil {
+		return errors.New(constant.ErrCreateHTTPRequest)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrSendHTTPRequest)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errors.New(constant.ErrUserNotFound)
+	}
+	return errors.New(constant.ErrInternalServer)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"packages/servicetoken"
+	"packages/userdata"
+	"strings"
+	"time"
+)
+
+// UserDataClient reaches the data one service keeps about users, for personal data exports and
+// account deletions.
+type UserDataClient interface {
+	Service() string
+	Export(ctx context.Context, userID uint) (json.RawMessage, error)
+	Erase(ctx context.Context, userID uint) error
+}
+
+type userDataClient struct {
+	service string
+	baseURL string
+	http    *http.Client
+}
+
+// NewUserDataClient calls the user data routes of packages/userdata on service, authenticated
+// with a service token.
+func NewUserDataClient(service, baseURL string, issuer *servicetoken.Issuer) UserDataClient {
+	return &userDataClient{
+		service: service,
+		baseURL: baseURL,
+		http:    issuer.Client(service, 30*time.Second),
+	}
+}
+
+func (c *userDataClient) Service() string {
+	return c.service
+}
+
+func (c *userDataClient) Export(ctx context.Context, userID uint) (json.RawMessage, error) {
+	resp, err := c.do(ctx, http.MethodGet, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var wrapper struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("%s: unreadable export: %w", c.service, err)
+	}
+	if len(wrapper.Data) == 0 {
+		return nil, fmt.Errorf("%s: export without data", c.service)
+	}
+	return wrapper.Data, nil
+}
+
+func (c *userDataClient) Erase(ctx context.Context, userID uint) error {
+	resp, err := c.do(ctx, http.MethodDelete, userID)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends the request and fails on any answer but 200, keeping the start of the body so the
+// cause shows up in the step status.
+func (c *userDataClient) do(ctx context.Context, method string, userID uint) (*http.Response, error) {
+	fullURL, err := url.JoinPath(c.baseURL, userdata.Path(userID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.service, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.service, err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.service, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return nil, fmt.Errorf("%s answered %s: %s", c.service, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserImportRepository interface {
+	Create(ctx context.Context, imp *model.UserImport) error
+	GetByImportID(ctx context.Context, importID string) (*model.UserImport, error)
+	List(ctx context.Context, limit int) ([]model.UserImport, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.UserImport, error)
+	UpdateRow(ctx context.Context, row *model.UserImportRow) error
+	Finish(ctx context.Context, imp *model.UserImport) error
+}
+
+type userImportRepository struct {
+	db *gorm.DB
+}
+
+func NewUserImportRepository(db *gorm.DB) UserImportRepository {
+	return &userImportRepository{db}
+}
+
+// Create stores the import together with its rows.
+func (r *userImportRepository) Create(ctx context.Context, imp *model.UserImport) error {
+	return r.db.WithContext(ctx).Create(imp).Error
+}
+
+// GetByImportID returns the import with every row, in the order of the file.
+func (r *userImportRepository) GetByImportID(ctx context.Context, importID string) (*model.UserImport, error) {
+	var imp model.UserImport
+	err := r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB { return db.Order("line") }).
+		Where("import_id = ?", importID).
+		First(&imp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// List returns the latest imports without their rows.
+func (r *userImportRepository) List(ctx context.Context, limit int) ([]model.UserImport, error) {
+	var imps []model.UserImport
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&imps).Error
+	return imps, err
+}
+
+// ClaimDue picks the imports that are due and pushes their DueAt one lease ahead, the way data
+// requests are claimed. An import claimed by a processor that crashes is picked up again once
+// the lease runs out, with only the rows it did not get to still pending.
+func (r *userImportRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]model.UserImport, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserImport{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND due_at <= ?",
+				[]string{constant.UserImportStatusPending, constant.UserImportStatusProcessing}, now).
+			Order("due_at").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&model.UserImport{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status": constant.UserImportStatusProcessing,
+				"due_at": now.Add(constant.UserImportLease),
+			}).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var imps []model.UserImport
+	err = r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB { return db.Order("line") }).
+		Where("id IN ?", ids).
+		Find(&imps).Error
+	return imps, err
+}
+
+func (r *userImportRepository) UpdateRow(ctx context.Context, row *model.UserImportRow) error {
+	return r.db.WithContext(ctx).Model(&model.UserImportRow{}).
+		Where("id = ?", row.ID).
+		Updates(map[string]interface{}{
+			"status":  row.Status,
+			"error":   row.Error,
+			"user_id": row.UserID,
+		}).Error
+}
+
+func (r *userImportRepository) Finish(ctx context.Context, imp *model.UserImport) error {
+	return r.db.WithContext(ctx).Model(&model.UserImport{}).
+		Where("id = ?", imp.ID).
+		Updates(map[string]interface{}{
+			"status":       imp.Status,
+			"succeeded":    imp.Succeeded,
+			"failed":       imp.Failed,
+			"completed_at": imp.CompletedAt,
+		}).Error
+}
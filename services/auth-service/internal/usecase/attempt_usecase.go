@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/kafka"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// throttlePolicy controls one counter. Once FailedCount reaches BackoffAfter every further
+// attempt has to wait BaseDelay*2^(n-BackoffAfter) (capped at MaxDelay) after the last failure;
+// reaching LockAfter locks the key for LockDuration. Counters restart after Window of quiet.
+type throttlePolicy struct {
+	BackoffAfter int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	LockAfter    int
+	LockDuration time.Duration
+	Window       time.Duration
+}
+
+type actionPolicy struct {
+	// Account throttles the account tried, by email or, for steps that carry none, by user ID
+	Account throttlePolicy
+	IP      throttlePolicy
+	// CountAll counts every request, not only failures, e.g. reset password which mails the user each time.
+	CountAll bool
+}
+
+var attemptPolicies = map[string]actionPolicy{
+	constant.AttemptActionLogin: {
+		Account: throttlePolicy{BackoffAfter: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockAfter: 10, LockDuration: 15 * time.Minute, Window: 15 * time.Minute},
+		IP:      throttlePolicy{BackoffAfter: 20, BaseDelay: time.Second, MaxDelay: time.Minute, LockAfter: 100, LockDuration: 15 * time.Minute, Window: 15 * time.Minute},
+	},
+	constant.AttemptActionResetPassword: {
+		Account:  throttlePolicy{BackoffAfter: 1, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute, LockAfter: 5, LockDuration: time.Hour, Window: time.Hour},
+		IP:       throttlePolicy{BackoffAfter: 10, BaseDelay: 5 * time.Second, MaxDelay: 5 * time.Minute, LockAfter: 30, LockDuration: time.Hour, Window: time.Hour},
+		CountAll: true,
+	},
+	constant.AttemptActionResendVerify: {
+		Account:  throttlePolicy{BackoffAfter: 1, BaseDelay: time.Minute, MaxDelay: 15 * time.Minute, LockAfter: 5, LockDuration: time.Hour, Window: time.Hour},
+		IP:       throttlePolicy{BackoffAfter: 10, BaseDelay: 5 * time.Second, MaxDelay: 5 * time.Minute, LockAfter: 30, LockDuration: time.Hour, Window: time.Hour},
+		CountAll: true,
+	},
+	constant.AttemptActionMagicLink: {
+		Account:  throttlePolicy{BackoffAfter: 1, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute, LockAfter: 10, LockDuration: time.Hour, Window: time.Hour},
+		IP:       throttlePolicy{BackoffAfter: 10, BaseDelay: 5 * time.Second, MaxDelay: 5 * time.Minute, LockAfter: 30, LockDuration: time.Hour, Window: time.Hour},
+		CountAll: true,
+	},
+	// redeeming carries no email, only the IP is throttled
+	constant.AttemptActionMagicRedeem: {
+		IP: throttlePolicy{BackoffAfter: 5, BaseDelay: time.Second, MaxDelay: time.Minute, LockAfter: 30, LockDuration: 15 * time.Minute, Window: 15 * time.Minute},
+	},
+	// the second factor is keyed by the user of the mfa_token; the lock outlasts the token, so
+	// a challenge is dead after LockAfter wrong codes and the password has to be entered again
+	constant.AttemptActionMFAVerify: {
+		Account: throttlePolicy{BackoffAfter: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockAfter: 5, LockDuration: 15 * time.Minute, Window: 15 * time.Minute},
+		IP:      throttlePolicy{BackoffAfter: 20, BaseDelay: time.Second, MaxDelay: time.Minute, LockAfter: 100, LockDuration: 15 * time.Minute, Window: 15 * time.Minute},
+	},
+}
+
+// countedFailures are the errors caused by the caller; server side failures never count against them.
+var countedFailures = map[string]bool{
+	constant.ErrInvalidCredentials: true,
+	constant.ErrUserNotFound:       true,
+	constant.ErrInvalidToken:       true,
+	constant.ErrInvalidMFACode:     true,
+	constant.ErrInvalidMFAToken:    true,
+}
+
+type AttemptUsecase struct {
+	attemptRepo repository.AttemptRepository
+	authRepo    repository.AuthRepository
+	kafkaProd   kafka.Producer
+	now         func() time.Time
+}
+
+func NewAttemptUsecase(attemptRepo repository.AttemptRepository, authRepo repository.AuthRepository, kafkaProd kafka.Producer) *AttemptUsecase {
+	return &AttemptUsecase{
+		attemptRepo: attemptRepo,
+		authRepo:    authRepo,
+		kafkaProd:   kafkaProd,
+		now:         time.Now,
+	}
+}
+
+// Allow reports whether the attempt may go ahead. When it may not, the returned duration
+// tells the client how long to wait and the rejection is written to the audit trail.
+func (u *AttemptUsecase) Allow(ctx context.Context, info dto.AttemptInfo) (time.Duration, error) {
+	policy, ok := attemptPolicies[info.Action]
+	if !ok {
+		return 0, nil
+	}
+
+	accountKey, ipKey := accountKey(info), throttleKey(info.Action, "ip", info.IP)
+	keys := []string{ipKey}
+	if accountKey != "" {
+		keys = append(keys, accountKey)
+	}
+	throttles, err := u.attemptRepo.GetThrottles(ctx, keys)
+	if err != nil {
+		// fail open: an audit/throttle outage must not take login down with it
+		log.Println("load auth throttles failed:", err)
+		return 0, nil
+	}
+
+	now := u.now()
+	var retryAfter time.Duration
+	var rejectErr error
+	for _, t := range throttles {
+		p, lockErr := policy.IP, constant.ErrTooManyAttempts
+		if t.Key == accountKey {
+			p, lockErr = policy.Account, constant.ErrAccountLocked
+		}
+
+		if t.LockedUntil != nil && t.LockedUntil.After(now) {
+			if wait := t.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter, rejectErr = wait, errors.New(lockErr)
+			}
+			continue
+		}
+		if wait := p.backoff(t, now); wait > retryAfter {
+			retryAfter, rejectErr = wait, errors.New(constant.ErrTooManyAttempts)
+		}
+	}
+
+	if rejectErr != nil {
+		u.audit(ctx, info, rejectErr, nil)
+	}
+	return retryAfter, rejectErr
+}
+
+// LoginLocked reports whether logins to the account of email are locked after too many wrong
+// passwords, so other ways in such as magic links are refused until the lock ends.
+func (u *AttemptUsecase) LoginLocked(ctx context.Context, email string) bool {
+	key := accountKey(dto.AttemptInfo{Action: constant.AttemptActionLogin, Email: email})
+	throttles, err := u.attemptRepo.GetThrottles(ctx, []string{key})
+	if err != nil {
+		// fail open, as Allow does
+		log.Println("load auth throttles failed:", err)
+		return false
+	}
+	now := u.now()
+	for _, t := range throttles {
+		if t.LockedUntil != nil && t.LockedUntil.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record writes the outcome of an attempt to the audit trail and updates the counters.
+// A lockout triggered by this attempt is announced to the account owner by email.
+func (u *AttemptUsecase) Record(ctx context.Context, info dto.AttemptInfo, attemptErr error) {
+	u.audit(ctx, info, attemptErr, nil)
+
+	policy, ok := attemptPolicies[info.Action]
+	if !ok {
+		return
+	}
+
+	accountKey := accountKey(info)
+	if attemptErr == nil && !policy.CountAll {
+		if accountKey == "" {
+			return
+		}
+		if err := u.attemptRepo.DeleteThrottles(ctx, []string{accountKey}); err != nil {
+			log.Println("reset auth throttle failed:", err)
+		}
+		return
+	}
+	if attemptErr != nil && !countedFailures[attemptErr.Error()] {
+		return
+	}
+
+	if _, err := u.fail(ctx, throttleKey(info.Action, "ip", info.IP), policy.IP); err != nil {
+		log.Println("update auth throttle failed:", err)
+	}
+	if accountKey == "" {
+		return
+	}
+	lockedUntil, err := u.fail(ctx, accountKey, policy.Account)
+	if err != nil {
+		log.Println("update auth throttle failed:", err)
+		return
+	}
+	if lockedUntil != nil && (info.Action == constant.AttemptActionLogin || info.Action == constant.AttemptActionMFAVerify) {
+		u.notifyLocked(ctx, info, *lockedUntil)
+	}
+}
+
+// Unlock clears every counter of the user's account so they can log in and reset their password again.
+func (u *AttemptUsecase) Unlock(ctx context.Context, userID uint, info dto.AttemptInfo, actorID uint) error {
+	user, err := u.authRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return errors.New(constant.ErrUserNotFound)
+	}
+
+	keys := make([]string, 0, 2*len(attemptPolicies))
+	for action := range attemptPolicies {
+		keys = append(keys,
+			accountKey(dto.AttemptInfo{Action: action, Email: user.Email}),
+			accountKey(dto.AttemptInfo{Action: action, UserID: user.UserID}))
+	}
+	if err := u.attemptRepo.DeleteThrottles(ctx, keys); err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+
+	info.Action = constant.AttemptActionUnlock
+	info.Email = user.Email
+	u.audit(ctx, info, nil, &actorID)
+	return nil
+}
+
+// fail increments the counter and returns the lock expiry when this failure crossed LockAfter.
+func (u *AttemptUsecase) fail(ctx context.Context, key string, p throttlePolicy) (*time.Time, error) {
+	now := u.now()
+	t, err := u.attemptRepo.IncrementFailure(ctx, key, p.Window, now)
+	if err != nil {
+		return nil, err
+	}
+	if t.FailedCount != p.LockAfter {
+		return nil, nil
+	}
+
+	until := now.Add(p.LockDuration)
+	if err := u.attemptRepo.LockUntil(ctx, key, until); err != nil {
+		return nil, err
+	}
+	return &until, nil
+}
+
+func (u *AttemptUsecase) notifyLocked(ctx context.Context, info dto.AttemptInfo, lockedUntil time.Time) {
+	var user *model.AuthUser
+	var err error
+	if info.Email != "" {
+		user, err = u.authRepo.GetByEmail(ctx, normalizeEmail(info.Email))
+	} else {
+		user, err = u.authRepo.GetByUserID(ctx, info.UserID)
+	}
+	if err != nil || user == nil {
+		// nobody to notify: the email does not belong to an account
+		return
+	}
+	if err := u.kafkaProd.PublishAccountLockedEvent(ctx, user.UserID, user.Email, lockedUntil); err != nil {
+		log.Println("publish account locked event failed:", err)
+	}
+}
+
+func (u *AttemptUsecase) audit(ctx context.Context, info dto.AttemptInfo, attemptErr error, actorID *uint) {
+	attempt := &model.AuthAttempt{
+		Action:    info.Action,
+		Email:     normalizeEmail(info.Email),
+		UserID:    info.UserID,
+		IP:        info.IP,
+		UserAgent: truncate(info.UserAgent, 255),
+		Success:   attemptErr == nil,
+		ActorID:   actorID,
+	}
+	if attemptErr != nil {
+		attempt.Reason = truncate(attemptErr.Error(), 100)
+	}
+	if err := u.attemptRepo.CreateAttempt(ctx, attempt); err != nil {
+		log.Println("write auth audit failed:", err)
+	}
+}
+
+// backoff returns how much longer the caller has to wait before the next attempt.
+func (p throttlePolicy) backoff(t model.AuthThrottle, now time.Time) time.Duration {
+	if t.LastFailedAt == nil || t.FailedCount < p.BackoffAfter || now.Sub(*t.LastFailedAt) > p.Window {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.BackoffAfter; i < t.FailedCount && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	wait := t.LastFailedAt.Add(delay).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// accountKey is the counter of the account tried, empty when the attempt names none.
+func accountKey(info dto.AttemptInfo) string {
+	switch {
+	case info.Email != "":
+		return throttleKey(info.Action, "email", info.Email)
+	case info.UserID != 0:
+		return throttleKey(info.Action, "user", strconv.FormatUint(uint64(info.UserID), 10))
+	}
+	return ""
+}
+
+func throttleKey(action, kind, value string) string {
+	if kind == "email" {
+		value = normalizeEmail(value)
+	}
+	return action + ":" + kind + ":" + value
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+// mockAttemptRepo keeps throttles in memory so the counters behave like the real table.
+type mockAttemptRepo struct {
+	attempts  []model.AuthAttempt
+	throttles map[string]*model.AuthThrottle
+}
+
+func newMockAttemptRepo() *mockAttemptRepo {
+	return &mockAttemptRepo{throttles: map[string]*model.AuthThrottle{}}
+}
+
+func (m *mockAttemptRepo) CreateAttempt(_ context.Context, attempt *model.AuthAttempt) error {
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func (m *mockAttemptRepo) GetThrottles(_ context.Context, keys []string) ([]model.AuthThrottle, error) {
+	var res []model.AuthThrottle
+	for _, k := range keys {
+		if t, ok := m.throttles[k]; ok {
+			res = append(res, *t)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockAttemptRepo) IncrementFailure(_ context.Context, key string, window time.Duration, now time.Time) (*model.AuthThrottle, error) {
+	t, ok := m.throttles[key]
+	if !ok {
+		t = &model.AuthThrottle{Key: key}
+		m.throttles[key] = t
+	}
+	if t.LastFailedAt == nil || now.Sub(*t.LastFailedAt) > window {
+		t.FailedCount = 0
+	}
+	t.FailedCount++
+	t.LastFailedAt = &now
+	copied := *t
+	return &copied, nil
+}
+
+func (m *mockAttemptRepo) LockUntil(_ context.Context, key string, until time.Time) error {
+	m.throttles[key].LockedUntil = &until
+	return nil
+}
+
+func (m *mockAttemptRepo) DeleteThrottles(_ context.Context, keys []string) error {
+	for _, k := range keys {
+		delete(m.throttles, k)
+	}
+	return nil
+}
+
+func loginAttempt() dto.AttemptInfo {
+	return dto.AttemptInfo{Action: constant.AttemptActionLogin, Email: "Test@Example.com", IP: "10.0.0.1"}
+}
+
+// ---------------- TEST CASES ----------------
+
+func TestAttempt_BackoffAfterRepeatedFailures(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	now := fixedNow
+	uc.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		uc.Record(context.Background(), loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	}
+
+	retryAfter, err := uc.Allow(context.Background(), loginAttempt())
+	assert.EqualError(t, err, constant.ErrTooManyAttempts)
+	assert.Equal(t, time.Second, retryAfter)
+
+	now = now.Add(2 * time.Second)
+	_, err = uc.Allow(context.Background(), loginAttempt())
+	assert.NoError(t, err)
+}
+
+func TestAttempt_LockoutSendsMail(t *testing.T) {
+	repo := newMockAttemptRepo()
+	var published dto.MailEvent
+	uc := NewAttemptUsecase(repo,
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, email string) (*model.AuthUser, error) {
+				return &model.AuthUser{Email: email}, nil
+			},
+		},
+		&mockKafka{
+			publishFn: func(_ context.Context, event dto.MailEvent) error {
+				published = event
+				return nil
+			},
+		})
+	uc.now = func() time.Time { return fixedNow }
+
+	for i := 0; i < 10; i++ {
+		uc.Record(context.Background(), loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	}
+
+	retryAfter, err := uc.Allow(context.Background(), loginAttempt())
+	assert.EqualError(t, err, constant.ErrAccountLocked)
+	assert.Equal(t, 15*time.Minute, retryAfter)
+	assert.Equal(t, constant.EventTypeAccountLocked, published.Type)
+	assert.Equal(t, "test@example.com", published.Email)
+}
+
+func TestAttempt_SuccessResetsEmailCounter(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	uc.now = func() time.Time { return fixedNow }
+
+	for i := 0; i < 3; i++ {
+		uc.Record(context.Background(), loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	}
+	uc.Record(context.Background(), loginAttempt(), nil)
+
+	assert.NotContains(t, repo.throttles, "login:email:test@example.com")
+	assert.Contains(t, repo.throttles, "login:ip:10.0.0.1")
+	assert.Len(t, repo.attempts, 4)
+	assert.True(t, repo.attempts[3].Success)
+}
+
+func TestAttempt_ServerErrorIsNotCounted(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+
+	uc.Record(context.Background(), loginAttempt(), errors.New(constant.ErrInternalServer))
+
+	assert.Empty(t, repo.throttles)
+	assert.Len(t, repo.attempts, 1)
+}
+
+func TestAttempt_ResetPasswordCountsEveryRequest(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	uc.now = func() time.Time { return fixedNow }
+	info := dto.AttemptInfo{Action: constant.AttemptActionResetPassword, Email: "test@example.com", IP: "10.0.0.1"}
+
+	uc.Record(context.Background(), info, nil)
+
+	retryAfter, err := uc.Allow(context.Background(), info)
+	assert.EqualError(t, err, constant.ErrTooManyAttempts)
+	assert.Equal(t, 30*time.Second, retryAfter)
+}
+
+func TestAttempt_WithoutEmail_ThrottlesOnlyIP(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	uc.now = func() time.Time { return fixedNow }
+	info := dto.AttemptInfo{Action: constant.AttemptActionMagicRedeem, IP: "10.0.0.1"}
+
+	for i := 0; i < 5; i++ {
+		uc.Record(context.Background(), info, errors.New(constant.ErrInvalidToken))
+	}
+
+	assert.Len(t, repo.throttles, 1)
+	assert.Contains(t, repo.throttles, "magic_link_redeem:ip:10.0.0.1")
+	_, err := uc.Allow(context.Background(), info)
+	assert.EqualError(t, err, constant.ErrTooManyAttempts)
+	_, err = uc.Allow(context.Background(), dto.AttemptInfo{Action: constant.AttemptActionMagicRedeem, IP: "10.0.0.2"})
+	assert.NoError(t, err)
+}
+
+func TestAttempt_Unlock(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo,
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) {
+				return &model.AuthUser{Email: "test@example.com"}, nil
+			},
+		}, &mockKafka{})
+	uc.now = func() time.Time { return fixedNow }
+
+	for i := 0; i < 10; i++ {
+		uc.Record(context.Background(), loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	}
+
+	err := uc.Unlock(context.Background(), 1, dto.AttemptInfo{IP: "10.0.0.2"}, 99)
+	assert.NoError(t, err)
+
+	_, err = uc.Allow(context.Background(), loginAttempt())
+	assert.NoError(t, err)
+	last := repo.attempts[len(repo.attempts)-1]
+	assert.Equal(t, constant.AttemptActionUnlock, last.Action)
+	assert.Equal(t, uint(99), *last.ActorID)
+}
+
+func TestAttempt_UnlockUnknownUser_ReturnsError(t *testing.T) {
+	uc := NewAttemptUsecase(newMockAttemptRepo(), &mockAuthRepo{}, &mockKafka{})
+
+	err := uc.Unlock(context.Background(), 1, dto.AttemptInfo{}, 99)
+	assert.EqualError(t, err, constant.ErrUserNotFound)
+}
+
+func TestAttempt_MFAVerifyLocksUserAfterWrongCodes(t *testing.T) {
+	repo := newMockAttemptRepo()
+	var published dto.MailEvent
+	uc := NewAttemptUsecase(repo,
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, userID uint) (*model.AuthUser, error) {
+				return &model.AuthUser{UserID: userID, Email: "test@example.com"}, nil
+			},
+		},
+		&mockKafka{
+			publishFn: func(_ context.Context, event dto.MailEvent) error {
+				published = event
+				return nil
+			},
+		})
+	uc.now = func() time.Time { return fixedNow }
+	info := dto.AttemptInfo{Action: constant.AttemptActionMFAVerify, UserID: 7, IP: "10.0.0.1"}
+
+	for i := 0; i < 5; i++ {
+		uc.Record(context.Background(), info, errors.New(constant.ErrInvalidMFACode))
+	}
+
+	assert.Contains(t, repo.throttles, "mfa_verify:user:7")
+	// another IP does not get a fresh set of guesses
+	retryAfter, err := uc.Allow(context.Background(), dto.AttemptInfo{Action: constant.AttemptActionMFAVerify, UserID: 7, IP: "10.0.0.2"})
+	assert.EqualError(t, err, constant.ErrAccountLocked)
+	assert.Equal(t, 15*time.Minute, retryAfter)
+	assert.Equal(t, constant.EventTypeAccountLocked, published.Type)
+	assert.Equal(t, uint(7), repo.attempts[0].UserID)
+
+	err = uc.Unlock(context.Background(), 7, dto.AttemptInfo{IP: "10.0.0.3"}, 99)
+	assert.NoError(t, err)
+	_, err = uc.Allow(context.Background(), info)
+	assert.NoError(t, err)
+}
+
+func TestAttempt_MFAVerifyInvalidTokenCountsOnlyIP(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	info := dto.AttemptInfo{Action: constant.AttemptActionMFAVerify, IP: "10.0.0.1"}
+
+	uc.Record(context.Background(), info, errors.New(constant.ErrInvalidMFAToken))
+
+	assert.Len(t, repo.throttles, 1)
+	assert.Contains(t, repo.throttles, "mfa_verify:ip:10.0.0.1")
+}
+
+func TestAttempt_LoginLocked(t *testing.T) {
+	repo := newMockAttemptRepo()
+	uc := NewAttemptUsecase(repo, &mockAuthRepo{}, &mockKafka{})
+	ctx := context.Background()
+
+	for i := 0; i < 9; i++ {
+		uc.Record(ctx, loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	}
+	assert.False(t, uc.LoginLocked(ctx, "test@example.com"))
+
+	uc.Record(ctx, loginAttempt(), errors.New(constant.ErrInvalidCredentials))
+	assert.True(t, uc.LoginLocked(ctx, "test@example.com"))
+
+	uc.now = func() time.Time { return time.Now().Add(16 * time.Minute) }
+	assert.False(t, uc.LoginLocked(ctx, "test@example.com"))
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"packages/servicetoken"
+	"packages/userdata"
+	"time"
+)
+
+// authDataStep is the part of data exports and account deletions auth-service carries out
+// itself. It runs first on deletion, so the account is locked before the other services start.
+type authDataStep struct {
+	authRepo    repository.AuthRepository
+	sessionRepo repository.SessionRepository
+	auditRepo   repository.ImpersonationRepository
+	dataRepo    repository.DataRequestRepository
+	now         func() time.Time
+}
+
+func NewAuthDataStep(authRepo repository.AuthRepository, sessionRepo repository.SessionRepository, auditRepo repository.ImpersonationRepository, dataRepo repository.DataRequestRepository) repository.UserDataClient {
+	return &authDataStep{
+		authRepo:    authRepo,
+		sessionRepo: sessionRepo,
+		auditRepo:   auditRepo,
+		dataRepo:    dataRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *authDataStep) Service() string {
+	return servicetoken.AuthService
+}
+
+// Export returns the account, every session and the impersonations of the user. Credentials are
+// left out.
+func (s *authDataStep) Export(ctx context.Context, userID uint) (json.RawMessage, error) {
+	user, err := s.authRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	sessions, err := s.sessionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	audits, err := s.auditRepo.ListByUser(ctx, userID, constant.ImpersonationAuditLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	export := dto.AuthDataExport{
+		Account: dto.AuthAccountExport{
+			Email:            user.Email,
+			Role:             user.Role,
+			IsVerified:       user.IsVerified,
+			IsActive:         user.IsActive,
+			TwoFactorEnabled: user.TwoFactorEnabled,
+			CreatedAt:        user.CreatedAt,
+		},
+		Sessions:       make([]dto.SessionExport, 0, len(sessions)),
+		Impersonations: auditEntries(audits),
+	}
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, dto.SessionExport{
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Location:   session.Location,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			RevokedAt:  session.RevokedAt,
+		})
+	}
+	return json.Marshal(export)
+}
+
+func (s *authDataStep) Erase(ctx context.Context, userID uint) error {
+	return s.dataRepo.EraseAuthData(ctx, userID, userdata.DeletedEmail(userID), s.now())
+}
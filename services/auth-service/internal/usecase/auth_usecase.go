@@ -8,26 +8,41 @@ import (
 	"auth-service/internal/repository"
 	"auth-service/internal/utils"
 	"context"
+	"encoding/json"
 	"errors"
+	"log"
+	"strconv"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type AuthUsecase struct {
-	authRepo   repository.AuthRepository
-	userClient repository.UserClient
-	kafkaProd  kafka.Producer
+	authRepo        repository.AuthRepository
+	userClient      repository.UserClient
+	kafkaProd       kafka.Producer
+	verificationTTL time.Duration
 }
 
 func NewAuthUsecase(authRepo repository.AuthRepository, userClient repository.UserClient, kafkaProd kafka.Producer) *AuthUsecase {
 	return &AuthUsecase{
-		authRepo:   authRepo,
-		userClient: userClient,
-		kafkaProd:  kafkaProd,
+		authRepo:        authRepo,
+		userClient:      userClient,
+		kafkaProd:       kafkaProd,
+		verificationTTL: constant.DefaultEmailVerifyTTL,
 	}
 }
 
+// SetVerificationTTL overrides how long email verification links stay valid.
+func (u *AuthUsecase) SetVerificationTTL(ttl time.Duration) {
+	u.verificationTTL = ttl
+}
+
+// SignUp registers a user across user-service and auth-service. The auth user and the
+// verification mail are committed together through the outbox; if that fails the profile
+// created in user-service is deleted again. Signing up again with an email whose account
+// was never verified, or whose profile was left behind, picks up where the last try stopped.
 func (u *AuthUsecase) SignUp(ctx context.Context, email, password, name string) error {
 	// 1. Check if email exists
 	existing, err := u.authRepo.GetByEmail(ctx, email)
@@ -35,7 +50,12 @@ func (u *AuthUsecase) SignUp(ctx context.Context, email, password, name string)
 		return errors.New(constant.ErrInternalServer)
 	}
 	if existing != nil {
-		return errors.New(constant.ErrEmailAlreadyExists)
+		if existing.IsVerified {
+			return errors.New(constant.ErrEmailAlreadyExists)
+		}
+		// the account is there but was never verified: only send a new link, never
+		// touch the stored password so nobody can pre-claim someone else's email
+		return u.sendVerification(ctx, existing)
 	}
 
 	// 2. Hash password
@@ -44,13 +64,13 @@ func (u *AuthUsecase) SignUp(ctx context.Context, email, password, name string)
 		return errors.New(constant.ErrPasswordHash)
 	}
 
-	// 3. Create user profile
-	userProfile, err := u.userClient.CreateUser(ctx, email, name, constant.USER_ROLE)
+	// 3. Create user profile, or adopt the one left by an earlier failed sign-up
+	userProfile, created, err := u.ensureUserProfile(ctx, email, name, constant.USER_ROLE)
 	if err != nil {
-		return errors.New(constant.ErrCreateUserProfile)
+		return err
 	}
 
-	// 4. Create auth user
+	// 4. Create auth user and queue the verification mail in one transaction
 	authUser := &model.AuthUser{
 		UserID:       userProfile.ID,
 		Email:        email,
@@ -58,18 +78,162 @@ func (u *AuthUsecase) SignUp(ctx context.Context, email, password, name string)
 		Role:         constant.USER_ROLE,
 		IsVerified:   false,
 	}
-	if err := u.authRepo.Create(ctx, authUser); err != nil {
+	event, err := u.verificationOutboxEvent(authUser)
+	if err != nil {
+		u.compensateUserProfile(ctx, userProfile.ID, created)
+		return err
+	}
+	if err := u.authRepo.CreateWithOutbox(ctx, authUser, event); err != nil {
+		u.compensateUserProfile(ctx, userProfile.ID, created)
 		return errors.New(constant.ErrCreateAuthUser)
 	}
 
-	// 5. Generate verification token
-	token, err := utils.GenerateAccessToken(authUser)
+	return nil
+}
+
+func (u *AuthUsecase) ensureUserProfile(ctx context.Context, email, name, role string) (*dto.CreateUserResponse, bool, error) {
+	userProfile, err := u.userClient.CreateUser(ctx, email, name, role)
+	if err == nil {
+		return userProfile, true, nil
+	}
+	if err.Error() != constant.ErrEmailAlreadyExists {
+		return nil, false, errors.New(constant.ErrCreateUserProfile)
+	}
+
+	// no auth user exists for this email (checked by the caller), so the profile is an orphan
+	userProfile, err = u.userClient.GetUserByEmail(ctx, email)
+	if err != nil || userProfile == nil {
+		return nil, false, errors.New(constant.ErrCreateUserProfile)
+	}
+	return userProfile, false, nil
+}
+
+// Invite creates an account for an admin import and returns the status of its row. The new
+// account has no password: the invitation mail, queued with it through the outbox, lets the user
+// choose one. An account that was signed up for but never verified keeps its password and only
+// gets a new verification link.
+func (u *AuthUsecase) Invite(ctx context.Context, email, name, role string) (string, uint, error) {
+	existing, err := u.authRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", 0, errors.New(constant.ErrInternalServer)
+	}
+	if existing != nil {
+		if existing.IsVerified {
+			return "", 0, errors.New(constant.ErrEmailAlreadyExists)
+		}
+		if err := u.sendVerification(ctx, existing); err != nil {
+			return "", 0, errors.New(constant.ErrPublishEvent)
+		}
+		return constant.UserImportRowVerification, existing.UserID, nil
+	}
+
+	userProfile, created, err := u.ensureUserProfile(ctx, email, name, role)
+	if err != nil {
+		return "", 0, err
+	}
+
+	authUser := &model.AuthUser{
+		UserID:     userProfile.ID,
+		Email:      email,
+		Role:       role,
+		IsVerified: false,
+	}
+	token, err := utils.GenerateInvitationToken(authUser, constant.InvitationTTL)
+	if err != nil {
+		u.compensateUserProfile(ctx, userProfile.ID, created)
+		return "", 0, errors.New(constant.ErrGenerateToken)
+	}
+	event, err := mailOutboxEvent(email, constant.EventTypeInvitation, map[string]string{"token": token, "name": name})
+	if err != nil {
+		u.compensateUserProfile(ctx, userProfile.ID, created)
+		return "", 0, err
+	}
+	if err := u.authRepo.CreateWithOutbox(ctx, authUser, event); err != nil {
+		u.compensateUserProfile(ctx, userProfile.ID, created)
+		return "", 0, errors.New(constant.ErrCreateAuthUser)
+	}
+	return constant.UserImportRowInvited, userProfile.ID, nil
+}
+
+// compensateUserProfile undoes a profile created by this sign-up. When the delete fails the
+// orphan stays behind, and the next sign-up with the same email adopts it.
+func (u *AuthUsecase) compensateUserProfile(ctx context.Context, userID uint, created bool) {
+	if !created {
+		return
+	}
+	if err := u.userClient.DeleteUser(ctx, userID); err != nil {
+		log.Printf("compensating delete of user profile %d failed: %v", userID, err)
+	}
+}
+
+func (u *AuthUsecase) verificationOutboxEvent(user *model.AuthUser) (*model.OutboxEvent, error) {
+	token, err := utils.GenerateEmailVerificationToken(user, u.verificationTTL)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateToken)
+	}
+
+	return mailOutboxEvent(user.Email, constant.EventTypeVerifyEmail, map[string]string{"token": token})
+}
+
+// mailOutboxEvent wraps a mail event for the outbox; the relay publishes it as is.
+func mailOutboxEvent(email, eventType string, data map[string]string) (*model.OutboxEvent, error) {
+	payload, err := json.Marshal(dto.MailEvent{
+		Email: email,
+		Type:  eventType,
+		Data:  data,
+	})
+	if err != nil {
+		return nil, errors.New(constant.ErrMarshalRequest)
+	}
+
+	return &model.OutboxEvent{
+		EventType:     eventType,
+		Key:           email,
+		Payload:       string(payload),
+		Status:        constant.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+// profileVerifiedOutboxEvent queues marking the user's profile verified in user-service.
+func profileVerifiedOutboxEvent(userID uint) (*model.OutboxEvent, error) {
+	payload, err := json.Marshal(dto.ProfileVerifiedEvent{UserID: userID})
+	if err != nil {
+		return nil, errors.New(constant.ErrMarshalRequest)
+	}
+
+	return &model.OutboxEvent{
+		EventType:     constant.EventTypeProfileVerified,
+		Key:           strconv.FormatUint(uint64(userID), 10),
+		Payload:       string(payload),
+		Status:        constant.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+// ResendVerification mails a fresh verification link to a user who has not verified yet.
+func (u *AuthUsecase) ResendVerification(ctx context.Context, email string) error {
+	user, err := u.authRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return errors.New(constant.ErrUserNotFound)
+	}
+	if user.IsVerified {
+		return errors.New(constant.ErrUserAlreadyVerified)
+	}
+
+	return u.sendVerification(ctx, user)
+}
+
+func (u *AuthUsecase) sendVerification(ctx context.Context, user *model.AuthUser) error {
+	token, err := utils.GenerateEmailVerificationToken(user, u.verificationTTL)
 	if err != nil {
 		return errors.New(constant.ErrGenerateToken)
 	}
 
-	// 6. Publish verification event
-	if err := u.kafkaProd.PublishVerificationEvent(ctx, email, token); err != nil {
+	if err := u.kafkaProd.PublishVerificationEvent(ctx, user.Email, token); err != nil {
 		return err
 	}
 
@@ -78,7 +242,7 @@ func (u *AuthUsecase) SignUp(ctx context.Context, email, password, name string)
 
 func (u *AuthUsecase) VerifyAccount(ctx context.Context, tokenString string) error {
 	tokenClaims, err := utils.ValidateToken(tokenString)
-	if err != nil {
+	if err != nil || tokenClaims.TokenType != constant.TokenTypeEmailVerify {
 		return errors.New(constant.ErrInvalidToken)
 	}
 
@@ -91,13 +255,53 @@ func (u *AuthUsecase) VerifyAccount(ctx context.Context, tokenString string) err
 		return errors.New(constant.ErrUserAlreadyVerified)
 	}
 
-	if err := u.authRepo.VerifyUser(ctx, user.Email); err != nil {
+	// user-service mirrors the flag for searching users; the outbox relay tells it, retrying
+	// until it is reachable
+	event, err := profileVerifiedOutboxEvent(user.UserID)
+	if err != nil {
+		return err
+	}
+	user.IsVerified = true
+	if err := u.authRepo.UpdateWithOutbox(ctx, user, event); err != nil {
 		return errors.New(constant.ErrFailedToUpdateUser)
 	}
 
 	return nil
 }
 
+// AcceptInvitation sets the first password of an invited account. Receiving the link proves the
+// address, so the account is verified too; that also makes the link unusable afterwards.
+func (u *AuthUsecase) AcceptInvitation(ctx context.Context, tokenString, password string) error {
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil || claims.TokenType != constant.TokenTypeInvitation {
+		return errors.New(constant.ErrInvalidToken)
+	}
+
+	user, err := u.authRepo.GetByUserID(ctx, claims.UserID)
+	if err != nil {
+		return errors.New(constant.ErrGetUserFailed)
+	}
+	// the address may have changed, or the account been verified some other way, since
+	if user == nil || user.Email != claims.Email || user.IsVerified {
+		return errors.New(constant.ErrInvalidToken)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New(constant.ErrPasswordHash)
+	}
+	event, err := profileVerifiedOutboxEvent(user.UserID)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hashedPassword)
+	user.IsVerified = true
+	if err := u.authRepo.UpdateWithOutbox(ctx, user, event); err != nil {
+		return errors.New(constant.ErrFailedToUpdateUser)
+	}
+	return nil
+}
+
 func (u *AuthUsecase) Authenticate(ctx context.Context, loginRequest *dto.LoginRequest) (*model.AuthUser, error) {
 	user, err := u.authRepo.GetByEmail(ctx, loginRequest.Email)
 	if err != nil {
@@ -113,7 +317,7 @@ func (u *AuthUsecase) Authenticate(ctx context.Context, loginRequest *dto.LoginR
 
 func (u *AuthUsecase) AuthenticateUserFromClaim(ctx context.Context, input *dto.RefreshTokenInput) (*model.AuthUser, error) {
 	claims, err := utils.ValidateToken(input.RefreshToken)
-	if err != nil {
+	if err != nil || claims.IsSingleUseToken() {
 		return nil, errors.New(constant.ErrExpiredOrInvalidRefreshToken)
 	}
 
@@ -6,10 +6,13 @@ import (
 	"auth-service/internal/model"
 	"auth-service/internal/utils"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -17,11 +20,12 @@ import (
 // ---------------- MOCKS ----------------
 
 type mockAuthRepo struct {
-	createFn      func(ctx context.Context, user *model.AuthUser) error
-	getByEmailFn  func(ctx context.Context, email string) (*model.AuthUser, error)
-	verifyUserFn  func(ctx context.Context, email string) error
-	updateUserFn  func(ctx context.Context, user *model.AuthUser) error
-	getByUserIDFn func(ctx context.Context, userID uint) (*model.AuthUser, error)
+	createFn           func(ctx context.Context, user *model.AuthUser) error
+	getByEmailFn       func(ctx context.Context, email string) (*model.AuthUser, error)
+	updateUserFn       func(ctx context.Context, user *model.AuthUser) error
+	getByUserIDFn      func(ctx context.Context, userID uint) (*model.AuthUser, error)
+	createWithOutboxFn func(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error
+	updateWithOutboxFn func(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error
 }
 
 func (m *mockAuthRepo) Create(ctx context.Context, user *model.AuthUser) error {
@@ -38,13 +42,6 @@ func (m *mockAuthRepo) GetByEmail(ctx context.Context, email string) (*model.Aut
 	return nil, nil
 }
 
-func (m *mockAuthRepo) VerifyUser(ctx context.Context, email string) error {
-	if m.verifyUserFn != nil {
-		return m.verifyUserFn(ctx, email)
-	}
-	return nil
-}
-
 func (m *mockAuthRepo) UpdateUser(ctx context.Context, user *model.AuthUser) error {
 	if m.updateUserFn != nil {
 		return m.updateUserFn(ctx, user)
@@ -59,8 +56,26 @@ func (m *mockAuthRepo) GetByUserID(ctx context.Context, userID uint) (*model.Aut
 	return nil, nil
 }
 
+func (m *mockAuthRepo) CreateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+	if m.createWithOutboxFn != nil {
+		return m.createWithOutboxFn(ctx, user, event)
+	}
+	return nil
+}
+
+func (m *mockAuthRepo) UpdateWithOutbox(ctx context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+	if m.updateWithOutboxFn != nil {
+		return m.updateWithOutboxFn(ctx, user, event)
+	}
+	return nil
+}
+
 type mockUserClient struct {
-	createUserFn func(ctx context.Context, email, name, role string) (*dto.CreateUserResponse, error)
+	createUserFn     func(ctx context.Context, email, name, role string) (*dto.CreateUserResponse, error)
+	getUserByEmailFn func(ctx context.Context, email string) (*dto.CreateUserResponse, error)
+	deleteUserFn     func(ctx context.Context, userID uint) error
+	updateEmailFn    func(ctx context.Context, userID uint, email string) error
+	markVerifiedFn   func(ctx context.Context, userID uint) error
 }
 
 func (m *mockUserClient) CreateUser(ctx context.Context, email, name, role string) (*dto.CreateUserResponse, error) {
@@ -70,6 +85,34 @@ func (m *mockUserClient) CreateUser(ctx context.Context, email, name, role strin
 	return nil, nil
 }
 
+func (m *mockUserClient) GetUserByEmail(ctx context.Context, email string) (*dto.CreateUserResponse, error) {
+	if m.getUserByEmailFn != nil {
+		return m.getUserByEmailFn(ctx, email)
+	}
+	return nil, nil
+}
+
+func (m *mockUserClient) DeleteUser(ctx context.Context, userID uint) error {
+	if m.deleteUserFn != nil {
+		return m.deleteUserFn(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockUserClient) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	if m.updateEmailFn != nil {
+		return m.updateEmailFn(ctx, userID, email)
+	}
+	return nil
+}
+
+func (m *mockUserClient) MarkVerified(ctx context.Context, userID uint) error {
+	if m.markVerifiedFn != nil {
+		return m.markVerifiedFn(ctx, userID)
+	}
+	return nil
+}
+
 type mockKafka struct {
 	publishFn func(ctx context.Context, event dto.MailEvent) error
 }
@@ -97,6 +140,15 @@ func (m *mockKafka) PublishResetPasswordEvent(ctx context.Context, email, newPas
 	})
 }
 
+func (m *mockKafka) PublishAccountLockedEvent(ctx context.Context, userID uint, email string, lockedUntil time.Time) error {
+	return m.PublishMailEvent(ctx, dto.MailEvent{
+		UserID: userID,
+		Email:  email,
+		Data:   map[string]string{"lockedUntil": lockedUntil.UTC().Format(time.RFC3339)},
+		Type:   constant.EventTypeAccountLocked,
+	})
+}
+
 func (m *mockKafka) Close() error { return nil }
 
 func gormErrNotFound() error {
@@ -111,7 +163,16 @@ func TestSignUp_NewUser_Success(t *testing.T) {
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
 			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return nil, gormErrNotFound() },
-			createFn:     func(_ context.Context, _ *model.AuthUser) error { return nil },
+			createWithOutboxFn: func(_ context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+				assert.Equal(t, uint(1), user.UserID)
+				assert.Equal(t, constant.EventTypeVerifyEmail, event.EventType)
+				assert.Equal(t, constant.OutboxStatusPending, event.Status)
+
+				var mail dto.MailEvent
+				assert.NoError(t, json.Unmarshal([]byte(event.Payload), &mail))
+				assert.NotEmpty(t, mail.Data["token"])
+				return nil
+			},
 		},
 		&mockUserClient{
 			createUserFn: func(_ context.Context, _, _, _ string) (*dto.CreateUserResponse, error) {
@@ -119,23 +180,125 @@ func TestSignUp_NewUser_Success(t *testing.T) {
 			},
 		},
 		&mockKafka{
-			publishFn: func(_ context.Context, event dto.MailEvent) error {
-				assert.Equal(t, constant.EventTypeVerifyEmail, event.Type)
-				assert.NotEmpty(t, event.Data["token"])
+			publishFn: func(_ context.Context, _ dto.MailEvent) error {
+				t.Fatal("sign-up must go through the outbox, not publish directly")
+				return nil
+			},
+		},
+	)
+
+	err := uc.SignUp(context.Background(), "test@example.com", "password123", "Test User")
+	assert.NoError(t, err)
+}
+
+func TestSignUp_CreateAuthUserFails_DeletesProfile(t *testing.T) {
+	var deleted uint
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return nil, gormErrNotFound() },
+			createWithOutboxFn: func(_ context.Context, _ *model.AuthUser, _ *model.OutboxEvent) error {
+				return errors.New("db error")
+			},
+		},
+		&mockUserClient{
+			createUserFn: func(_ context.Context, _, _, _ string) (*dto.CreateUserResponse, error) {
+				return &dto.CreateUserResponse{ID: 7}, nil
+			},
+			deleteUserFn: func(_ context.Context, userID uint) error {
+				deleted = userID
 				return nil
 			},
 		},
+		&mockKafka{},
+	)
+
+	err := uc.SignUp(context.Background(), "test@example.com", "password123", "Test User")
+	assert.EqualError(t, err, constant.ErrCreateAuthUser)
+	assert.Equal(t, uint(7), deleted)
+}
+
+func TestSignUp_OrphanProfile_IsAdopted(t *testing.T) {
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return nil, gormErrNotFound() },
+			createWithOutboxFn: func(_ context.Context, user *model.AuthUser, _ *model.OutboxEvent) error {
+				assert.Equal(t, uint(3), user.UserID)
+				return nil
+			},
+		},
+		&mockUserClient{
+			createUserFn: func(_ context.Context, _, _, _ string) (*dto.CreateUserResponse, error) {
+				return nil, errors.New(constant.ErrEmailAlreadyExists)
+			},
+			getUserByEmailFn: func(_ context.Context, email string) (*dto.CreateUserResponse, error) {
+				return &dto.CreateUserResponse{ID: 3, Email: email}, nil
+			},
+		},
+		&mockKafka{},
 	)
 
 	err := uc.SignUp(context.Background(), "test@example.com", "password123", "Test User")
 	assert.NoError(t, err)
 }
 
+func TestSignUp_AdoptedProfile_NotDeletedOnFailure(t *testing.T) {
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return nil, gormErrNotFound() },
+			createWithOutboxFn: func(_ context.Context, _ *model.AuthUser, _ *model.OutboxEvent) error {
+				return errors.New("db error")
+			},
+		},
+		&mockUserClient{
+			createUserFn: func(_ context.Context, _, _, _ string) (*dto.CreateUserResponse, error) {
+				return nil, errors.New(constant.ErrEmailAlreadyExists)
+			},
+			getUserByEmailFn: func(_ context.Context, email string) (*dto.CreateUserResponse, error) {
+				return &dto.CreateUserResponse{ID: 3, Email: email}, nil
+			},
+			deleteUserFn: func(_ context.Context, _ uint) error {
+				t.Fatal("a profile this call did not create must not be deleted")
+				return nil
+			},
+		},
+		&mockKafka{},
+	)
+
+	err := uc.SignUp(context.Background(), "test@example.com", "password123", "Test User")
+	assert.EqualError(t, err, constant.ErrCreateAuthUser)
+}
+
+func TestSignUp_UnverifiedAccount_ResendsVerification(t *testing.T) {
+	published := false
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, email string) (*model.AuthUser, error) {
+				return &model.AuthUser{Email: email, PasswordHash: "old"}, nil
+			},
+			updateUserFn: func(_ context.Context, _ *model.AuthUser) error {
+				t.Fatal("re-entry must not change the stored account")
+				return nil
+			},
+		},
+		&mockUserClient{},
+		&mockKafka{
+			publishFn: func(_ context.Context, event dto.MailEvent) error {
+				published = event.Type == constant.EventTypeVerifyEmail
+				return nil
+			},
+		},
+	)
+
+	err := uc.SignUp(context.Background(), "pending@example.com", "pass", "Name")
+	assert.NoError(t, err)
+	assert.True(t, published)
+}
+
 func TestSignUp_EmailAlreadyExists_ReturnsError(t *testing.T) {
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
 			getByEmailFn: func(_ context.Context, email string) (*model.AuthUser, error) {
-				return &model.AuthUser{Email: email}, nil
+				return &model.AuthUser{Email: email, IsVerified: true}, nil
 			},
 		},
 		&mockUserClient{}, &mockKafka{},
@@ -160,19 +323,50 @@ func TestSignUp_RepoError_ReturnsInternalServerError(t *testing.T) {
 
 // -------- VerifyAccount --------
 
-func TestVerifyAccount_ValidToken_Success(t *testing.T) {
-	user := &model.AuthUser{Email: "test@example.com", IsVerified: false}
-	token, _ := utils.GenerateAccessToken(user)
+func TestVerifyAccount_ValidToken_QueuesProfileVerified(t *testing.T) {
+	user := &model.AuthUser{UserID: 7, Email: "test@example.com", IsVerified: false}
+	token, _ := utils.GenerateEmailVerificationToken(user, time.Hour)
 
+	var saved *model.AuthUser
+	var queued *model.OutboxEvent
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
 			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return user, nil },
-			verifyUserFn: func(_ context.Context, _ string) error { return nil },
+			updateWithOutboxFn: func(_ context.Context, u *model.AuthUser, event *model.OutboxEvent) error {
+				saved, queued = u, event
+				return nil
+			},
 		},
-		&mockUserClient{}, &mockKafka{})
+		&mockUserClient{markVerifiedFn: func(_ context.Context, _ uint) error {
+			t.Fatal("user-service is told by the outbox relay, not inline")
+			return nil
+		}},
+		&mockKafka{})
 
 	err := uc.VerifyAccount(context.Background(), token)
 	assert.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.True(t, saved.IsVerified)
+	require.NotNil(t, queued)
+	assert.Equal(t, constant.EventTypeProfileVerified, queued.EventType)
+	assert.JSONEq(t, `{"user_id":7}`, queued.Payload)
+}
+
+func TestVerifyAccount_SaveFails_ReturnsError(t *testing.T) {
+	user := &model.AuthUser{UserID: 7, Email: "test@example.com", IsVerified: false}
+	token, _ := utils.GenerateEmailVerificationToken(user, time.Hour)
+
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return user, nil },
+			updateWithOutboxFn: func(_ context.Context, _ *model.AuthUser, _ *model.OutboxEvent) error {
+				return errors.New("db down")
+			},
+		},
+		&mockUserClient{}, &mockKafka{})
+
+	err := uc.VerifyAccount(context.Background(), token)
+	assert.EqualError(t, err, constant.ErrFailedToUpdateUser)
 }
 
 func TestVerifyAccount_InvalidToken_ReturnsError(t *testing.T) {
@@ -181,9 +375,23 @@ func TestVerifyAccount_InvalidToken_ReturnsError(t *testing.T) {
 	assert.EqualError(t, err, constant.ErrInvalidToken)
 }
 
+func TestVerifyAccount_AccessToken_ReturnsError(t *testing.T) {
+	user := &model.AuthUser{Email: "test@example.com", IsVerified: false}
+	token, _ := utils.GenerateAccessToken(user, "")
+
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockUserClient{}, &mockKafka{})
+
+	err := uc.VerifyAccount(context.Background(), token)
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+}
+
 func TestVerifyAccount_UserAlreadyVerified_ReturnsError(t *testing.T) {
 	user := &model.AuthUser{Email: "test@example.com", IsVerified: true}
-	token, _ := utils.GenerateAccessToken(user)
+	token, _ := utils.GenerateEmailVerificationToken(user, time.Hour)
 
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
@@ -195,6 +403,53 @@ func TestVerifyAccount_UserAlreadyVerified_ReturnsError(t *testing.T) {
 	assert.EqualError(t, err, constant.ErrUserAlreadyVerified)
 }
 
+// -------- ResendVerification --------
+
+func TestResendVerification_Unverified_Success(t *testing.T) {
+	user := &model.AuthUser{Email: "test@example.com", IsVerified: false}
+
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockUserClient{},
+		&mockKafka{
+			publishFn: func(_ context.Context, event dto.MailEvent) error {
+				claims, err := utils.ValidateToken(event.Data["token"])
+				assert.NoError(t, err)
+				assert.Equal(t, constant.TokenTypeEmailVerify, claims.TokenType)
+				return nil
+			},
+		})
+
+	err := uc.ResendVerification(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+}
+
+func TestResendVerification_AlreadyVerified_ReturnsError(t *testing.T) {
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, email string) (*model.AuthUser, error) {
+				return &model.AuthUser{Email: email, IsVerified: true}, nil
+			},
+		},
+		&mockUserClient{}, &mockKafka{})
+
+	err := uc.ResendVerification(context.Background(), "test@example.com")
+	assert.EqualError(t, err, constant.ErrUserAlreadyVerified)
+}
+
+func TestResendVerification_UserNotFound_ReturnsError(t *testing.T) {
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, _ string) (*model.AuthUser, error) { return nil, gormErrNotFound() },
+		},
+		&mockUserClient{}, &mockKafka{})
+
+	err := uc.ResendVerification(context.Background(), "test@example.com")
+	assert.EqualError(t, err, constant.ErrUserNotFound)
+}
+
 // -------- Authenticate --------
 
 func TestAuthenticate_ValidCredentials_Success(t *testing.T) {
@@ -230,7 +485,7 @@ func TestAuthenticate_WrongPassword_ReturnsError(t *testing.T) {
 
 func TestAuthenticateUserFromClaim_ValidToken_Success(t *testing.T) {
 	user := &model.AuthUser{Email: "test@example.com", IsActive: true, IsVerified: true}
-	token, _ := utils.GenerateAccessToken(user)
+	token, _ := utils.GenerateAccessToken(user, "")
 
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
@@ -245,7 +500,7 @@ func TestAuthenticateUserFromClaim_ValidToken_Success(t *testing.T) {
 
 func TestAuthenticateUserFromClaim_UserNotVerified_ReturnsError(t *testing.T) {
 	user := &model.AuthUser{Email: "test@example.com", IsActive: true, IsVerified: false}
-	token, _ := utils.GenerateAccessToken(user)
+	token, _ := utils.GenerateAccessToken(user, "")
 
 	uc := NewAuthUsecase(
 		&mockAuthRepo{
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"archive/zip"
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"packages/servicetoken"
+	"path/filepath"
+	"time"
+)
+
+// DataRequestProcessor carries out data exports and account deletions once they are due. Each
+// service is retried on its own with exponential back-off, so one being down does not redo the
+// work of the others.
+type DataRequestProcessor struct {
+	dataRepo  repository.DataRequestRepository
+	steps     map[string]repository.UserDataClient
+	exportDir string
+	now       func() time.Time
+}
+
+// NewDataRequestProcessor writes finished exports as ZIP files under exportDir.
+func NewDataRequestProcessor(dataRepo repository.DataRequestRepository, steps []repository.UserDataClient, exportDir string) *DataRequestProcessor {
+	byService := make(map[string]repository.UserDataClient, len(steps))
+	for _, step := range steps {
+		byService[step.Service()] = step
+	}
+	return &DataRequestProcessor{
+		dataRepo:  dataRepo,
+		steps:     byService,
+		exportDir: exportDir,
+		now:       time.Now,
+	}
+}
+
+// Run processes due requests and removes expired exports until ctx is cancelled.
+func (p *DataRequestProcessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(constant.DataRequestPollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.ProcessDue(ctx)
+		p.PurgeExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue works on one batch of due requests and returns how many of them are over.
+func (p *DataRequestProcessor) ProcessDue(ctx context.Context) int {
+	reqs, err := p.dataRepo.ClaimDue(ctx, p.now(), constant.DataRequestBatchSize)
+	if err != nil {
+		log.Println("claim data requests failed:", err)
+		return 0
+	}
+
+	finished := 0
+	for i := range reqs {
+		if p.process(ctx, &reqs[i]) {
+			finished++
+		}
+	}
+	return finished
+}
+
+// process runs the steps still pending, then either reschedules the request or finishes it.
+func (p *DataRequestProcessor) process(ctx context.Context, req *model.DataRequest) bool {
+	for i := range req.Steps {
+		step := &req.Steps[i]
+		if step.Status != constant.DataStepStatusPending {
+			continue
+		}
+		p.runStep(ctx, req, step)
+		// the account has to be locked before anything else of it goes away
+		if req.Kind == constant.DataRequestKindDeletion && step.Service == servicetoken.AuthService {
+			if step.Status == constant.DataStepStatusFailed {
+				p.finish(ctx, req, true)
+				return true
+			}
+			if step.Status == constant.DataStepStatusPending {
+				break
+			}
+		}
+	}
+
+	attempts, pending, failed := 0, false, false
+	for _, step := range req.Steps {
+		switch step.Status {
+		case constant.DataStepStatusPending:
+			pending = true
+			attempts = max(attempts, step.Attempts)
+		case constant.DataStepStatusFailed:
+			failed = true
+		}
+	}
+	if pending {
+		if err := p.dataRepo.Reschedule(ctx, req.ID, p.now().Add(dataRequestBackoff(attempts))); err != nil {
+			log.Println("reschedule data request failed:", err)
+		}
+		return false
+	}
+
+	p.finish(ctx, req, failed)
+	return true
+}
+
+func (p *DataRequestProcessor) runStep(ctx context.Context, req *model.DataRequest, step *model.DataRequestStep) {
+	var err error
+	client, ok := p.steps[step.Service]
+	switch {
+	case !ok:
+		err = fmt.Errorf("unknown service %s", step.Service)
+	case req.Kind == constant.DataRequestKindExport:
+		var data json.RawMessage
+		if data, err = client.Export(ctx, req.UserID); err == nil {
+			step.Data = string(data)
+		}
+	default:
+		err = client.Erase(ctx, req.UserID)
+	}
+
+	if err == nil {
+		now := p.now()
+		step.Status = constant.DataStepStatusDone
+		step.LastError = ""
+		step.CompletedAt = &now
+	} else {
+		step.Attempts++
+		step.LastError = truncate(err.Error(), 255)
+		if step.Attempts >= constant.DataRequestMaxAttempts {
+			log.Printf("%s of data request %s gave up on %s after %d attempts: %v",
+				req.Kind, req.RequestID, step.Service, step.Attempts, err)
+			step.Status = constant.DataStepStatusFailed
+		}
+	}
+	if err := p.dataRepo.UpdateStep(ctx, step); err != nil {
+		log.Println("update data request step failed:", err)
+	}
+}
+
+// finish completes the request, writing the archive of an export. An export where some
+// service gave up fails as a whole rather than handing out partial data.
+func (p *DataRequestProcessor) finish(ctx context.Context, req *model.DataRequest, failed bool) {
+	now := p.now()
+	req.Status = constant.DataRequestStatusCompleted
+	if failed {
+		req.Status = constant.DataRequestStatusFailed
+	}
+	req.CompletedAt = &now
+
+	if req.Kind == constant.DataRequestKindExport && !failed {
+		path, err := p.writeArchive(req)
+		if err != nil {
+			log.Printf("write archive of data request %s failed: %v", req.RequestID, err)
+			req.Status = constant.DataRequestStatusFailed
+		} else {
+			expiresAt := now.Add(constant.DataExportTTL)
+			req.ArchivePath = path
+			req.ExpiresAt = &expiresAt
+		}
+	}
+
+	if err := p.dataRepo.Finish(ctx, req); err != nil {
+		log.Println("finish data request failed:", err)
+	}
+}
+
+// writeArchive puts the data of every service in its own JSON file of a ZIP. The file is written
+// next to its destination first, so a download never sees it half written.
+func (p *DataRequestProcessor) writeArchive(req *model.DataRequest) (string, error) {
+	if err := os.MkdirAll(p.exportDir, 0o700); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(p.exportDir, ".export-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	for _, step := range req.Steps {
+		w, err := zw.Create(step.Service + ".json")
+		if err != nil {
+			tmp.Close()
+			return "", err
+		}
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(step.Data), "", "  "); err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("%s: %w", step.Service, err)
+		}
+		if _, err := w.Write(pretty.Bytes()); err != nil {
+			tmp.Close()
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(p.exportDir, req.RequestID+".zip")
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// PurgeExpired removes the archives of exports that can no longer be downloaded.
+func (p *DataRequestProcessor) PurgeExpired(ctx context.Context) {
+	reqs, err := p.dataRepo.ListExpiredExports(ctx, p.now())
+	if err != nil {
+		log.Println("list expired data exports failed:", err)
+		return
+	}
+	for _, req := range reqs {
+		if req.ArchivePath != "" {
+			if err := os.Remove(req.ArchivePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("remove archive of data request %s failed: %v", req.RequestID, err)
+				continue
+			}
+		}
+		if err := p.dataRepo.MarkExpired(ctx, req.ID); err != nil {
+			log.Println("mark data export expired failed:", err)
+		}
+	}
+}
+
+func dataRequestBackoff(attempts int) time.Duration {
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < constant.DataRequestMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, constant.DataRequestMaxBackoff)
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type DataRequestUsecase struct {
+	authRepo repository.AuthRepository
+	dataRepo repository.DataRequestRepository
+	services []string
+	now      func() time.Time
+}
+
+// NewDataRequestUsecase lets users export their data and delete their account. A request gets
+// one step per client in steps, run in that order by the DataRequestProcessor.
+func NewDataRequestUsecase(authRepo repository.AuthRepository, dataRepo repository.DataRequestRepository, steps []repository.UserDataClient) *DataRequestUsecase {
+	services := make([]string, 0, len(steps))
+	for _, step := range steps {
+		services = append(services, step.Service())
+	}
+	return &DataRequestUsecase{
+		authRepo: authRepo,
+		dataRepo: dataRepo,
+		services: services,
+		now:      time.Now,
+	}
+}
+
+// RequestExport queues the collection of the data of the user from every service.
+func (u *DataRequestUsecase) RequestExport(ctx context.Context, userID uint) (*dto.DataRequestResponse, error) {
+	return u.create(ctx, userID, constant.DataRequestKindExport, u.now())
+}
+
+// RequestDeletion schedules the deletion of the account after checking the current password.
+// Nothing happens before the grace period is over; until then the user can still log in and
+// cancel.
+func (u *DataRequestUsecase) RequestDeletion(ctx context.Context, userID uint, password string) (*dto.DataRequestResponse, error) {
+	user, err := u.authRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, errors.New(constant.ErrInvalidCredentials)
+	}
+	return u.create(ctx, userID, constant.DataRequestKindDeletion, u.now().Add(constant.AccountDeletionGracePeriod))
+}
+
+func (u *DataRequestUsecase) create(ctx context.Context, userID uint, kind string, dueAt time.Time) (*dto.DataRequestResponse, error) {
+	active, err := u.dataRepo.GetActive(ctx, userID, kind)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	if active != nil {
+		return nil, errors.New(constant.ErrDataRequestPending)
+	}
+
+	requestID, err := utils.GenerateRandomID()
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	req := &model.DataRequest{
+		RequestID: requestID,
+		UserID:    userID,
+		Kind:      kind,
+		Status:    constant.DataRequestStatusPending,
+		DueAt:     dueAt,
+	}
+	for _, service := range u.services {
+		req.Steps = append(req.Steps, model.DataRequestStep{
+			RequestID: requestID,
+			Service:   service,
+			Status:    constant.DataStepStatusPending,
+		})
+	}
+	if err := u.dataRepo.Create(ctx, req); err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := dataRequestResponse(req)
+	return &res, nil
+}
+
+// CancelDeletion stops a deletion still in its grace period.
+func (u *DataRequestUsecase) CancelDeletion(ctx context.Context, userID uint) error {
+	req, err := u.dataRepo.GetActive(ctx, userID, constant.DataRequestKindDeletion)
+	if err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	if req == nil || req.Status != constant.DataRequestStatusPending {
+		return errors.New(constant.ErrDataRequestNotFound)
+	}
+
+	if err := u.dataRepo.Cancel(ctx, req.RequestID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the grace period ran out in the meantime
+			return errors.New(constant.ErrDataRequestNotFound)
+		}
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+// List returns the latest requests of the user and how far each service got.
+func (u *DataRequestUsecase) List(ctx context.Context, userID uint) ([]dto.DataRequestResponse, error) {
+	reqs, err := u.dataRepo.ListByUser(ctx, userID, constant.DataRequestListLimit)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := make([]dto.DataRequestResponse, 0, len(reqs))
+	for i := range reqs {
+		res = append(res, dataRequestResponse(&reqs[i]))
+	}
+	return res, nil
+}
+
+// ExportArchive returns the path of the ZIP of a completed export of the user. Exports of other
+// users are reported as not found.
+func (u *DataRequestUsecase) ExportArchive(ctx context.Context, userID uint, requestID string) (string, error) {
+	req, err := u.dataRepo.GetByRequestID(ctx, requestID)
+	if err != nil {
+		return "", errors.New(constant.ErrInternalServer)
+	}
+	if req == nil || req.UserID != userID || req.Kind != constant.DataRequestKindExport {
+		return "", errors.New(constant.ErrDataRequestNotFound)
+	}
+	if req.Status != constant.DataRequestStatusCompleted || req.ArchivePath == "" ||
+		req.ExpiresAt == nil || !req.ExpiresAt.After(u.now()) {
+		return "", errors.New(constant.ErrExportNotReady)
+	}
+	return req.ArchivePath, nil
+}
+
+func dataRequestResponse(req *model.DataRequest) dto.DataRequestResponse {
+	res := dto.DataRequestResponse{
+		ID:          req.RequestID,
+		Kind:        req.Kind,
+		Status:      req.Status,
+		CreatedAt:   req.CreatedAt,
+		CompletedAt: req.CompletedAt,
+		Steps:       make([]dto.DataRequestStepResponse, 0, len(req.Steps)),
+	}
+	if req.Kind == constant.DataRequestKindDeletion && req.Status == constant.DataRequestStatusPending {
+		dueAt := req.DueAt
+		res.ScheduledFor = &dueAt
+	}
+	if req.Kind == constant.DataRequestKindExport && req.Status == constant.DataRequestStatusCompleted {
+		res.ExpiresAt = req.ExpiresAt
+		res.DownloadURL = fmt.Sprintf(constant.DataExportDownloadURL, req.RequestID)
+	}
+	for _, step := range req.Steps {
+		res.Steps = append(res.Steps, dto.DataRequestStepResponse{
+			Service:     step.Service,
+			Status:      step.Status,
+			CompletedAt: step.CompletedAt,
+		})
+	}
+	return res
+}
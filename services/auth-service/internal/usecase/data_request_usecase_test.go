@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"archive/zip"
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"packages/servicetoken"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockDataRequestRepo struct {
+	active      *model.DataRequest
+	created     *model.DataRequest
+	due         []model.DataRequest
+	stepUpdates []model.DataRequestStep
+	rescheduled map[uint]time.Time
+	finished    []model.DataRequest
+	cancelled   []string
+}
+
+func (m *mockDataRequestRepo) Create(_ context.Context, req *model.DataRequest) error {
+	m.created = req
+	return nil
+}
+
+func (m *mockDataRequestRepo) GetActive(_ context.Context, _ uint, kind string) (*model.DataRequest, error) {
+	if m.active != nil && m.active.Kind == kind {
+		return m.active, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDataRequestRepo) GetByRequestID(_ context.Context, requestID string) (*model.DataRequest, error) {
+	if m.active != nil && m.active.RequestID == requestID {
+		return m.active, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDataRequestRepo) ListByUser(_ context.Context, _ uint, _ int) ([]model.DataRequest, error) {
+	return nil, nil
+}
+
+func (m *mockDataRequestRepo) Cancel(_ context.Context, requestID string) error {
+	m.cancelled = append(m.cancelled, requestID)
+	return nil
+}
+
+func (m *mockDataRequestRepo) ClaimDue(_ context.Context, _ time.Time, _ int) ([]model.DataRequest, error) {
+	return m.due, nil
+}
+
+func (m *mockDataRequestRepo) UpdateStep(_ context.Context, step *model.DataRequestStep) error {
+	m.stepUpdates = append(m.stepUpdates, *step)
+	return nil
+}
+
+func (m *mockDataRequestRepo) Reschedule(_ context.Context, id uint, dueAt time.Time) error {
+	if m.rescheduled == nil {
+		m.rescheduled = map[uint]time.Time{}
+	}
+	m.rescheduled[id] = dueAt
+	return nil
+}
+
+func (m *mockDataRequestRepo) Finish(_ context.Context, req *model.DataRequest) error {
+	m.finished = append(m.finished, *req)
+	return nil
+}
+
+func (m *mockDataRequestRepo) ListExpiredExports(_ context.Context, _ time.Time) ([]model.DataRequest, error) {
+	return nil, nil
+}
+
+func (m *mockDataRequestRepo) MarkExpired(_ context.Context, _ uint) error { return nil }
+
+func (m *mockDataRequestRepo) EraseAuthData(_ context.Context, _ uint, _ string, _ time.Time) error {
+	return nil
+}
+
+type fakeDataClient struct {
+	service string
+	data    string
+	err     error
+	calls   int
+}
+
+func (f *fakeDataClient) Service() string { return f.service }
+
+func (f *fakeDataClient) Export(_ context.Context, _ uint) (json.RawMessage, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return json.RawMessage(f.data), nil
+}
+
+func (f *fakeDataClient) Erase(_ context.Context, _ uint) error {
+	f.calls++
+	return f.err
+}
+
+func dataRequest(kind string, services ...string) model.DataRequest {
+	req := model.DataRequest{RequestID: "req1", UserID: 7, Kind: kind, Status: constant.DataRequestStatusProcessing}
+	req.ID = 1
+	for _, s := range services {
+		req.Steps = append(req.Steps, model.DataRequestStep{RequestID: "req1", Service: s, Status: constant.DataStepStatusPending})
+	}
+	return req
+}
+
+// ---------------- TEST CASES ----------------
+
+func TestRequestDeletion_WrongPassword(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Secret1!"), bcrypt.MinCost)
+	authRepo := &mockAuthRepo{getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) {
+		return &model.AuthUser{UserID: 7, PasswordHash: string(hash)}, nil
+	}}
+	dataRepo := &mockDataRequestRepo{}
+	uc := NewDataRequestUsecase(authRepo, dataRepo, nil)
+
+	_, err := uc.RequestDeletion(context.Background(), 7, "wrong")
+
+	assert.EqualError(t, err, constant.ErrInvalidCredentials)
+	assert.Nil(t, dataRepo.created)
+}
+
+func TestRequestDeletion_ScheduledAfterGracePeriod(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Secret1!"), bcrypt.MinCost)
+	authRepo := &mockAuthRepo{getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) {
+		return &model.AuthUser{UserID: 7, PasswordHash: string(hash)}, nil
+	}}
+	dataRepo := &mockDataRequestRepo{}
+	steps := []*fakeDataClient{{service: servicetoken.AuthService}, {service: servicetoken.UserService}}
+	uc := NewDataRequestUsecase(authRepo, dataRepo, []repository.UserDataClient{steps[0], steps[1]})
+	uc.now = func() time.Time { return fixedNow }
+
+	res, err := uc.RequestDeletion(context.Background(), 7, "Secret1!")
+
+	require.NoError(t, err)
+	require.NotNil(t, dataRepo.created)
+	assert.Equal(t, fixedNow.Add(constant.AccountDeletionGracePeriod), dataRepo.created.DueAt)
+	assert.Equal(t, constant.DataRequestStatusPending, dataRepo.created.Status)
+	require.Len(t, res.Steps, 2)
+	assert.Equal(t, servicetoken.AuthService, res.Steps[0].Service)
+	assert.Equal(t, constant.DataStepStatusPending, res.Steps[1].Status)
+	require.NotNil(t, res.ScheduledFor)
+}
+
+func TestRequestExport_AlreadyPending(t *testing.T) {
+	dataRepo := &mockDataRequestRepo{active: &model.DataRequest{Kind: constant.DataRequestKindExport, Status: constant.DataRequestStatusProcessing}}
+	uc := NewDataRequestUsecase(&mockAuthRepo{}, dataRepo, nil)
+
+	_, err := uc.RequestExport(context.Background(), 7)
+
+	assert.EqualError(t, err, constant.ErrDataRequestPending)
+}
+
+func TestCancelDeletion_AfterGracePeriod_NotFound(t *testing.T) {
+	dataRepo := &mockDataRequestRepo{active: &model.DataRequest{RequestID: "req1", Kind: constant.DataRequestKindDeletion, Status: constant.DataRequestStatusProcessing}}
+	uc := NewDataRequestUsecase(&mockAuthRepo{}, dataRepo, nil)
+
+	err := uc.CancelDeletion(context.Background(), 7)
+
+	assert.EqualError(t, err, constant.ErrDataRequestNotFound)
+	assert.Empty(t, dataRepo.cancelled)
+}
+
+func TestExportArchive_OfAnotherUser_NotFound(t *testing.T) {
+	expiresAt := fixedNow.Add(time.Hour)
+	dataRepo := &mockDataRequestRepo{active: &model.DataRequest{RequestID: "req1", UserID: 8, Kind: constant.DataRequestKindExport,
+		Status: constant.DataRequestStatusCompleted, ArchivePath: "exports/req1.zip", ExpiresAt: &expiresAt}}
+	uc := NewDataRequestUsecase(&mockAuthRepo{}, dataRepo, nil)
+	uc.now = func() time.Time { return fixedNow }
+
+	_, err := uc.ExportArchive(context.Background(), 7, "req1")
+	assert.EqualError(t, err, constant.ErrDataRequestNotFound)
+
+	path, err := uc.ExportArchive(context.Background(), 8, "req1")
+	assert.NoError(t, err)
+	assert.Equal(t, "exports/req1.zip", path)
+}
+
+func TestDataRequestProcessor_Export_RetriesThenWritesArchive(t *testing.T) {
+	auth := &fakeDataClient{service: servicetoken.AuthService, data: `{"account":{"email":"a@b.com"}}`}
+	chat := &fakeDataClient{service: servicetoken.ChatService, err: errors.New("chat-service answered 503")}
+	req := dataRequest(constant.DataRequestKindExport, auth.service, chat.service)
+	dataRepo := &mockDataRequestRepo{due: []model.DataRequest{req}}
+	p := NewDataRequestProcessor(dataRepo, []repository.UserDataClient{auth, chat}, t.TempDir())
+	p.now = func() time.Time { return fixedNow }
+
+	// chat-service is down: the request waits, keeping what auth-service returned
+	assert.Equal(t, 0, p.ProcessDue(context.Background()))
+	assert.Equal(t, fixedNow.Add(time.Minute), dataRepo.rescheduled[1])
+	assert.Empty(t, dataRepo.finished)
+
+	// next round only asks chat-service again
+	req = dataRequest(constant.DataRequestKindExport, auth.service, chat.service)
+	req.Steps[0] = dataRepo.stepUpdates[0]
+	req.Steps[1] = dataRepo.stepUpdates[1]
+	dataRepo.due = []model.DataRequest{req}
+	chat.err, chat.data = nil, `{"messages":[]}`
+
+	assert.Equal(t, 1, p.ProcessDue(context.Background()))
+	assert.Equal(t, 1, auth.calls)
+	require.Len(t, dataRepo.finished, 1)
+	done := dataRepo.finished[0]
+	assert.Equal(t, constant.DataRequestStatusCompleted, done.Status)
+	assert.Equal(t, fixedNow.Add(constant.DataExportTTL), *done.ExpiresAt)
+
+	zr, err := zip.OpenReader(done.ArchivePath)
+	require.NoError(t, err)
+	defer zr.Close()
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	assert.Contains(t, files["auth-service.json"], `"email": "a@b.com"`)
+	assert.Contains(t, files, "chat-service.json")
+}
+
+func TestDataRequestProcessor_Deletion_WaitsForAuthStep(t *testing.T) {
+	auth := &fakeDataClient{service: servicetoken.AuthService, err: errors.New("db down")}
+	user := &fakeDataClient{service: servicetoken.UserService}
+	dataRepo := &mockDataRequestRepo{due: []model.DataRequest{dataRequest(constant.DataRequestKindDeletion, auth.service, user.service)}}
+	p := NewDataRequestProcessor(dataRepo, []repository.UserDataClient{auth, user}, t.TempDir())
+
+	p.ProcessDue(context.Background())
+
+	assert.Equal(t, 1, auth.calls)
+	assert.Equal(t, 0, user.calls, "nothing is erased elsewhere before the account is locked")
+	assert.Contains(t, dataRepo.rescheduled, uint(1))
+}
+
+func TestDataRequestProcessor_Deletion_StepGivesUp(t *testing.T) {
+	auth := &fakeDataClient{service: servicetoken.AuthService}
+	chat := &fakeDataClient{service: servicetoken.ChatService, err: errors.New("chat-service answered 500")}
+	req := dataRequest(constant.DataRequestKindDeletion, auth.service, chat.service)
+	req.Steps[1].Attempts = constant.DataRequestMaxAttempts - 1
+	dataRepo := &mockDataRequestRepo{due: []model.DataRequest{req}}
+	p := NewDataRequestProcessor(dataRepo, []repository.UserDataClient{auth, chat}, t.TempDir())
+
+	assert.Equal(t, 1, p.ProcessDue(context.Background()))
+
+	require.Len(t, dataRepo.finished, 1)
+	assert.Equal(t, constant.DataRequestStatusFailed, dataRepo.finished[0].Status)
+	assert.Equal(t, constant.DataStepStatusDone, dataRepo.finished[0].Steps[0].Status)
+	assert.Equal(t, constant.DataStepStatusFailed, dataRepo.finished[0].Steps[1].Status)
+	assert.Equal(t, "chat-service answered 500", dataRepo.finished[0].Steps[1].LastError)
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type EmailChangeUsecase struct {
+	authRepo   repository.AuthRepository
+	changeRepo repository.EmailChangeRepository
+	userClient repository.UserClient
+	now        func() time.Time
+}
+
+// NewEmailChangeUsecase moves accounts to a new email address in auth-service and user-service.
+func NewEmailChangeUsecase(authRepo repository.AuthRepository, changeRepo repository.EmailChangeRepository, userClient repository.UserClient) *EmailChangeUsecase {
+	return &EmailChangeUsecase{
+		authRepo:   authRepo,
+		changeRepo: changeRepo,
+		userClient: userClient,
+		now:        time.Now,
+	}
+}
+
+// Request starts a change after checking the current password. A confirmation link is mailed
+// to the new address and a notice with a revert link to the current one; nothing changes
+// until the new address confirms.
+func (u *EmailChangeUsecase) Request(ctx context.Context, userID uint, newEmail, password string) error {
+	user, err := u.authRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return errors.New(constant.ErrUserNotFound)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return errors.New(constant.ErrInvalidCredentials)
+	}
+	if newEmail == user.Email {
+		return errors.New(constant.ErrSameEmail)
+	}
+	if err := u.ensureEmailFree(ctx, newEmail); err != nil {
+		return err
+	}
+
+	changeID, err := utils.GenerateRandomID()
+	if err != nil {
+		return errors.New(constant.ErrGenerateToken)
+	}
+	now := u.now()
+	change := &model.EmailChange{
+		ChangeID:        changeID,
+		UserID:          user.UserID,
+		OldEmail:        user.Email,
+		NewEmail:        newEmail,
+		Status:          constant.EmailChangeStatusPending,
+		ExpiresAt:       now.Add(constant.EmailChangeTTL),
+		RevertibleUntil: now.Add(constant.EmailChangeTTL + constant.EmailChangeRevertWindow),
+	}
+
+	events, err := emailChangeOutboxEvents(change)
+	if err != nil {
+		return err
+	}
+	if err := u.changeRepo.CreatePending(ctx, change, events); err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+func emailChangeOutboxEvents(change *model.EmailChange) ([]model.OutboxEvent, error) {
+	confirmToken, err := utils.GenerateEmailChangeToken(change, constant.TokenTypeEmailChange)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateToken)
+	}
+	revertToken, err := utils.GenerateEmailChangeToken(change, constant.TokenTypeEmailRevert)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateToken)
+	}
+
+	confirm, err := mailOutboxEvent(change.NewEmail, constant.EventTypeEmailChange, map[string]string{"token": confirmToken})
+	if err != nil {
+		return nil, err
+	}
+	notice, err := mailOutboxEvent(change.OldEmail, constant.EventTypeEmailNotice, map[string]string{
+		"token":    revertToken,
+		"newEmail": change.NewEmail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []model.OutboxEvent{*confirm, *notice}, nil
+}
+
+// Confirm applies a change from the link mailed to the new address. user-service is updated
+// first and put back if auth-service cannot follow, the same order sign-up uses. All sessions
+// of the user are revoked, so tokens carrying the old email stop working.
+func (u *EmailChangeUsecase) Confirm(ctx context.Context, token string) error {
+	change, err := u.changeFromToken(ctx, token, constant.TokenTypeEmailChange)
+	if err != nil {
+		return err
+	}
+	now := u.now()
+	if change.Status != constant.EmailChangeStatusPending || !change.ExpiresAt.After(now) {
+		return errors.New(constant.ErrInvalidToken)
+	}
+	if err := u.ensureEmailFree(ctx, change.NewEmail); err != nil {
+		return err
+	}
+
+	change.Status = constant.EmailChangeStatusConfirmed
+	change.ConfirmedAt = &now
+	return u.switchEmail(ctx, change, constant.EmailChangeStatusPending, change.OldEmail, change.NewEmail, now)
+}
+
+// Revert handles the link mailed to the old address. A pending change is cancelled; a confirmed
+// one is undone within the revert window, signing out every device of the account.
+func (u *EmailChangeUsecase) Revert(ctx context.Context, token string) error {
+	change, err := u.changeFromToken(ctx, token, constant.TokenTypeEmailRevert)
+	if err != nil {
+		return err
+	}
+	now := u.now()
+
+	switch {
+	case change.Status == constant.EmailChangeStatusPending:
+		if err := u.changeRepo.Cancel(ctx, change.ChangeID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(constant.ErrInvalidToken)
+			}
+			return errors.New(constant.ErrInternalServer)
+		}
+		return nil
+	case change.Status == constant.EmailChangeStatusConfirmed && change.RevertibleUntil.After(now):
+		if err := u.ensureEmailFree(ctx, change.OldEmail); err != nil {
+			return err
+		}
+		change.Status = constant.EmailChangeStatusReverted
+		change.RevertedAt = &now
+		return u.switchEmail(ctx, change, constant.EmailChangeStatusConfirmed, change.NewEmail, change.OldEmail, now)
+	default:
+		return errors.New(constant.ErrInvalidToken)
+	}
+}
+
+func (u *EmailChangeUsecase) changeFromToken(ctx context.Context, token, tokenType string) (*model.EmailChange, error) {
+	claims, err := utils.ValidateToken(token)
+	if err != nil || claims.TokenType != tokenType || claims.ID == "" {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+
+	change, err := u.changeRepo.GetByChangeID(ctx, claims.ID)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	if change == nil || change.UserID != claims.UserID {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+	return change, nil
+}
+
+func (u *EmailChangeUsecase) ensureEmailFree(ctx context.Context, email string) error {
+	existing, err := u.authRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(constant.ErrGetUserFailed)
+	}
+	if existing != nil {
+		return errors.New(constant.ErrEmailAlreadyExists)
+	}
+	return nil
+}
+
+func (u *EmailChangeUsecase) switchEmail(ctx context.Context, change *model.EmailChange, fromStatus, from, to string, at time.Time) error {
+	if err := u.userClient.UpdateEmail(ctx, change.UserID, to); err != nil {
+		if err.Error() == constant.ErrEmailAlreadyExists {
+			return err
+		}
+		return errors.New(constant.ErrUpdateUser)
+	}
+
+	if err := u.changeRepo.Apply(ctx, change, fromStatus, from, to, at); err != nil {
+		if rollbackErr := u.userClient.UpdateEmail(ctx, change.UserID, from); rollbackErr != nil {
+			log.Printf("restoring email of user profile %d failed: %v", change.UserID, rollbackErr)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(constant.ErrInvalidToken)
+		}
+		return errors.New(constant.ErrUpdateUser)
+	}
+	return nil
+}
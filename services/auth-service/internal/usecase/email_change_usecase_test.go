@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockEmailChangeRepo struct {
+	changes  map[string]*model.EmailChange
+	events   []model.OutboxEvent
+	applied  []string // "from->to"
+	applyErr error
+}
+
+func newMockEmailChangeRepo() *mockEmailChangeRepo {
+	return &mockEmailChangeRepo{changes: map[string]*model.EmailChange{}}
+}
+
+func (m *mockEmailChangeRepo) CreatePending(_ context.Context, change *model.EmailChange, events []model.OutboxEvent) error {
+	m.changes[change.ChangeID] = change
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *mockEmailChangeRepo) GetByChangeID(_ context.Context, changeID string) (*model.EmailChange, error) {
+	if c, ok := m.changes[changeID]; ok {
+		cp := *c
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (m *mockEmailChangeRepo) Cancel(_ context.Context, changeID string) error {
+	c, ok := m.changes[changeID]
+	if !ok || c.Status != constant.EmailChangeStatusPending {
+		return gorm.ErrRecordNotFound
+	}
+	c.Status = constant.EmailChangeStatusCancelled
+	return nil
+}
+
+func (m *mockEmailChangeRepo) Apply(_ context.Context, change *model.EmailChange, fromStatus, fromEmail, toEmail string, _ time.Time) error {
+	if m.applyErr != nil {
+		return m.applyErr
+	}
+	if m.changes[change.ChangeID].Status != fromStatus {
+		return gorm.ErrRecordNotFound
+	}
+	m.changes[change.ChangeID] = change
+	m.applied = append(m.applied, fromEmail+"->"+toEmail)
+	return nil
+}
+
+type emailChangeFixture struct {
+	uc         *EmailChangeUsecase
+	changeRepo *mockEmailChangeRepo
+	profile    []string // emails sent to user-service, in order
+}
+
+func newEmailChangeFixture(t *testing.T) *emailChangeFixture {
+	hash, err := bcrypt.GenerateFromPassword([]byte("Secret1!"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	f := &emailChangeFixture{changeRepo: newMockEmailChangeRepo()}
+	authRepo := &mockAuthRepo{
+		getByUserIDFn: func(ctx context.Context, userID uint) (*model.AuthUser, error) {
+			return &model.AuthUser{UserID: userID, Email: "old@example.com", PasswordHash: string(hash)}, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (*model.AuthUser, error) {
+			if email == "taken@example.com" {
+				return &model.AuthUser{UserID: 9, Email: email}, nil
+			}
+			return nil, gormErrNotFound()
+		},
+	}
+	userClient := &mockUserClient{
+		updateEmailFn: func(ctx context.Context, userID uint, email string) error {
+			f.profile = append(f.profile, email)
+			return nil
+		},
+	}
+	f.uc = NewEmailChangeUsecase(authRepo, f.changeRepo, userClient)
+	return f
+}
+
+// request starts a change to new@example.com and returns the confirm and revert tokens mailed for it.
+func (f *emailChangeFixture) request(t *testing.T) (string, string) {
+	err := f.uc.Request(context.Background(), 1, "new@example.com", "Secret1!")
+	assert.NoError(t, err)
+	assert.Len(t, f.changeRepo.events, 2)
+
+	tokens := map[string]string{}
+	for _, e := range f.changeRepo.events {
+		var event dto.MailEvent
+		assert.NoError(t, json.Unmarshal([]byte(e.Payload), &event))
+		tokens[event.Email] = event.Data["token"]
+	}
+	return tokens["new@example.com"], tokens["old@example.com"]
+}
+
+// ---------------- TESTS ----------------
+
+func TestEmailChangeRequest_MailsBothAddresses(t *testing.T) {
+	f := newEmailChangeFixture(t)
+
+	confirmToken, revertToken := f.request(t)
+
+	assert.Equal(t, constant.EventTypeEmailChange, f.changeRepo.events[0].EventType)
+	assert.Equal(t, "new@example.com", f.changeRepo.events[0].Key)
+	assert.Equal(t, constant.EventTypeEmailNotice, f.changeRepo.events[1].EventType)
+	assert.Contains(t, f.changeRepo.events[1].Payload, `"newEmail":"new@example.com"`)
+
+	claims, err := utils.ValidateToken(confirmToken)
+	assert.NoError(t, err)
+	assert.Equal(t, constant.TokenTypeEmailChange, claims.TokenType)
+	assert.True(t, claims.IsSingleUseToken())
+	claims, err = utils.ValidateToken(revertToken)
+	assert.NoError(t, err)
+	assert.Equal(t, constant.TokenTypeEmailRevert, claims.TokenType)
+	assert.Empty(t, f.profile, "nothing changes before confirmation")
+}
+
+func TestEmailChangeRequest_Refused(t *testing.T) {
+	tests := []struct {
+		name     string
+		newEmail string
+		password string
+		wantErr  string
+	}{
+		{"wrong password", "new@example.com", "nope", constant.ErrInvalidCredentials},
+		{"same email", "old@example.com", "Secret1!", constant.ErrSameEmail},
+		{"email taken", "taken@example.com", "Secret1!", constant.ErrEmailAlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmailChangeFixture(t)
+
+			err := f.uc.Request(context.Background(), 1, tt.newEmail, tt.password)
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, f.changeRepo.changes)
+		})
+	}
+}
+
+func TestEmailChangeConfirm_UpdatesBothServices(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	confirmToken, _ := f.request(t)
+
+	err := f.uc.Confirm(context.Background(), confirmToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new@example.com"}, f.profile)
+	assert.Equal(t, []string{"old@example.com->new@example.com"}, f.changeRepo.applied)
+
+	// the link works only once
+	err = f.uc.Confirm(context.Background(), confirmToken)
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+}
+
+func TestEmailChangeConfirm_RevertTokenRefused(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	_, revertToken := f.request(t)
+
+	err := f.uc.Confirm(context.Background(), revertToken)
+
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+	assert.Empty(t, f.profile)
+}
+
+func TestEmailChangeConfirm_AuthUpdateFails_RestoresProfile(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	confirmToken, _ := f.request(t)
+	f.changeRepo.applyErr = errors.New("db down")
+
+	err := f.uc.Confirm(context.Background(), confirmToken)
+
+	assert.EqualError(t, err, constant.ErrUpdateUser)
+	assert.Equal(t, []string{"new@example.com", "old@example.com"}, f.profile)
+}
+
+func TestEmailChangeRevert_PendingIsCancelled(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	confirmToken, revertToken := f.request(t)
+
+	err := f.uc.Revert(context.Background(), revertToken)
+	assert.NoError(t, err)
+
+	err = f.uc.Confirm(context.Background(), confirmToken)
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+	assert.Empty(t, f.profile)
+}
+
+func TestEmailChangeRevert_ConfirmedIsUndone(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	confirmToken, revertToken := f.request(t)
+	assert.NoError(t, f.uc.Confirm(context.Background(), confirmToken))
+
+	err := f.uc.Revert(context.Background(), revertToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new@example.com", "old@example.com"}, f.profile)
+	assert.Equal(t, []string{"old@example.com->new@example.com", "new@example.com->old@example.com"}, f.changeRepo.applied)
+
+}
+
+func TestEmailChangeRevert_AfterWindow_ReturnsError(t *testing.T) {
+	f := newEmailChangeFixture(t)
+	confirmToken, revertToken := f.request(t)
+	assert.NoError(t, f.uc.Confirm(context.Background(), confirmToken))
+	f.uc.now = func() time.Time {
+		return time.Now().Add(constant.EmailChangeTTL + constant.EmailChangeRevertWindow + time.Hour)
+	}
+
+	err := f.uc.Revert(context.Background(), revertToken)
+
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+	assert.Equal(t, []string{"new@example.com"}, f.profile)
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"packages/policy"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ImpersonationUsecase struct {
+	authRepo  repository.AuthRepository
+	auditRepo repository.ImpersonationRepository
+	now       func() time.Time
+}
+
+// NewImpersonationUsecase lets admins act as a user for support, leaving an audit trail the user can read.
+func NewImpersonationUsecase(authRepo repository.AuthRepository, auditRepo repository.ImpersonationRepository) *ImpersonationUsecase {
+	return &ImpersonationUsecase{
+		authRepo:  authRepo,
+		auditRepo: auditRepo,
+		now:       time.Now,
+	}
+}
+
+// Start issues a short-lived impersonation token for the target user. Only plain users can be
+// impersonated, so the token can never carry more rights than a customer has. The start is
+// audited before the token is handed out.
+func (u *ImpersonationUsecase) Start(ctx context.Context, actorID, targetID uint, info dto.AttemptInfo) (*dto.ImpersonationResponse, error) {
+	if actorID == targetID {
+		return nil, errors.New(constant.ErrCannotImpersonate)
+	}
+
+	actor, err := u.authRepo.GetByUserID(ctx, actorID)
+	if err != nil {
+		return nil, errors.New(constant.ErrGetUserFailed)
+	}
+	// the role in the admin's token may be stale, check the stored one
+	if actor == nil || !actor.IsActive || !policy.Can(actor.Role, policy.UserImpersonate) {
+		return nil, errors.New(constant.ErrPermissionDenied)
+	}
+
+	target, err := u.authRepo.GetByUserID(ctx, targetID)
+	if err != nil {
+		return nil, errors.New(constant.ErrGetUserFailed)
+	}
+	if target == nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	if target.Role != policy.RoleUser {
+		return nil, errors.New(constant.ErrCannotImpersonate)
+	}
+	if !target.IsActive {
+		return nil, errors.New(constant.ErrUserNotActive)
+	}
+	if !target.IsVerified {
+		return nil, errors.New(constant.ErrUserNotVerified)
+	}
+
+	now := u.now()
+	expiresAt := now.Add(constant.ImpersonationTokenTTL)
+	token, err := utils.GenerateImpersonationToken(target, policy.Actor{UserID: actor.UserID, Email: actor.Email}, expiresAt)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateTokenFailed)
+	}
+
+	entry := model.ImpersonationAudit{
+		UserID:     target.UserID,
+		ActorID:    actor.UserID,
+		ActorEmail: actor.Email,
+		Action:     constant.ImpersonationActionStart,
+		IP:         info.IP,
+		UserAgent:  truncate(info.UserAgent, 255),
+		OccurredAt: now,
+	}
+	if err := u.auditRepo.Create(ctx, []model.ImpersonationAudit{entry}); err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	return &dto.ImpersonationResponse{
+		AccessToken: token,
+		ExpiresIn:   int(constant.ImpersonationTokenTTL.Seconds()),
+		UserID:      target.UserID,
+		Email:       target.Email,
+	}, nil
+}
+
+// RecordRequests stores the requests reported by the api-gateway. The user and the admin are
+// taken from each token after verifying it, so a forged "act" claim never reaches the log.
+// It returns how many requests were rejected.
+func (u *ImpersonationUsecase) RecordRequests(ctx context.Context, requests []dto.ImpersonatedRequest) (int, error) {
+	entries := make([]model.ImpersonationAudit, 0, len(requests))
+	rejected := 0
+	for _, req := range requests {
+		claims, err := utils.ValidateToken(req.Token, jwt.WithLeeway(constant.ImpersonationAuditLeeway))
+		if err != nil || claims.TokenType != constant.TokenTypeImpersonation || claims.Act == nil {
+			rejected++
+			continue
+		}
+
+		occurredAt := req.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = u.now()
+		}
+		entries = append(entries, model.ImpersonationAudit{
+			UserID:     claims.UserID,
+			ActorID:    claims.Act.UserID,
+			ActorEmail: claims.Act.Email,
+			Action:     constant.ImpersonationActionRequest,
+			Method:     truncate(req.Method, 10),
+			Path:       truncate(req.Path, 512),
+			Status:     req.Status,
+			IP:         truncate(req.IP, 45),
+			UserAgent:  truncate(req.UserAgent, 255),
+			OccurredAt: occurredAt,
+		})
+	}
+
+	if len(entries) == 0 {
+		return rejected, nil
+	}
+	if err := u.auditRepo.Create(ctx, entries); err != nil {
+		return rejected, errors.New(constant.ErrInternalServer)
+	}
+	return rejected, nil
+}
+
+// List returns the most recent impersonation entries concerning the user.
+func (u *ImpersonationUsecase) List(ctx context.Context, userID uint) ([]dto.ImpersonationAuditEntry, error) {
+	entries, err := u.auditRepo.ListByUser(ctx, userID, constant.ImpersonationAuditLimit)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	return auditEntries(entries), nil
+}
+
+func auditEntries(entries []model.ImpersonationAudit) []dto.ImpersonationAuditEntry {
+	res := make([]dto.ImpersonationAuditEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, dto.ImpersonationAuditEntry{
+			Action:     e.Action,
+			ActorID:    e.ActorID,
+			ActorEmail: e.ActorEmail,
+			Method:     e.Method,
+			Path:       e.Path,
+			Status:     e.Status,
+			IP:         e.IP,
+			OccurredAt: e.OccurredAt,
+		})
+	}
+	return res
+}
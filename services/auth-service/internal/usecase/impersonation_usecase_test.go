@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"packages/policy"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockImpersonationRepo struct {
+	entries []model.ImpersonationAudit
+}
+
+func (m *mockImpersonationRepo) Create(_ context.Context, entries []model.ImpersonationAudit) error {
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *mockImpersonationRepo) ListByUser(_ context.Context, userID uint, limit int) ([]model.ImpersonationAudit, error) {
+	var res []model.ImpersonationAudit
+	for _, e := range m.entries {
+		if e.UserID == userID && len(res) < limit {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func newImpersonationUsecase(users map[uint]*model.AuthUser) (*ImpersonationUsecase, *mockImpersonationRepo) {
+	authRepo := &mockAuthRepo{
+		getByUserIDFn: func(ctx context.Context, userID uint) (*model.AuthUser, error) {
+			return users[userID], nil
+		},
+	}
+	auditRepo := &mockImpersonationRepo{}
+	uc := NewImpersonationUsecase(authRepo, auditRepo)
+	uc.now = func() time.Time { return fixedNow }
+	return uc, auditRepo
+}
+
+func impersonationUsers() map[uint]*model.AuthUser {
+	return map[uint]*model.AuthUser{
+		1: {UserID: 1, Email: "admin@example.com", Role: constant.ADMIN_ROLE, IsActive: true, IsVerified: true},
+		2: {UserID: 2, Email: "user@example.com", Role: constant.USER_ROLE, IsActive: true, IsVerified: true},
+		3: {UserID: 3, Email: "mod@example.com", Role: constant.MODERATOR_ROLE, IsActive: true, IsVerified: true},
+	}
+}
+
+// ---------------- TESTS ----------------
+
+func TestImpersonationStart_IssuesTokenWithActClaim(t *testing.T) {
+	uc, auditRepo := newImpersonationUsecase(impersonationUsers())
+
+	res, err := uc.Start(context.Background(), 1, 2, dto.AttemptInfo{IP: "10.0.0.1", UserAgent: "support-console"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), res.UserID)
+	assert.Equal(t, int(constant.ImpersonationTokenTTL.Seconds()), res.ExpiresIn)
+
+	claims, err := utils.ValidateToken(res.AccessToken, jwt.WithoutClaimsValidation())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), claims.UserID)
+	assert.Equal(t, constant.TokenTypeImpersonation, claims.TokenType)
+	assert.Equal(t, &policy.Actor{UserID: 1, Email: "admin@example.com"}, claims.Act)
+	assert.True(t, claims.IsSingleUseToken(), "impersonation tokens must not be refreshable")
+
+	assert.Len(t, auditRepo.entries, 1)
+	assert.Equal(t, constant.ImpersonationActionStart, auditRepo.entries[0].Action)
+	assert.Equal(t, uint(1), auditRepo.entries[0].ActorID)
+	assert.Equal(t, "10.0.0.1", auditRepo.entries[0].IP)
+}
+
+func TestImpersonationStart_Refused(t *testing.T) {
+	users := impersonationUsers()
+	users[4] = &model.AuthUser{UserID: 4, Role: constant.USER_ROLE, IsActive: false, IsVerified: true}
+
+	tests := []struct {
+		name     string
+		actorID  uint
+		targetID uint
+		wantErr  string
+	}{
+		{"self", 1, 1, constant.ErrCannotImpersonate},
+		{"privileged target", 1, 3, constant.ErrCannotImpersonate},
+		{"inactive target", 1, 4, constant.ErrUserNotActive},
+		{"unknown target", 1, 99, constant.ErrUserNotFound},
+		{"actor without permission", 3, 2, constant.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, auditRepo := newImpersonationUsecase(users)
+
+			res, err := uc.Start(context.Background(), tt.actorID, tt.targetID, dto.AttemptInfo{})
+
+			assert.Nil(t, res)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, auditRepo.entries)
+		})
+	}
+}
+
+func TestImpersonationRecordRequests_TrustsOnlyVerifiedTokens(t *testing.T) {
+	users := impersonationUsers()
+	uc, auditRepo := newImpersonationUsecase(users)
+
+	token, err := utils.GenerateImpersonationToken(users[2], policy.Actor{UserID: 1, Email: "admin@example.com"}, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	accessToken, err := utils.GenerateAccessToken(users[2], "")
+	assert.NoError(t, err)
+
+	rejected, err := uc.RecordRequests(context.Background(), []dto.ImpersonatedRequest{
+		{Token: token, Method: "GET", Path: "/api/booking/me", Status: 200, OccurredAt: fixedNow},
+		{Token: accessToken, Method: "GET", Path: "/api/booking/me", Status: 200},
+		{Token: "forged", Method: "DELETE", Path: "/api/booking/1", Status: 401},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rejected)
+	assert.Len(t, auditRepo.entries, 1)
+	entry := auditRepo.entries[0]
+	assert.Equal(t, uint(2), entry.UserID)
+	assert.Equal(t, uint(1), entry.ActorID)
+	assert.Equal(t, constant.ImpersonationActionRequest, entry.Action)
+	assert.Equal(t, "/api/booking/me", entry.Path)
+	assert.Equal(t, fixedNow, entry.OccurredAt)
+}
+
+func TestImpersonationRecordRequests_NothingToStore(t *testing.T) {
+	uc, auditRepo := newImpersonationUsecase(impersonationUsers())
+
+	rejected, err := uc.RecordRequests(context.Background(), []dto.ImpersonatedRequest{{Token: "forged", Method: "GET", Path: "/api/booking/me"}})
+
+	assert.NoError(t, err, "the caller learns from the counts that nothing was recorded")
+	assert.Equal(t, 1, rejected)
+	assert.Empty(t, auditRepo.entries)
+}
+
+func TestImpersonationList_OnlyOwnEntries(t *testing.T) {
+	uc, auditRepo := newImpersonationUsecase(impersonationUsers())
+	auditRepo.entries = []model.ImpersonationAudit{
+		{UserID: 2, ActorID: 1, ActorEmail: "admin@example.com", Action: constant.ImpersonationActionStart},
+		{UserID: 5, ActorID: 1, Action: constant.ImpersonationActionStart},
+	}
+
+	entries, err := uc.List(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "admin@example.com", entries[0].ActorEmail)
+}
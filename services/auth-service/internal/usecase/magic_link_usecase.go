@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// loginLock tells whether logins to an account are locked, see AttemptUsecase.LoginLocked.
+type loginLock interface {
+	LoginLocked(ctx context.Context, email string) bool
+}
+
+type MagicLinkUsecase struct {
+	authRepo repository.AuthRepository
+	linkRepo repository.MagicLinkRepository
+	lock     loginLock
+	now      func() time.Time
+}
+
+// NewMagicLinkUsecase lets users log in with a link mailed to them instead of their password.
+// Links are neither sent nor redeemed while the login of the account is locked.
+func NewMagicLinkUsecase(authRepo repository.AuthRepository, linkRepo repository.MagicLinkRepository, lock loginLock) *MagicLinkUsecase {
+	return &MagicLinkUsecase{
+		authRepo: authRepo,
+		linkRepo: linkRepo,
+		lock:     lock,
+		now:      time.Now,
+	}
+}
+
+// Request mails a login link to the account and returns the device secret the link is bound
+// to. The secret is returned even when no link was sent, so callers can answer the same way
+// whether or not the email belongs to an account that may log in.
+func (u *MagicLinkUsecase) Request(ctx context.Context, email string) (string, error) {
+	deviceSecret, err := utils.GenerateRandomID()
+	if err != nil {
+		return "", errors.New(constant.ErrGenerateToken)
+	}
+
+	user, err := u.authRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return deviceSecret, errors.New(constant.ErrUserNotFound)
+	}
+	if err := checkCanLogin(user); err != nil {
+		return deviceSecret, err
+	}
+	if u.lock.LoginLocked(ctx, user.Email) {
+		return deviceSecret, errors.New(constant.ErrAccountLocked)
+	}
+
+	token, err := utils.GenerateRandomID()
+	if err != nil {
+		return "", errors.New(constant.ErrGenerateToken)
+	}
+	link := &model.MagicLink{
+		UserID:     user.UserID,
+		TokenHash:  utils.HashToken(token),
+		DeviceHash: utils.HashToken(deviceSecret),
+		ExpiresAt:  u.now().Add(constant.MagicLinkTTL),
+	}
+	event, err := mailOutboxEvent(user.Email, constant.EventTypeMagicLink, map[string]string{"token": token})
+	if err != nil {
+		return "", err
+	}
+	if err := u.linkRepo.CreateWithOutbox(ctx, link, event); err != nil {
+		return "", errors.New(constant.ErrInternalServer)
+	}
+	return deviceSecret, nil
+}
+
+// Redeem exchanges a link for the user it was sent to. It must be presented with the secret of
+// the device that requested it, before it expires and only once. A link opened on another
+// device is refused without being used up.
+func (u *MagicLinkUsecase) Redeem(ctx context.Context, token, deviceSecret string) (*model.AuthUser, error) {
+	if token == "" || deviceSecret == "" {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+
+	link, err := u.linkRepo.GetByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	now := u.now()
+	if link == nil || link.UsedAt != nil || !link.ExpiresAt.After(now) {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(deviceSecret)), []byte(link.DeviceHash)) != 1 {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+
+	user, err := u.authRepo.GetByUserID(ctx, link.UserID)
+	if err != nil {
+		return nil, errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+	// the account may have been deactivated since the link was sent
+	if err := checkCanLogin(user); err != nil {
+		return nil, err
+	}
+	// left unused, so it still works once the lock is over
+	if u.lock.LoginLocked(ctx, user.Email) {
+		return nil, errors.New(constant.ErrAccountLocked)
+	}
+
+	used, err := u.linkRepo.Use(ctx, link.ID, now)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	if !used {
+		return nil, errors.New(constant.ErrInvalidToken)
+	}
+	return user, nil
+}
+
+func checkCanLogin(user *model.AuthUser) error {
+	if !user.IsActive {
+		return errors.New(constant.ErrUserNotActive)
+	}
+	if !user.IsVerified {
+		return errors.New(constant.ErrUserNotVerified)
+	}
+	return nil
+}
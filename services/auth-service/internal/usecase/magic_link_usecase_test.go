@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockMagicLinkRepo struct {
+	links  []*model.MagicLink
+	events []model.OutboxEvent
+}
+
+func (m *mockMagicLinkRepo) CreateWithOutbox(_ context.Context, link *model.MagicLink, event *model.OutboxEvent) error {
+	link.ID = uint(len(m.links) + 1)
+	m.links = append(m.links, link)
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *mockMagicLinkRepo) GetByTokenHash(_ context.Context, tokenHash string) (*model.MagicLink, error) {
+	for _, l := range m.links {
+		if l.TokenHash == tokenHash {
+			cp := *l
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockMagicLinkRepo) Use(_ context.Context, id uint, at time.Time) (bool, error) {
+	for _, l := range m.links {
+		if l.ID == id && l.UsedAt == nil {
+			l.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type stubLoginLock map[string]bool
+
+func (s stubLoginLock) LoginLocked(_ context.Context, email string) bool {
+	return s[email]
+}
+
+func newMagicLinkUsecase(user *model.AuthUser) (*MagicLinkUsecase, *mockMagicLinkRepo) {
+	authRepo := &mockAuthRepo{
+		getByEmailFn: func(ctx context.Context, email string) (*model.AuthUser, error) {
+			if user != nil && email == user.Email {
+				return user, nil
+			}
+			return nil, gormErrNotFound()
+		},
+		getByUserIDFn: func(ctx context.Context, userID uint) (*model.AuthUser, error) {
+			return user, nil
+		},
+	}
+	linkRepo := &mockMagicLinkRepo{}
+	uc := NewMagicLinkUsecase(authRepo, linkRepo, stubLoginLock{})
+	uc.now = func() time.Time { return fixedNow }
+	return uc, linkRepo
+}
+
+func magicLinkUser() *model.AuthUser {
+	return &model.AuthUser{UserID: 1, Email: "test@example.com", IsActive: true, IsVerified: true}
+}
+
+// mailedToken returns the token carried by the last magic link mail.
+func mailedToken(t *testing.T, repo *mockMagicLinkRepo) string {
+	var event dto.MailEvent
+	assert.NoError(t, json.Unmarshal([]byte(repo.events[len(repo.events)-1].Payload), &event))
+	assert.Equal(t, constant.EventTypeMagicLink, event.Type)
+	return event.Data["token"]
+}
+
+// ---------------- TESTS ----------------
+
+func TestMagicLinkRequest_StoresOnlyHashes(t *testing.T) {
+	uc, repo := newMagicLinkUsecase(magicLinkUser())
+
+	deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deviceSecret)
+	assert.Len(t, repo.links, 1)
+	token := mailedToken(t, repo)
+	assert.Equal(t, utils.HashToken(token), repo.links[0].TokenHash)
+	assert.Equal(t, utils.HashToken(deviceSecret), repo.links[0].DeviceHash)
+	assert.Equal(t, fixedNow.Add(constant.MagicLinkTTL), repo.links[0].ExpiresAt)
+}
+
+func TestMagicLinkRequest_NoLinkForUnusableAccounts(t *testing.T) {
+	inactive := magicLinkUser()
+	inactive.IsActive = false
+	unverified := magicLinkUser()
+	unverified.IsVerified = false
+
+	tests := []struct {
+		name    string
+		user    *model.AuthUser
+		wantErr string
+	}{
+		{"unknown email", nil, constant.ErrUserNotFound},
+		{"inactive", inactive, constant.ErrUserNotActive},
+		{"unverified", unverified, constant.ErrUserNotVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo := newMagicLinkUsecase(tt.user)
+
+			deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.NotEmpty(t, deviceSecret, "callers answer the same way for every email")
+			assert.Empty(t, repo.links)
+			assert.Empty(t, repo.events)
+		})
+	}
+}
+
+func TestMagicLinkRedeem_SingleUse(t *testing.T) {
+	uc, repo := newMagicLinkUsecase(magicLinkUser())
+	deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	token := mailedToken(t, repo)
+
+	user, err := uc.Redeem(context.Background(), token, deviceSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.UserID)
+
+	_, err = uc.Redeem(context.Background(), token, deviceSecret)
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+}
+
+func TestMagicLinkRedeem_OtherDevice_RefusedWithoutUsingLink(t *testing.T) {
+	uc, repo := newMagicLinkUsecase(magicLinkUser())
+	deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	token := mailedToken(t, repo)
+
+	_, err = uc.Redeem(context.Background(), token, "another-device")
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+	_, err = uc.Redeem(context.Background(), token, "")
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+
+	_, err = uc.Redeem(context.Background(), token, deviceSecret)
+	assert.NoError(t, err)
+}
+
+func TestMagicLinkRedeem_Expired_ReturnsError(t *testing.T) {
+	uc, repo := newMagicLinkUsecase(magicLinkUser())
+	deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	uc.now = func() time.Time { return fixedNow.Add(constant.MagicLinkTTL) }
+
+	_, err = uc.Redeem(context.Background(), mailedToken(t, repo), deviceSecret)
+
+	assert.EqualError(t, err, constant.ErrInvalidToken)
+}
+
+func TestMagicLinkRedeem_DeactivatedSinceRequest_ReturnsError(t *testing.T) {
+	user := magicLinkUser()
+	uc, repo := newMagicLinkUsecase(user)
+	deviceSecret, err := uc.Request(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	user.IsActive = false
+
+	_, err = uc.Redeem(context.Background(), mailedToken(t, repo), deviceSecret)
+
+	assert.EqualError(t, err, constant.ErrUserNotActive)
+	assert.Nil(t, repo.links[0].UsedAt)
+}
+
+func TestMagicLink_LoginLocked_NoLinkAndNoRedeem(t *testing.T) {
+	user := magicLinkUser()
+	uc, repo := newMagicLinkUsecase(user)
+
+	secret, err := uc.Request(context.Background(), user.Email)
+	assert.NoError(t, err)
+	token := mailedToken(t, repo)
+
+	uc.lock = stubLoginLock{user.Email: true}
+	_, err = uc.Request(context.Background(), user.Email)
+	assert.EqualError(t, err, constant.ErrAccountLocked)
+	assert.Len(t, repo.links, 1)
+
+	_, err = uc.Redeem(context.Background(), token, secret)
+	assert.EqualError(t, err, constant.ErrAccountLocked)
+	assert.Nil(t, repo.links[0].UsedAt)
+
+	uc.lock = stubLoginLock{}
+	redeemed, err := uc.Redeem(context.Background(), token, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, user.UserID, redeemed.UserID)
+}
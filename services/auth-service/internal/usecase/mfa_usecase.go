@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+type MFAUsecase struct {
+	authRepo      repository.AuthRepository
+	mfaRepo       repository.MFARepository
+	enforcedRoles map[string]bool
+	now           func() time.Time
+}
+
+// NewMFAUsecase wires TOTP based two-factor authentication. When enforcePrivileged is set,
+// admins and moderators cannot obtain tokens until they have enrolled an authenticator.
+func NewMFAUsecase(authRepo repository.AuthRepository, mfaRepo repository.MFARepository, enforcePrivileged bool) *MFAUsecase {
+	enforcedRoles := map[string]bool{}
+	if enforcePrivileged {
+		enforcedRoles[constant.ADMIN_ROLE] = true
+		enforcedRoles[constant.MODERATOR_ROLE] = true
+	}
+	return &MFAUsecase{
+		authRepo:      authRepo,
+		mfaRepo:       mfaRepo,
+		enforcedRoles: enforcedRoles,
+		now:           time.Now,
+	}
+}
+
+// BeginChallenge returns the intermediate token login must hand out instead of the token pair,
+// or nil when the user does not need a second factor.
+func (u *MFAUsecase) BeginChallenge(ctx context.Context, user *model.AuthUser) (*dto.MFAChallengeResponse, error) {
+	tokenType := ""
+	switch {
+	case user.TwoFactorEnabled:
+		tokenType = constant.TokenTypeMFAChallenge
+	case u.enforcedRoles[user.Role]:
+		tokenType = constant.TokenTypeMFAEnrollment
+	default:
+		return nil, nil
+	}
+
+	token, err := utils.GenerateMFAToken(user, tokenType)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateTokenFailed)
+	}
+
+	return &dto.MFAChallengeResponse{
+		MFAToken:      token,
+		SetupRequired: tokenType == constant.TokenTypeMFAEnrollment,
+		ExpiresIn:     int(constant.MFATokenTTL.Seconds()),
+	}, nil
+}
+
+// Setup generates a new pending secret. It only becomes active after Enable confirms a code.
+func (u *MFAUsecase) Setup(ctx context.Context, userID uint) (*dto.MFASetupResponse, error) {
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New(constant.ErrMFAAlreadyEnabled)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateMFASecret)
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := u.authRepo.UpdateUser(ctx, user); err != nil {
+		return nil, errors.New(constant.ErrUpdateUser)
+	}
+
+	return &dto.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.BuildOTPAuthURI(constant.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code from the authenticator app and
+// returns a fresh set of recovery codes, which are only ever shown once.
+func (u *MFAUsecase) Enable(ctx context.Context, userID uint, code string) (*model.AuthUser, []string, error) {
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, nil, errors.New(constant.ErrMFAAlreadyEnabled)
+	}
+	if user.TOTPSecret == "" {
+		return nil, nil, errors.New(constant.ErrMFASetupRequired)
+	}
+
+	if err := u.checkCode(ctx, user, code); err != nil {
+		return nil, nil, err
+	}
+
+	user.TwoFactorEnabled = true
+	if err := u.authRepo.UpdateUser(ctx, user); err != nil {
+		return nil, nil, errors.New(constant.ErrUpdateUser)
+	}
+
+	codes, err := u.issueRecoveryCodes(ctx, user.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, codes, nil
+}
+
+// ChallengeUserID returns the user an mfa_token was issued to, 0 when the token is not a valid
+// challenge. It lets wrong codes be throttled per user before Verify runs.
+func (u *MFAUsecase) ChallengeUserID(mfaToken string) uint {
+	claims, err := utils.ValidateToken(mfaToken)
+	if err != nil || claims.TokenType != constant.TokenTypeMFAChallenge {
+		return 0
+	}
+	return claims.UserID
+}
+
+// Verify completes a login challenge with either a TOTP code or an unused recovery code.
+func (u *MFAUsecase) Verify(ctx context.Context, req dto.MFAVerifyRequest) (*model.AuthUser, error) {
+	claims, err := utils.ValidateToken(req.MFAToken)
+	if err != nil || claims.TokenType != constant.TokenTypeMFAChallenge {
+		return nil, errors.New(constant.ErrInvalidMFAToken)
+	}
+
+	user, err := u.getUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New(constant.ErrMFANotEnabled)
+	}
+
+	if req.RecoveryCode != "" {
+		used, err := u.mfaRepo.UseRecoveryCode(ctx, user.UserID, utils.HashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			return nil, errors.New(constant.ErrInternalServer)
+		}
+		if !used {
+			return nil, errors.New(constant.ErrInvalidMFACode)
+		}
+		return user, nil
+	}
+
+	if err := u.checkCode(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Disable turns two-factor authentication off. Roles covered by the policy switch cannot opt out.
+func (u *MFAUsecase) Disable(ctx context.Context, userID uint, code string) error {
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New(constant.ErrMFANotEnabled)
+	}
+	if u.enforcedRoles[user.Role] {
+		return errors.New(constant.ErrMFARequiredForRole)
+	}
+	if err := u.checkCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	user.TwoFactorEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	if err := u.authRepo.UpdateUser(ctx, user); err != nil {
+		return errors.New(constant.ErrUpdateUser)
+	}
+	if err := u.mfaRepo.DeleteRecoveryCodes(ctx, user.UserID); err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes invalidates every previous recovery code and returns a new set.
+func (u *MFAUsecase) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New(constant.ErrMFANotEnabled)
+	}
+	if err := u.checkCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return u.issueRecoveryCodes(ctx, user.UserID)
+}
+
+func (u *MFAUsecase) getUser(ctx context.Context, userID uint) (*model.AuthUser, error) {
+	user, err := u.authRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(constant.ErrGetUserFailed)
+	}
+	if user == nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	return user, nil
+}
+
+// checkCode accepts a TOTP code once. The step is claimed with a conditional update, so a code
+// replayed in parallel with the first use is refused even though both read the same last step.
+func (u *MFAUsecase) checkCode(ctx context.Context, user *model.AuthUser, code string) error {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, u.now(), user.TOTPLastStep)
+	if !ok {
+		return errors.New(constant.ErrInvalidMFACode)
+	}
+	used, err := u.mfaRepo.UseTOTPStep(ctx, user.UserID, step)
+	if err != nil {
+		return errors.New(constant.ErrUpdateUser)
+	}
+	if !used {
+		return errors.New(constant.ErrInvalidMFACode)
+	}
+	user.TOTPLastStep = step
+	return nil
+}
+
+func (u *MFAUsecase) issueRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(constant.RecoveryCodeCount)
+	if err != nil {
+		return nil, errors.New(constant.ErrGenerateRecoveryCodes)
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, utils.HashRecoveryCode(c))
+	}
+	if err := u.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, errors.New(constant.ErrGenerateRecoveryCodes)
+	}
+	return codes, nil
+}
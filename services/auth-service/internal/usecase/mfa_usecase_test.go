@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockMFARepo struct {
+	replaceFn func(ctx context.Context, userID uint, codeHashes []string) error
+	useFn     func(ctx context.Context, userID uint, codeHash string) (bool, error)
+	lastStep  int64 // the stored totp_last_step, claimed like the conditional update does
+}
+
+func (m *mockMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	if m.replaceFn != nil {
+		return m.replaceFn(ctx, userID, codeHashes)
+	}
+	return nil
+}
+
+func (m *mockMFARepo) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	if m.useFn != nil {
+		return m.useFn(ctx, userID, codeHash)
+	}
+	return false, nil
+}
+
+func (m *mockMFARepo) DeleteRecoveryCodes(ctx context.Context, userID uint) error { return nil }
+
+func (m *mockMFARepo) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	if step <= m.lastStep {
+		return false, nil
+	}
+	m.lastStep = step
+	return true, nil
+}
+
+var fixedNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestMFAUsecase(repo *mockAuthRepo, mfaRepo *mockMFARepo, enforce bool) *MFAUsecase {
+	uc := NewMFAUsecase(repo, mfaRepo, enforce)
+	uc.now = func() time.Time { return fixedNow }
+	return uc
+}
+
+// ---------------- TEST CASES ----------------
+
+func TestTOTP_RFC6238Vector(t *testing.T) {
+	// RFC 6238 appendix B, SHA1 key "12345678901234567890" at T=59s -> 94287082 (last 6 digits)
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	code, err := utils.GenerateTOTPCode(secret, time.Unix(59, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestBeginChallenge_NoMFA_ReturnsNil(t *testing.T) {
+	uc := newTestMFAUsecase(&mockAuthRepo{}, &mockMFARepo{}, true)
+
+	challenge, err := uc.BeginChallenge(context.Background(), &model.AuthUser{Role: constant.USER_ROLE})
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestBeginChallenge_EnforcedRoleWithoutMFA_RequiresSetup(t *testing.T) {
+	uc := newTestMFAUsecase(&mockAuthRepo{}, &mockMFARepo{}, true)
+
+	challenge, err := uc.BeginChallenge(context.Background(), &model.AuthUser{UserID: 1, Role: constant.ADMIN_ROLE})
+	assert.NoError(t, err)
+	assert.True(t, challenge.SetupRequired)
+
+	claims, err := utils.ValidateToken(challenge.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, constant.TokenTypeMFAEnrollment, claims.TokenType)
+	assert.False(t, claims.IsVerified)
+}
+
+func TestEnable_ValidCode_ReturnsRecoveryCodes(t *testing.T) {
+	secret, _ := utils.GenerateTOTPSecret()
+	user := &model.AuthUser{UserID: 1, TOTPSecret: secret}
+	var stored []string
+
+	uc := newTestMFAUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockMFARepo{
+			replaceFn: func(_ context.Context, _ uint, hashes []string) error {
+				stored = hashes
+				return nil
+			},
+		}, false)
+
+	code, _ := utils.GenerateTOTPCode(secret, fixedNow)
+	_, codes, err := uc.Enable(context.Background(), 1, code)
+
+	assert.NoError(t, err)
+	assert.True(t, user.TwoFactorEnabled)
+	assert.Len(t, codes, constant.RecoveryCodeCount)
+	assert.Equal(t, utils.HashRecoveryCode(codes[0]), stored[0])
+}
+
+func TestVerify_ReplayedCode_ReturnsError(t *testing.T) {
+	secret, _ := utils.GenerateTOTPSecret()
+	user := &model.AuthUser{UserID: 1, TOTPSecret: secret, TwoFactorEnabled: true}
+	token, _ := utils.GenerateMFAToken(user, constant.TokenTypeMFAChallenge)
+
+	uc := newTestMFAUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockMFARepo{}, false)
+
+	code, _ := utils.GenerateTOTPCode(secret, fixedNow)
+	req := dto.MFAVerifyRequest{MFAToken: token, Code: code}
+
+	_, err := uc.Verify(context.Background(), req)
+	assert.NoError(t, err)
+
+	_, err = uc.Verify(context.Background(), req)
+	assert.EqualError(t, err, constant.ErrInvalidMFACode)
+}
+
+func TestVerify_CodeReplayedInParallel_ReturnsError(t *testing.T) {
+	secret, _ := utils.GenerateTOTPSecret()
+	user := &model.AuthUser{UserID: 1, TOTPSecret: secret, TwoFactorEnabled: true}
+	token, _ := utils.GenerateMFAToken(user, constant.TokenTypeMFAChallenge)
+
+	// both requests read the user before either has used the code
+	uc := newTestMFAUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { copied := *user; return &copied, nil },
+		},
+		&mockMFARepo{}, false)
+
+	code, _ := utils.GenerateTOTPCode(secret, fixedNow)
+	req := dto.MFAVerifyRequest{MFAToken: token, Code: code}
+
+	_, err := uc.Verify(context.Background(), req)
+	assert.NoError(t, err)
+
+	_, err = uc.Verify(context.Background(), req)
+	assert.EqualError(t, err, constant.ErrInvalidMFACode)
+}
+
+func TestVerify_AccessTokenInsteadOfChallenge_ReturnsError(t *testing.T) {
+	user := &model.AuthUser{UserID: 1, TwoFactorEnabled: true}
+	token, _ := utils.GenerateAccessToken(user, "")
+
+	uc := newTestMFAUsecase(&mockAuthRepo{}, &mockMFARepo{}, false)
+
+	_, err := uc.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: token, Code: "123456"})
+	assert.EqualError(t, err, constant.ErrInvalidMFAToken)
+}
+
+func TestVerify_RecoveryCode_Success(t *testing.T) {
+	user := &model.AuthUser{UserID: 1, TwoFactorEnabled: true}
+	token, _ := utils.GenerateMFAToken(user, constant.TokenTypeMFAChallenge)
+
+	uc := newTestMFAUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockMFARepo{
+			useFn: func(_ context.Context, _ uint, hash string) (bool, error) {
+				return hash == utils.HashRecoveryCode("abcde-fghjk"), nil
+			},
+		}, false)
+
+	res, err := uc.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: token, RecoveryCode: "ABCDE-FGHJK"})
+	assert.NoError(t, err)
+	assert.Equal(t, user, res)
+}
+
+func TestDisable_EnforcedRole_ReturnsError(t *testing.T) {
+	user := &model.AuthUser{UserID: 1, Role: constant.MODERATOR_ROLE, TwoFactorEnabled: true}
+
+	uc := newTestMFAUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { return user, nil },
+		},
+		&mockMFARepo{}, true)
+
+	err := uc.Disable(context.Background(), 1, "000000")
+	assert.EqualError(t, err, constant.ErrMFARequiredForRole)
+}
+
+func TestAuthenticateUserFromClaim_MFAToken_ReturnsError(t *testing.T) {
+	user := &model.AuthUser{Email: "test@example.com", IsActive: true, IsVerified: true}
+	token, _ := utils.GenerateMFAToken(user, constant.TokenTypeMFAChallenge)
+
+	uc := NewAuthUsecase(&mockAuthRepo{}, &mockUserClient{}, &mockKafka{})
+
+	_, err := uc.AuthenticateUserFromClaim(context.Background(), &dto.RefreshTokenInput{RefreshToken: token})
+	assert.EqualError(t, err, constant.ErrExpiredOrInvalidRefreshToken)
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/kafka"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// OutboxRelay publishes queued outbox events to Kafka, retrying with exponential back-off.
+// PROFILE_VERIFIED events are delivered to user-service instead.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	kafkaProd  kafka.Producer
+	userClient repository.UserClient
+	now        func() time.Time
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, kafkaProd kafka.Producer, userClient repository.UserClient) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		kafkaProd:  kafkaProd,
+		userClient: userClient,
+		now:        time.Now,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(constant.OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.DispatchPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending sends one batch of due events and returns how many were delivered.
+func (r *OutboxRelay) DispatchPending(ctx context.Context) int {
+	events, err := r.outboxRepo.ClaimDue(ctx, r.now(), constant.OutboxBatchSize)
+	if err != nil {
+		log.Println("claim outbox events failed:", err)
+		return 0
+	}
+
+	sent := 0
+	for _, e := range events {
+		retry, err := r.deliver(ctx, e)
+		if err != nil && !retry {
+			// will never succeed, park it right away
+			if err := r.outboxRepo.MarkFailed(ctx, e.ID, e.Attempts+1, err.Error()); err != nil {
+				log.Println("mark outbox event failed:", err)
+			}
+			continue
+		}
+		if err != nil {
+			r.retry(ctx, e.ID, e.Attempts+1, err)
+			continue
+		}
+		if err := r.outboxRepo.MarkSent(ctx, e.ID, r.now()); err != nil {
+			log.Println("mark outbox event sent failed:", err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// deliver hands the event over; retry tells whether a failure may be retried.
+func (r *OutboxRelay) deliver(ctx context.Context, e model.OutboxEvent) (retry bool, err error) {
+	if e.EventType == constant.EventTypeProfileVerified {
+		var event dto.ProfileVerifiedEvent
+		if err := json.Unmarshal([]byte(e.Payload), &event); err != nil {
+			return false, err
+		}
+		err := r.userClient.MarkVerified(ctx, event.UserID)
+		if err != nil && err.Error() == constant.ErrUserNotFound {
+			// the profile is gone, e.g. the account was deleted since
+			return false, err
+		}
+		return true, err
+	}
+
+	var event dto.MailEvent
+	if err := json.Unmarshal([]byte(e.Payload), &event); err != nil {
+		return false, err
+	}
+	return true, r.kafkaProd.PublishMailEvent(ctx, event)
+}
+
+func (r *OutboxRelay) retry(ctx context.Context, id uint, attempts int, cause error) {
+	if attempts >= constant.OutboxMaxAttempts {
+		log.Printf("outbox event %d gave up after %d attempts: %v", id, attempts, cause)
+		if err := r.outboxRepo.MarkFailed(ctx, id, attempts, cause.Error()); err != nil {
+			log.Println("mark outbox event failed:", err)
+		}
+		return
+	}
+
+	backoff := time.Second
+	for i := 1; i < attempts && backoff < constant.OutboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > constant.OutboxMaxBackoff {
+		backoff = constant.OutboxMaxBackoff
+	}
+	if err := r.outboxRepo.MarkRetry(ctx, id, attempts, r.now().Add(backoff), cause.Error()); err != nil {
+		log.Println("reschedule outbox event failed:", err)
+	}
+}
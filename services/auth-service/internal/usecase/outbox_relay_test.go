@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockOutboxRepo struct {
+	events  []model.OutboxEvent
+	sent    []uint
+	retried map[uint]time.Time
+	failed  []uint
+}
+
+func (m *mockOutboxRepo) ClaimDue(_ context.Context, _ time.Time, _ int) ([]model.OutboxEvent, error) {
+	return m.events, nil
+}
+
+func (m *mockOutboxRepo) MarkSent(_ context.Context, id uint, _ time.Time) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkRetry(_ context.Context, id uint, _ int, nextAttemptAt time.Time, _ string) error {
+	if m.retried == nil {
+		m.retried = map[uint]time.Time{}
+	}
+	m.retried[id] = nextAttemptAt
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkFailed(_ context.Context, id uint, _ int, _ string) error {
+	m.failed = append(m.failed, id)
+	return nil
+}
+
+func outboxEvent(id uint, attempts int, payload string) model.OutboxEvent {
+	e := model.OutboxEvent{EventType: constant.EventTypeVerifyEmail, Payload: payload, Attempts: attempts}
+	e.ID = id
+	return e
+}
+
+// ---------------- TEST CASES ----------------
+
+func TestOutboxRelay_PublishesAndMarksSent(t *testing.T) {
+	repo := &mockOutboxRepo{events: []model.OutboxEvent{
+		outboxEvent(1, 0, `{"email":"a@b.com","type":"VERIFY_EMAIL","data":{"token":"t"}}`),
+	}}
+	var published dto.MailEvent
+	relay := NewOutboxRelay(repo, &mockKafka{
+		publishFn: func(_ context.Context, event dto.MailEvent) error {
+			published = event
+			return nil
+		},
+	}, &mockUserClient{})
+
+	sent := relay.DispatchPending(context.Background())
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []uint{1}, repo.sent)
+	assert.Equal(t, "t", published.Data["token"])
+}
+
+func TestOutboxRelay_PublishError_SchedulesRetryWithBackoff(t *testing.T) {
+	repo := &mockOutboxRepo{events: []model.OutboxEvent{
+		outboxEvent(1, 2, `{"email":"a@b.com","type":"VERIFY_EMAIL"}`),
+	}}
+	relay := NewOutboxRelay(repo, &mockKafka{
+		publishFn: func(_ context.Context, _ dto.MailEvent) error { return errors.New("broker down") },
+	}, &mockUserClient{})
+	relay.now = func() time.Time { return fixedNow }
+
+	relay.DispatchPending(context.Background())
+
+	assert.Empty(t, repo.sent)
+	assert.Equal(t, fixedNow.Add(4*time.Second), repo.retried[1])
+}
+
+func TestOutboxRelay_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &mockOutboxRepo{events: []model.OutboxEvent{
+		outboxEvent(1, constant.OutboxMaxAttempts-1, `{"email":"a@b.com","type":"VERIFY_EMAIL"}`),
+		outboxEvent(2, 0, `not json`),
+	}}
+	relay := NewOutboxRelay(repo, &mockKafka{
+		publishFn: func(_ context.Context, _ dto.MailEvent) error { return errors.New("broker down") },
+	}, &mockUserClient{})
+
+	relay.DispatchPending(context.Background())
+
+	assert.Equal(t, []uint{1, 2}, repo.failed)
+	assert.Empty(t, repo.retried)
+}
+
+func TestOutboxRelay_ProfileVerified_CallsUserService(t *testing.T) {
+	verified := model.OutboxEvent{EventType: constant.EventTypeProfileVerified, Payload: `{"user_id":7}`}
+	verified.ID = 1
+	gone := model.OutboxEvent{EventType: constant.EventTypeProfileVerified, Payload: `{"user_id":8}`}
+	gone.ID = 2
+	down := model.OutboxEvent{EventType: constant.EventTypeProfileVerified, Payload: `{"user_id":9}`}
+	down.ID = 3
+	repo := &mockOutboxRepo{events: []model.OutboxEvent{verified, gone, down}}
+
+	var marked []uint
+	relay := NewOutboxRelay(repo, &mockKafka{
+		publishFn: func(_ context.Context, _ dto.MailEvent) error {
+			t.Fatal("not a mail")
+			return nil
+		},
+	}, &mockUserClient{markVerifiedFn: func(_ context.Context, userID uint) error {
+		marked = append(marked, userID)
+		switch userID {
+		case 8:
+			return errors.New(constant.ErrUserNotFound)
+		case 9:
+			return errors.New(constant.ErrSendHTTPRequest)
+		}
+		return nil
+	}})
+
+	sent := relay.DispatchPending(context.Background())
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []uint{7, 8, 9}, marked)
+	assert.Equal(t, []uint{1}, repo.sent)
+	assert.Equal(t, []uint{2}, repo.failed)
+	assert.Contains(t, repo.retried, uint(3))
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"packages/session"
+	"time"
+)
+
+type SessionUsecase struct {
+	sessionRepo repository.SessionRepository
+	geoIP       *utils.GeoIPDB
+	now         func() time.Time
+}
+
+// NewSessionUsecase tracks login sessions. geoIP may be nil, in which case no location is recorded.
+func NewSessionUsecase(sessionRepo repository.SessionRepository, geoIP *utils.GeoIPDB) *SessionUsecase {
+	return &SessionUsecase{
+		sessionRepo: sessionRepo,
+		geoIP:       geoIP,
+		now:         time.Now,
+	}
+}
+
+// Start records a new session for a completed login. Its ID goes into the issued tokens.
+func (u *SessionUsecase) Start(ctx context.Context, user *model.AuthUser, ip, userAgent string) (*model.Session, error) {
+	sessionID, err := utils.GenerateSessionID()
+	if err != nil {
+		return nil, errors.New(constant.ErrCreateSession)
+	}
+
+	now := u.now()
+	session := &model.Session{
+		SessionID:  sessionID,
+		UserID:     user.UserID,
+		UserAgent:  truncate(userAgent, 255),
+		IP:         ip,
+		Location:   u.geoIP.Lookup(ip),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(constant.RefreshTokenTTL),
+	}
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, errors.New(constant.ErrCreateSession)
+	}
+	return session, nil
+}
+
+// Resume checks that the session behind a refresh token is still alive and marks it as used.
+func (u *SessionUsecase) Resume(ctx context.Context, refreshToken, ip string) (string, error) {
+	claims, err := utils.ValidateToken(refreshToken)
+	if err != nil || claims.SessionID == "" {
+		return "", errors.New(constant.ErrExpiredOrInvalidRefreshToken)
+	}
+
+	session, err := u.sessionRepo.GetBySessionID(ctx, claims.SessionID)
+	if err != nil {
+		return "", errors.New(constant.ErrInternalServer)
+	}
+	now := u.now()
+	if session == nil || session.UserID != claims.UserID || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return "", errors.New(constant.ErrExpiredOrInvalidRefreshToken)
+	}
+
+	location := session.Location
+	if ip != session.IP {
+		location = u.geoIP.Lookup(ip)
+	}
+	if err := u.sessionRepo.Touch(ctx, session.SessionID, ip, location, now); err != nil {
+		return "", errors.New(constant.ErrInternalServer)
+	}
+	return session.SessionID, nil
+}
+
+// List returns the active sessions of the user, flagging the one identified by currentID.
+func (u *SessionUsecase) List(ctx context.Context, userID uint, currentID string) ([]dto.SessionResponse, error) {
+	sessions, err := u.sessionRepo.ListActiveByUser(ctx, userID, u.now())
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, dto.SessionResponse{
+			ID:         s.SessionID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			Location:   s.Location,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.SessionID == currentID,
+		})
+	}
+	return res, nil
+}
+
+// Revoke ends one session of the user. Sessions of other users are reported as not found.
+func (u *SessionUsecase) Revoke(ctx context.Context, userID uint, sessionID string) error {
+	session, err := u.sessionRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return errors.New(constant.ErrSessionNotFound)
+	}
+
+	if err := u.sessionRepo.Revoke(ctx, sessionID, u.now()); err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+// RevokeAll ends every session of the user, e.g. when an admin deactivates the account.
+func (u *SessionUsecase) RevokeAll(ctx context.Context, userID uint) error {
+	if err := u.sessionRepo.RevokeAllByUser(ctx, userID, u.now()); err != nil {
+		return errors.New(constant.ErrInternalServer)
+	}
+	return nil
+}
+
+// RevokedSince lists sessions revoked after since. Tokens are stateless, so every service learns
+// about revocations from this feed until the last refresh token of the session has expired.
+func (u *SessionUsecase) RevokedSince(ctx context.Context, since time.Time) (*dto.RevokedSessionsResponse, error) {
+	now := u.now()
+	if oldest := now.Add(-constant.RefreshTokenTTL); since.Before(oldest) {
+		since = oldest
+	}
+
+	sessions, err := u.sessionRepo.ListRevokedSince(ctx, since)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := &dto.RevokedSessionsResponse{
+		Sessions:   make([]dto.RevokedSession, 0, len(sessions)),
+		ServerTime: now.Unix(),
+	}
+	for _, s := range sessions {
+		res.Sessions = append(res.Sessions, dto.RevokedSession{ID: s.SessionID, RevokedAt: *s.RevokedAt})
+	}
+	return res, nil
+}
+
+// Revocations reads the feed as a session.Source, so auth-service mirrors it like every other
+// service does.
+func (u *SessionUsecase) Revocations(ctx context.Context, since int64) ([]session.Revoked, int64, error) {
+	feed, err := u.RevokedSince(ctx, time.Unix(since, 0))
+	if err != nil {
+		return nil, 0, err
+	}
+	revoked := make([]session.Revoked, 0, len(feed.Sessions))
+	for _, s := range feed.Sessions {
+		revoked = append(revoked, session.Revoked{ID: s.ID, RevokedAt: s.RevokedAt})
+	}
+	return revoked, feed.ServerTime, nil
+}
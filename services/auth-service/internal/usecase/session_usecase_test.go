@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockSessionRepo struct {
+	sessions map[string]*model.Session
+}
+
+func newMockSessionRepo() *mockSessionRepo {
+	return &mockSessionRepo{sessions: map[string]*model.Session{}}
+}
+
+func (m *mockSessionRepo) Create(_ context.Context, session *model.Session) error {
+	m.sessions[session.SessionID] = session
+	return nil
+}
+
+func (m *mockSessionRepo) GetBySessionID(_ context.Context, sessionID string) (*model.Session, error) {
+	if s, ok := m.sessions[sessionID]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) ListActiveByUser(_ context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	var res []model.Session
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.ExpiresAt.After(now) {
+			res = append(res, *s)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockSessionRepo) ListByUser(_ context.Context, userID uint) ([]model.Session, error) {
+	var res []model.Session
+	for _, s := range m.sessions {
+		if s.UserID == userID {
+			res = append(res, *s)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockSessionRepo) Touch(_ context.Context, sessionID, ip, location string, at time.Time) error {
+	s := m.sessions[sessionID]
+	s.IP, s.Location, s.LastUsedAt = ip, location, at
+	return nil
+}
+
+func (m *mockSessionRepo) Revoke(_ context.Context, sessionID string, at time.Time) error {
+	m.sessions[sessionID].RevokedAt = &at
+	return nil
+}
+
+func (m *mockSessionRepo) RevokeAllByUser(_ context.Context, userID uint, at time.Time) error {
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ListRevokedSince(_ context.Context, since time.Time) ([]model.Session, error) {
+	var res []model.Session
+	for _, s := range m.sessions {
+		if s.RevokedAt != nil && !s.RevokedAt.Before(since) {
+			res = append(res, *s)
+		}
+	}
+	return res, nil
+}
+
+func newTestGeoIP(t *testing.T) *utils.GeoIPDB {
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	csv := `"16777216","16777471","AU","Australia","Queensland","Brisbane"
+"3232235520","3232301055","VN","Viet Nam","Ho Chi Minh","Ho Chi Minh City"
+`
+	assert.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+	db, err := utils.LoadGeoIPDB(path)
+	assert.NoError(t, err)
+	return db
+}
+
+// ---------------- TEST CASES ----------------
+
+func TestSession_StartRecordsDeviceAndLocation(t *testing.T) {
+	repo := newMockSessionRepo()
+	uc := NewSessionUsecase(repo, newTestGeoIP(t))
+
+	session, err := uc.Start(context.Background(), &model.AuthUser{UserID: 1}, "192.168.1.10", "Mozilla/5.0")
+
+	assert.NoError(t, err)
+	assert.Len(t, session.SessionID, 32)
+	assert.Equal(t, "Ho Chi Minh City, Ho Chi Minh, Viet Nam", session.Location)
+	assert.Equal(t, "Mozilla/5.0", repo.sessions[session.SessionID].UserAgent)
+}
+
+func TestSession_StartWithoutGeoIP_LeavesLocationEmpty(t *testing.T) {
+	uc := NewSessionUsecase(newMockSessionRepo(), nil)
+
+	session, err := uc.Start(context.Background(), &model.AuthUser{UserID: 1}, "192.168.1.10", "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, session.Location)
+}
+
+func TestSession_ResumeRevokedSession_ReturnsError(t *testing.T) {
+	repo := newMockSessionRepo()
+	uc := NewSessionUsecase(repo, nil)
+	user := &model.AuthUser{UserID: 1}
+
+	session, _ := uc.Start(context.Background(), user, "1.0.0.1", "")
+	refresh, _ := utils.GenerateRefreshToken(user, session.SessionID)
+
+	sid, err := uc.Resume(context.Background(), refresh, "1.0.0.2")
+	assert.NoError(t, err)
+	assert.Equal(t, session.SessionID, sid)
+	assert.Equal(t, "1.0.0.2", repo.sessions[sid].IP)
+
+	assert.NoError(t, uc.Revoke(context.Background(), 1, sid))
+	_, err = uc.Resume(context.Background(), refresh, "1.0.0.2")
+	assert.EqualError(t, err, constant.ErrExpiredOrInvalidRefreshToken)
+}
+
+func TestSession_ResumeTokenWithoutSession_ReturnsError(t *testing.T) {
+	uc := NewSessionUsecase(newMockSessionRepo(), nil)
+	refresh, _ := utils.GenerateRefreshToken(&model.AuthUser{UserID: 1}, "")
+
+	_, err := uc.Resume(context.Background(), refresh, "1.0.0.1")
+	assert.EqualError(t, err, constant.ErrExpiredOrInvalidRefreshToken)
+}
+
+func TestSession_RevokeOtherUsersSession_ReturnsNotFound(t *testing.T) {
+	uc := NewSessionUsecase(newMockSessionRepo(), nil)
+	session, _ := uc.Start(context.Background(), &model.AuthUser{UserID: 1}, "1.0.0.1", "")
+
+	err := uc.Revoke(context.Background(), 2, session.SessionID)
+	assert.EqualError(t, err, constant.ErrSessionNotFound)
+}
+
+func TestSession_ListFlagsCurrentAndRevokedFeed(t *testing.T) {
+	repo := newMockSessionRepo()
+	uc := NewSessionUsecase(repo, nil)
+	uc.now = func() time.Time { return fixedNow }
+	user := &model.AuthUser{UserID: 1}
+
+	first, _ := uc.Start(context.Background(), user, "1.0.0.1", "")
+	second, _ := uc.Start(context.Background(), user, "1.0.0.2", "")
+
+	sessions, err := uc.List(context.Background(), 1, second.SessionID)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	for _, s := range sessions {
+		assert.Equal(t, s.ID == second.SessionID, s.Current)
+	}
+
+	assert.NoError(t, uc.RevokeAll(context.Background(), 1))
+	sessions, _ = uc.List(context.Background(), 1, "")
+	assert.Empty(t, sessions)
+
+	feed, err := uc.RevokedSince(context.Background(), time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, feed.Sessions, 2)
+	assert.ElementsMatch(t, []string{first.SessionID, second.SessionID}, []string{feed.Sessions[0].ID, feed.Sessions[1].ID})
+}
+
+func TestSession_RevocationsKeptForRefreshTokenLifetime(t *testing.T) {
+	repo := newMockSessionRepo()
+	uc := NewSessionUsecase(repo, nil)
+	user := &model.AuthUser{UserID: 1}
+
+	// revoked a day ago: access tokens expired long since, but the refresh token is still valid
+	uc.now = func() time.Time { return fixedNow.Add(-24 * time.Hour) }
+	recent, _ := uc.Start(context.Background(), user, "1.0.0.1", "")
+	assert.NoError(t, uc.Revoke(context.Background(), 1, recent.SessionID))
+	uc.now = func() time.Time { return fixedNow.Add(-constant.RefreshTokenTTL - time.Hour) }
+	old, _ := uc.Start(context.Background(), user, "1.0.0.2", "")
+	assert.NoError(t, uc.Revoke(context.Background(), 1, old.SessionID))
+	uc.now = func() time.Time { return fixedNow }
+
+	revoked, serverTime, err := uc.Revocations(context.Background(), 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fixedNow.Unix(), serverTime)
+	assert.Len(t, revoked, 1)
+	assert.Equal(t, recent.SessionID, revoked[0].ID)
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"log"
+	"time"
+)
+
+// UserImportProcessor creates the accounts of queued imports, one row after the other. A row
+// that fails is reported and not retried: the admin can import the failed rows again, which
+// picks up unverified accounts and orphan profiles the way sign-up does.
+type UserImportProcessor struct {
+	importRepo repository.UserImportRepository
+	auth       *AuthUsecase
+	now        func() time.Time
+}
+
+func NewUserImportProcessor(importRepo repository.UserImportRepository, auth *AuthUsecase) *UserImportProcessor {
+	return &UserImportProcessor{
+		importRepo: importRepo,
+		auth:       auth,
+		now:        time.Now,
+	}
+}
+
+// Run processes queued imports until ctx is cancelled.
+func (p *UserImportProcessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(constant.UserImportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.ProcessDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue works through one batch of imports and returns how many it claimed.
+func (p *UserImportProcessor) ProcessDue(ctx context.Context) int {
+	imps, err := p.importRepo.ClaimDue(ctx, p.now(), constant.UserImportBatchSize)
+	if err != nil {
+		log.Println("claim user imports failed:", err)
+		return 0
+	}
+
+	for i := range imps {
+		p.process(ctx, &imps[i])
+	}
+	return len(imps)
+}
+
+func (p *UserImportProcessor) process(ctx context.Context, imp *model.UserImport) {
+	for i := range imp.Rows {
+		row := &imp.Rows[i]
+		if row.Status != constant.UserImportRowPending {
+			continue
+		}
+		// stop between rows on shutdown; the import is claimed again once its lease runs out
+		if ctx.Err() != nil {
+			return
+		}
+
+		status, userID, err := p.auth.Invite(ctx, row.Email, row.Name, row.Role)
+		if err != nil {
+			row.Status = constant.UserImportRowFailed
+			row.Error = truncate(err.Error(), 255)
+		} else {
+			row.Status = status
+			row.UserID = userID
+		}
+		if err := p.importRepo.UpdateRow(ctx, row); err != nil {
+			log.Printf("update row %d of user import %s failed: %v", row.Line, imp.ImportID, err)
+		}
+	}
+
+	now := p.now()
+	imp.Status = constant.UserImportStatusCompleted
+	imp.CompletedAt = &now
+	imp.Succeeded, imp.Failed = 0, 0
+	for _, row := range imp.Rows {
+		if row.Status == constant.UserImportRowFailed {
+			imp.Failed++
+		} else {
+			imp.Succeeded++
+		}
+	}
+	if err := p.importRepo.Finish(ctx, imp); err != nil {
+		log.Println("finish user import failed:", err)
+	}
+}
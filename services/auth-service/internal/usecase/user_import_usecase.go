@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/mail"
+	"packages/policy"
+	"strings"
+	"time"
+)
+
+type UserImportUsecase struct {
+	importRepo repository.UserImportRepository
+	now        func() time.Time
+}
+
+// NewUserImportUsecase lets admins create users in bulk from a CSV or JSON file. The accounts
+// are created by the UserImportProcessor.
+func NewUserImportUsecase(importRepo repository.UserImportRepository) *UserImportUsecase {
+	return &UserImportUsecase{
+		importRepo: importRepo,
+		now:        time.Now,
+	}
+}
+
+// Create checks every row of the file and queues the import. A file that cannot be read at all
+// is refused; a bad row only fails itself and shows up in the report.
+func (u *UserImportUsecase) Create(ctx context.Context, adminID uint, format string, file io.Reader) (*dto.UserImportResponse, error) {
+	raw, err := io.ReadAll(io.LimitReader(file, constant.UserImportMaxBytes+1))
+	if err != nil {
+		return nil, errors.New(constant.ErrImportInvalid)
+	}
+	if len(raw) > constant.UserImportMaxBytes {
+		return nil, errors.New(constant.ErrImportTooLarge)
+	}
+
+	var rows []model.UserImportRow
+	switch format {
+	case constant.UserImportFormatCSV:
+		rows, err = parseImportCSV(raw)
+	case constant.UserImportFormatJSON:
+		rows, err = parseImportJSON(raw)
+	default:
+		err = errors.New(constant.ErrImportFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New(constant.ErrImportEmpty)
+	}
+	if len(rows) > constant.UserImportMaxRows {
+		return nil, errors.New(constant.ErrImportTooLarge)
+	}
+
+	importID, err := utils.GenerateRandomID()
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	imp := &model.UserImport{
+		ImportID:  importID,
+		CreatedBy: adminID,
+		Format:    format,
+		Status:    constant.UserImportStatusPending,
+		DueAt:     u.now(),
+		Total:     len(rows),
+		Rows:      rows,
+	}
+	validateImportRows(imp.Rows)
+	for i := range imp.Rows {
+		imp.Rows[i].ImportID = importID
+		if imp.Rows[i].Status == constant.UserImportRowFailed {
+			imp.Failed++
+		}
+	}
+	if err := u.importRepo.Create(ctx, imp); err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := userImportResponse(imp, false)
+	return &res, nil
+}
+
+// List returns the latest imports, without their rows.
+func (u *UserImportUsecase) List(ctx context.Context) ([]dto.UserImportResponse, error) {
+	imps, err := u.importRepo.List(ctx, constant.UserImportListLimit)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	res := make([]dto.UserImportResponse, 0, len(imps))
+	for i := range imps {
+		res = append(res, userImportResponse(&imps[i], false))
+	}
+	return res, nil
+}
+
+// Get returns an import with the report of each row, only those with the given status when
+// status is set.
+func (u *UserImportUsecase) Get(ctx context.Context, importID, status string) (*dto.UserImportResponse, error) {
+	imp, err := u.importRepo.GetByImportID(ctx, importID)
+	if err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	if imp == nil {
+		return nil, errors.New(constant.ErrImportNotFound)
+	}
+
+	if status != "" {
+		rows := imp.Rows[:0]
+		for _, row := range imp.Rows {
+			if row.Status == status {
+				rows = append(rows, row)
+			}
+		}
+		imp.Rows = rows
+	}
+	res := userImportResponse(imp, true)
+	return &res, nil
+}
+
+// parseImportCSV reads a CSV file whose first line names the columns. Only email is required;
+// unknown columns are ignored, so the export of user-service can be imported as is.
+func parseImportCSV(raw []byte) ([]model.UserImportRow, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\ufeff"))))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New(constant.ErrImportEmpty)
+		}
+		return nil, errors.New(constant.ErrImportInvalid)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	emailCol, ok := columns["email"]
+	if !ok {
+		return nil, errors.New(constant.ErrImportInvalid)
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []model.UserImportRow
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.New(constant.ErrImportInvalid)
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, model.UserImportRow{
+			Line:  line,
+			Email: record[emailCol],
+			Name:  field(record, "name"),
+			Role:  field(record, "role"),
+		})
+		if len(rows) > constant.UserImportMaxRows {
+			return nil, errors.New(constant.ErrImportTooLarge)
+		}
+	}
+}
+
+// parseImportJSON reads an array of dto.ImportedUser; the line of a row is its position.
+func parseImportJSON(raw []byte) ([]model.UserImportRow, error) {
+	var users []dto.ImportedUser
+	if err := json.Unmarshal(raw, &users); err != nil {
+		return nil, errors.New(constant.ErrImportInvalid)
+	}
+
+	rows := make([]model.UserImportRow, 0, len(users))
+	for i, user := range users {
+		rows = append(rows, model.UserImportRow{
+			Line:  i + 1,
+			Email: user.Email,
+			Name:  user.Name,
+			Role:  user.Role,
+		})
+	}
+	return rows, nil
+}
+
+// validateImportRows normalises the rows and marks those that cannot be imported as failed.
+// An email appearing twice is only imported the first time.
+func validateImportRows(rows []model.UserImportRow) {
+	seen := make(map[string]bool, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		row.Email = strings.ToLower(strings.TrimSpace(row.Email))
+		row.Name = strings.TrimSpace(row.Name)
+		row.Role = strings.ToLower(strings.TrimSpace(row.Role))
+		if row.Role == "" {
+			row.Role = constant.USER_ROLE
+		}
+		row.Status = constant.UserImportRowPending
+
+		var rowErr string
+		switch {
+		case !isPlainEmail(row.Email):
+			rowErr = constant.ErrInvalidEmail
+		case row.Name == "" || len(row.Name) > 255:
+			rowErr = constant.ErrInvalidName
+		case !policy.IsGlobalRole(row.Role):
+			rowErr = constant.ErrInvalidRole
+		case seen[row.Email]:
+			rowErr = constant.ErrDuplicateRow
+		}
+		if rowErr != "" {
+			row.Status = constant.UserImportRowFailed
+			row.Error = rowErr
+			// keep what was sent within the columns, for the report
+			row.Email = truncate(row.Email, 255)
+			row.Name = truncate(row.Name, 255)
+			row.Role = truncate(row.Role, 50)
+			continue
+		}
+		seen[row.Email] = true
+	}
+}
+
+// isPlainEmail accepts a bare address, without a display name or angle brackets.
+func isPlainEmail(email string) bool {
+	if email == "" || len(email) > 255 {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+func userImportResponse(imp *model.UserImport, withRows bool) dto.UserImportResponse {
+	res := dto.UserImportResponse{
+		ID:          imp.ImportID,
+		Format:      imp.Format,
+		Status:      imp.Status,
+		Total:       imp.Total,
+		Succeeded:   imp.Succeeded,
+		Failed:      imp.Failed,
+		CreatedBy:   imp.CreatedBy,
+		CreatedAt:   imp.CreatedAt,
+		CompletedAt: imp.CompletedAt,
+	}
+	if !withRows {
+		return res
+	}
+	res.Rows = make([]dto.UserImportRowResponse, 0, len(imp.Rows))
+	for _, row := range imp.Rows {
+		res.Rows = append(res.Rows, dto.UserImportRowResponse{
+			Line:   row.Line,
+			Email:  row.Email,
+			Name:   row.Name,
+			Role:   row.Role,
+			Status: row.Status,
+			Error:  row.Error,
+			UserID: row.UserID,
+		})
+	}
+	return res
+}
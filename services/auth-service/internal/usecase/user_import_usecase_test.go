@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"auth-service/internal/constant"
+	"auth-service/internal/dto"
+	"auth-service/internal/model"
+	"auth-service/internal/utils"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ---------------- MOCKS ----------------
+
+type mockUserImportRepo struct {
+	created    *model.UserImport
+	due        []model.UserImport
+	rowUpdates []model.UserImportRow
+	finished   []model.UserImport
+}
+
+func (m *mockUserImportRepo) Create(_ context.Context, imp *model.UserImport) error {
+	m.created = imp
+	return nil
+}
+
+func (m *mockUserImportRepo) GetByImportID(_ context.Context, importID string) (*model.UserImport, error) {
+	if m.created != nil && m.created.ImportID == importID {
+		return m.created, nil
+	}
+	return nil, nil
+}
+
+func (m *mockUserImportRepo) List(_ context.Context, _ int) ([]model.UserImport, error) {
+	return nil, nil
+}
+
+func (m *mockUserImportRepo) ClaimDue(_ context.Context, _ time.Time, _ int) ([]model.UserImport, error) {
+	due := m.due
+	m.due = nil
+	return due, nil
+}
+
+func (m *mockUserImportRepo) UpdateRow(_ context.Context, row *model.UserImportRow) error {
+	m.rowUpdates = append(m.rowUpdates, *row)
+	return nil
+}
+
+func (m *mockUserImportRepo) Finish(_ context.Context, imp *model.UserImport) error {
+	m.finished = append(m.finished, *imp)
+	return nil
+}
+
+// ---------------- TEST CASES ----------------
+
+// -------- Create --------
+
+func TestUserImportCreate_CSV_ReportsBadRows(t *testing.T) {
+	repo := &mockUserImportRepo{}
+	uc := NewUserImportUsecase(repo)
+	file := "\ufeffID,Email,Name,Phone,Role\n" +
+		"1, Alice@Example.com ,Alice,,\n" +
+		"2,not-an-email,Bob,,user\n" +
+		"3,carol@example.com,,,user\n" +
+		"4,dave@example.com,Dave,,system\n" +
+		"5,alice@example.com,Alice Again,,user\n" +
+		"6,erin@example.com,Erin,,Moderator\n"
+
+	res, err := uc.Create(context.Background(), 1, constant.UserImportFormatCSV, strings.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, res.Total)
+	assert.Equal(t, 4, res.Failed)
+	assert.Equal(t, constant.UserImportStatusPending, res.Status)
+
+	rows := repo.created.Rows
+	require.Len(t, rows, 6)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "alice@example.com", rows[0].Email)
+	assert.Equal(t, constant.USER_ROLE, rows[0].Role)
+	assert.Equal(t, constant.UserImportRowPending, rows[0].Status)
+	assert.Equal(t, constant.ErrInvalidEmail, rows[1].Error)
+	assert.Equal(t, constant.ErrInvalidName, rows[2].Error)
+	assert.Equal(t, constant.ErrInvalidRole, rows[3].Error)
+	assert.Equal(t, constant.ErrDuplicateRow, rows[4].Error)
+	assert.Equal(t, constant.MODERATOR_ROLE, rows[5].Role)
+	assert.Equal(t, constant.UserImportRowPending, rows[5].Status)
+	for _, row := range rows {
+		assert.Equal(t, res.ID, row.ImportID)
+	}
+}
+
+func TestUserImportCreate_JSON(t *testing.T) {
+	repo := &mockUserImportRepo{}
+	uc := NewUserImportUsecase(repo)
+	file := `[{"email":"a@example.com","name":"A"},{"email":"b@example.com","name":"B","role":"admin"}]`
+
+	res, err := uc.Create(context.Background(), 1, constant.UserImportFormatJSON, strings.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	assert.Zero(t, res.Failed)
+	assert.Equal(t, 2, repo.created.Rows[1].Line)
+	assert.Equal(t, constant.ADMIN_ROLE, repo.created.Rows[1].Role)
+}
+
+func TestUserImportCreate_UnreadableFiles(t *testing.T) {
+	cases := map[string]struct {
+		format, file, want string
+	}{
+		"unknown format":  {"xlsx", "email\na@example.com\n", constant.ErrImportFormat},
+		"no email column": {constant.UserImportFormatCSV, "name\nA\n", constant.ErrImportInvalid},
+		"header only":     {constant.UserImportFormatCSV, "email,name\n", constant.ErrImportEmpty},
+		"ragged csv":      {constant.UserImportFormatCSV, "email,name\na@example.com\n", constant.ErrImportInvalid},
+		"not an array":    {constant.UserImportFormatJSON, `{"email":"a@example.com"}`, constant.ErrImportInvalid},
+		"too many rows": {constant.UserImportFormatCSV,
+			"email,name\n" + strings.Repeat("a@example.com,A\n", constant.UserImportMaxRows+1), constant.ErrImportTooLarge},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockUserImportRepo{}
+			uc := NewUserImportUsecase(repo)
+
+			_, err := uc.Create(context.Background(), 1, tc.format, strings.NewReader(tc.file))
+
+			assert.EqualError(t, err, tc.want)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+// -------- UserImportProcessor --------
+
+func TestUserImportProcessor_CreatesAccountsAndReports(t *testing.T) {
+	var created []*model.AuthUser
+	var invitations []dto.MailEvent
+	var verifications []string
+	authUC := NewAuthUsecase(
+		&mockAuthRepo{
+			getByEmailFn: func(_ context.Context, email string) (*model.AuthUser, error) {
+				switch email {
+				case "pending@example.com":
+					return &model.AuthUser{UserID: 5, Email: email}, nil
+				case "taken@example.com":
+					return &model.AuthUser{UserID: 6, Email: email, IsVerified: true}, nil
+				}
+				return nil, gormErrNotFound()
+			},
+			createWithOutboxFn: func(_ context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+				created = append(created, user)
+				var mail dto.MailEvent
+				require.NoError(t, json.Unmarshal([]byte(event.Payload), &mail))
+				invitations = append(invitations, mail)
+				return nil
+			},
+		},
+		&mockUserClient{
+			createUserFn: func(_ context.Context, email, _, role string) (*dto.CreateUserResponse, error) {
+				assert.Equal(t, constant.MODERATOR_ROLE, role)
+				return &dto.CreateUserResponse{ID: 9, Email: email}, nil
+			},
+		},
+		&mockKafka{
+			publishFn: func(_ context.Context, event dto.MailEvent) error {
+				assert.Equal(t, constant.EventTypeVerifyEmail, event.Type)
+				verifications = append(verifications, event.Email)
+				return nil
+			},
+		},
+	)
+	repo := &mockUserImportRepo{due: []model.UserImport{{
+		ImportID: "imp",
+		Status:   constant.UserImportStatusProcessing,
+		Rows: []model.UserImportRow{
+			{Line: 2, Email: "new@example.com", Name: "New", Role: constant.MODERATOR_ROLE, Status: constant.UserImportRowPending},
+			{Line: 3, Email: "pending@example.com", Name: "Pending", Role: constant.USER_ROLE, Status: constant.UserImportRowPending},
+			{Line: 4, Email: "taken@example.com", Name: "Taken", Role: constant.USER_ROLE, Status: constant.UserImportRowPending},
+			{Line: 5, Email: "bad", Status: constant.UserImportRowFailed, Error: constant.ErrInvalidEmail},
+		},
+	}}}
+	p := NewUserImportProcessor(repo, authUC)
+
+	assert.Equal(t, 1, p.ProcessDue(context.Background()))
+
+	require.Len(t, created, 1)
+	assert.Equal(t, uint(9), created[0].UserID)
+	assert.Equal(t, constant.MODERATOR_ROLE, created[0].Role)
+	assert.Empty(t, created[0].PasswordHash, "invited users choose their password")
+	assert.False(t, created[0].IsVerified)
+	require.Len(t, invitations, 1)
+	assert.Equal(t, constant.EventTypeInvitation, invitations[0].Type)
+	assert.Equal(t, "New", invitations[0].Data["name"])
+	assert.NotEmpty(t, invitations[0].Data["token"])
+	assert.Equal(t, []string{"pending@example.com"}, verifications)
+
+	require.Len(t, repo.rowUpdates, 3, "rows failed at upload are not retried")
+	assert.Equal(t, constant.UserImportRowInvited, repo.rowUpdates[0].Status)
+	assert.Equal(t, uint(9), repo.rowUpdates[0].UserID)
+	assert.Equal(t, constant.UserImportRowVerification, repo.rowUpdates[1].Status)
+	assert.Equal(t, uint(5), repo.rowUpdates[1].UserID)
+	assert.Equal(t, constant.UserImportRowFailed, repo.rowUpdates[2].Status)
+	assert.Equal(t, constant.ErrEmailAlreadyExists, repo.rowUpdates[2].Error)
+
+	require.Len(t, repo.finished, 1)
+	assert.Equal(t, constant.UserImportStatusCompleted, repo.finished[0].Status)
+	assert.Equal(t, 2, repo.finished[0].Succeeded)
+	assert.Equal(t, 2, repo.finished[0].Failed)
+	assert.NotNil(t, repo.finished[0].CompletedAt)
+}
+
+// -------- AcceptInvitation --------
+
+func TestAcceptInvitation_SetsPasswordAndVerifies(t *testing.T) {
+	invited := &model.AuthUser{UserID: 9, Email: "new@example.com", Role: constant.USER_ROLE}
+	token, err := utils.GenerateInvitationToken(invited, time.Hour)
+	require.NoError(t, err)
+
+	var saved *model.AuthUser
+	var queued *model.OutboxEvent
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, userID uint) (*model.AuthUser, error) {
+				if saved != nil {
+					return saved, nil
+				}
+				copied := *invited
+				return &copied, nil
+			},
+			updateWithOutboxFn: func(_ context.Context, user *model.AuthUser, event *model.OutboxEvent) error {
+				saved, queued = user, event
+				return nil
+			},
+		},
+		&mockUserClient{},
+		&mockKafka{},
+	)
+
+	require.NoError(t, uc.AcceptInvitation(context.Background(), token, "Secret#123"))
+	require.NotNil(t, saved)
+	assert.True(t, saved.IsVerified)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(saved.PasswordHash), []byte("Secret#123")))
+	require.NotNil(t, queued)
+	assert.Equal(t, constant.EventTypeProfileVerified, queued.EventType)
+	assert.JSONEq(t, `{"user_id":9}`, queued.Payload)
+
+	// the link cannot be used to change the password again
+	assert.EqualError(t, uc.AcceptInvitation(context.Background(), token, "Other#456"), constant.ErrInvalidToken)
+}
+
+func TestAcceptInvitation_OtherTokenTypes_Rejected(t *testing.T) {
+	user := &model.AuthUser{UserID: 9, Email: "new@example.com"}
+	token, err := utils.GenerateEmailVerificationToken(user, time.Hour)
+	require.NoError(t, err)
+	uc := NewAuthUsecase(
+		&mockAuthRepo{
+			getByUserIDFn: func(_ context.Context, _ uint) (*model.AuthUser, error) { return user, nil },
+			updateWithOutboxFn: func(_ context.Context, _ *model.AuthUser, _ *model.OutboxEvent) error {
+				t.Fatal("a verification token must not set a password")
+				return nil
+			},
+		},
+		&mockUserClient{},
+		&mockKafka{},
+	)
+
+	assert.EqualError(t, uc.AcceptInvitation(context.Background(), token, "Secret#123"), constant.ErrInvalidToken)
+}
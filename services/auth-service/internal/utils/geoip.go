@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type geoIPRange struct {
+	from     uint32
+	to       uint32
+	location string
+}
+
+// GeoIPDB resolves IPv4 addresses to an approximate "City, Region, Country" string.
+// It reads the IP2Location LITE CSV layout (ip_from, ip_to, country_code, country_name
+// [, region_name, city_name]) fully into memory; lookups are a binary search.
+type GeoIPDB struct {
+	ranges []geoIPRange
+}
+
+func LoadGeoIPDB(path string) (*GeoIPDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	db := &GeoIPDB{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 4 {
+			continue
+		}
+		from, errFrom := strconv.ParseUint(record[0], 10, 32)
+		to, errTo := strconv.ParseUint(record[1], 10, 32)
+		if errFrom != nil || errTo != nil {
+			// header line or IPv6 row
+			continue
+		}
+
+		// most specific first: city, region, country
+		var parts []string
+		for i := len(record) - 1; i >= 3; i-- {
+			if v := strings.TrimSpace(record[i]); v != "" && v != "-" {
+				parts = append(parts, v)
+			}
+		}
+		db.ranges = append(db.ranges, geoIPRange{from: uint32(from), to: uint32(to), location: strings.Join(parts, ", ")})
+	}
+	if len(db.ranges) == 0 {
+		return nil, errors.New("geoip database is empty")
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].from < db.ranges[j].from })
+	return db, nil
+}
+
+// Lookup returns the location of ip, or an empty string when it is unknown.
+func (db *GeoIPDB) Lookup(ip string) string {
+	if db == nil {
+		return ""
+	}
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return ""
+	}
+	n := binary.BigEndian.Uint32(parsed)
+
+	i := sort.Search(len(db.ranges), func(i int) bool { return db.ranges[i].from > n }) - 1
+	if i < 0 || n > db.ranges[i].to {
+		return ""
+	}
+	return db.ranges[i].location
+}
@@ -1,10 +1,12 @@
 package utils
 
 import (
+	"auth-service/internal/constant"
 	"auth-service/internal/model"
 	"errors"
 	"log"
 	"os"
+	"packages/policy"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,6 +18,10 @@ type Claims struct {
 	Role       string `json:"role"`
 	IsActive   bool   `json:"is_active"`
 	IsVerified bool   `json:"is_verified"`
+	TokenType  string `json:"token_type,omitempty"`
+	SessionID  string `json:"sid,omitempty"`
+	// Act names the admin behind an impersonation token
+	Act *policy.Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -32,14 +38,16 @@ func InitJWT() {
 	}
 }
 
-func GenerateAccessToken(user *model.AuthUser) (string, error) {
-	expirationTime := time.Now().Add(15 * time.Minute)
+func GenerateAccessToken(user *model.AuthUser, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(constant.AccessTokenTTL)
 	claims := &Claims{
 		UserID:     user.UserID,
 		Email:      user.Email,
 		Role:       user.Role,
 		IsActive:   user.IsActive,
 		IsVerified: user.IsVerified,
+		TokenType:  constant.TokenTypeAccess,
+		SessionID:  sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -50,14 +58,16 @@ func GenerateAccessToken(user *model.AuthUser) (string, error) {
 	return token.SignedString([]byte(jwtSecretKey))
 }
 
-func GenerateRefreshToken(user *model.AuthUser) (string, error) {
-	expirationTime := time.Now().Add(7 * 24 * time.Hour)
+func GenerateRefreshToken(user *model.AuthUser, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(constant.RefreshTokenTTL)
 	claims := &Claims{
 		UserID:     user.UserID,
 		Email:      user.Email,
 		Role:       user.Role,
 		IsActive:   user.IsActive,
 		IsVerified: user.IsVerified,
+		TokenType:  constant.TokenTypeRefresh,
+		SessionID:  sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -68,14 +78,125 @@ func GenerateRefreshToken(user *model.AuthUser) (string, error) {
 	return token.SignedString([]byte(jwtSecretKey))
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+// GenerateMFAToken issues the short-lived token returned by login while a second factor is pending.
+// Role, IsActive and IsVerified are left empty on purpose so that the RequireAuth middleware of
+// the other services refuses it as a bearer token.
+func GenerateMFAToken(user *model.AuthUser, tokenType string) (string, error) {
+	claims := &Claims{
+		UserID:    user.UserID,
+		Email:     user.Email,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(constant.MFATokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecretKey))
+}
+
+// GenerateEmailVerificationToken issues the single-purpose token mailed after sign-up. Like the
+// MFA token it carries no role or flags, so it can never be used as a bearer token.
+func GenerateEmailVerificationToken(user *model.AuthUser, ttl time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:    user.UserID,
+		Email:     user.Email,
+		TokenType: constant.TokenTypeEmailVerify,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecretKey))
+}
+
+// GenerateInvitationToken issues the token mailed to a user created by an admin import. It
+// lets the user pick a password, which verifies the address at the same time.
+func GenerateInvitationToken(user *model.AuthUser, ttl time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:    user.UserID,
+		Email:     user.Email,
+		TokenType: constant.TokenTypeInvitation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecretKey))
+}
+
+// GenerateImpersonationToken issues an access token for user on behalf of actor. It carries the
+// user's role and flags so every service accepts it, has no session and cannot be refreshed.
+func GenerateImpersonationToken(user *model.AuthUser, actor policy.Actor, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		UserID:     user.UserID,
+		Email:      user.Email,
+		Role:       user.Role,
+		IsActive:   user.IsActive,
+		IsVerified: user.IsVerified,
+		TokenType:  constant.TokenTypeImpersonation,
+		Act:        &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecretKey))
+}
+
+// GenerateEmailChangeToken issues the link token of an email change: tokenType is either the
+// confirmation sent to the new address or the revert sent to the old one. The change is
+// referenced by the "jti" claim, and like other single-purpose tokens it carries no role.
+func GenerateEmailChangeToken(change *model.EmailChange, tokenType string) (string, error) {
+	email, expiresAt := change.NewEmail, change.ExpiresAt
+	if tokenType == constant.TokenTypeEmailRevert {
+		email, expiresAt = change.OldEmail, change.RevertibleUntil
+	}
+	claims := &Claims{
+		UserID:    change.UserID,
+		Email:     email,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        change.ChangeID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecretKey))
+}
+
+// IsMFAToken reports whether the claims belong to a pending second-factor token.
+func (c *Claims) IsMFAToken() bool {
+	return c.TokenType == constant.TokenTypeMFAChallenge || c.TokenType == constant.TokenTypeMFAEnrollment
+}
+
+// IsSingleUseToken reports whether the claims belong to a token that must not start a session.
+func (c *Claims) IsSingleUseToken() bool {
+	switch c.TokenType {
+	case constant.TokenTypeEmailVerify, constant.TokenTypeImpersonation, constant.TokenTypeEmailChange, constant.TokenTypeEmailRevert,
+		constant.TokenTypeInvitation:
+		return true
+	}
+	return c.IsMFAToken()
+}
+
+func ValidateToken(tokenString string, opts ...jwt.ParserOption) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("error.unexpected_signing_method")
 		}
 		return []byte(jwtSecretKey), nil
-	})
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
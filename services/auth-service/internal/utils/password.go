@@ -2,6 +2,8 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"math/big"
 )
 
@@ -18,3 +20,23 @@ func GenerateRandomPassword(length int) (string, error) {
 	}
 	return string(password), nil
 }
+
+// GenerateSessionID returns a random 128-bit identifier for a login session.
+func GenerateSessionID() (string, error) {
+	return GenerateRandomID()
+}
+
+// GenerateRandomID returns a random 128-bit identifier encoded as 32 hex characters.
+func GenerateRandomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the sha256 hex digest of an opaque token, the only form in which it is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpSecretSize = 20 // 160-bit key as recommended by RFC 4226
+	totpDigits     = 6
+	totpPeriod     = 30
+	totpSkew       = 1 // accept one step before/after to absorb clock drift
+
+	recoveryCodeCharset = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength  = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 encoded secret for an authenticator app.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// BuildOTPAuthURI builds the otpauth:// URI understood by authenticator apps (usually rendered as a QR code).
+func BuildOTPAuthURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// GenerateTOTPCode returns the RFC 6238 code of the secret at the given time.
+func GenerateTOTPCode(secret string, at time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, at.Unix()/totpPeriod), nil
+}
+
+// ValidateTOTP checks the code against the current time step and its neighbours.
+// Steps at or before lastStep are refused so an accepted code cannot be replayed.
+// It returns the matched step, which the caller persists as the new lastStep.
+func ValidateTOTP(secret, code string, at time.Time, lastStep int64) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := at.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns n one-time codes formatted as "xxxxx-xxxxx".
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		code := make([]byte, recoveryCodeLength)
+		for j := range code {
+			randomInt, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeCharset))))
+			if err != nil {
+				return nil, err
+			}
+			code[j] = recoveryCodeCharset[randomInt.Int64()]
+		}
+		half := recoveryCodeLength / 2
+		codes = append(codes, string(code[:half])+"-"+string(code[half:]))
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode normalises a recovery code and returns its sha256 hex digest.
+func HashRecoveryCode(code string) string {
+	normalised := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalised))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	return totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
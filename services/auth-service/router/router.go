@@ -1,12 +1,20 @@
 package router
 
 import (
+	"auth-service/internal/constant"
 	"auth-service/internal/handler"
 	"auth-service/internal/kafka"
+	"auth-service/internal/middleware"
 	"auth-service/internal/repository"
 	"auth-service/internal/usecase"
+	"auth-service/internal/utils"
 	"log"
 	"os"
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/session"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -14,29 +22,176 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRouter(r *gin.Engine, dbConn *gorm.DB, kafkaProducer kafka.Producer) {
+// SetupRouter registers the routes of auth-service. It returns the outbox relay, the processors
+// of data requests (exports and account deletions) and of user imports, and the store of revoked
+// sessions RequireAuth checks, which the caller runs in the background.
+func SetupRouter(r *gin.Engine, dbConn *gorm.DB, kafkaProducer kafka.Producer) (*usecase.OutboxRelay, *usecase.DataRequestProcessor, *usecase.UserImportProcessor, *session.RevocationStore) {
 	baseURL := os.Getenv("USER_SERVICE_URL")
 	if baseURL == "" {
 		log.Fatal("missing env: USER_SERVICE_URL")
 	}
 
+	// Admins and moderators must use 2FA unless explicitly switched off
+	enforceMFA := true
+	if v := os.Getenv(constant.EnvMFAEnforceRoles); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("invalid env %s: %v", constant.EnvMFAEnforceRoles, err)
+		}
+		enforceMFA = parsed
+	}
+
+	verificationTTL := constant.DefaultEmailVerifyTTL
+	if v := os.Getenv(constant.EnvEmailVerifyTTL); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("invalid env %s: %q", constant.EnvEmailVerifyTTL, v)
+		}
+		verificationTTL = parsed
+	}
+
+	// Calls between services are authenticated with tokens signed by a shared key
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceIssuer := servicetoken.NewIssuer(servicetoken.AuthService, serviceSecret)
+	serviceVerifier := servicetoken.NewVerifier(servicetoken.AuthService, serviceSecret)
+
+	// Every service holding data about users takes part in data exports and account deletions
+	dataServices := []struct{ name, env, url string }{
+		{name: servicetoken.UserService, env: "USER_SERVICE_URL"},
+		{name: servicetoken.BookingService, env: "BOOKING_SERVICE_URL"},
+		{name: servicetoken.PaymentService, env: "PAYMENT_SERVICE_URL"},
+		{name: servicetoken.ChatService, env: "CHAT_SERVICE_URL"},
+		{name: servicetoken.NotificationService, env: "NOTIFICATION_SERVICE_URL"},
+	}
+	for i := range dataServices {
+		dataServices[i].url = os.Getenv(dataServices[i].env)
+		if dataServices[i].url == "" {
+			log.Fatalf("missing env: %s", dataServices[i].env)
+		}
+	}
+	exportDir := os.Getenv(constant.EnvDataExportDir)
+	if exportDir == "" {
+		exportDir = constant.DefaultDataExportDir
+	}
+
+	// Optional local GeoIP database used to show approximate session locations
+	var geoIP *utils.GeoIPDB
+	if path := os.Getenv(constant.EnvGeoIPDBPath); path != "" {
+		db, err := utils.LoadGeoIPDB(path)
+		if err != nil {
+			log.Printf("GeoIP database %s not loaded, session locations disabled: %v", path, err)
+		} else {
+			geoIP = db
+		}
+	}
+
 	// Init dependencies
 	authRepo := repository.NewAuthRepository(dbConn)
-	userClient := repository.NewUserClient(baseURL)
+	mfaRepo := repository.NewMFARepository(dbConn)
+	attemptRepo := repository.NewAttemptRepository(dbConn)
+	sessionRepo := repository.NewSessionRepository(dbConn)
+	impersonationRepo := repository.NewImpersonationRepository(dbConn)
+	emailChangeRepo := repository.NewEmailChangeRepository(dbConn)
+	magicLinkRepo := repository.NewMagicLinkRepository(dbConn)
+	dataRequestRepo := repository.NewDataRequestRepository(dbConn)
+	userImportRepo := repository.NewUserImportRepository(dbConn)
+	userClient := repository.NewUserClient(baseURL, serviceIssuer)
+	// auth-service goes first so a deleted account is locked before its data disappears
+	dataSteps := []repository.UserDataClient{usecase.NewAuthDataStep(authRepo, sessionRepo, impersonationRepo, dataRequestRepo)}
+	for _, svc := range dataServices {
+		dataSteps = append(dataSteps, repository.NewUserDataClient(svc.name, svc.url, serviceIssuer))
+	}
 
 	authUC := usecase.NewAuthUsecase(authRepo, userClient, kafkaProducer)
-	authHandler := handler.NewAuthHandler(*authUC)
+	authUC.SetVerificationTTL(verificationTTL)
+	mfaUC := usecase.NewMFAUsecase(authRepo, mfaRepo, enforceMFA)
+	attemptUC := usecase.NewAttemptUsecase(attemptRepo, authRepo, kafkaProducer)
+	sessionUC := usecase.NewSessionUsecase(sessionRepo, geoIP)
+	impersonationUC := usecase.NewImpersonationUsecase(authRepo, impersonationRepo)
+	emailChangeUC := usecase.NewEmailChangeUsecase(authRepo, emailChangeRepo, userClient)
+	magicLinkUC := usecase.NewMagicLinkUsecase(authRepo, magicLinkRepo, attemptUC)
+	dataRequestUC := usecase.NewDataRequestUsecase(authRepo, dataRequestRepo, dataSteps)
+	userImportUC := usecase.NewUserImportUsecase(userImportRepo)
+	authHandler := handler.NewAuthHandler(*authUC, mfaUC, attemptUC, sessionUC, magicLinkUC)
+	mfaHandler := handler.NewMFAHandler(mfaUC, attemptUC, sessionUC)
+	sessionHandler := handler.NewSessionHandler(sessionUC)
+	revocations := session.NewRevocationStore(sessionUC.Revocations)
+	middleware.TrackRevocations(revocations)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationUC)
+	emailChangeHandler := handler.NewEmailChangeHandler(emailChangeUC)
+	dataRequestHandler := handler.NewDataRequestHandler(dataRequestUC)
+	userImportHandler := handler.NewUserImportHandler(userImportUC)
 
 	// Routes
 	api := r.Group("/api/v1/auth")
 	api.POST("/sign-up", authHandler.SignUp)
 	api.GET("/verify-account", authHandler.VerifyAccount)
+	api.POST("/verify-account/resend", authHandler.ResendVerification)
 	api.POST("/login", authHandler.Login)
 	api.POST("/refresh-token", authHandler.RefreshToken)
 	api.POST("/reset-password", authHandler.ResetPassword)
+	api.POST("/magic-link", authHandler.RequestMagicLink)
+	api.GET("/magic-link/redeem", authHandler.RedeemMagicLink)
+	api.POST("/invitation/accept", authHandler.AcceptInvitation)
+
+	// two-factor authentication
+	mfa := api.Group("/2fa")
+	mfa.POST("/verify", mfaHandler.VerifyMFA)
+	mfa.POST("/setup", middleware.RequireAuth(constant.TokenTypeMFAEnrollment), mfaHandler.SetupMFA)
+	mfa.POST("/enable", middleware.RequireAuth(constant.TokenTypeMFAEnrollment), mfaHandler.EnableMFA)
+	mfa.POST("/disable", middleware.RequireAuth(), mfaHandler.DisableMFA)
+	mfa.POST("/recovery-codes", middleware.RequireAuth(), mfaHandler.RegenerateRecoveryCodes)
+
+	// sessions
+	api.GET("/sessions", middleware.RequireAuth(), sessionHandler.ListSessions)
+	api.DELETE("/sessions/:id", middleware.RequireAuth(), sessionHandler.RevokeSession)
+
+	// email change, confirmed by the new address and revertible from the old one
+	api.POST("/email-change", middleware.RequireAuth(), emailChangeHandler.RequestEmailChange)
+	api.GET("/email-change/confirm", emailChangeHandler.ConfirmEmailChange)
+	api.GET("/email-change/revert", emailChangeHandler.RevertEmailChange)
+
+	// impersonation audit of the current user
+	api.GET("/impersonations", middleware.RequireAuth(), impersonationHandler.ListImpersonations)
+
+	// personal data export and account deletion
+	account := api.Group("/account", middleware.RequireAuth())
+	account.GET("/requests", dataRequestHandler.ListRequests)
+	account.POST("/export", dataRequestHandler.RequestExport)
+	account.GET("/export/:id/download", dataRequestHandler.DownloadExport)
+	account.POST("/delete", dataRequestHandler.RequestDeletion)
+	account.DELETE("/delete", dataRequestHandler.CancelDeletion)
+
+	// admin
+	admin := api.Group("/admin", middleware.RequireAuth(), middleware.RequirePermission(policy.UserManage))
+	admin.POST("/users/:id/unlock", authHandler.UnlockAccount)
+	admin.GET("/users/:id/sessions", sessionHandler.AdminListSessions)
+	admin.DELETE("/users/:id/sessions", sessionHandler.AdminRevokeAllSessions)
+	admin.DELETE("/users/:id/sessions/:sid", sessionHandler.AdminRevokeSession)
+	admin.POST("/users/:id/impersonate", middleware.RequirePermission(policy.UserImpersonate), impersonationHandler.Impersonate)
+	admin.GET("/users/:id/impersonations", impersonationHandler.AdminListImpersonations)
+	admin.POST("/users/import", userImportHandler.CreateImport)
+	admin.GET("/users/imports", userImportHandler.ListImports)
+	admin.GET("/users/imports/:id", userImportHandler.GetImport)
+
+	// internal, not routed by the api-gateway
+	internal := r.Group("/api/v1/internal")
+	// every service mirrors revoked sessions to refuse their tokens
+	internal.GET("/sessions/revoked", middleware.RequireService(serviceVerifier), sessionHandler.RevokedSessions)
+	// services record impersonated requests before serving them
+	internal.POST("/impersonations", middleware.RequireService(serviceVerifier, servicetoken.UserService, servicetoken.VenueService,
+		servicetoken.BookingService, servicetoken.PaymentService, servicetoken.ChatService), impersonationHandler.RecordImpersonatedRequests)
 
 	//user-service
-	api.PUT("/users", authHandler.UpdateAuthUser)
+	api.PUT("/users", middleware.RequireService(serviceVerifier, servicetoken.UserService), authHandler.UpdateAuthUser)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return usecase.NewOutboxRelay(repository.NewOutboxRepository(dbConn), kafkaProducer, userClient),
+		usecase.NewDataRequestProcessor(dataRequestRepo, dataSteps, exportDir),
+		usecase.NewUserImportProcessor(userImportRepo, authUC),
+		revocations
 }
@@ -5,12 +5,19 @@ import (
 	_ "booking-service/docs"
 	"booking-service/internal/handler"
 	"booking-service/internal/kafka"
+	"booking-service/internal/middleware"
 	"booking-service/internal/repository"
 	"booking-service/internal/router"
 	"booking-service/internal/service"
 	"booking-service/internal/usecase"
+	"context"
 	"fmt"
+	"log"
 	"os"
+	"packages/impersonation"
+	"packages/servicetoken"
+	"packages/session"
+	"time"
 )
 
 func main() {
@@ -20,8 +27,30 @@ func main() {
 	if venueServiceDomain == "" {
 		venueServiceDomain = "http://venue-service:8083"
 	}
+	userServiceDomain := os.Getenv("USER_SERVICE_URL")
+	if userServiceDomain == "" {
+		userServiceDomain = "http://user-service:8082"
+	}
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
 	repo := repository.NewBookingRepository(config.DB)
-	venueSvc := service.NewVenueHTTPService(venueServiceDomain)
+	issuer := servicetoken.NewIssuer(servicetoken.BookingService, serviceSecret)
+	venueSvc := service.NewVenueHTTPService(venueServiceDomain, issuer)
+	userSvc := service.NewUserHTTPService(userServiceDomain, issuer)
+
+	// refuse the tokens of sessions revoked in auth-service
+	authURL := os.Getenv("AUTH_SERVICE_URL")
+	if authURL == "" {
+		log.Fatal("missing env: AUTH_SERVICE_URL")
+	}
+	authClient := issuer.Client(servicetoken.AuthService, 5*time.Second)
+	revocations := session.NewRevocationStore(session.HTTPSource(authURL, authClient))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	middleware.TrackRevocations(revocations)
+	// record impersonated requests in auth-service before serving them
+	middleware.RecordImpersonations(impersonation.NewRecorder(authURL, authClient))
 
 	brokers := os.Getenv("KAFKA_BROKERS")
 	if brokers == "" {
@@ -35,10 +64,10 @@ func main() {
 		[]string{brokers},
 		topic,
 	)
-	uc := usecase.NewBookingUsecase(repo, venueSvc, producer)
+	uc := usecase.NewBookingUsecase(repo, venueSvc, userSvc, producer)
 	h := handler.NewBookingHandler(uc)
 
-	r := router.SetupRouter(h)
+	r := router.SetupRouter(h, servicetoken.NewVerifier(servicetoken.BookingService, serviceSecret))
 
 	port := os.Getenv("BOOKING_SERVICE_PORT")
 	if port == "" {
@@ -87,7 +87,7 @@ func ConnectDB() {
 	sqlDB.SetMaxOpenConns(100)
 
 	DB = db
-	err = db.AutoMigrate(model.Booking{})
+	err = db.AutoMigrate(model.Booking{}, model.QuotaLock{})
 	if err != nil {
 		log.Fatalf("❌ AutoMigrate failed: %v", err)
 	}
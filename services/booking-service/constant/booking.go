@@ -6,6 +6,12 @@ const (
 	BookingStatusCancelled = "CANCELLED"
 )
 
+// roles of the members of an organization in user-service
+const (
+	OrgRoleOwner = "owner"
+	OrgRoleAdmin = "admin"
+)
+
 var AllowedBookingStatuses = map[string]bool{
 	BookingStatusPending:   true,
 	BookingStatusConfirmed: true,
@@ -5,4 +5,13 @@ import "errors"
 var (
 	ErrInvalidBookingTime = errors.New("invalid booking time range")
 	ErrSpaceNotFound      = errors.New("space not found")
+	// the user is not an active member of the organization the booking is billed to
+	ErrNotOrganizationMember = errors.New("not a member of the organization")
+	ErrQuotaExceeded         = errors.New("monthly booking quota exceeded")
+	ErrInvalidMonth          = errors.New("invalid month")
+	// the space is not open throughout the booking, see service.VenueService.CheckOpening
+	ErrSpaceClosed   = errors.New("space is closed at the requested time")
+	ErrVenueNotFound = errors.New("venue not found")
+	// the user holds no role on the venue or its spaces that lets them read its bookings
+	ErrForbidden = errors.New("forbidden")
 )
@@ -1,5 +1,6 @@
 package dto
 
+import "booking-service/internal/model"
 
 type CheckAvailabilityRequest struct {
 	SpaceIDs  []uint    `json:"space_ids"`
@@ -10,3 +11,21 @@ type CheckAvailabilityRequest struct {
 type CheckAvailabilityResponse struct {
 	UnavailableSpaceIDs []uint `json:"unavailable_space_ids"`
 }
+
+// OrganizationStatement sums what the members of an organization booked on its account in a
+// month, cancelled bookings left out.
+type OrganizationStatement struct {
+	OrganizationID uint              `json:"organization_id"`
+	Month          string            `json:"month"` // YYYY-MM, in UTC
+	TotalHours     float64           `json:"total_hours"`
+	TotalAmount    float64           `json:"total_amount"`
+	Members        []MemberStatement `json:"members"`
+	Bookings       []model.Booking   `json:"bookings"`
+}
+
+type MemberStatement struct {
+	UserID   uint    `json:"user_id"`
+	Bookings int     `json:"bookings"`
+	Hours    float64 `json:"hours"`
+	Amount   float64 `json:"amount"`
+}
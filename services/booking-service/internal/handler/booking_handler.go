@@ -6,6 +6,7 @@ import (
 	"booking-service/internal/usecase"
 	"errors"
 	"net/http"
+	"packages/policy"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +26,8 @@ type BookingRequest struct {
 	SpaceID   uint   `json:"space_id"`
 	StartTime string `json:"start_time"`
 	EndTime   string `json:"end_time"`
+	// bills the booking to this organization of the user, within their monthly quota
+	OrganizationID *uint `json:"organization_id,omitempty"`
 }
 
 type UpdateBookingStatusRequest struct {
@@ -41,7 +44,10 @@ type UpdateBookingStatusRequest struct {
 // @Success      201 {object} map[string]interface{} "booking created"
 // @Failure      400 {object} map[string]string "invalid input"
 // @Failure      401 {object} map[string]string "unauthorized"
+// @Failure      403 {object} map[string]string "not a member of the organization"
 // @Failure      404 {object} map[string]string "space not found"
+// @Failure      409 {object} map[string]string "space closed at the requested time"
+// @Failure      422 {object} map[string]string "monthly quota exceeded"
 // @Router       /bookings [post]
 func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	var req BookingRequest
@@ -72,13 +78,19 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.usecase.BookSpace(userID, req.SpaceID, start, end)
+	booking, err := h.usecase.BookSpace(userID, req.SpaceID, req.OrganizationID, start, end)
 	if err != nil {
 		switch {
 		case errors.Is(err, constant.ErrInvalidBookingTime):
 			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		case errors.Is(err, constant.ErrSpaceNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case errors.Is(err, constant.ErrNotOrganizationMember):
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		case errors.Is(err, constant.ErrQuotaExceeded):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		case errors.Is(err, constant.ErrSpaceClosed):
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
 		}
@@ -194,9 +206,7 @@ func (h *BookingHandler) GetBookingByUserID(c *gin.Context) {
 // @Router       /bookings [get]
 func (h *BookingHandler) GetAllBooking(c *gin.Context) {
 	// Có thể check role từ middleware: admin/mod mới được gọi
-	roleVal, _ := c.Get("role")
-	role := roleVal.(string)
-	if role != "admin" && role != "moderator" {
+	if !policy.Can(c.GetString("role"), policy.BookingReadAll) {
 		c.JSON(http.StatusForbidden, gin.H{"message": "forbidden"})
 		return
 	}
@@ -210,6 +220,107 @@ func (h *BookingHandler) GetAllBooking(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "bookings.fetched.successfully", "data": bookings})
 }
 
+// GetOrganizationStatement godoc
+// @Summary      Get the monthly statement of an organization
+// @Description  Bookings billed to the organization in a month, with hours and amount per member.
+// @Description  For owners and admins of the organization, and staff.
+// @Tags         bookings
+// @Produce      json
+// @Param        id    path  int     true   "Organization ID"
+// @Param        month query string  false  "Month in UTC (YYYY-MM), the current one by default"
+// @Success      200 {object} map[string]interface{} "statement"
+// @Failure      400 {object} map[string]string "invalid input"
+// @Failure      403 {object} map[string]string "forbidden"
+// @Failure      500 {object} map[string]string "internal server error"
+// @Router       /bookings/organizations/{id} [get]
+func (h *BookingHandler) GetOrganizationStatement(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid.organization_id"})
+		return
+	}
+	month := time.Now()
+	if m := c.Query("month"); m != "" {
+		month, err = time.Parse("2006-01", m)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidMonth.Error()})
+			return
+		}
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "unauthorized"})
+		return
+	}
+	uid, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid userID type"})
+		return
+	}
+
+	statement, err := h.usecase.GetOrganizationStatement(c.Request.Context(), uint(orgID), uid, c.GetString("role"), month)
+	if err != nil {
+		if errors.Is(err, constant.ErrNotOrganizationMember) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "statement.fetched.successfully", "data": statement})
+}
+
+// GetVenueBookings godoc
+// @Summary      List the bookings of a venue
+// @Description  Bookings of the venue's spaces, the latest first. Owners and staff of the venue see
+// @Description  all of them, managers those of the spaces they manage.
+// @Tags         bookings
+// @Produce      json
+// @Param        id  path  int  true  "Venue ID"
+// @Success      200 {object} map[string]interface{} "bookings"
+// @Failure      400 {object} map[string]string "invalid input"
+// @Failure      403 {object} map[string]string "forbidden"
+// @Failure      404 {object} map[string]string "venue not found"
+// @Failure      500 {object} map[string]string "internal server error"
+// @Router       /bookings/venues/{id} [get]
+func (h *BookingHandler) GetVenueBookings(c *gin.Context) {
+	venueID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid.venue_id"})
+		return
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "unauthorized"})
+		return
+	}
+	uid, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid userID type"})
+		return
+	}
+	sub := policy.Subject{UserID: uid, Role: c.GetString("role")}
+	if actor, ok := c.Get("actor"); ok {
+		sub.Actor, _ = actor.(*policy.Actor)
+	}
+
+	bookings, err := h.usecase.GetVenueBookings(c.Request.Context(), sub, uint(venueID))
+	if err != nil {
+		switch {
+		case errors.Is(err, constant.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+		case errors.Is(err, constant.ErrVenueNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrVenueNotFound.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookings.fetched.successfully", "data": bookings})
+}
+
 func (h *BookingHandler) CheckAvailability(c *gin.Context) {
 	var req dto.CheckAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -237,4 +348,60 @@ func (h *BookingHandler) CheckAvailability(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.CheckAvailabilityResponse{
 		UnavailableSpaceIDs: unavailable,
 	})
-}
\ No newline at end of file
+}
+
+// ExportUserData returns the bookings of a user for their personal data export.
+// Internal endpoint called by auth-service.
+func (h *BookingHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid user id"})
+		return
+	}
+
+	bookings, err := h.usecase.GetBookingByUserID(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"bookings": bookings}})
+}
+
+// EraseUserData handles the deletion of a user account. Internal endpoint called by auth-service.
+func (h *BookingHandler) EraseUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid user id"})
+		return
+	}
+
+	if err := h.usecase.EraseUserData(c.Request.Context(), uint(userID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user data erased"})
+}
+
+// ListUserBookingIDs returns the IDs of the bookings of a user, whose payments payment-service
+// looks up. Internal endpoint called by payment-service.
+func (h *BookingHandler) ListUserBookingIDs(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid user id"})
+		return
+	}
+
+	bookings, err := h.usecase.GetBookingByUserID(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		return
+	}
+
+	ids := make([]uint, 0, len(bookings))
+	for _, b := range bookings {
+		ids = append(ids, b.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ids})
+}
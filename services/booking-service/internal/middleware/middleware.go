@@ -3,12 +3,35 @@ package middleware
 import (
 	"booking-service/internal/utils"
 	"net/http"
+	"packages/impersonation"
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/session"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RequireAuth(allowedRoles ...string) gin.HandlerFunc {
+// revocations mirrors the sessions revoked in auth-service; RequireAuth refuses their tokens
+// once TrackRevocations has been called.
+var revocations *session.RevocationStore
+
+func TrackRevocations(store *session.RevocationStore) {
+	revocations = store
+}
+
+// impersonations writes the audit trail of impersonated requests; RequireAuth refuses
+// impersonation tokens until RecordImpersonations has been called.
+var impersonations *impersonation.Recorder
+
+func RecordImpersonations(recorder *impersonation.Recorder) {
+	impersonations = recorder
+}
+
+// RequireAuth validates the bearer token and, when permissions are given, only lets
+// through callers whose global role holds all of them. Scoped checks are left to usecases.
+func RequireAuth(perms ...policy.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,12 +48,19 @@ func RequireAuth(allowedRoles ...string) gin.HandlerFunc {
 		tokenStr := tokenParts[1]
 
 		claims, err := utils.ValidateToken(tokenStr)
-		if err != nil {
+		// refresh tokens and single-purpose tokens are signed with the same key
+		if err != nil || !session.Bearer(claims.TokenType) {
 			c.JSON(http.StatusUnauthorized, gin.H{"message": "error.invalid_token"})
 			c.Abort()
 			return
 		}
 
+		if revocations != nil && revocations.IsRevoked(claims.SessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "error.session_revoked"})
+			c.Abort()
+			return
+		}
+
 		if !claims.IsVerified {
 			c.JSON(http.StatusForbidden, gin.H{"message": "error.user_account_is_not_verified"})
 			c.Abort()
@@ -43,24 +73,53 @@ func RequireAuth(allowedRoles ...string) gin.HandlerFunc {
 			return
 		}
 
-		if len(allowedRoles) > 0 {
-			allowed := false
-			for _, role := range allowedRoles {
-				if claims.Role == role {
-					allowed = true
-					break
-				}
-			}
-			if !allowed {
-				c.JSON(http.StatusForbidden, gin.H{"message": "error.forbidden"})
-				c.Abort()
-				return
-			}
+		if !policy.CanAll(claims.Role, perms...) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "error.forbidden"})
+			c.Abort()
+			return
+		}
+
+		if claims.Act != nil && !policy.AllowedWhileImpersonating(perms...) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "error.impersonation_not_allowed"})
+			c.Abort()
+			return
+		}
+
+		// impersonated requests are only served once auth-service has stored them
+		if claims.Act != nil && (impersonations == nil || impersonations.Record(c.Request.Context(), impersonation.Request{
+			Token:      tokenStr,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			OccurredAt: time.Now(),
+		}) != nil) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": "error.impersonation_audit_unavailable"})
+			c.Abort()
+			return
 		}
 
 		c.Set("userEmail", claims.Email)
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
+		if claims.Act != nil {
+			c.Set("actor", claims.Act)
+		}
+		c.Next()
+	}
+}
+
+// RequireService only lets through other services holding a token addressed to this one,
+// and only the listed callers when any are given. It guards the service-to-service routes.
+func RequireService(verifier *servicetoken.Verifier, callers ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := verifier.Verify(c.GetHeader(servicetoken.Header), callers...)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "error.invalid_service_token"})
+			c.Abort()
+			return
+		}
+		c.Set("service", claims.Subject)
 		c.Next()
 	}
 }
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"booking-service/internal/utils"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"packages/impersonation"
+	"packages/policy"
+	"packages/session"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func signedToken(t *testing.T, tokenType, sid string) string {
+	return sign(t, tokenType, sid, nil)
+}
+
+func sign(t *testing.T, tokenType, sid string, act *policy.Actor) string {
+	claims := &utils.Claims{
+		UserID:     1,
+		Email:      "user@example.com",
+		Role:       "user",
+		IsActive:   true,
+		IsVerified: true,
+		TokenType:  tokenType,
+		SessionID:  sid,
+		Act:        act,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func authStatus(token string) int {
+	r := gin.New()
+	r.GET("/", RequireAuth(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func initTestJWT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("SECRET_KEY", testSecret)
+	t.Setenv("JWT_ISSUER", "test")
+	utils.InitJWT()
+}
+
+func TestRequireAuth_TokenTypesAndRevokedSessions(t *testing.T) {
+	initTestJWT(t)
+
+	store := session.NewRevocationStore(func(context.Context, int64) ([]session.Revoked, int64, error) {
+		return []session.Revoked{{ID: "revoked", RevokedAt: time.Now().Add(-24 * time.Hour)}}, time.Now().Unix(), nil
+	})
+	require.NoError(t, store.Sync(context.Background()))
+	TrackRevocations(store)
+	defer TrackRevocations(nil)
+
+	assert.Equal(t, http.StatusOK, authStatus(signedToken(t, session.TokenTypeAccess, "live")))
+	assert.Equal(t, http.StatusOK, authStatus(signedToken(t, session.TokenTypeImpersonation, "")))
+	assert.Equal(t, http.StatusUnauthorized, authStatus(signedToken(t, "refresh", "live")), "refresh tokens are no bearer tokens")
+	assert.Equal(t, http.StatusUnauthorized, authStatus(signedToken(t, "", "live")))
+	assert.Equal(t, http.StatusUnauthorized, authStatus(signedToken(t, session.TokenTypeAccess, "revoked")))
+}
+
+func TestRequireAuth_RecordsImpersonatedRequestsFirst(t *testing.T) {
+	initTestJWT(t)
+	up := true
+	var recorded []impersonation.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body struct {
+			Requests []impersonation.Request `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		recorded = append(recorded, body.Requests...)
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]int{"recorded": len(body.Requests)}})
+	}))
+	defer server.Close()
+	token := sign(t, session.TokenTypeImpersonation, "", &policy.Actor{UserID: 9, Email: "admin@example.com"})
+
+	assert.Equal(t, http.StatusServiceUnavailable, authStatus(token), "never served unaudited")
+
+	RecordImpersonations(impersonation.NewRecorder(server.URL, server.Client()))
+	defer RecordImpersonations(nil)
+	assert.Equal(t, http.StatusOK, authStatus(token))
+	require.Len(t, recorded, 1)
+	assert.Equal(t, token, recorded[0].Token)
+	assert.Equal(t, http.MethodGet, recorded[0].Method)
+
+	up = false
+	assert.Equal(t, http.StatusServiceUnavailable, authStatus(token))
+	assert.Equal(t, http.StatusOK, authStatus(signedToken(t, session.TokenTypeAccess, "live")), "other tokens need no audit")
+}
@@ -8,10 +8,12 @@ import (
 
 type Booking struct {
 	gorm.Model
-	UserID     uint      `gorm:"not null;index"`
-	SpaceID    uint      `gorm:"not null;index"`
-	StartTime  time.Time `gorm:"not null"`
-	EndTime    time.Time `gorm:"not null"`
-	Status     string    `gorm:"type:varchar(50);default:'PENDING'"` // PENDING, CONFIRMED, CANCELED
-	TotalPrice float64   `gorm:"type:decimal(10,2);not null"`
+	UserID    uint      `gorm:"not null;index;index:idx_bookings_org_member,priority:2"`
+	SpaceID   uint      `gorm:"not null;index"`
+	StartTime time.Time `gorm:"not null;index:idx_bookings_org_member,priority:3"`
+	EndTime   time.Time `gorm:"not null"`
+	// set when the booking is billed to an organization of the user instead of the user
+	OrganizationID *uint   `gorm:"index:idx_bookings_org_member,priority:1" json:",omitempty"`
+	Status         string  `gorm:"type:varchar(50);default:'PENDING'"` // PENDING, CONFIRMED, CANCELED
+	TotalPrice     float64 `gorm:"type:decimal(10,2);not null"`
 }
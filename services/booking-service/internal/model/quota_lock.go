@@ -0,0 +1,9 @@
+package model
+
+// QuotaLock has one row per member of an organization who booked on its account. The row is
+// locked while a new booking is checked against the quota of the member, so the check is
+// serialised even when the member has no booking to lock yet.
+type QuotaLock struct {
+	OrganizationID uint `gorm:"primaryKey;autoIncrement:false"`
+	UserID         uint `gorm:"primaryKey;autoIncrement:false"`
+}
@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"booking-service/constant"
 	"booking-service/internal/model"
 	"context"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookingRepository interface {
@@ -15,6 +17,10 @@ type BookingRepository interface {
 	GetByUserID(userID uint) ([]model.Booking, error)
 	GetAll() ([]model.Booking, error)
 	FindOverlaps(ctx context.Context, spaceIDs []uint, start, end time.Time) ([]uint, error)
+	CancelPendingByUser(ctx context.Context, userID uint, after time.Time) (int64, error)
+	CreateWithinQuota(ctx context.Context, booking *model.Booking, from, to time.Time, check func(booked []model.Booking) error) error
+	GetByOrganization(ctx context.Context, orgID uint, from, to time.Time) ([]model.Booking, error)
+	GetBySpaces(ctx context.Context, spaceIDs []uint) ([]model.Booking, error)
 }
 
 type bookingRepository struct {
@@ -70,4 +76,72 @@ func (r *bookingRepository) FindOverlaps(ctx context.Context, spaceIDs []uint, s
 		return nil, err
 	}
 	return result, nil
-}
\ No newline at end of file
+}
+
+// CancelPendingByUser cancels the unpaid bookings of the user that have not started yet.
+func (r *bookingRepository) CancelPendingByUser(ctx context.Context, userID uint, after time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.Booking{}).
+		Where("user_id = ? AND status = ? AND start_time > ?", userID, constant.BookingStatusPending, after).
+		Update("status", constant.BookingStatusCancelled)
+	return res.RowsAffected, res.Error
+}
+
+// CreateWithinQuota creates a booking billed to an organization once check accepts it, given the
+// bookings the same member billed to the organization starting between from and to. The quota
+// lock of the member, created on the first booking, stays locked until the booking is stored,
+// so two bookings made at once cannot both use the rest of a quota.
+func (r *bookingRepository) CreateWithinQuota(ctx context.Context, booking *model.Booking, from, to time.Time, check func(booked []model.Booking) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lock := model.QuotaLock{OrganizationID: *booking.OrganizationID, UserID: booking.UserID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&lock).First(&model.QuotaLock{}).Error; err != nil {
+			return err
+		}
+
+		var booked []model.Booking
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("organization_id = ? AND user_id = ?", booking.OrganizationID, booking.UserID).
+			Where("start_time >= ? AND start_time < ?", from, to).
+			Where("status <> ?", constant.BookingStatusCancelled).
+			Find(&booked).Error
+		if err != nil {
+			return err
+		}
+		if err := check(booked); err != nil {
+			return err
+		}
+		return tx.Create(booking).Error
+	})
+}
+
+// GetByOrganization returns the bookings billed to the organization starting between from and
+// to, cancelled ones left out.
+func (r *bookingRepository) GetByOrganization(ctx context.Context, orgID uint, from, to time.Time) ([]model.Booking, error) {
+	var bookings []model.Booking
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Where("start_time >= ? AND start_time < ?", from, to).
+		Where("status <> ?", constant.BookingStatusCancelled).
+		Order("start_time").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// GetBySpaces returns the bookings of the spaces, the latest starting first.
+func (r *bookingRepository) GetBySpaces(ctx context.Context, spaceIDs []uint) ([]model.Booking, error) {
+	var bookings []model.Booking
+	err := r.db.WithContext(ctx).
+		Where("space_id IN ?", spaceIDs).
+		Order("start_time DESC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -3,23 +3,31 @@ package router
 import (
 	"booking-service/internal/handler"
 	"booking-service/internal/middleware"
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/userdata"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(bookingHandler *handler.BookingHandler) *gin.Engine {
+func SetupRouter(bookingHandler *handler.BookingHandler, serviceVerifier *servicetoken.Verifier) *gin.Engine {
 	router := gin.Default()
 	router.HandleMethodNotAllowed = true // return 405 on wrong method
 
-	router.POST("/api/v1/bookings", middleware.RequireAuth("user"), bookingHandler.CreateBooking)
-	router.PUT("/api/v1/bookings/:id/status", bookingHandler.UpdateBookingStatus)
+	router.POST("/api/v1/bookings", middleware.RequireAuth(policy.BookingCreate), bookingHandler.CreateBooking)
+	router.PUT("/api/v1/bookings/:id/status", middleware.RequireService(serviceVerifier, servicetoken.PaymentService), bookingHandler.UpdateBookingStatus)
 	router.GET("/api/v1/bookings/:id", bookingHandler.GetBookingByID)
-	router.GET("/api/v1/bookings/me", middleware.RequireAuth("user"), bookingHandler.GetBookingByUserID)
-	router.GET("/api/v1/bookings", middleware.RequireAuth("admin", "moderator"), bookingHandler.GetAllBooking)
+	router.GET("/api/v1/bookings/me", middleware.RequireAuth(), bookingHandler.GetBookingByUserID)
+	router.GET("/api/v1/bookings", middleware.RequireAuth(policy.BookingReadAll), bookingHandler.GetAllBooking)
+	router.GET("/api/v1/bookings/organizations/:id", middleware.RequireAuth(), bookingHandler.GetOrganizationStatement)
+	router.GET("/api/v1/bookings/venues/:id", middleware.RequireAuth(), bookingHandler.GetVenueBookings)
 
-	router.POST("/api/v1/internal/bookings/check-availability", bookingHandler.CheckAvailability)
+	router.POST("/api/v1/internal/bookings/check-availability", middleware.RequireService(serviceVerifier, servicetoken.VenueService), bookingHandler.CheckAvailability)
+	router.GET("/api/v1/internal/bookings", middleware.RequireService(serviceVerifier, servicetoken.PaymentService), bookingHandler.ListUserBookingIDs)
+	router.GET(userdata.Route, middleware.RequireService(serviceVerifier, servicetoken.AuthService), bookingHandler.ExportUserData)
+	router.DELETE(userdata.Route, middleware.RequireService(serviceVerifier, servicetoken.AuthService), bookingHandler.EraseUserData)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"booking-service/constant"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"packages/servicetoken"
+	"time"
+)
+
+type UserService interface {
+	GetMembership(ctx context.Context, orgID, userID uint) (*Membership, error)
+}
+
+// Membership is an active member of an organization. A nil quota means no limit.
+type Membership struct {
+	OrganizationID uint     `json:"organization_id"`
+	UserID         uint     `json:"user_id"`
+	Role           string   `json:"role"`
+	MonthlyHours   *float64 `json:"monthly_hours"`
+	MonthlySpend   *float64 `json:"monthly_spend"`
+}
+
+type membershipResponse struct {
+	Data Membership `json:"data"`
+}
+
+type userHTTPService struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewUserHTTPService calls user-service, authenticated with a service token.
+func NewUserHTTPService(baseURL string, issuer *servicetoken.Issuer) UserService {
+	return &userHTTPService{
+		baseURL: baseURL,
+		client:  issuer.Client(servicetoken.UserService, 5*time.Second),
+	}
+}
+
+// GetMembership returns constant.ErrNotOrganizationMember when the user is not an active member.
+func (s *userHTTPService) GetMembership(ctx context.Context, orgID, userID uint) (*Membership, error) {
+	url := fmt.Sprintf("%s/api/v1/internal/organizations/%d/members/%d", s.baseURL, orgID, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, constant.ErrNotOrganizationMember
+	default:
+		return nil, fmt.Errorf("user service returned status %d", resp.StatusCode)
+	}
+
+	var result membershipResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
@@ -1,14 +1,20 @@
 package service
 
 import (
+	"booking-service/constant"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"packages/servicetoken"
 	"time"
 )
 
 type VenueService interface {
 	GetSpaceByID(spaceID uint) (*Space, error)
+	CheckOpening(ctx context.Context, spaceID uint, start, end time.Time) (*Opening, error)
+	GetVenueAccess(ctx context.Context, venueID, userID uint) (*VenueAccess, error)
 }
 
 type venueHTTPService struct {
@@ -30,10 +36,39 @@ type Space struct {
   Description string  `json:"Description"`
 }
 
-func NewVenueHTTPService(baseURL string) VenueService {
+// Opening tells whether a space is open over a whole period, and if not why.
+type Opening struct {
+	Open   bool   `json:"open"`
+	Reason string `json:"reason"`
+}
+
+type openingResponse struct {
+	Data Opening `json:"data"`
+}
+
+// VenueAccess lists the spaces of a venue and the roles a user holds on the venue and on them.
+type VenueAccess struct {
+	VenueID  uint          `json:"venue_id"`
+	SpaceIDs []uint        `json:"space_ids"`
+	Grants   []ScopedGrant `json:"grants"`
+}
+
+// ScopedGrant is a role held on the venue or space ID.
+type ScopedGrant struct {
+	Role  string `json:"role"`
+	Scope string `json:"scope"`
+	ID    uint   `json:"id"`
+}
+
+type venueAccessResponse struct {
+	Data VenueAccess `json:"data"`
+}
+
+// NewVenueHTTPService calls venue-service, authenticated with a service token.
+func NewVenueHTTPService(baseURL string, issuer *servicetoken.Issuer) VenueService {
 	return &venueHTTPService{
 		baseURL: baseURL,
-		client:  &http.Client{Timeout: 5 * time.Second},
+		client:  issuer.Client(servicetoken.VenueService, 5*time.Second),
 	}
 }
 
@@ -56,3 +91,65 @@ func (s *venueHTTPService) GetSpaceByID(spaceID uint) (*Space, error) {
 
   return &result.Data, nil
 }
+
+// CheckOpening asks venue-service whether the space is open from start to end, going by its
+// opening hours, the public holidays of its venue and its closures. It returns
+// constant.ErrSpaceNotFound when there is no such space.
+func (s *venueHTTPService) CheckOpening(ctx context.Context, spaceID uint, start, end time.Time) (*Opening, error) {
+	query := url.Values{}
+	query.Set("start", start.Format(time.RFC3339))
+	query.Set("end", end.Format(time.RFC3339))
+	url := fmt.Sprintf("%s/api/v1/internal/spaces/%d/opening?%s", s.baseURL, spaceID, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, constant.ErrSpaceNotFound
+	default:
+		return nil, fmt.Errorf("venue service returned status %d", resp.StatusCode)
+	}
+
+	var result openingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// GetVenueAccess asks venue-service which roles the user holds on the venue and its spaces. It
+// returns constant.ErrVenueNotFound when there is no such venue.
+func (s *venueHTTPService) GetVenueAccess(ctx context.Context, venueID, userID uint) (*VenueAccess, error) {
+	url := fmt.Sprintf("%s/api/v1/internal/venues/%d/access?user_id=%d", s.baseURL, venueID, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, constant.ErrVenueNotFound
+	default:
+		return nil, fmt.Errorf("venue service returned status %d", resp.StatusCode)
+	}
+
+	var result venueAccessResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
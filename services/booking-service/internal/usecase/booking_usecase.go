@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"booking-service/constant"
+	"booking-service/internal/dto"
 	"booking-service/internal/kafka"
 	"booking-service/internal/model"
 	"booking-service/internal/repository"
@@ -10,29 +11,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"packages/policy"
+	"packages/preferences"
+	"sort"
 	"time"
 )
 
 type BookingUsecase interface {
-	BookSpace(userID, spaceID uint, start, end time.Time) (*model.Booking, error)
+	BookSpace(userID, spaceID uint, orgID *uint, start, end time.Time) (*model.Booking, error)
 	UpdateStatus(id uint, status string) (*model.Booking, error)
 	GetBookingByID(id uint) (*model.Booking, error)
 	GetBookingByUserID(userID uint) ([]model.Booking, error)
 	GetAllBooking() ([]model.Booking, error)
 	CheckAvailability(ctx context.Context, spaceIDs []uint, start time.Time, end time.Time) ([]uint, error)
+	EraseUserData(ctx context.Context, userID uint) error
+	GetOrganizationStatement(ctx context.Context, orgID, userID uint, role string, month time.Time) (*dto.OrganizationStatement, error)
+	GetVenueBookings(ctx context.Context, sub policy.Subject, venueID uint) ([]model.Booking, error)
 }
 
 type bookingUsecase struct {
 	repo         repository.BookingRepository
 	venueService service.VenueService
+	userService  service.UserService
 	producer     kafka.KafkaProducer
 }
 
-func NewBookingUsecase(r repository.BookingRepository, venueService service.VenueService, producer kafka.KafkaProducer) BookingUsecase {
-	return &bookingUsecase{r, venueService, producer}
+func NewBookingUsecase(r repository.BookingRepository, venueService service.VenueService, userService service.UserService, producer kafka.KafkaProducer) BookingUsecase {
+	return &bookingUsecase{r, venueService, userService, producer}
 }
 
-func (uc *bookingUsecase) BookSpace(userID, spaceID uint, start, end time.Time) (*model.Booking, error) {
+// BookSpace books the space for the user, billed to the organization orgID when it is set. The
+// user must then be an active member of it, within their monthly quotas. The space must be open
+// throughout the booking.
+func (uc *bookingUsecase) BookSpace(userID, spaceID uint, orgID *uint, start, end time.Time) (*model.Booking, error) {
 	space, err := uc.venueService.GetSpaceByID(spaceID)
 	if err != nil {
 		return nil, constant.ErrSpaceNotFound
@@ -45,6 +56,14 @@ func (uc *bookingUsecase) BookSpace(userID, spaceID uint, start, end time.Time)
 		return nil, constant.ErrInvalidBookingTime
 	}
 
+	opening, err := uc.venueService.CheckOpening(context.Background(), spaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if !opening.Open {
+		return nil, fmt.Errorf("%w: %s", constant.ErrSpaceClosed, opening.Reason)
+	}
+
 	totalPrice := duration * space.Price
 
 	booking := &model.Booking{
@@ -56,16 +75,24 @@ func (uc *bookingUsecase) BookSpace(userID, spaceID uint, start, end time.Time)
 		Status:     constant.BookingStatusPending,
 	}
 
-	if err := uc.repo.Create(booking); err != nil {
+	if orgID != nil {
+		booking.OrganizationID = orgID
+		if err := uc.createForOrganization(booking); err != nil {
+			return nil, err
+		}
+	} else if err := uc.repo.Create(booking); err != nil {
 		return nil, err
 	}
 
 	// === Push Kafka event ===
+	// notification-service writes the content in the language and timezone of the user from
+	// space_name and the booking; content is the fallback
 	event := map[string]interface{}{
-		"user_id": userID,
-		"type":    "BOOKING_CREATED",
-		"content": fmt.Sprintf("You booked %s from %s to %s", space.Name, start, end),
-		"booking": booking,
+		"user_id":    userID,
+		"type":       preferences.EventBookingCreated,
+		"content":    fmt.Sprintf("You booked %s from %s to %s", space.Name, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339)),
+		"space_name": space.Name,
+		"booking":    booking,
 	}
 	payload, _ := json.Marshal(event)
 	if err := uc.producer.Publish(context.Background(), []byte(fmt.Sprint(userID)), payload); err != nil {
@@ -94,7 +121,7 @@ func (u *bookingUsecase) UpdateStatus(id uint, status string) (*model.Booking, e
 	if u.producer != nil {
 		event := map[string]interface{}{
 			"user_id": booking.UserID,
-			"type":    "BOOKING_STATUS_UPDATED",
+			"type":    preferences.EventBookingStatusUpdated,
 			"content": fmt.Sprintf("Your booking %d status changed to %s", booking.ID, booking.Status),
 			"booking": booking,
 		}
@@ -133,3 +160,131 @@ func (u *bookingUsecase) CheckAvailability(ctx context.Context, spaceIDs []uint,
 
 	return u.repo.FindOverlaps(ctx, spaceIDs, start, end)
 }
+
+// EraseUserData runs when the account of the user is deleted. Bookings are financial records and
+// are kept; only those still waiting for payment are cancelled so the spaces free up.
+func (u *bookingUsecase) EraseUserData(ctx context.Context, userID uint) error {
+	cancelled, err := u.repo.CancelPendingByUser(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if cancelled > 0 {
+		log.Printf("cancelled %d pending bookings of deleted user %d", cancelled, userID)
+	}
+	return nil
+}
+
+// createForOrganization stores a booking billed to an organization, checking the monthly quotas
+// of the member against what they booked on its account in the UTC month the booking starts.
+func (uc *bookingUsecase) createForOrganization(booking *model.Booking) error {
+	ctx := context.Background()
+	member, err := uc.userService.GetMembership(ctx, *booking.OrganizationID, booking.UserID)
+	if err != nil {
+		return err
+	}
+	if member.MonthlyHours == nil && member.MonthlySpend == nil {
+		return uc.repo.Create(booking)
+	}
+
+	from, to := monthRange(booking.StartTime)
+	return uc.repo.CreateWithinQuota(ctx, booking, from, to, func(booked []model.Booking) error {
+		hours, spend := bookingHours(booking), booking.TotalPrice
+		for i := range booked {
+			hours += bookingHours(&booked[i])
+			spend += booked[i].TotalPrice
+		}
+		if member.MonthlyHours != nil && hours > *member.MonthlyHours {
+			return constant.ErrQuotaExceeded
+		}
+		if member.MonthlySpend != nil && spend > *member.MonthlySpend {
+			return constant.ErrQuotaExceeded
+		}
+		return nil
+	})
+}
+
+// GetOrganizationStatement sums the bookings billed to the organization in the UTC month of
+// month, per member. Owners and admins of the organization may read it, as may staff allowed to
+// read all bookings.
+func (u *bookingUsecase) GetOrganizationStatement(ctx context.Context, orgID, userID uint, role string, month time.Time) (*dto.OrganizationStatement, error) {
+	if !policy.Can(role, policy.BookingReadAll) {
+		member, err := u.userService.GetMembership(ctx, orgID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member.Role != constant.OrgRoleOwner && member.Role != constant.OrgRoleAdmin {
+			return nil, constant.ErrNotOrganizationMember
+		}
+	}
+
+	from, to := monthRange(month)
+	bookings, err := u.repo.GetByOrganization(ctx, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &dto.OrganizationStatement{
+		OrganizationID: orgID,
+		Month:          from.Format("2006-01"),
+		Members:        []dto.MemberStatement{},
+		Bookings:       bookings,
+	}
+	members := map[uint]*dto.MemberStatement{}
+	for i := range bookings {
+		b := &bookings[i]
+		m, ok := members[b.UserID]
+		if !ok {
+			m = &dto.MemberStatement{UserID: b.UserID}
+			members[b.UserID] = m
+		}
+		hours := bookingHours(b)
+		m.Bookings++
+		m.Hours += hours
+		m.Amount += b.TotalPrice
+		statement.TotalHours += hours
+		statement.TotalAmount += b.TotalPrice
+	}
+	for _, m := range members {
+		statement.Members = append(statement.Members, *m)
+	}
+	sort.Slice(statement.Members, func(i, j int) bool { return statement.Members[i].UserID < statement.Members[j].UserID })
+	return statement, nil
+}
+
+// GetVenueBookings returns the bookings of the venue's spaces the subject may read: all of them
+// for its owner and staff, or those of the spaces they manage.
+func (u *bookingUsecase) GetVenueBookings(ctx context.Context, sub policy.Subject, venueID uint) ([]model.Booking, error) {
+	access, err := u.venueService.GetVenueAccess(ctx, venueID, sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range access.Grants {
+		sub.Grants = append(sub.Grants, policy.Grant{Role: g.Role, Scope: policy.Scope{Kind: g.Scope, ID: g.ID}})
+	}
+
+	venue := policy.VenueScope(venueID)
+	var spaceIDs []uint
+	for _, id := range access.SpaceIDs {
+		if sub.Can(policy.BookingRead, policy.SpaceScope(id), venue) {
+			spaceIDs = append(spaceIDs, id)
+		}
+	}
+	if len(spaceIDs) == 0 {
+		if !sub.Can(policy.BookingRead, venue) {
+			return nil, constant.ErrForbidden
+		}
+		return []model.Booking{}, nil
+	}
+	return u.repo.GetBySpaces(ctx, spaceIDs)
+}
+
+// monthRange returns the start of the UTC month of t and of the month after.
+func monthRange(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, 0)
+}
+
+func bookingHours(b *model.Booking) float64 {
+	return b.EndTime.Sub(b.StartTime).Hours()
+}
@@ -14,12 +14,19 @@ const (
 	ErrInternalServer          = "internal server error"
 	ErrUserNotFound            = "user not found"
 	ErrUnauthorized            = "unauthorized"
+	ErrFailedToExportData      = "error.failed_to_export_user_data"
+	ErrFailedToEraseData       = "error.failed_to_erase_user_data"
 )
 
 const (
 	SuccessGetConversation = "success.get_conversation"
+	SuccessEraseUserData   = "success.erase_user_data"
 )
 
+// DeletedMessageContent replaces the content of the messages of a deleted account. The messages
+// themselves stay so the other side of each conversation keeps its thread.
+const DeletedMessageContent = "[deleted]"
+
 const (
 	GetUserUrl = "/api/v1/users/%d"
 )
@@ -11,7 +11,10 @@ type ChatMessageDTO struct {
 }
 
 type UserResponse struct {
-	ID    uint   `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID             uint   `json:"id"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
 }
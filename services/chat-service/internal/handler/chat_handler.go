@@ -89,3 +89,37 @@ func (h *ChatHandler) GetConversation(c *gin.Context) {
 		"data":    messages,
 	})
 }
+
+// ExportUserData returns the messages of a user for their personal data export.
+// Internal endpoint called by auth-service.
+func (h *ChatHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	messages, err := h.chatUsecase.ExportUserData(c.Request.Context(), uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"messages": messages}})
+}
+
+// EraseUserData handles the deletion of a user account. Internal endpoint called by auth-service.
+func (h *ChatHandler) EraseUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	if err := h.chatUsecase.EraseUserData(c.Request.Context(), uint(userID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": constant.SuccessEraseUserData})
+}
@@ -10,6 +10,8 @@ import (
 type ChatRepository interface {
 	SaveMessage(ctx context.Context, msg *model.ChatMessage) error
 	GetConversation(ctx context.Context, user1, user2 uint) ([]model.ChatMessage, error)
+	GetUserMessages(ctx context.Context, userID uint) ([]model.ChatMessage, error)
+	ReplaceSentContent(ctx context.Context, senderID uint, content string) (int64, error)
 }
 
 type chatRepository struct {
@@ -32,3 +34,22 @@ func (r *chatRepository) GetConversation(ctx context.Context, user1, user2 uint)
 		Find(&messages).Error
 	return messages, err
 }
+
+// GetUserMessages returns every message the user sent or received.
+func (r *chatRepository) GetUserMessages(ctx context.Context, userID uint) ([]model.ChatMessage, error) {
+	var messages []model.ChatMessage
+	err := r.db.WithContext(ctx).
+		Where("sender_id = ? OR receiver_id = ?", userID, userID).
+		Order("created_at ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// ReplaceSentContent overwrites the content of every message sent by the user.
+func (r *chatRepository) ReplaceSentContent(ctx context.Context, senderID uint, content string) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.ChatMessage{}).
+		Where("sender_id = ?", senderID).
+		Update("content", content)
+	return res.RowsAffected, res.Error
+}
@@ -6,11 +6,14 @@ import (
 	"chat-service/internal/repository"
 	"context"
 	"errors"
+	"log"
 )
 
 type ChatUsecase interface {
 	SaveMessage(ctx context.Context, msg *model.ChatMessage) error
 	GetConversation(ctx context.Context, user1, user2 uint) ([]model.ChatMessage, error)
+	ExportUserData(ctx context.Context, userID uint) ([]model.ChatMessage, error)
+	EraseUserData(ctx context.Context, userID uint) error
 }
 
 type chatUsecase struct {
@@ -40,3 +43,24 @@ func (u *chatUsecase) GetConversation(ctx context.Context, user1, user2 uint) ([
 	}
 	return u.chatRepo.GetConversation(ctx, user1, user2)
 }
+
+// ExportUserData returns the messages the user sent and received. The user is not looked up in
+// user-service: the account may already be on its way out.
+func (u *chatUsecase) ExportUserData(ctx context.Context, userID uint) ([]model.ChatMessage, error) {
+	messages, err := u.chatRepo.GetUserMessages(ctx, userID)
+	if err != nil {
+		return nil, errors.New(constant.ErrFailedToExportData)
+	}
+	return messages, nil
+}
+
+// EraseUserData blanks out what the user wrote. Messages received by the user belong to their
+// senders and are left alone.
+func (u *chatUsecase) EraseUserData(ctx context.Context, userID uint) error {
+	erased, err := u.chatRepo.ReplaceSentContent(ctx, userID, constant.DeletedMessageContent)
+	if err != nil {
+		return errors.New(constant.ErrFailedToEraseData)
+	}
+	log.Printf("erased %d messages of deleted user %d", erased, userID)
+	return nil
+}
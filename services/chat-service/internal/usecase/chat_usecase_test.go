@@ -28,6 +28,16 @@ func (m *MockChatRepo) GetConversation(ctx context.Context, user1, user2 uint) (
 	return args.Get(0).([]model.ChatMessage), args.Error(1)
 }
 
+func (m *MockChatRepo) GetUserMessages(ctx context.Context, userID uint) ([]model.ChatMessage, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.ChatMessage), args.Error(1)
+}
+
+func (m *MockChatRepo) ReplaceSentContent(ctx context.Context, senderID uint, content string) (int64, error) {
+	args := m.Called(ctx, senderID, content)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Mock UserClient
 type MockUserClient struct {
 	mock.Mock
@@ -116,3 +126,29 @@ func TestGetConversation_RepoSuccess_ReturnMessages(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, msgs)
 }
+
+func TestEraseUserData_ReplacesSentContent(t *testing.T) {
+	mockRepo := new(MockChatRepo)
+	mockUser := new(MockUserClient)
+	uc := usecase.NewChatUsecase(mockRepo, mockUser)
+
+	mockRepo.On("ReplaceSentContent", mock.Anything, uint(1), constant.DeletedMessageContent).
+		Return(int64(3), nil)
+
+	err := uc.EraseUserData(context.Background(), 1)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockUser.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func TestEraseUserData_RepoFails_ReturnError(t *testing.T) {
+	mockRepo := new(MockChatRepo)
+	mockUser := new(MockUserClient)
+	uc := usecase.NewChatUsecase(mockRepo, mockUser)
+
+	mockRepo.On("ReplaceSentContent", mock.Anything, uint(1), constant.DeletedMessageContent).
+		Return(int64(0), errors.New("db error"))
+
+	err := uc.EraseUserData(context.Background(), 1)
+	assert.EqualError(t, err, constant.ErrFailedToEraseData)
+}
@@ -6,8 +6,14 @@ import (
 	"chat-service/internal/repository"
 	"chat-service/internal/usecase"
 	ws "chat-service/internal/websocket"
+	"context"
 	"log"
 	"os"
+	"packages/impersonation"
+	"packages/servicetoken"
+	"packages/session"
+	"packages/userdata"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -20,6 +26,24 @@ func SetupRouter(r *gin.Engine, db *gorm.DB) {
 	if baseURL == "" {
 		log.Fatal("missing env: USER_SERVICE_URL")
 	}
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceVerifier := servicetoken.NewVerifier(servicetoken.ChatService, serviceSecret)
+
+	// refuse the tokens of sessions revoked in auth-service
+	authURL := os.Getenv("AUTH_SERVICE_URL")
+	if authURL == "" {
+		log.Fatal("missing env: AUTH_SERVICE_URL")
+	}
+	authClient := servicetoken.NewIssuer(servicetoken.ChatService, serviceSecret).Client(servicetoken.AuthService, 5*time.Second)
+	revocations := session.NewRevocationStore(session.HTTPSource(authURL, authClient))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	middleware.TrackRevocations(revocations)
+	// record impersonated requests in auth-service before serving them
+	middleware.RecordImpersonations(impersonation.NewRecorder(authURL, authClient))
+
 	chatRepo := repository.NewChatRepository(db)
 	userClient := repository.NewUserClient(baseURL)
 	chatUC := usecase.NewChatUsecase(chatRepo, userClient)
@@ -30,5 +54,9 @@ func SetupRouter(r *gin.Engine, db *gorm.DB) {
 	chatApi.GET("/ws", chatHandler.SendMessage)
 	chatApi.GET("/conversations/:user2", middleware.RequireAuth(), chatHandler.GetConversation)
 
+	requireAuthService := middleware.RequireService(serviceVerifier, servicetoken.AuthService)
+	r.GET(userdata.Route, requireAuthService, chatHandler.ExportUserData)
+	r.DELETE(userdata.Route, requireAuthService, chatHandler.EraseUserData)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
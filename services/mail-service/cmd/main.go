@@ -15,6 +15,8 @@ func main() {
 	}
 	cfg := config.LoadConfig()
 	mailSender := utils.NewMailSender(cfg)
+	prefs := utils.NewPreferenceStore()
+	kafka.StartPreferenceConsumer(cfg, prefs)
 	log.Println("Mail Service started...")
-	kafka.StartConsumer(cfg, mailSender)
+	kafka.StartConsumer(cfg, mailSender, prefs)
 }
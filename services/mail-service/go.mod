@@ -6,6 +6,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/segmentio/kafka-go v0.4.48
 	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
+	packages v0.0.0
 )
 
 require (
@@ -16,3 +17,5 @@ require (
 	golang.org/x/text v0.23.0 // indirect
 	gopkg.in/alexcesaro/quotedprintable.v3 v3.0.0-20150716171945-2caba252f4dc // indirect
 )
+
+replace packages => ../../packages
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"packages/preferences"
 	"strconv"
 )
 
@@ -14,6 +15,8 @@ type MailConfig struct {
 	AppBaseUrl     string
 	KafkaBroker    string
 	KafkaMailTopic string
+	// compacted topic user-service publishes preferences to
+	KafkaPreferenceTopic string
 }
 
 func LoadConfig() *MailConfig {
@@ -22,13 +25,17 @@ func LoadConfig() *MailConfig {
 		log.Fatal("Invalid SMTP port")
 	}
 	cfg := &MailConfig{
-		FromEmail:      os.Getenv("FROM_EMAIL"),
-		Password:       os.Getenv("FROM_EMAIL_PASSWORD"),
-		Host:           os.Getenv("FROM_EMAIL_SMTP_HOST"),
-		Port:           port,
-		AppBaseUrl:     os.Getenv("AUTH_SERVICE_URL"),
-		KafkaBroker:    os.Getenv("KAFKA_BROKERS"),
-		KafkaMailTopic: os.Getenv("KAFKA_TOPIC_VERIFY_EMAIL"),
+		FromEmail:            os.Getenv("FROM_EMAIL"),
+		Password:             os.Getenv("FROM_EMAIL_PASSWORD"),
+		Host:                 os.Getenv("FROM_EMAIL_SMTP_HOST"),
+		Port:                 port,
+		AppBaseUrl:           os.Getenv("AUTH_SERVICE_URL"),
+		KafkaBroker:          os.Getenv("KAFKA_BROKERS"),
+		KafkaMailTopic:       os.Getenv("KAFKA_TOPIC_VERIFY_EMAIL"),
+		KafkaPreferenceTopic: os.Getenv(preferences.EnvTopic),
+	}
+	if cfg.KafkaPreferenceTopic == "" {
+		cfg.KafkaPreferenceTopic = preferences.DefaultTopic
 	}
 	if cfg.FromEmail == "" || cfg.Password == "" || cfg.Host == "" || cfg.AppBaseUrl == "" || cfg.KafkaBroker == "" || cfg.KafkaMailTopic == "" {
 		log.Fatal("missing required email environment variables")
@@ -3,6 +3,21 @@ package constant
 const (
 	EventTypeVerifyEmail   = "VERIFY_EMAIL"
 	EventTypeResetPassword = "RESET_PASSWORD"
+	EventTypeAccountLocked = "ACCOUNT_LOCKED"
+	EventTypeEmailChange   = "EMAIL_CHANGE_CONFIRM"
+	EventTypeEmailNotice   = "EMAIL_CHANGE_NOTICE"
+	EventTypeMagicLink     = "MAGIC_LINK"
+	EventTypeInvitation    = "USER_INVITATION"
+	EventTypeOrgInvitation = "ORGANIZATION_INVITATION"
+	EventTypeSuspended     = "ACCOUNT_SUSPENDED"
+	EventTypeReinstated    = "ACCOUNT_REINSTATED"
 	MailServiceGroup       = "mail-service-group"
 	VerifyAccountUrl       = "/api/v1/auth/verify-account"
+	ConfirmEmailChangeUrl  = "/api/v1/auth/email-change/confirm"
+	RevertEmailChangeUrl   = "/api/v1/auth/email-change/revert"
+	MagicLinkUrl           = "/api/v1/auth/magic-link/redeem"
+	// page of the web app that posts the chosen password to /api/v1/auth/invitation/accept
+	AcceptInvitationUrl = "/accept-invitation"
+	// page of the web app listing the organization invitations of the signed-in user
+	OrgInvitationsUrl = "/organizations/invitations"
 )
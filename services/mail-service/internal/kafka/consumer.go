@@ -7,17 +7,20 @@ import (
 	"mail-service/internal/config"
 	"mail-service/internal/constant"
 	"mail-service/internal/utils"
+	"packages/preferences"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
 type MailEvent struct {
-	Email string            `json:"email"`
-	Type  string            `json:"type"` // "VERIFY_EMAIL"
-	Data  map[string]string `json:"data,omitempty"`
+	UserID uint              `json:"user_id,omitempty"`
+	Email  string            `json:"email"`
+	Type   string            `json:"type"` // "VERIFY_EMAIL"
+	Data   map[string]string `json:"data,omitempty"`
 }
 
-func StartConsumer(cfg *config.MailConfig, sender *utils.MailSender) {
+func StartConsumer(cfg *config.MailConfig, sender *utils.MailSender, prefs *utils.PreferenceStore) {
 	r := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: []string{cfg.KafkaBroker},
 		Topic:   cfg.KafkaMailTopic,
@@ -53,8 +56,113 @@ func StartConsumer(cfg *config.MailConfig, sender *utils.MailSender) {
 				continue
 			}
 			sender.SendResetPassword(event.Email, newPassword)
+		case constant.EventTypeAccountLocked:
+			lockedUntil, err := time.Parse(time.RFC3339, event.Data["lockedUntil"])
+			if err != nil {
+				log.Println("Missing lock expiry in account locked email event")
+				continue
+			}
+			sender.SendAccountLocked(event.Email, prefs.Get(event.UserID).FormatTime(lockedUntil))
+		case constant.EventTypeEmailChange:
+			token := event.Data["token"]
+			if token == "" {
+				log.Println("Missing token in email change event")
+				continue
+			}
+			sender.SendEmailChangeConfirmation(event.Email, token)
+		case constant.EventTypeEmailNotice:
+			token, newEmail := event.Data["token"], event.Data["newEmail"]
+			if token == "" || newEmail == "" {
+				log.Println("Missing token or new email in email change notice event")
+				continue
+			}
+			sender.SendEmailChangeNotice(event.Email, newEmail, token)
+		case constant.EventTypeMagicLink:
+			token := event.Data["token"]
+			if token == "" {
+				log.Println("Missing token in magic link event")
+				continue
+			}
+			sender.SendMagicLink(event.Email, token)
+		case constant.EventTypeInvitation:
+			token := event.Data["token"]
+			if token == "" {
+				log.Println("Missing token in invitation event")
+				continue
+			}
+			sender.SendInvitation(event.Email, event.Data["name"], token)
+		case constant.EventTypeOrgInvitation:
+			organization := event.Data["organization"]
+			if organization == "" {
+				log.Println("Missing organization in organization invitation event")
+				continue
+			}
+			sender.SendOrganizationInvitation(event.Email, organization, event.Data["inviter"])
+		case constant.EventTypeSuspended:
+			// no end date means the suspension lasts until it is lifted
+			suspendedUntil := ""
+			if until, err := time.Parse(time.RFC3339, event.Data["suspendedUntil"]); err == nil {
+				suspendedUntil = prefs.Get(event.UserID).FormatTime(until)
+			}
+			sender.SendAccountSuspended(event.Email, event.Data["reason"], suspendedUntil)
+		case constant.EventTypeReinstated:
+			sender.SendAccountReinstated(event.Email)
 		default:
 			log.Println("Unknown mail type:", event.Type)
 		}
 	}
 }
+
+// StartPreferenceConsumer fills prefs from the preferences topic. It reads every partition
+// without a consumer group, from the start, so every instance gets the preferences of every
+// user. Partitions added to the topic later are read after a restart.
+func StartPreferenceConsumer(cfg *config.MailConfig, prefs *utils.PreferenceStore) {
+	go func() {
+		for _, p := range listPartitions(cfg.KafkaBroker, cfg.KafkaPreferenceTopic) {
+			go readPreferences(cfg, p.ID, prefs)
+		}
+	}()
+}
+
+func readPreferences(cfg *config.MailConfig, partition int, prefs *utils.PreferenceStore) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{cfg.KafkaBroker},
+		Topic:       cfg.KafkaPreferenceTopic,
+		Partition:   partition,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer r.Close()
+
+	for {
+		m, err := r.ReadMessage(context.Background())
+		if err != nil {
+			log.Println("Error reading message:", err)
+			continue
+		}
+
+		pref, err := preferences.Decode(m.Value)
+		if err != nil {
+			log.Println("Invalid preferences:", err)
+			continue
+		}
+		prefs.Set(pref)
+	}
+}
+
+// listPartitions asks the broker for the partitions of the topic, retrying until it answers.
+func listPartitions(broker, topic string) []kafka.Partition {
+	for {
+		conn, err := kafka.Dial("tcp", broker)
+		if err == nil {
+			partitions, err := conn.ReadPartitions(topic)
+			conn.Close()
+			if err == nil && len(partitions) > 0 {
+				return partitions
+			}
+			log.Printf("Error listing partitions of %s: %v", topic, err)
+		} else {
+			log.Println("Error connecting to kafka:", err)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"html/template"
 	"log"
 	"mail-service/internal/config"
 	"mail-service/internal/constant"
@@ -58,3 +59,126 @@ func (m *MailSender) SendResetPassword(userEmail string, resetPassword string) e
 	`, resetPassword)
 	return m.SendEmail(userEmail, subject, html)
 }
+
+// SendAccountLocked tells the user until when the account is locked, lockedUntil being already
+// written in their timezone.
+func (m *MailSender) SendAccountLocked(userEmail string, lockedUntil string) error {
+	subject := "Your account has been temporarily locked"
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>We noticed too many failed sign-in attempts on your account, so it has been locked until <b>%s</b>.</p>
+		<p>If this was not you, we recommend resetting your password once the lock expires or contacting support.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, lockedUntil)
+	return m.SendEmail(userEmail, subject, html)
+}
+
+func (m *MailSender) SendEmailChangeConfirmation(newEmail, token string) error {
+	link := fmt.Sprintf("%s%s?token=%s", m.cfg.AppBaseUrl, constant.ConfirmEmailChangeUrl, token)
+	subject := "Confirm your new email address"
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>You asked to use this address for your account. Please confirm it by clicking the link below:</p>
+		<a href="%s">Confirm Email</a>
+		<p>If you did not ask for this, you can ignore this email.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, link)
+	return m.SendEmail(newEmail, subject, html)
+}
+
+func (m *MailSender) SendEmailChangeNotice(oldEmail, newEmail, token string) error {
+	link := fmt.Sprintf("%s%s?token=%s", m.cfg.AppBaseUrl, constant.RevertEmailChangeUrl, token)
+	subject := "Your email address is being changed"
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>A request was made to change the email address of your account to <b>%s</b>.</p>
+		<p>If this was not you, click the link below to cancel the change, or to switch back to this address if it already happened, and sign out every device:</p>
+		<a href="%s">This was not me</a>
+		<p>We also recommend resetting your password.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, newEmail, link)
+	return m.SendEmail(oldEmail, subject, html)
+}
+
+func (m *MailSender) SendMagicLink(userEmail, token string) error {
+	link := fmt.Sprintf("%s%s?token=%s", m.cfg.AppBaseUrl, constant.MagicLinkUrl, token)
+	subject := "Your sign-in link"
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>Click the link below to sign in. It works once, for 15 minutes, on the device where you asked for it:</p>
+		<a href="%s">Sign in</a>
+		<p>If you did not ask for this, you can ignore this email.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, link)
+	return m.SendEmail(userEmail, subject, html)
+}
+
+// SendInvitation mails a user created by an admin the link to choose a password.
+func (m *MailSender) SendInvitation(userEmail, name, token string) error {
+	link := fmt.Sprintf("%s%s?token=%s", m.cfg.AppBaseUrl, constant.AcceptInvitationUrl, token)
+	subject := "You have been invited to Co-working Booking System"
+	html := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>An account was created for you. Click the link below to choose your password; the link works for 7 days:</p>
+		<a href="%s">Set up my account</a>
+		<p>If you were not expecting this, you can ignore this email.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, template.HTMLEscapeString(name), link)
+	return m.SendEmail(userEmail, subject, html)
+}
+
+// SendOrganizationInvitation tells a user they were invited to book on an organization's
+// account. The invitation is accepted in the app, signed in with this address.
+func (m *MailSender) SendOrganizationInvitation(userEmail, organization, inviter string) error {
+	link := fmt.Sprintf("%s%s", m.cfg.AppBaseUrl, constant.OrgInvitationsUrl)
+	subject := fmt.Sprintf("Join %s on Co-working Booking System", organization)
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>%s invited you to join <b>%s</b> and book spaces on its account.</p>
+		<p>Sign in, or sign up, with this email address to accept:</p>
+		<a href="%s">See my invitations</a>
+		<p>If you were not expecting this, you can ignore this email.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, template.HTMLEscapeString(inviter), template.HTMLEscapeString(organization), link)
+	return m.SendEmail(userEmail, subject, html)
+}
+
+// suspensionReasons words the reason codes of user-service for the user.
+var suspensionReasons = map[string]string{
+	"spam":            "sending spam",
+	"fraud":           "suspected fraud",
+	"harassment":      "harassment of other users or staff",
+	"payment_dispute": "an unresolved payment dispute",
+	"terms_violation": "a violation of our terms of service",
+}
+
+// SendAccountSuspended tells the user their account was suspended and why. suspendedUntil is
+// already written in their timezone, or empty when the suspension has no end date.
+func (m *MailSender) SendAccountSuspended(userEmail, reason, suspendedUntil string) error {
+	subject := "Your account has been suspended"
+	why := "."
+	if text, ok := suspensionReasons[reason]; ok {
+		why = fmt.Sprintf(" for %s.", text)
+	}
+	until := "until further notice"
+	if suspendedUntil != "" {
+		until = fmt.Sprintf("until <b>%s</b>", suspendedUntil)
+	}
+	html := fmt.Sprintf(`
+		<h2>Hello,</h2>
+		<p>Your account has been suspended%s You have been signed out and cannot sign in or book %s.</p>
+		<p>If you think this is a mistake, please reply to this email or contact support.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`, why, until)
+	return m.SendEmail(userEmail, subject, html)
+}
+
+func (m *MailSender) SendAccountReinstated(userEmail string) error {
+	subject := "Your account has been reinstated"
+	html := `
+		<h2>Hello,</h2>
+		<p>The suspension of your account has ended. You can sign in and book again.</p>
+		<p>Regards,<br>Co-working Booking System</p>
+	`
+	return m.SendEmail(userEmail, subject, html)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"packages/preferences"
+	"sync"
+)
+
+// PreferenceStore holds the latest preferences of every user in memory. It is filled by reading
+// the whole preferences topic, so it needs no storage of its own.
+type PreferenceStore struct {
+	mu    sync.RWMutex
+	users map[uint]preferences.Preferences
+}
+
+func NewPreferenceStore() *PreferenceStore {
+	return &PreferenceStore{users: make(map[uint]preferences.Preferences)}
+}
+
+func (s *PreferenceStore) Set(pref preferences.Preferences) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[pref.UserID] = pref
+}
+
+// Get returns the defaults for unknown users, including mails without a user ID.
+func (s *PreferenceStore) Get(userID uint) preferences.Preferences {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if pref, ok := s.users[userID]; ok {
+		return pref
+	}
+	return preferences.Default(userID)
+}
@@ -13,6 +13,12 @@ import (
 	"time"
 )
 
+// catalogFields are the venue fields the map needs from the catalogue.
+const (
+	catalogFields   = "id,name,address,city,description,status,latitude,longitude"
+	catalogPageSize = 100
+)
+
 type VenueService interface {
 	FetchVenues() ([]dto.Venue, error)
 }
@@ -38,42 +44,50 @@ func (s *venueServiceImpl) FetchVenues() ([]dto.Venue, error) {
 		}
 	}
 
-	// resp, err := http.Get(fmt.Sprintf("%s/api/v1/venues", s.BaseURL))
-	// if err != nil {
-	// 	return nil, err
-	// }
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/venues", s.BaseURL), nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
+	// the public catalogue only lists approved venues, so nothing pending or blocked is mapped
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch venues: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch venues: %v", resp.Status)
-	}
+	var venues []dto.Venue
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/v1/catalog/venues?fields=%s&limit=%d&page=%d", s.BaseURL, catalogFields, catalogPageSize, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch venues: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch venues: %v", resp.Status)
+		}
 
-	var result struct {
-		Message string      `json:"message"`
-		Data    []dto.Venue `json:"data"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+		var result struct {
+			Message    string      `json:"message"`
+			Data       []dto.Venue `json:"data"`
+			Pagination struct {
+				Total int `json:"total"`
+			} `json:"pagination"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		venues = append(venues, result.Data...)
+		if len(result.Data) == 0 || len(venues) >= result.Pagination.Total {
+			break
+		}
 	}
 
-	if err := config.Set(ctx, cacheKey, string(body), 5*time.Minute); err != nil {
-		log.Printf("Failed to set cache for key %s: %v", cacheKey, err)
+	if data, err := json.Marshal(venues); err == nil {
+		if err := config.Set(ctx, cacheKey, string(data), 5*time.Minute); err != nil {
+			log.Printf("Failed to set cache for key %s: %v", cacheKey, err)
+		}
 	}
 
-	return result.Data, nil
+	return venues, nil
 }
@@ -32,6 +32,10 @@ func (u *MapUsecase) GetVenuesWithLocation() ([]dto.Venue, error) {
 	}
 
 	for i, v := range venues {
+		// venue-service already has coordinates for venues it could locate
+		if v.Latitude != 0 || v.Longitude != 0 {
+			continue
+		}
 		coord, err := service.GeocodeAddress(fmt.Sprintf("%s, %s", v.Address, v.City))
 		if err != nil {
 			log.Printf("⚠️ Geocode failed for venue %q (%s, %s): %v", v.Name, v.Address, v.City, err)
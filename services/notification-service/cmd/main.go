@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"notification-service/config"
 	"notification-service/internal/handler"
 	"notification-service/internal/kafka"
@@ -9,13 +10,21 @@ import (
 	"notification-service/internal/route"
 	"notification-service/internal/usecase"
 	"os"
+	"packages/preferences"
+	"packages/servicetoken"
 )
 
 func main() {
 	config.ConnectDB()
 
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	repo := repository.NewNotificationRepository(config.DB)
-	uc := usecase.NewNotificationUsecase(repo)
+	prefRepo := repository.NewPreferenceRepository(config.DB)
+	uc := usecase.NewNotificationUsecase(repo, prefRepo)
 	h := handler.NewNotificationHandler(uc)
 
 	brokers := os.Getenv("KAFKA_BROKERS")
@@ -38,7 +47,18 @@ func main() {
 		uc,
 	)
 
-	r := route.SetupRouter(h)
+	prefTopic := os.Getenv(preferences.EnvTopic)
+	if prefTopic == "" {
+		prefTopic = preferences.DefaultTopic
+	}
+	kafka.StartPreferenceConsumer(
+		[]string{brokers},
+		prefTopic,
+		group,
+		uc,
+	)
+
+	r := route.SetupRouter(h, servicetoken.NewVerifier(servicetoken.NotificationService, serviceSecret))
 
 	port := os.Getenv("NOTIFICATION_SERVICE_PORT")
 	if port == "" {
@@ -86,7 +86,7 @@ func ConnectDB() {
 	sqlDB.SetMaxOpenConns(100)
 
 	DB = db
-	err = db.AutoMigrate(model.Notification{})
+	err = db.AutoMigrate(model.Notification{}, model.UserPreference{})
 	if err != nil {
 		log.Fatalf("❌ AutoMigrate failed: %v", err)
 	}
@@ -53,3 +53,33 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "notification.get_success", "data": notifications})
 }
+
+// ExportUserData returns the notifications of a user for their personal data export.
+// Internal endpoint called by auth-service.
+func (h *NotificationHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "notification.invalid_user_id"})
+		return
+	}
+	notifications, err := h.usecase.GetUserNotifications(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "notification.get_error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"notifications": notifications}})
+}
+
+// EraseUserData handles the deletion of a user account. Internal endpoint called by auth-service.
+func (h *NotificationHandler) EraseUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "notification.invalid_user_id"})
+		return
+	}
+	if err := h.usecase.DeleteUserNotifications(uint(userID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "notification.delete_error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "notification.delete_success"})
+}
@@ -25,21 +25,45 @@ func StartBookingConsumer(brokers []string, topic, group string, uc usecase.Noti
 				continue
 			}
 
-			var event map[string]interface{}
+			var event usecase.BookingEvent
 			if err := json.Unmarshal(m.Value, &event); err != nil {
 				log.Printf("invalid booking event: %v", err)
 				continue
 			}
 
-			// Extract data
-			userID := uint(event["user_id"].(float64))
-			typ := event["type"].(string)
-			content := event["content"].(string)
-
 			// Save + Push WS
-			notif, err := uc.SendNotification(userID, typ, content)
-			if err == nil {
-				config.SendToUser(userID, notif)
+			notif, err := uc.NotifyBookingEvent(event)
+			if err != nil {
+				log.Printf("notify booking event failed: %v", err)
+				continue
+			}
+			if notif != nil {
+				config.SendToUser(event.UserID, notif)
+			}
+		}
+	}()
+}
+
+// StartPreferenceConsumer keeps the preferences user-service publishes, so notifications are
+// written the way each user asked for.
+func StartPreferenceConsumer(brokers []string, topic, group string, uc usecase.NotificationUsecase) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     group,
+		StartOffset: kafka.FirstOffset,
+	})
+
+	go func() {
+		for {
+			m, err := r.ReadMessage(context.Background())
+			if err != nil {
+				log.Printf("error reading kafka message: %v", err)
+				continue
+			}
+
+			if err := uc.SavePreferences(m.Value); err != nil {
+				log.Printf("save user preferences failed: %v", err)
 			}
 		}
 	}()
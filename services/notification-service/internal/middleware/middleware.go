@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"packages/servicetoken"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireService only lets through other services holding a token addressed to this one,
+// and only the listed callers when any are given. It guards the service-to-service routes.
+func RequireService(verifier *servicetoken.Verifier, callers ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := verifier.Verify(c.GetHeader(servicetoken.Header), callers...)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "error.invalid_service_token"})
+			c.Abort()
+			return
+		}
+		c.Set("service", claims.Subject)
+		c.Next()
+	}
+}
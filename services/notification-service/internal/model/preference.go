@@ -0,0 +1,8 @@
+package model
+
+// UserPreference is the latest preferences message user-service published for the user, kept
+// as is and read with preferences.Decode.
+type UserPreference struct {
+	UserID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	Payload string `gorm:"type:text;not null"`
+}
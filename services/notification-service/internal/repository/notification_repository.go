@@ -9,6 +9,7 @@ type NotificationRepository interface {
 	Create(notification *model.Notification) error
 	GetByUserID(userID uint) ([]model.Notification, error)
 	MarkAsRead(id uint) error
+	DeleteByUserID(userID uint) error
 }
 
 type notificationRepository struct {
@@ -32,3 +33,8 @@ func (r *notificationRepository) GetByUserID(userID uint) ([]model.Notification,
 func (r *notificationRepository) MarkAsRead(id uint) error {
 	return r.db.Model(&model.Notification{}).Where("id = ?", id).Update("is_read", true).Error
 }
+
+// DeleteByUserID removes the notifications of the user for good, soft-deleted ones included.
+func (r *notificationRepository) DeleteByUserID(userID uint) error {
+	return r.db.Unscoped().Where("user_id = ?", userID).Delete(&model.Notification{}).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"notification-service/internal/model"
+	"packages/preferences"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceRepository interface {
+	Save(userID uint, payload string) error
+	Get(userID uint) (preferences.Preferences, error)
+}
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepository {
+	return &preferenceRepository{db}
+}
+
+// Save replaces the preferences of the user with payload.
+func (r *preferenceRepository) Save(userID uint, payload string) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&model.UserPreference{UserID: userID, Payload: payload}).Error
+}
+
+// Get returns the defaults for users user-service never published preferences of.
+func (r *preferenceRepository) Get(userID uint) (preferences.Preferences, error) {
+	var pref model.UserPreference
+	if err := r.db.First(&pref, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return preferences.Default(userID), nil
+		}
+		return preferences.Preferences{}, err
+	}
+	return preferences.Decode([]byte(pref.Payload))
+}
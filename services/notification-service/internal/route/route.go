@@ -5,16 +5,23 @@ import (
 	"net/http"
 	"notification-service/config"
 	"notification-service/internal/handler"
+	"notification-service/internal/middleware"
+	"packages/servicetoken"
+	"packages/userdata"
 	"strconv"
 )
 
-func SetupRouter(notificationHandler *handler.NotificationHandler) *gin.Engine {
+func SetupRouter(notificationHandler *handler.NotificationHandler, serviceVerifier *servicetoken.Verifier) *gin.Engine {
 	router := gin.Default()
 	router.HandleMethodNotAllowed = true // return 405 on wrong method
 
 	router.POST("/api/v1/notifications", notificationHandler.SendNotification)
 	router.GET("/api/v1/notifications/:userId", notificationHandler.GetNotifications)
 
+	requireAuthService := middleware.RequireService(serviceVerifier, servicetoken.AuthService)
+	router.GET(userdata.Route, requireAuthService, notificationHandler.ExportUserData)
+	router.DELETE(userdata.Route, requireAuthService, notificationHandler.EraseUserData)
+
 	// WebSocket connect
 	router.GET("/ws/:userId", func(c *gin.Context) {
 		userIDStr := c.Param("userId")
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"fmt"
+	"notification-service/internal/model"
+	"packages/preferences"
+	"time"
+)
+
+// BookingEvent is what booking-service publishes on the notification topic. Content is an
+// English fallback for event types without a message of their own.
+type BookingEvent struct {
+	UserID    uint   `json:"user_id"`
+	Type      string `json:"type"`
+	Content   string `json:"content"`
+	SpaceName string `json:"space_name"`
+	Booking   struct {
+		ID        uint
+		StartTime time.Time
+		EndTime   time.Time
+		Status    string
+	} `json:"booking"`
+}
+
+var bookingMessages = map[string]map[string]string{
+	preferences.LanguageEnglish: {
+		preferences.EventBookingCreated:       "You booked %s from %s to %s",
+		preferences.EventBookingStatusUpdated: "Your booking %d is now %s",
+	},
+	preferences.LanguageVietnamese: {
+		preferences.EventBookingCreated:       "Bạn đã đặt %s từ %s đến %s",
+		preferences.EventBookingStatusUpdated: "Lượt đặt chỗ %d của bạn hiện %s",
+	},
+}
+
+var bookingStatuses = map[string]map[string]string{
+	preferences.LanguageEnglish: {
+		"PENDING":   "pending",
+		"CONFIRMED": "confirmed",
+		"CANCELLED": "cancelled",
+	},
+	preferences.LanguageVietnamese: {
+		"PENDING":   "đang chờ xử lý",
+		"CONFIRMED": "đã được xác nhận",
+		"CANCELLED": "đã bị huỷ",
+	},
+}
+
+// NotifyBookingEvent stores and returns the in-app notification of event, written in the
+// language and timezone of the user. It returns nil when the user turned in-app notifications
+// of the event off.
+func (u *notificationUsecase) NotifyBookingEvent(event BookingEvent) (*model.Notification, error) {
+	pref, err := u.prefRepo.Get(event.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !pref.Wants(event.Type, preferences.ChannelInApp) {
+		return nil, nil
+	}
+	return u.SendNotification(event.UserID, event.Type, bookingContent(event, pref))
+}
+
+func bookingContent(event BookingEvent, pref preferences.Preferences) string {
+	messages, ok := bookingMessages[pref.Language]
+	if !ok {
+		messages = bookingMessages[preferences.DefaultLanguage]
+	}
+	statuses, ok := bookingStatuses[pref.Language]
+	if !ok {
+		statuses = bookingStatuses[preferences.DefaultLanguage]
+	}
+
+	switch event.Type {
+	case preferences.EventBookingCreated:
+		if event.SpaceName != "" {
+			return fmt.Sprintf(messages[event.Type], event.SpaceName,
+				pref.FormatTime(event.Booking.StartTime), pref.FormatTime(event.Booking.EndTime))
+		}
+	case preferences.EventBookingStatusUpdated:
+		if status, ok := statuses[event.Booking.Status]; ok {
+			return fmt.Sprintf(messages[event.Type], event.Booking.ID, status)
+		}
+	}
+	return event.Content
+}
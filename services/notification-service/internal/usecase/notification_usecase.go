@@ -3,20 +3,25 @@ package usecase
 import (
 	"notification-service/internal/model"
 	"notification-service/internal/repository"
+	"packages/preferences"
 )
 
 type NotificationUsecase interface {
 	SendNotification(userID uint, notifType, content string) (*model.Notification, error)
 	GetUserNotifications(userID uint) ([]model.Notification, error)
 	MarkAsRead(id uint) error
+	DeleteUserNotifications(userID uint) error
+	NotifyBookingEvent(event BookingEvent) (*model.Notification, error)
+	SavePreferences(payload []byte) error
 }
 
 type notificationUsecase struct {
-	repo repository.NotificationRepository
+	repo     repository.NotificationRepository
+	prefRepo repository.PreferenceRepository
 }
 
-func NewNotificationUsecase(repo repository.NotificationRepository) NotificationUsecase {
-	return &notificationUsecase{repo}
+func NewNotificationUsecase(repo repository.NotificationRepository, prefRepo repository.PreferenceRepository) NotificationUsecase {
+	return &notificationUsecase{repo, prefRepo}
 }
 
 func (u *notificationUsecase) SendNotification(userID uint, notifType, content string) (*model.Notification, error) {
@@ -40,3 +45,18 @@ func (u *notificationUsecase) GetUserNotifications(userID uint) ([]model.Notific
 func (u *notificationUsecase) MarkAsRead(id uint) error {
 	return u.repo.MarkAsRead(id)
 }
+
+// DeleteUserNotifications runs when the account of the user is deleted. Notifications are
+// only meaningful to their recipient, so nothing is kept.
+func (u *notificationUsecase) DeleteUserNotifications(userID uint) error {
+	return u.repo.DeleteByUserID(userID)
+}
+
+// SavePreferences keeps the preferences message user-service published for a user.
+func (u *notificationUsecase) SavePreferences(payload []byte) error {
+	pref, err := preferences.Decode(payload)
+	if err != nil {
+		return err
+	}
+	return u.prefRepo.Save(pref.UserID, string(payload))
+}
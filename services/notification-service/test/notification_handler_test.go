@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"notification-service/internal/handler"
 	"notification-service/internal/model"
+	"notification-service/internal/usecase"
 	"testing"
 )
 
@@ -33,6 +34,21 @@ func (m *MockNotificationUsecase) MarkAsRead(id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockNotificationUsecase) DeleteUserNotifications(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationUsecase) NotifyBookingEvent(event usecase.BookingEvent) (*model.Notification, error) {
+	args := m.Called(event)
+	return args.Get(0).(*model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) SavePreferences(payload []byte) error {
+	args := m.Called(payload)
+	return args.Error(0)
+}
+
 func TestSendNotificationHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
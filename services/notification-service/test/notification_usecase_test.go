@@ -2,7 +2,9 @@ package test
 
 import (
 	"errors"
+	"packages/preferences"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,10 +29,27 @@ func (m *MockNotificationRepo) MarkAsRead(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+func (m *MockNotificationRepo) DeleteByUserID(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+type MockPreferenceRepo struct {
+	mock.Mock
+}
+
+func (m *MockPreferenceRepo) Save(userID uint, payload string) error {
+	args := m.Called(userID, payload)
+	return args.Error(0)
+}
+func (m *MockPreferenceRepo) Get(userID uint) (preferences.Preferences, error) {
+	args := m.Called(userID)
+	return args.Get(0).(preferences.Preferences), args.Error(1)
+}
 
 func TestSendNotification(t *testing.T) {
 	mockRepo := new(MockNotificationRepo)
-	uc := usecase.NewNotificationUsecase(mockRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, new(MockPreferenceRepo))
 
 	notif := &model.Notification{
 		UserID:  1,
@@ -53,7 +72,7 @@ func TestSendNotification(t *testing.T) {
 
 func TestGetUserNotifications(t *testing.T) {
 	mockRepo := new(MockNotificationRepo)
-	uc := usecase.NewNotificationUsecase(mockRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, new(MockPreferenceRepo))
 
 	expected := []model.Notification{
 		{UserID: 1, Content: "Hello"},
@@ -72,7 +91,7 @@ func TestGetUserNotifications(t *testing.T) {
 
 func TestMarkAsRead(t *testing.T) {
 	mockRepo := new(MockNotificationRepo)
-	uc := usecase.NewNotificationUsecase(mockRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, new(MockPreferenceRepo))
 
 	mockRepo.On("MarkAsRead", uint(1)).Return(nil)
 
@@ -84,7 +103,7 @@ func TestMarkAsRead(t *testing.T) {
 
 func TestSendNotification_Error(t *testing.T) {
 	mockRepo := new(MockNotificationRepo)
-	uc := usecase.NewNotificationUsecase(mockRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, new(MockPreferenceRepo))
 
 	mockRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(errors.New("db error"))
 
@@ -93,3 +112,66 @@ func TestSendNotification_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+func bookingCreated() usecase.BookingEvent {
+	event := usecase.BookingEvent{UserID: 1, Type: preferences.EventBookingCreated, Content: "fallback", SpaceName: "Room A"}
+	event.Booking.StartTime = time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)
+	event.Booking.EndTime = time.Date(2026, 3, 5, 4, 0, 0, 0, time.UTC)
+	return event
+}
+
+func TestNotifyBookingEvent_UsesLanguageAndTimezone(t *testing.T) {
+	mockRepo, prefRepo := new(MockNotificationRepo), new(MockPreferenceRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, prefRepo)
+
+	pref := preferences.Default(1)
+	pref.Language, pref.Timezone = preferences.LanguageVietnamese, "Asia/Ho_Chi_Minh"
+	prefRepo.On("Get", uint(1)).Return(pref, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(nil)
+
+	notif, err := uc.NotifyBookingEvent(bookingCreated())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bạn đã đặt Room A từ 09:00 05/03/2026 (+07) đến 11:00 05/03/2026 (+07)", notif.Content)
+	assert.Equal(t, preferences.EventBookingCreated, notif.Type)
+}
+
+func TestNotifyBookingEvent_InAppTurnedOff(t *testing.T) {
+	mockRepo, prefRepo := new(MockNotificationRepo), new(MockPreferenceRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, prefRepo)
+
+	pref := preferences.Default(1)
+	pref.Channels[preferences.EventBookingCreated] = []string{preferences.ChannelEmail}
+	prefRepo.On("Get", uint(1)).Return(pref, nil)
+
+	notif, err := uc.NotifyBookingEvent(bookingCreated())
+
+	assert.NoError(t, err)
+	assert.Nil(t, notif)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestNotifyBookingEvent_UnknownStatusFallsBack(t *testing.T) {
+	mockRepo, prefRepo := new(MockNotificationRepo), new(MockPreferenceRepo)
+	uc := usecase.NewNotificationUsecase(mockRepo, prefRepo)
+	prefRepo.On("Get", uint(1)).Return(preferences.Default(1), nil)
+	mockRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(nil)
+
+	event := usecase.BookingEvent{UserID: 1, Type: preferences.EventBookingStatusUpdated, Content: "Your booking 3 status changed to REFUNDED"}
+	event.Booking.ID, event.Booking.Status = 3, "REFUNDED"
+	notif, err := uc.NotifyBookingEvent(event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Your booking 3 status changed to REFUNDED", notif.Content)
+}
+
+func TestSavePreferences(t *testing.T) {
+	prefRepo := new(MockPreferenceRepo)
+	uc := usecase.NewNotificationUsecase(new(MockNotificationRepo), prefRepo)
+	payload := `{"user_id":7,"language":"vi","timezone":"Asia/Ho_Chi_Minh"}`
+	prefRepo.On("Save", uint(7), payload).Return(nil)
+
+	assert.NoError(t, uc.SavePreferences([]byte(payload)))
+	assert.Error(t, uc.SavePreferences([]byte("not json")))
+	prefRepo.AssertExpectations(t)
+}
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"packages/impersonation"
+	"packages/servicetoken"
+	"packages/session"
 	_ "payment-service/docs"
 	"payment-service/internal/config"
 	"payment-service/internal/handler"
+	"payment-service/internal/middleware"
 	"payment-service/internal/repository"
 	"payment-service/internal/router"
 	"payment-service/internal/usecase"
+	"time"
 )
 
 func main() {
@@ -20,11 +26,29 @@ func main() {
 		log.Fatal("BOOKING_SERVICE_URL environment variable is not set")
 	}
 
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	issuer := servicetoken.NewIssuer(servicetoken.PaymentService, serviceSecret)
+	// refuse the tokens of sessions revoked in auth-service
+	authURL := os.Getenv("AUTH_SERVICE_URL")
+	if authURL == "" {
+		log.Fatal("missing env: AUTH_SERVICE_URL")
+	}
+	authClient := issuer.Client(servicetoken.AuthService, 5*time.Second)
+	revocations := session.NewRevocationStore(session.HTTPSource(authURL, authClient))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	middleware.TrackRevocations(revocations)
+	// record impersonated requests in auth-service before serving them
+	middleware.RecordImpersonations(impersonation.NewRecorder(authURL, authClient))
+
 	transactionRepo := repository.NewTransactionRepository(config.DB)
-	PaymentUsecase := usecase.NewPaymentUsecase(transactionRepo, config.GetVnpayConfig(), bookingServiceURL)
+	PaymentUsecase := usecase.NewPaymentUsecase(transactionRepo, config.GetVnpayConfig(), bookingServiceURL, issuer)
 	paymentHandler := handler.NewPaymentHandler(PaymentUsecase)
 
-	r := router.SetupRouter(paymentHandler)
+	r := router.SetupRouter(paymentHandler, servicetoken.NewVerifier(servicetoken.PaymentService, serviceSecret))
 
 	port := os.Getenv("PAYMENT_SERVICE_PORT")
 	if port == "" {
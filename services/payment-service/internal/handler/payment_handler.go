@@ -73,3 +73,31 @@ func (h *PaymentHandler) VnpayReturn(c *gin.Context) {
 
 	c.Redirect(http.StatusFound, redirectURL)
 }
+
+// ExportUserData returns the payment transactions of a user for their personal data export.
+// Internal endpoint called by auth-service.
+func (h *PaymentHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid.user_id"})
+		return
+	}
+
+	txs, err := h.usecase.ExportUserData(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"transactions": txs}})
+}
+
+// EraseUserData handles the deletion of a user account. Transactions are financial records
+// holding no personal data besides the booking they pay for, so they are kept unchanged.
+// Internal endpoint called by auth-service.
+func (h *PaymentHandler) EraseUserData(c *gin.Context) {
+	if _, err := strconv.ParseUint(c.Param("id"), 10, 64); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid.user_id"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "nothing to erase"})
+}
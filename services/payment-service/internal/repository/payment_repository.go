@@ -10,6 +10,7 @@ type TransactionRepository interface {
   Create(tx *model.PaymentTransaction) error
   FindByTxnRef(txnRef string) (*model.PaymentTransaction, error)
   Update(tx *model.PaymentTransaction) error
+  FindByBookingIDs(bookingIDs []uint) ([]model.PaymentTransaction, error)
 }
 
 type transactionRepositoryImpl struct {
@@ -36,3 +37,12 @@ func (r *transactionRepositoryImpl) FindByTxnRef(txnRef string) (*model.PaymentT
 func (r *transactionRepositoryImpl) Update(tx *model.PaymentTransaction) error {
   return r.db.Save(tx).Error
 }
+
+func (r *transactionRepositoryImpl) FindByBookingIDs(bookingIDs []uint) ([]model.PaymentTransaction, error) {
+  var txs []model.PaymentTransaction
+  if len(bookingIDs) == 0 {
+    return txs, nil
+  }
+  err := r.db.Where("booking_id IN ?", bookingIDs).Order("created_at").Find(&txs).Error
+  return txs, err
+}
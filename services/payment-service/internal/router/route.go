@@ -1,26 +1,35 @@
 package router
 
 import (
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/userdata"
 	"payment-service/internal/handler"
+	"payment-service/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(paymentHandler *handler.PaymentHandler) *gin.Engine {
+func SetupRouter(paymentHandler *handler.PaymentHandler, serviceVerifier *servicetoken.Verifier) *gin.Engine {
 	router := gin.Default()
 	router.HandleMethodNotAllowed = true // return 405 on wrong method
 
 	paymentGroup := router.Group("/api/v1/payments")
 	{
 		// GET: /api/payments/create?booking_id=123
-		paymentGroup.GET("/create", paymentHandler.CreatePaymentUrl)
+		// impersonation tokens are refused: payment:create is withheld from them
+		paymentGroup.GET("/create", middleware.RequireAuth(policy.PaymentCreate), paymentHandler.CreatePaymentUrl)
 
 		// GET: /api/payments/vnpay/callback?...
 		paymentGroup.GET("/vnpay/callback", paymentHandler.VnpayReturn)
 	}
 
+	requireAuthService := middleware.RequireService(serviceVerifier, servicetoken.AuthService)
+	router.GET(userdata.Route, requireAuthService, paymentHandler.ExportUserData)
+	router.DELETE(userdata.Route, requireAuthService, paymentHandler.EraseUserData)
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return router
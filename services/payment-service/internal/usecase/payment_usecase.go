@@ -6,6 +6,7 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"packages/servicetoken"
 	"payment-service/internal/config"
 	"payment-service/internal/model"
 	"payment-service/internal/repository"
@@ -18,6 +19,7 @@ import (
 type PaymentUsecase interface {
 	CreatePaymentUrl(bookingID uint, clientIP string) (string, error)
 	HandleVnpReturn(params url.Values) (string, error)
+	ExportUserData(userID uint) ([]model.PaymentTransaction, error)
 }
 
 type paymentUsecaseImpl struct {
@@ -27,11 +29,12 @@ type paymentUsecaseImpl struct {
 	bookingServiceURL string // e.g. http://booking-service:8080
 }
 
-func NewPaymentUsecase(repo repository.TransactionRepository, cfg config.VnpayConfig, bookingURL string) PaymentUsecase {
+// NewPaymentUsecase calls booking-service with tokens signed by issuer.
+func NewPaymentUsecase(repo repository.TransactionRepository, cfg config.VnpayConfig, bookingURL string, issuer *servicetoken.Issuer) PaymentUsecase {
 	return &paymentUsecaseImpl{
 		txRepo:            repo,
 		cfg:               cfg,
-		httpClient:        &http.Client{Timeout: 5 * time.Second},
+		httpClient:        issuer.Client(servicetoken.BookingService, 5*time.Second),
 		bookingServiceURL: bookingURL,
 	}
 }
@@ -118,3 +121,26 @@ func (s *paymentUsecaseImpl) HandleVnpReturn(params url.Values) (string, error)
 
 	return s.cfg.ReturnURL, nil
 }
+
+// ExportUserData returns the transactions paying for the bookings of a user. Transactions only
+// know their booking, so the bookings of the user are asked from booking-service first.
+func (s *paymentUsecaseImpl) ExportUserData(userID uint) ([]model.PaymentTransaction, error) {
+	resp, err := s.httpClient.Get(fmt.Sprintf("%s/api/v1/internal/bookings?user_id=%d", s.bookingServiceURL, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call booking-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("booking-service returned %s", resp.Status)
+	}
+
+	var result struct {
+		Data []uint `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return s.txRepo.FindByBookingIDs(result.Data)
+}
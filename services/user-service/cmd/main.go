@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"packages/preferences"
+	"strings"
 	"user-service/db"
+	"user-service/internal/constant"
+	"user-service/internal/kafka"
 	"user-service/router"
 
 	_ "user-service/docs"
@@ -35,8 +41,29 @@ func main() {
 	}
 	db.InitDB()
 	db.AutoMigrate()
+	brokers := os.Getenv(constant.EnvKafkaBrokers) // format: "broker1:9092,broker2:9092"
+	if brokers == "" {
+		log.Fatal("missing env: " + constant.EnvKafkaBrokers)
+	}
+	topic := os.Getenv(preferences.EnvTopic)
+	if topic == "" {
+		topic = preferences.DefaultTopic
+	}
+	producer := kafka.NewProducer(strings.Split(brokers, ","), topic)
+	defer producer.Close()
+	mailTopic := os.Getenv(constant.EnvKafkaMailTopic)
+	if mailTopic == "" {
+		log.Fatal("missing env: " + constant.EnvKafkaMailTopic)
+	}
+	mailProducer := kafka.NewProducer(strings.Split(brokers, ","), mailTopic)
+	defer mailProducer.Close()
+
 	r := gin.Default()
-	router.SetupRouter(r)
+	relay, expirer := router.SetupRouter(r, producer, mailProducer)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go relay.Run(jobsCtx)
+	go expirer.Run(jobsCtx)
 	err = r.Run(":8082")
 	if err != nil {
 		log.Fatal("Server failed:", err)
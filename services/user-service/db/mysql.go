@@ -30,8 +30,15 @@ func InitDB() {
 }
 
 func AutoMigrate() {
-	err := DB.AutoMigrate(&model.User{})
+	err := DB.AutoMigrate(&model.User{}, &model.UserPreference{}, &model.Organization{}, &model.OrganizationMember{}, &model.PhoneVerification{}, &model.Suspension{}, &model.FavoriteSpace{})
 	if err != nil {
 		log.Fatal("AutoMigrate failed:", err)
 	}
+
+	// created_at belongs to the embedded gorm.Model, which cannot be tagged
+	if !DB.Migrator().HasIndex(&model.User{}, "idx_users_created_at") {
+		if err := DB.Exec("CREATE INDEX idx_users_created_at ON users (created_at)").Error; err != nil {
+			log.Fatal("AutoMigrate failed:", err)
+		}
+	}
 }
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 const (
 	ErrInvalidInput          = "invalid input data"
 	ErrEmailAlreadyExists    = "email already exists"
@@ -23,10 +25,46 @@ const (
 	ErrSendHTTPRequest       = "unable to connect to the server"
 	ErrUnmarshalResponse     = "unable to read response from the server"
 	ErrInvalidRole           = "invalid role"
+	ErrInvalidSearchParam    = "error.invalid_search_parameter"
+	ErrInvalidCursor         = "error.invalid_cursor"
+	ErrImageTooLarge         = "error.image_too_large"
+	ErrUnsupportedImage      = "error.unsupported_image_type"
+	ErrInvalidImage          = "error.invalid_image"
+	ErrStoreAvatar           = "error.store_avatar_failed"
+	ErrInvalidLanguage       = "error.invalid_language"
+	ErrInvalidTimezone       = "error.invalid_timezone"
+	ErrInvalidCurrency       = "error.invalid_currency"
+	ErrInvalidChannels       = "error.invalid_notification_channels"
+	ErrOrganizationNotFound  = "error.organization_not_found"
+	ErrMemberNotFound        = "error.organization_member_not_found"
+	ErrInvitationNotFound    = "error.organization_invitation_not_found"
+	ErrAlreadyMember         = "error.already_organization_member"
+	ErrOrganizationForbidden = "error.organization_forbidden"
+	ErrLastOwner             = "error.organization_last_owner"
+	ErrInvalidOrgRole        = "error.invalid_organization_role"
+	ErrInvalidQuota          = "error.invalid_quota"
+	ErrPhoneNotMobile        = "error.phone_cannot_receive_sms"
+	ErrPhoneAlreadyVerified  = "error.phone_already_verified"
+	ErrPhoneNotVerified      = "error.phone_not_verified"
+	ErrInvalidOTP            = "error.invalid_otp"
+	ErrOTPExpired            = "error.otp_expired"
+	ErrOTPTooManyAttempts    = "error.otp_too_many_attempts"
+	ErrOTPResendTooSoon      = "error.otp_resend_too_soon"
+	ErrOTPDailyLimit         = "error.otp_daily_limit_reached"
+	ErrSendSMS               = "error.send_sms_failed"
+	ErrInvalidSuspension     = "error.invalid_suspension"
+	ErrAlreadySuspended      = "error.user_already_suspended"
+	ErrNotSuspended          = "error.user_not_suspended"
+	ErrCannotSuspendSelf     = "error.cannot_suspend_self"
+	ErrUserSuspended         = "error.user_suspended"
+	ErrSpaceNotFound         = "error.space_not_found"
+	ErrTooManyFavorites      = "error.too_many_favorites"
+	ErrVenueService          = "error.venue_service_unavailable"
 )
 
 const (
 	UpdateAuthUserURL = "/api/v1/auth/users"
+	GetSpacesURL      = "/api/v1/internal/spaces"
 )
 
 const (
@@ -34,3 +72,77 @@ const (
 	RoleModerator = "moderator"
 	RoleAdmin     = "admin"
 )
+
+const (
+	EnvKafkaBrokers = "KAFKA_BROKERS"
+	// avatars are stored as configured by AVATAR_STORAGE, AVATAR_LOCAL_DIR and AVATAR_PUBLIC_URL
+	EnvAvatarPrefix = "AVATAR"
+	// where user-service serves the local avatar directory
+	AvatarRoute = "/api/v1/users/files"
+	// how often the preference relay looks for changes Kafka did not get yet
+	PreferencePollInterval = 5 * time.Second
+	PreferenceBatchSize    = 100
+)
+
+const (
+	// users read per query while writing a CSV export
+	UserExportBatchSize = 500
+	// favourite spaces a user can keep
+	MaxFavoriteSpaces = 100
+	// spaces venue-service looks up at once; longer lists are sent in several requests
+	MaxSpaceBatch = 100
+	// status venue-service gives a venue taken down by moderators; its spaces drop out of favourites
+	VenueStatusBlocked = "blocked"
+)
+
+const (
+	// phone verification codes sent by SMS
+	PhoneOTPLength         = 6
+	PhoneOTPTTL            = 5 * time.Minute
+	PhoneOTPMaxAttempts    = 5
+	PhoneOTPResendInterval = time.Minute
+	PhoneOTPMaxSendsPerDay = 5
+)
+
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+
+	MemberStatusInvited = "invited"
+	MemberStatusActive  = "active"
+
+	// organization invitations are mailed through the topic of auth-service mails
+	EnvKafkaMailTopic      = "KAFKA_TOPIC_VERIFY_EMAIL"
+	EventTypeOrgInvitation = "ORGANIZATION_INVITATION"
+)
+
+// Reasons a user can be suspended for.
+const (
+	SuspensionReasonSpam           = "spam"
+	SuspensionReasonFraud          = "fraud"
+	SuspensionReasonHarassment     = "harassment"
+	SuspensionReasonPaymentDispute = "payment_dispute"
+	SuspensionReasonTermsViolation = "terms_violation"
+	SuspensionReasonOther          = "other"
+)
+
+var SuspensionReasons = []string{
+	SuspensionReasonSpam,
+	SuspensionReasonFraud,
+	SuspensionReasonHarassment,
+	SuspensionReasonPaymentDispute,
+	SuspensionReasonTermsViolation,
+	SuspensionReasonOther,
+}
+
+const (
+	// how often suspensions that reached their end are lifted
+	SuspensionPollInterval = time.Minute
+	SuspensionBatchSize    = 100
+	// the lift note of a suspension closed because the account was deleted
+	SuspensionNoteAccountDeleted = "account deleted"
+
+	EventTypeAccountSuspended  = "ACCOUNT_SUSPENDED"
+	EventTypeAccountReinstated = "ACCOUNT_REINSTATED"
+)
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+type CreateOrganizationRequest struct {
+	Name         string `json:"name" binding:"required"`
+	BillingEmail string `json:"billing_email"`
+}
+
+// UpdateOrganizationRequest changes the fields that are set.
+type UpdateOrganizationRequest struct {
+	Name         *string `json:"name,omitempty"`
+	BillingEmail *string `json:"billing_email,omitempty"`
+}
+
+// OrganizationResponse carries the role of the caller in the organization.
+type OrganizationResponse struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	BillingEmail string    `json:"billing_email"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// InviteMemberRequest invites Email with Role, member when empty.
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// MemberQuotaRequest replaces both monthly quotas of a member; a missing one means no limit.
+type MemberQuotaRequest struct {
+	MonthlyHours *float64 `json:"monthly_hours"`
+	MonthlySpend *float64 `json:"monthly_spend"`
+}
+
+type MemberResponse struct {
+	ID             uint      `json:"id"`
+	OrganizationID uint      `json:"organization_id"`
+	UserID         uint      `json:"user_id,omitempty"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	Status         string    `json:"status"`
+	MonthlyHours   *float64  `json:"monthly_hours"`
+	MonthlySpend   *float64  `json:"monthly_spend"`
+	InvitedBy      uint      `json:"invited_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type InvitationResponse struct {
+	ID               uint      `json:"id"`
+	OrganizationID   uint      `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	Role             string    `json:"role"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// MailEvent is read by mail-service from the mail topic.
+type MailEvent struct {
+	UserID uint              `json:"user_id,omitempty"`
+	Email  string            `json:"email"`
+	Type   string            `json:"type"`
+	Data   map[string]string `json:"data,omitempty"`
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "user-service/internal/model"
+import (
+	"time"
+	"user-service/internal/model"
+)
 
 type CreateUserRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -16,11 +19,14 @@ type CreateUserResponse struct {
 }
 
 type GetProfileResponse struct {
-	ID       uint   `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	IsActive bool   `json:"is_active" binding:"omitempty"`
+	ID             uint   `json:"id"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	PhoneVerified  bool   `json:"phone_verified"`
+	IsActive       bool   `json:"is_active" binding:"omitempty"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
 }
 
 type UpdateProfileRequest struct {
@@ -29,9 +35,24 @@ type UpdateProfileRequest struct {
 }
 
 type UpdateProfileResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	ID             uint   `json:"id"`
+	Name           string `json:"name"`
+	Phone          string `json:"phone"`
+	PhoneVerified  bool   `json:"phone_verified"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
+}
+
+type AvatarResponse struct {
+	AvatarURL      string `json:"avatar_url"`
+	AvatarThumbURL string `json:"avatar_thumb_url"`
+}
+
+// UserDetailResponse is a user with the links to its avatar.
+type UserDetailResponse struct {
+	model.User
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
 }
 
 type UserListResponse struct {
@@ -48,4 +69,153 @@ type UpdateAuthUserRequest struct {
 	UserID   uint    `json:"user_id"`
 	Role     *string `json:"role,omitempty"`
 	IsActive *bool   `json:"is_active,omitempty"`
-}
\ No newline at end of file
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// SearchUsersRequest holds the filters of the admin user search. Dates are days, both ends
+// included; Cursor is the next_cursor of the previous page, issued for the same sort and order.
+type SearchUsersRequest struct {
+	Query       string    `form:"q" binding:"omitempty,max=100"`
+	Role        string    `form:"role" binding:"omitempty,oneof=admin user moderator"`
+	IsActive    *bool     `form:"is_active"`
+	IsVerified  *bool     `form:"is_verified"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02"`
+	Sort        string    `form:"sort" binding:"omitempty,oneof=email name role is_active is_verified created_at"`
+	Order       string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit       int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string    `form:"cursor"`
+}
+
+// UserSearchFilter is a validated search, with the position of the previous page decoded.
+type UserSearchFilter struct {
+	Query       string
+	Role        string
+	IsActive    *bool
+	IsVerified  *bool
+	CreatedFrom time.Time
+	CreatedTo   time.Time // exclusive
+	Sort        string
+	Desc        bool
+	Limit       int
+	After       *UserCursor
+}
+
+// UserCursor is the sort value and ID of the last user of a page.
+type UserCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+type UserSummary struct {
+	ID         uint      `json:"id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Phone      string    `json:"phone"`
+	Role       string    `json:"role"`
+	IsActive   bool      `json:"is_active"`
+	IsVerified bool      `json:"is_verified"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type SearchUsersResponse struct {
+	Users      []UserSummary `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// PreferencesResponse lists the channels of every notification event, defaults included.
+type PreferencesResponse struct {
+	Language string              `json:"language"`
+	Timezone string              `json:"timezone"`
+	Currency string              `json:"currency"`
+	Channels map[string][]string `json:"channels"`
+}
+
+// UpdatePreferencesRequest changes the fields that are set. Channels replaces the channels of
+// the events it names, an empty list turning the event off.
+type UpdatePreferencesRequest struct {
+	Language *string             `json:"language,omitempty"`
+	Timezone *string             `json:"timezone,omitempty"`
+	Currency *string             `json:"currency,omitempty"`
+	Channels map[string][]string `json:"channels,omitempty"`
+}
+
+// SendPhoneCodeRequest names the number to verify, the phone of the profile when empty.
+type SendPhoneCodeRequest struct {
+	Phone string `json:"phone"`
+}
+
+type PhoneCodeResponse struct {
+	Phone       string    `json:"phone"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ResendAfter time.Time `json:"resend_after"`
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type PhoneVerifiedResponse struct {
+	Phone         string `json:"phone"`
+	PhoneVerified bool   `json:"phone_verified"`
+}
+
+// SuspendUserRequest suspends a user until ExpiresAt, or until lifted when it is not set.
+// Reason is one of the reason codes; Note is for moderators and is not sent to the user.
+type SuspendUserRequest struct {
+	Reason    string     `json:"reason" binding:"required"`
+	Note      string     `json:"note" binding:"max=2000"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type LiftSuspensionRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type SuspensionResponse struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	Reason      string     `json:"reason"`
+	Note        string     `json:"note,omitempty"`
+	SuspendedBy uint       `json:"suspended_by"`
+	SuspendedAt time.Time  `json:"suspended_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Active      bool       `json:"active"`
+	LiftedAt    *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy    uint       `json:"lifted_by,omitempty"` // 0 when it expired
+	LiftNote    string     `json:"lift_note,omitempty"`
+}
+
+// SpaceSummary is a space as venue-service describes it to other services. Bookable is false
+// while its venue is not approved.
+type SpaceSummary struct {
+	ID          uint    `json:"id"`
+	VenueID     uint    `json:"venue_id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Capacity    int     `json:"capacity"`
+	Price       float64 `json:"price"`
+	OpenHour    string  `json:"open_hour"`
+	CloseHour   string  `json:"close_hour"`
+	VenueName   string  `json:"venue_name"`
+	City        string  `json:"city"`
+	Address     string  `json:"address"`
+	VenueStatus string  `json:"venue_status"`
+	Bookable    bool    `json:"bookable"`
+}
+
+type FavoriteSpaceResponse struct {
+	SpaceSummary
+	FavoritedAt time.Time `json:"favorited_at"`
+}
+
+// FavoriteSpaceExport is a favourite in the personal data export, without the live space data.
+type FavoriteSpaceExport struct {
+	SpaceID     uint      `json:"space_id"`
+	FavoritedAt time.Time `json:"favorited_at"`
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"user-service/internal/constant"
+	"user-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteHandler struct {
+	uc usecase.FavoriteUsecase
+}
+
+func NewFavoriteHandler(uc usecase.FavoriteUsecase) *FavoriteHandler {
+	return &FavoriteHandler{uc: uc}
+}
+
+// AddFavoriteSpace godoc
+// @Summary      Save a space as favourite
+// @Description  Adds the space to the favourites of the current user; adding it again changes nothing. Up to 100 spaces.
+// @Tags         Favorites
+// @Produce      json
+// @Param        id   path      int  true  "Space ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      502  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/favorites/spaces/{id} [post]
+func (h *FavoriteHandler) AddFavoriteSpace(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	spaceID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.AddSpace(c.Request.Context(), email, spaceID)
+	if err != nil {
+		writeFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Space added to favorites",
+		"data":    res,
+	})
+}
+
+// RemoveFavoriteSpace godoc
+// @Summary      Remove a favourite space
+// @Description  Removes the space from the favourites of the current user, if it is one.
+// @Tags         Favorites
+// @Produce      json
+// @Param        id   path      int  true  "Space ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/favorites/spaces/{id} [delete]
+func (h *FavoriteHandler) RemoveFavoriteSpace(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	spaceID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.RemoveSpace(c.Request.Context(), email, spaceID); err != nil {
+		writeFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Space removed from favorites"})
+}
+
+// ListFavoriteSpaces godoc
+// @Summary      List favourite spaces
+// @Description  Lists the favourite spaces of the current user, latest first, with their current data from
+// @Description  venue-service and whether they can be booked now. Spaces deleted since, or in a blocked venue,
+// @Description  are left out and removed from the favourites.
+// @Tags         Favorites
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      502  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/favorites/spaces [get]
+func (h *FavoriteHandler) ListFavoriteSpaces(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.ListSpaces(c.Request.Context(), email)
+	if err != nil {
+		writeFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Favorite spaces fetched successfully",
+		"data":    res,
+	})
+}
+
+func writeFavoriteError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrUserNotFound, constant.ErrSpaceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrTooManyFavorites:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case constant.ErrVenueService:
+		c.JSON(http.StatusBadGateway, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
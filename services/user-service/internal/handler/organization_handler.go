@@ -0,0 +1,528 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	uc usecase.OrganizationUsecase
+}
+
+func NewOrganizationHandler(uc usecase.OrganizationUsecase) *OrganizationHandler {
+	return &OrganizationHandler{uc: uc}
+}
+
+// CreateOrganization godoc
+// @Summary      Create an organization
+// @Description  Creates an organization owned by the current user. billing_email defaults to their email.
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.CreateOrganizationRequest  true  "Organization"
+// @Success      201   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	var req dto.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	org, err := h.uc.CreateOrganization(c.Request.Context(), email, req)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Organization created successfully",
+		"data":    org,
+	})
+}
+
+// ListOrganizations godoc
+// @Summary      List my organizations
+// @Description  Lists the organizations the current user is a member of, with their role in each
+// @Tags         Organizations
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+
+	orgs, err := h.uc.ListOrganizations(c.Request.Context(), email)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Organizations fetched successfully",
+		"data":    orgs,
+	})
+}
+
+// GetOrganization godoc
+// @Summary      Get an organization
+// @Tags         Organizations
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	org, err := h.uc.GetOrganization(c.Request.Context(), email, orgID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Organization fetched successfully",
+		"data":    org,
+	})
+}
+
+// UpdateOrganization godoc
+// @Summary      Update an organization (owners)
+// @Description  Changes the name or billing email that are sent
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                            true  "Organization ID"
+// @Param        body  body      dto.UpdateOrganizationRequest  true  "Fields to change"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	org, err := h.uc.UpdateOrganization(c.Request.Context(), email, orgID, req)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Organization updated successfully",
+		"data":    org,
+	})
+}
+
+// ListMembers godoc
+// @Summary      List the members of an organization
+// @Description  Lists the members and the invitations not accepted yet, with their monthly quotas
+// @Tags         Organizations
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	members, err := h.uc.ListMembers(c.Request.Context(), email, orgID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Members fetched successfully",
+		"data":    members,
+	})
+}
+
+// InviteMember godoc
+// @Summary      Invite a member (owners and admins)
+// @Description  Mails an invitation to the address, which the user accepts once signed in with it.
+// @Description  role is owner, admin or member (the default); admins may only invite members.
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                      true  "Organization ID"
+// @Param        body  body      dto.InviteMemberRequest  true  "Invitation"
+// @Success      201   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id}/members [post]
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	member, err := h.uc.InviteMember(c.Request.Context(), email, orgID, req)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Invitation sent successfully",
+		"data":    member,
+	})
+}
+
+// UpdateMemberRole godoc
+// @Summary      Change the role of a member (owners)
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                          true  "Organization ID"
+// @Param        memberID  path      int                          true  "Member ID"
+// @Param        body      body      dto.UpdateMemberRoleRequest  true  "Role"
+// @Success      200       {object}  map[string]interface{}
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id}/members/{memberID}/role [put]
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := uintParam(c, "memberID")
+	if !ok {
+		return
+	}
+	var req dto.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	member, err := h.uc.UpdateMemberRole(c.Request.Context(), email, orgID, memberID, req.Role)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member updated successfully",
+		"data":    member,
+	})
+}
+
+// UpdateMemberQuota godoc
+// @Summary      Set the monthly booking quotas of a member (owners and admins)
+// @Description  Replaces both quotas: the hours and the amount the member may book each month on the
+// @Description  organization's account. A quota left out or null means no limit.
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                     true  "Organization ID"
+// @Param        memberID  path      int                     true  "Member ID"
+// @Param        body      body      dto.MemberQuotaRequest  true  "Quotas"
+// @Success      200       {object}  map[string]interface{}
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id}/members/{memberID}/quota [put]
+func (h *OrganizationHandler) UpdateMemberQuota(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := uintParam(c, "memberID")
+	if !ok {
+		return
+	}
+	var req dto.MemberQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	member, err := h.uc.UpdateMemberQuota(c.Request.Context(), email, orgID, memberID, req)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member updated successfully",
+		"data":    member,
+	})
+}
+
+// RemoveMember godoc
+// @Summary      Remove a member or withdraw an invitation
+// @Description  Owners remove anyone, admins members; any member may remove themselves to leave.
+// @Description  The last owner cannot leave.
+// @Tags         Organizations
+// @Produce      json
+// @Param        id        path      int  true  "Organization ID"
+// @Param        memberID  path      int  true  "Member ID"
+// @Success      200       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/{id}/members/{memberID} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := uintParam(c, "memberID")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.RemoveMember(c.Request.Context(), email, orgID, memberID); err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// ListInvitations godoc
+// @Summary      List my invitations
+// @Description  Lists the invitations to organizations sent to the email of the current user
+// @Tags         Organizations
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/invitations [get]
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.uc.ListInvitations(c.Request.Context(), email)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitations fetched successfully",
+		"data":    invitations,
+	})
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation
+// @Tags         Organizations
+// @Produce      json
+// @Param        id   path      int  true  "Invitation ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/invitations/{id}/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	member, err := h.uc.AcceptInvitation(c.Request.Context(), email, id)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"data":    member,
+	})
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline an invitation
+// @Tags         Organizations
+// @Produce      json
+// @Param        id   path      int  true  "Invitation ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /organizations/invitations/{id} [delete]
+func (h *OrganizationHandler) DeclineInvitation(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeclineInvitation(c.Request.Context(), email, id); err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined successfully"})
+}
+
+// GetMembership godoc
+// @Summary      Get the membership of a user (internal)
+// @Description  Used by booking-service to check who may book on an organization's account, and their quotas
+// @Tags         Internal
+// @Produce      json
+// @Param        id      path      int  true  "Organization ID"
+// @Param        userID  path      int  true  "User ID"
+// @Success      200     {object}  map[string]interface{}
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /internal/organizations/{id}/members/{userID} [get]
+func (h *OrganizationHandler) GetMembership(c *gin.Context) {
+	orgID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "userID")
+	if !ok {
+		return
+	}
+
+	member, err := h.uc.GetMembership(c.Request.Context(), orgID, userID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// currentEmail returns the email of the signed-in user, or writes the error response.
+func currentEmail(c *gin.Context) (string, bool) {
+	emailValue, exists := c.Get("userEmail")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized})
+		return "", false
+	}
+	email, ok := emailValue.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInvalidEmailType})
+		return "", false
+	}
+	return email, true
+}
+
+// uintParam parses the path parameter name as an ID, or writes the error response.
+func uintParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeOrganizationError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrUserNotFound, constant.ErrOrganizationNotFound, constant.ErrMemberNotFound, constant.ErrInvitationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrInvalidInput, constant.ErrNameRequired, constant.ErrInvalidOrgRole, constant.ErrInvalidQuota:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case constant.ErrOrganizationForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case constant.ErrAlreadyMember, constant.ErrLastOwner:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuspensionHandler struct {
+	uc usecase.SuspensionUsecase
+}
+
+func NewSuspensionHandler(uc usecase.SuspensionUsecase) *SuspensionHandler {
+	return &SuspensionHandler{uc: uc}
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user (admin only)
+// @Description  Deactivates the user and signs them out everywhere, until expires_at or until the suspension is
+// @Description  lifted when it is not set. reason is one of spam, fraud, harassment, payment_dispute,
+// @Description  terms_violation, other; the user is told the reason by email, the note is only seen by staff.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                     true  "User ID"
+// @Param        body  body      dto.SuspendUserRequest  true  "Suspension"
+// @Success      201   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/suspensions [post]
+func (h *SuspensionHandler) SuspendUser(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	res, err := h.uc.SuspendUser(c.Request.Context(), email, userID, req)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "User suspended successfully",
+		"data":    res,
+	})
+}
+
+// LiftSuspension godoc
+// @Summary      Lift the suspension of a user (admin only)
+// @Description  Ends the current suspension before it expires and reactivates the user.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                        true   "User ID"
+// @Param        body  body      dto.LiftSuspensionRequest  false  "Note"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/suspensions/lift [post]
+func (h *SuspensionHandler) LiftSuspension(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.LiftSuspensionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+			return
+		}
+	}
+
+	res, err := h.uc.LiftSuspension(c.Request.Context(), email, userID, req)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Suspension lifted successfully",
+		"data":    res,
+	})
+}
+
+// ListSuspensions godoc
+// @Summary      Suspension history of a user (moderators and admins)
+// @Description  Lists every suspension of the user, latest first, with who suspended and lifted it.
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/suspensions [get]
+func (h *SuspensionHandler) ListSuspensions(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.ListSuspensions(c.Request.Context(), userID)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Suspensions fetched successfully",
+		"data":    res,
+	})
+}
+
+func writeSuspensionError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrInvalidSuspension, constant.ErrCannotSuspendSelf:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case constant.ErrUnauthorized:
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case constant.ErrAlreadySuspended, constant.ErrNotSuspended:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
@@ -1,22 +1,32 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"user-service/internal/constant"
 	"user-service/internal/dto"
 	"user-service/internal/usecase"
+	"user-service/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	uc usecase.UserUsecase
+	uc    usecase.UserUsecase
+	prefs usecase.PreferenceUsecase
+	orgs  usecase.OrganizationUsecase
+	phone usecase.PhoneUsecase
+	// suspensions keeps UpdateUser from reactivating a suspended user; closed on account deletion
+	suspensions usecase.SuspensionUsecase
+	favorites   usecase.FavoriteUsecase
 }
 
-func NewUserHandler(uc usecase.UserUsecase) *UserHandler {
-	return &UserHandler{uc: uc}
+func NewUserHandler(uc usecase.UserUsecase, prefs usecase.PreferenceUsecase, orgs usecase.OrganizationUsecase, phone usecase.PhoneUsecase, suspensions usecase.SuspensionUsecase, favorites usecase.FavoriteUsecase) *UserHandler {
+	return &UserHandler{uc: uc, prefs: prefs, orgs: orgs, phone: phone, suspensions: suspensions, favorites: favorites}
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
@@ -94,7 +104,8 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 
 // UpdateUserProfile godoc
 // @Summary      Update profile of current user
-// @Description  Update name, phone, etc. of logged-in user
+// @Description  Update name, phone, etc. of logged-in user. The phone is stored in E.164 format, numbers
+// @Description  without a country code being read as Vietnamese; a new number has to be verified again.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -127,10 +138,12 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrNameRequired})
 		return
 	}
-	if len(req.Phone) < 9 || len(req.Phone) > 11 {
+	phone, err := utils.NormalizePhone(req.Phone)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidPhoneNumber})
 		return
 	}
+	req.Phone = phone
 
 	profile, err := h.uc.UpdateProfile(c.Request.Context(), email, &req)
 	if err != nil {
@@ -142,6 +155,11 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 		}
 		return
 	}
+	// SMS must stop going to a number that was replaced
+	if err := h.phone.SyncSMSPhone(c.Request.Context(), email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrUpdateFailed})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile updated successfully",
@@ -188,6 +206,366 @@ func (h *UserHandler) GetUserList(c *gin.Context) {
 	})
 }
 
+// UploadAvatar godoc
+// @Summary      Upload avatar of current user
+// @Description  Replaces the avatar with a JPEG or PNG picture of up to 5 MB, sent as the "avatar" form field.
+// @Description  The picture is cropped to a square, stripped of its metadata and stored with a thumbnail.
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        avatar  formData  file  true  "Avatar picture"
+// @Success      200     {object}  map[string]interface{}
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      413     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/profile/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	emailValue, exists := c.Get("userEmail")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized})
+		return
+	}
+	email, ok := emailValue.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInvalidEmailType})
+		return
+	}
+
+	// leave room for the multipart envelope around the picture
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, utils.MaxAvatarBytes+64<<10)
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": constant.ErrImageTooLarge})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+	if fileHeader.Size > utils.MaxAvatarBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": constant.ErrImageTooLarge})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, utils.MaxAvatarBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	res, err := h.uc.UploadAvatar(c.Request.Context(), email, data)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+		case constant.ErrImageTooLarge:
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": err.Error()})
+		case constant.ErrUnsupportedImage, constant.ErrInvalidImage:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Avatar uploaded successfully",
+		"data":    res,
+	})
+}
+
+// GetPreferences godoc
+// @Summary      Get preferences of current user
+// @Description  Returns the language, timezone, currency and the channels of every notification event
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/profile/preferences [get]
+func (h *UserHandler) GetPreferences(c *gin.Context) {
+	emailValue, exists := c.Get("userEmail")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized})
+		return
+	}
+	email, ok := emailValue.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInvalidEmailType})
+		return
+	}
+
+	prefs, err := h.prefs.GetPreferences(c.Request.Context(), email)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Preferences fetched successfully",
+		"data":    prefs,
+	})
+}
+
+// UpdatePreferences godoc
+// @Summary      Update preferences of current user
+// @Description  Changes the fields that are sent. language is one of en, vi; timezone an IANA name such as
+// @Description  Asia/Ho_Chi_Minh; currency one of VND, USD, EUR. channels maps BOOKING_CREATED or
+// @Description  BOOKING_STATUS_UPDATED to any of in_app, email, sms; an empty list turns the event off.
+// @Description  sms needs a verified phone.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.UpdatePreferencesRequest  true  "Preferences to change"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/profile/preferences [put]
+func (h *UserHandler) UpdatePreferences(c *gin.Context) {
+	emailValue, exists := c.Get("userEmail")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized})
+		return
+	}
+	email, ok := emailValue.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInvalidEmailType})
+		return
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	prefs, err := h.prefs.UpdatePreferences(c.Request.Context(), email, req)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+		case constant.ErrInvalidLanguage, constant.ErrInvalidTimezone, constant.ErrInvalidCurrency, constant.ErrInvalidChannels,
+			constant.ErrPhoneNotVerified:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Preferences updated successfully",
+		"data":    prefs,
+	})
+}
+
+// SendPhoneCode godoc
+// @Summary      Send a phone verification code
+// @Description  Texts a 6-digit code to the number, or to the phone of the profile when none is sent. The code
+// @Description  expires after 5 minutes; a new one can be asked for once a minute, 5 times a day.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.SendPhoneCodeRequest  false  "Number to verify"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      429   {object}  map[string]string
+// @Failure      502   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/profile/phone/verification [post]
+func (h *UserHandler) SendPhoneCode(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	var req dto.SendPhoneCodeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+			return
+		}
+	}
+
+	res, err := h.phone.SendVerificationCode(c.Request.Context(), email, req.Phone)
+	if err != nil {
+		writePhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification code sent successfully",
+		"data":    res,
+	})
+}
+
+// VerifyPhone godoc
+// @Summary      Verify the phone with the code
+// @Description  Makes the number the code was sent to the verified phone of the profile, which SMS notifications
+// @Description  go to. 5 wrong codes void the code.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.VerifyPhoneRequest  true  "Code"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      429   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/profile/phone/verify [post]
+func (h *UserHandler) VerifyPhone(c *gin.Context) {
+	email, ok := currentEmail(c)
+	if !ok {
+		return
+	}
+	var req dto.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidRequest})
+		return
+	}
+
+	res, err := h.phone.VerifyPhone(c.Request.Context(), email, strings.TrimSpace(req.Code))
+	if err != nil {
+		writePhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Phone verified successfully",
+		"data":    res,
+	})
+}
+
+func writePhoneError(c *gin.Context, err error) {
+	switch err.Error() {
+	case constant.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case constant.ErrInvalidPhoneNumber, constant.ErrPhoneNotMobile, constant.ErrInvalidOTP, constant.ErrOTPExpired:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case constant.ErrPhoneAlreadyVerified:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case constant.ErrOTPResendTooSoon, constant.ErrOTPDailyLimit, constant.ErrOTPTooManyAttempts:
+		c.JSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+	case constant.ErrSendSMS:
+		c.JSON(http.StatusBadGateway, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
+
+// SearchUsers godoc
+// @Summary      Search users (admin and moderator)
+// @Description  Finds users by email or name fragment, role, active flag, verification and signup dates.
+// @Description  Results are sorted by one field and paged with the next_cursor of the previous page.
+// @Tags         Users
+// @Produce      json
+// @Param        q             query     string  false  "Email or name fragment"
+// @Param        role          query     string  false  "Role"  Enums(admin, user, moderator)
+// @Param        is_active     query     bool    false  "Active flag"
+// @Param        is_verified   query     bool    false  "Email verified"
+// @Param        created_from  query     string  false  "First signup day (YYYY-MM-DD)"
+// @Param        created_to    query     string  false  "Last signup day (YYYY-MM-DD)"
+// @Param        sort          query     string  false  "Sort field"  Enums(email, name, role, is_active, is_verified, created_at)
+// @Param        order         query     string  false  "Sort order"  Enums(asc, desc)
+// @Param        limit         query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor        query     string  false  "next_cursor of the previous page"
+// @Success      200           {object}  map[string]interface{}
+// @Failure      400           {object}  map[string]string
+// @Failure      500           {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/search [get]
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	var req dto.SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidSearchParam})
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+
+	res, err := h.uc.SearchUsers(c.Request.Context(), req)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrInvalidSearchParam, constant.ErrInvalidCursor:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Users fetched successfully",
+		"data":    res,
+	})
+}
+
+// ExportUsers godoc
+// @Summary      Export users as CSV (admin only)
+// @Description  Download every user matching the filters of the search as a CSV file, in the chosen order.
+// @Description  The file can be imported again with POST /auth/admin/users/import.
+// @Tags         Users
+// @Produce      text/csv
+// @Param        q             query     string  false  "Email or name fragment"
+// @Param        role          query     string  false  "Role"  Enums(admin, user, moderator)
+// @Param        is_active     query     bool    false  "Active flag"
+// @Param        is_verified   query     bool    false  "Email verified"
+// @Param        created_from  query     string  false  "First signup day (YYYY-MM-DD)"
+// @Param        created_to    query     string  false  "Last signup day (YYYY-MM-DD)"
+// @Param        sort          query     string  false  "Sort field"  Enums(email, name, role, is_active, is_verified, created_at)
+// @Param        order         query     string  false  "Sort order"  Enums(asc, desc)
+// @Success      200           {file}    file
+// @Failure      400           {object}  map[string]string
+// @Failure      500           {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	var req dto.SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidSearchParam})
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="users.csv"`)
+	err := h.uc.ExportUsers(c.Request.Context(), req, c.Writer)
+	if err == nil {
+		return
+	}
+	// once rows went out the status is sent, the client only sees a cut file
+	if c.Writer.Written() {
+		log.Println("export users failed:", err)
+		return
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	switch err.Error() {
+	case constant.ErrInvalidSearchParam, constant.ErrInvalidCursor:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+	}
+}
+
 // GetUserByID godoc
 // @Summary      Get user by ID (admin only)
 // @Description  Returns user info by ID
@@ -226,7 +604,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary      Update user role, active status by ID (admin only)
-// @Description  Update role, isactive
+// @Description  Update role, isactive. A suspended user can only be reactivated by lifting the suspension.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -235,6 +613,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Success      200   {object}  map[string]interface{}
 // @Failure      400   {object}  map[string]string
 // @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
 // @Failure      500   {object}  map[string]string
 // @Security     BearerAuth
 // @Router       /users/{id} [put]
@@ -252,6 +631,18 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if req.IsActive != nil && *req.IsActive {
+		suspended, err := h.suspensions.IsSuspended(c.Request.Context(), uint(userID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+			return
+		}
+		if suspended {
+			c.JSON(http.StatusConflict, gin.H{"message": constant.ErrUserSuspended})
+			return
+		}
+	}
+
 	user, err := h.uc.UpdateUser(c.Request.Context(), req, uint(userID))
 	if err != nil {
 		if err.Error() == constant.ErrUserNotFound {
@@ -267,3 +658,231 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		"data":    user,
 	})
 }
+
+// GetUserByEmail godoc
+// @Summary      Get user by email (internal)
+// @Description  Used by auth-service to resume an unfinished sign-up
+// @Tags         Internal
+// @Produce      json
+// @Param        email  query     string  true  "Email"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /internal/users [get]
+func (h *UserHandler) GetUserByEmail(c *gin.Context) {
+	email := strings.ToLower(strings.TrimSpace(c.Query("email")))
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidEmailType})
+		return
+	}
+
+	user, err := h.uc.GetUserByEmail(c.Request.Context(), email)
+	if err != nil {
+		if err.Error() == constant.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User fetched successfully",
+		"data":    user,
+	})
+}
+
+// DeleteUser godoc
+// @Summary      Delete user (internal)
+// @Description  Compensating delete used by auth-service when a sign-up cannot be completed
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /internal/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	if err := h.uc.DeleteUser(c.Request.Context(), uint(id)); err != nil {
+		if err.Error() == constant.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
+}
+
+// ChangeEmail godoc
+// @Summary      Change user email (internal)
+// @Description  Used by auth-service when a user confirms or reverts an email change
+// @Tags         Internal
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                     true  "User ID"
+// @Param        request  body      dto.ChangeEmailRequest  true  "New email"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /internal/users/{id}/email [put]
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidInput})
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	if err := h.uc.ChangeEmail(c.Request.Context(), uint(id), req.Email); err != nil {
+		switch err.Error() {
+		case constant.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+		case constant.ErrEmailAlreadyExists:
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrEmailAlreadyExists})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email changed successfully"})
+}
+
+// MarkVerified godoc
+// @Summary      Mark user as verified (internal)
+// @Description  Used by auth-service once the user verified their email
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /internal/users/{id}/verified [put]
+func (h *UserHandler) MarkVerified(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	if err := h.uc.MarkVerified(c.Request.Context(), uint(id)); err != nil {
+		if err.Error() == constant.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user marked as verified"})
+}
+
+// ExportUserData godoc
+// @Summary      Export the data of a user (internal)
+// @Description  Used by auth-service to build the personal data export of a user
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /internal/user-data/{id} [get]
+func (h *UserHandler) ExportUserData(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	user, err := h.uc.ExportUserData(c.Request.Context(), uint(id))
+	if err != nil {
+		if err.Error() == constant.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": constant.ErrUserNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	prefs, err := h.prefs.ExportPreferences(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	orgs, err := h.orgs.ExportMemberships(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	favorites, err := h.favorites.ExportFavorites(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"profile": user, "preferences": prefs, "organizations": orgs, "favorite_spaces": favorites}})
+}
+
+// EraseUserData godoc
+// @Summary      Anonymise the profile of a deleted account (internal)
+// @Description  Used by auth-service when the account of the user is deleted
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /internal/user-data/{id} [delete]
+func (h *UserHandler) EraseUserData(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidUserID})
+		return
+	}
+
+	// memberships go first, while the profile still holds the address invitations were sent to
+	if err := h.orgs.EraseMemberships(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	if err := h.phone.ErasePhoneVerification(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	if err := h.favorites.EraseFavorites(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	if err := h.suspensions.EraseSuspensions(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	if err := h.uc.EraseUserData(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+	if err := h.prefs.ResetPreferences(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": constant.ErrInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user data erased"})
+}
@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Producer interface {
+	Publish(ctx context.Context, key, value []byte) error
+	Close() error
+}
+
+type producer struct {
+	writer *kafka.Writer
+}
+
+// NewProducer hashes keys to partitions, so the messages of one key stay in order.
+func NewProducer(brokers []string, topic string) Producer {
+	return &producer{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+	}
+}
+
+func (p *producer) Publish(ctx context.Context, key, value []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   key,
+		Value: value,
+	})
+}
+
+func (p *producer) Close() error {
+	return p.writer.Close()
+}
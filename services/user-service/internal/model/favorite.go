@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// FavoriteSpace is a space of venue-service the user saved to book again. Rows pointing at a
+// space that was deleted, or whose venue was blocked, are removed when the list is read.
+type FavoriteSpace struct {
+	UserID    uint `gorm:"primaryKey;autoIncrement:false"`
+	SpaceID   uint `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// Organization is a company whose members book on its account. Bookings billed to it are kept
+// by booking-service; BillingEmail is where its statements go.
+type Organization struct {
+	gorm.Model
+	Name         string `gorm:"type:varchar(255);not null"`
+	BillingEmail string `gorm:"type:varchar(255)"`
+	CreatedBy    uint   `gorm:"not null"`
+}
+
+// OrganizationMember is a user of an organization, or an invitation to the address in Email
+// until it is accepted; UserID is only set from then on. A nil quota means no limit.
+type OrganizationMember struct {
+	gorm.Model
+	OrganizationID uint   `gorm:"not null;uniqueIndex:idx_org_member_email,priority:1;index:idx_org_member_user,priority:1"`
+	Email          string `gorm:"type:varchar(255);not null;uniqueIndex:idx_org_member_email,priority:2;index"`
+	UserID         uint   `gorm:"index:idx_org_member_user,priority:2"`
+	Role           string `gorm:"type:varchar(20);not null"` // owner, admin, member
+	Status         string `gorm:"type:varchar(20);not null"` // invited, active
+	InvitedBy      uint
+	// monthly limits on what the member books on the organization's account
+	MonthlyHourQuota  *float64      `gorm:"type:decimal(8,2)"`
+	MonthlySpendQuota *float64      `gorm:"type:decimal(12,2)"`
+	Organization      *Organization `json:",omitempty"`
+}
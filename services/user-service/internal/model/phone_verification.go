@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// PhoneVerification is the code last sent to a user to verify Phone; only its salted hash is
+// kept. SendCount counts the codes sent since WindowStart, to cap them per day.
+type PhoneVerification struct {
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false"`
+	Phone       string    `gorm:"type:varchar(20);not null"`
+	CodeHash    string    `gorm:"type:char(64);not null"`
+	Salt        string    `gorm:"type:char(32);not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	Attempts    int       `gorm:"not null;default:0"`
+	SentAt      time.Time `gorm:"not null"`
+	SendCount   int       `gorm:"not null;default:0"`
+	WindowStart time.Time `gorm:"not null"`
+}
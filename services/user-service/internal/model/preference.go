@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// UserPreference holds what a user chose in their preferences; users without a row get
+// preferences.Default. Channels maps a notification event to the channels it is delivered on.
+// Phone is published with them so whoever sends SMS has the number, and only once verified.
+// Every save bumps Version and clears Published, and the preference relay publishes the row
+// again, so consumers catch up even when Kafka was down during the change.
+type UserPreference struct {
+	UserID    uint                `gorm:"primaryKey;autoIncrement:false"`
+	Language  string              `gorm:"type:varchar(8);not null"`
+	Timezone  string              `gorm:"type:varchar(64);not null"`
+	Currency  string              `gorm:"type:char(3);not null"`
+	Channels  map[string][]string `gorm:"type:text;serializer:json"`
+	Phone     string              `gorm:"type:varchar(20)"` // verified number SMS goes to
+	Version   int                 `gorm:"not null;default:0"`
+	Published bool                `gorm:"not null;default:false;index"`
+	UpdatedAt time.Time
+}
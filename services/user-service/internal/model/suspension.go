@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Suspension is a period a user is barred from the platform, kept once over as its history. It
+// ends at ExpiresAt, or never when nil, unless it is lifted first; LiftedBy stays 0 when it
+// expired on its own.
+type Suspension struct {
+	gorm.Model
+	UserID      uint       `gorm:"not null;index"`
+	Reason      string     `gorm:"type:varchar(32);not null"`
+	Note        string     `gorm:"type:text"`
+	SuspendedBy uint       `gorm:"not null"`
+	ExpiresAt   *time.Time `gorm:"index"`
+	LiftedAt    *time.Time `gorm:"index"`
+	LiftedBy    uint
+	LiftNote    string `gorm:"type:text"`
+}
+
+// Active reports whether the suspension was not lifted yet. An expired one is active until the
+// expiry job lifts it.
+func (s *Suspension) Active() bool {
+	return s.LiftedAt == nil
+}
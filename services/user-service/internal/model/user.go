@@ -4,11 +4,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// User is indexed on every column the admin search filters or sorts on; created_at, which
+// comes with gorm.Model, is indexed in db.AutoMigrate. InnoDB appends the primary key to
+// secondary indexes, giving the (column, id) order the search cursor walks.
+// IsVerified mirrors the email verification held by auth-service so users can be searched by it.
 type User struct {
 	gorm.Model
 	Email        string `gorm:"type:varchar(255);not null;uniqueIndex"`
-	Name         string `gorm:"type:varchar(255)"`
+	Name         string `gorm:"type:varchar(255);index"`
 	Phone        string `gorm:"type:varchar(20)"`
-	Role         string `gorm:"type:varchar(50);not null"` // e.g. USER, MODERATOR, ADMIN
-	IsActive     bool   `gorm:"default:true"`
+	Role         string `gorm:"type:varchar(50);not null;index"` // e.g. USER, MODERATOR, ADMIN
+	IsActive     bool   `gorm:"default:true;index"`
+	IsVerified   bool   `gorm:"default:false;index"`
+	// set once the user proved they own Phone with a code sent by SMS
+	PhoneVerified bool `gorm:"default:false"`
+	// storage keys of the avatar and its thumbnail; clients get URLs, which may expire
+	AvatarKey      string `gorm:"type:varchar(255)" json:"-"`
+	AvatarThumbKey string `gorm:"type:varchar(255)" json:"-"`
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"packages/servicetoken"
 	"time"
 	"user-service/internal/constant"
 	"user-service/internal/dto"
@@ -22,12 +23,11 @@ type authClient struct {
 	http    *http.Client
 }
 
-func NewAuthClient(baseURL string) AuthClient {
+// NewAuthClient calls auth-service, authenticated with a service token.
+func NewAuthClient(baseURL string, issuer *servicetoken.Issuer) AuthClient {
 	return &authClient{
 		baseURL: baseURL,
-		http: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		http:    issuer.Client(servicetoken.AuthService, 5*time.Second),
 	}
 }
 
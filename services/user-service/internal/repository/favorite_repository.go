@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository interface {
+	AddSpace(ctx context.Context, fav *model.FavoriteSpace, max int) error
+	RemoveSpaces(ctx context.Context, userID uint, spaceIDs ...uint) error
+	ListSpaces(ctx context.Context, userID uint) ([]model.FavoriteSpace, error)
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type favoriteRepo struct{ db *gorm.DB }
+
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepo{db: db}
+}
+
+// AddSpace keeps the existing row, and its date, when the space is already a favourite. A user
+// with max favourites gets no more: the user row is locked while they are counted, so parallel
+// requests cannot go past it.
+func (r *favoriteRepo) AddSpace(ctx context.Context, fav *model.FavoriteSpace, max int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.User{}, fav.UserID).Error; err != nil {
+			return err
+		}
+		var existing model.FavoriteSpace
+		err := tx.Where("user_id = ? AND space_id = ?", fav.UserID, fav.SpaceID).Limit(1).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing.UserID != 0 {
+			*fav = existing
+			return nil
+		}
+		var n int64
+		if err := tx.Model(&model.FavoriteSpace{}).Where("user_id = ?", fav.UserID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n >= int64(max) {
+			return errors.New(constant.ErrTooManyFavorites)
+		}
+		return tx.Create(fav).Error
+	})
+	if err != nil {
+		if err.Error() == constant.ErrTooManyFavorites {
+			return err
+		}
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+func (r *favoriteRepo) RemoveSpaces(ctx context.Context, userID uint, spaceIDs ...uint) error {
+	if len(spaceIDs) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND space_id IN ?", userID, spaceIDs).
+		Delete(&model.FavoriteSpace{}).Error
+	if err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+// ListSpaces returns the favourites of the user, latest first.
+func (r *favoriteRepo) ListSpaces(ctx context.Context, userID uint) ([]model.FavoriteSpace, error) {
+	var favs []model.FavoriteSpace
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&favs).Error; err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return favs, nil
+}
+
+func (r *favoriteRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.FavoriteSpace{}).Error
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *model.Organization, owner *model.OrganizationMember) error
+	GetByID(ctx context.Context, id uint) (*model.Organization, error)
+	Update(ctx context.Context, org *model.Organization) error
+	ListMemberships(ctx context.Context, userID uint) ([]model.OrganizationMember, error)
+	ListInvitations(ctx context.Context, email string) ([]model.OrganizationMember, error)
+	GetMember(ctx context.Context, id uint) (*model.OrganizationMember, error)
+	GetMemberByUser(ctx context.Context, orgID, userID uint) (*model.OrganizationMember, error)
+	GetMemberByEmail(ctx context.Context, orgID uint, email string) (*model.OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID uint) ([]model.OrganizationMember, error)
+	CountOwners(ctx context.Context, orgID uint) (int64, error)
+	CreateMember(ctx context.Context, member *model.OrganizationMember) error
+	UpdateMember(ctx context.Context, member *model.OrganizationMember) error
+	DeleteMember(ctx context.Context, id uint) error
+	DeleteByUser(ctx context.Context, userID uint, email string) error
+}
+
+type organizationRepo struct{ db *gorm.DB }
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepo{db: db}
+}
+
+// Create stores org with its first owner.
+func (r *organizationRepo) Create(ctx context.Context, org *model.Organization, owner *model.OrganizationMember) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		owner.OrganizationID = org.ID
+		return tx.Create(owner).Error
+	})
+	if err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+// GetByID returns nil when there is no such organization.
+func (r *organizationRepo) GetByID(ctx context.Context, id uint) (*model.Organization, error) {
+	var org model.Organization
+	if err := r.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return &org, nil
+}
+
+func (r *organizationRepo) Update(ctx context.Context, org *model.Organization) error {
+	if err := r.db.WithContext(ctx).Save(org).Error; err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+// ListMemberships returns the organizations the user is an active member of.
+func (r *organizationRepo) ListMemberships(ctx context.Context, userID uint) ([]model.OrganizationMember, error) {
+	members := []model.OrganizationMember{}
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("user_id = ? AND status = ?", userID, constant.MemberStatusActive).
+		Order("id").
+		Find(&members).Error
+	if err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return members, nil
+}
+
+// ListInvitations returns the invitations sent to email that are still open.
+func (r *organizationRepo) ListInvitations(ctx context.Context, email string) ([]model.OrganizationMember, error) {
+	members := []model.OrganizationMember{}
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("email = ? AND status = ?", email, constant.MemberStatusInvited).
+		Order("id").
+		Find(&members).Error
+	if err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return members, nil
+}
+
+// GetMember returns nil when there is no such member.
+func (r *organizationRepo) GetMember(ctx context.Context, id uint) (*model.OrganizationMember, error) {
+	return r.firstMember(ctx, "id = ?", id)
+}
+
+// GetMemberByUser returns nil when the user is not a member of the organization.
+func (r *organizationRepo) GetMemberByUser(ctx context.Context, orgID, userID uint) (*model.OrganizationMember, error) {
+	return r.firstMember(ctx, "organization_id = ? AND user_id = ?", orgID, userID)
+}
+
+// GetMemberByEmail returns nil when email is neither a member nor invited.
+func (r *organizationRepo) GetMemberByEmail(ctx context.Context, orgID uint, email string) (*model.OrganizationMember, error) {
+	return r.firstMember(ctx, "organization_id = ? AND email = ?", orgID, email)
+}
+
+func (r *organizationRepo) firstMember(ctx context.Context, query string, args ...interface{}) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return &member, nil
+}
+
+func (r *organizationRepo) ListMembers(ctx context.Context, orgID uint) ([]model.OrganizationMember, error) {
+	members := []model.OrganizationMember{}
+	if err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("id").Find(&members).Error; err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return members, nil
+}
+
+func (r *organizationRepo) CountOwners(ctx context.Context, orgID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.OrganizationMember{}).
+		Where("organization_id = ? AND role = ? AND status = ?", orgID, constant.OrgRoleOwner, constant.MemberStatusActive).
+		Count(&count).Error
+	if err != nil {
+		return 0, errors.New(constant.ErrDatabase)
+	}
+	return count, nil
+}
+
+func (r *organizationRepo) CreateMember(ctx context.Context, member *model.OrganizationMember) error {
+	if err := r.db.WithContext(ctx).Create(member).Error; err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+func (r *organizationRepo) UpdateMember(ctx context.Context, member *model.OrganizationMember) error {
+	// the organization is only loaded for reading
+	if err := r.db.WithContext(ctx).Omit("Organization").Save(member).Error; err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+// DeleteMember removes the row for good, so the address can be invited again.
+func (r *organizationRepo) DeleteMember(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Unscoped().Delete(&model.OrganizationMember{}, id).Error; err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+// DeleteByUser removes the memberships of the user and the invitations still open for email.
+func (r *organizationRepo) DeleteByUser(ctx context.Context, userID uint, email string) error {
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? OR (email = ? AND status = ?)", userID, email, constant.MemberStatusInvited).
+		Delete(&model.OrganizationMember{}).Error
+	if err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PhoneVerificationRepository interface {
+	Get(ctx context.Context, userID uint) (*model.PhoneVerification, error)
+	ClaimSend(ctx context.Context, userID uint, claim func(v *model.PhoneVerification) error) error
+	Delete(ctx context.Context, userID uint) error
+	CountAttempt(ctx context.Context, userID uint, codeHash string, max int) (bool, error)
+	ClearCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+}
+
+type phoneVerificationRepo struct{ db *gorm.DB }
+
+func NewPhoneVerificationRepository(db *gorm.DB) PhoneVerificationRepository {
+	return &phoneVerificationRepo{db: db}
+}
+
+// Get returns nil when no code was sent to the user.
+func (r *phoneVerificationRepo) Get(ctx context.Context, userID uint) (*model.PhoneVerification, error) {
+	var v model.PhoneVerification
+	if err := r.db.WithContext(ctx).First(&v, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return &v, nil
+}
+
+// ClaimSend records the next code sent to the user before it is texted. claim checks the last
+// send and updates v, a new row when no code was sent yet; an error from it is returned as is
+// and nothing is stored. The user row is locked meanwhile, so parallel requests are checked one
+// after the other and cannot all get past the resend interval or the daily limit.
+func (r *phoneVerificationRepo) ClaimSend(ctx context.Context, userID uint, claim func(v *model.PhoneVerification) error) error {
+	var claimErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.User{}, userID).Error; err != nil {
+			return err
+		}
+		v := model.PhoneVerification{UserID: userID}
+		if err := tx.Where("user_id = ?", userID).Limit(1).Find(&v).Error; err != nil {
+			return err
+		}
+		if claimErr = claim(&v); claimErr != nil {
+			return claimErr
+		}
+		return tx.Save(&v).Error
+	})
+	if claimErr != nil {
+		return claimErr
+	}
+	if err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+func (r *phoneVerificationRepo) Delete(ctx context.Context, userID uint) error {
+	if err := r.db.WithContext(ctx).Delete(&model.PhoneVerification{}, "user_id = ?", userID).Error; err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+// CountAttempt counts a guess at the code, in one statement so concurrent guesses cannot get
+// past max. It reports false once max guesses were made, or when the code was replaced since.
+func (r *phoneVerificationRepo) CountAttempt(ctx context.Context, userID uint, codeHash string, max int) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.PhoneVerification{}).
+		Where("user_id = ? AND code_hash = ? AND attempts < ?", userID, codeHash, max).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, errors.New(constant.ErrDatabase)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ClearCode uses up the code. It reports false when it was used or replaced in the meantime.
+func (r *phoneVerificationRepo) ClearCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.PhoneVerification{}).
+		Where("user_id = ? AND code_hash = ?", userID, codeHash).
+		UpdateColumn("code_hash", "")
+	if result.Error != nil {
+		return false, errors.New(constant.ErrDatabase)
+	}
+	return result.RowsAffected == 1, nil
+}
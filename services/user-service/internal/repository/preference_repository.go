@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceRepository interface {
+	Get(ctx context.Context, userID uint) (*model.UserPreference, error)
+	Save(ctx context.Context, pref *model.UserPreference) error
+	ListUnpublished(ctx context.Context, limit int) ([]model.UserPreference, error)
+	MarkPublished(ctx context.Context, userID uint, version int) error
+}
+
+type preferenceRepo struct{ db *gorm.DB }
+
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepository {
+	return &preferenceRepo{db: db}
+}
+
+// Get returns nil when the user never saved any preferences.
+func (r *preferenceRepo) Get(ctx context.Context, userID uint) (*model.UserPreference, error) {
+	var pref model.UserPreference
+	if err := r.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return &pref, nil
+}
+
+// Save stores pref as the next version of the preferences of the user, waiting to be published.
+func (r *preferenceRepo) Save(ctx context.Context, pref *model.UserPreference) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current model.UserPreference
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("version").
+			First(&current, "user_id = ?", pref.UserID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		pref.Version = current.Version + 1
+		pref.Published = false
+		return tx.Save(pref).Error
+	})
+	if err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+func (r *preferenceRepo) ListUnpublished(ctx context.Context, limit int) ([]model.UserPreference, error) {
+	var prefs []model.UserPreference
+	err := r.db.WithContext(ctx).
+		Where("published = ?", false).
+		Order("updated_at").
+		Limit(limit).
+		Find(&prefs).Error
+	if err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return prefs, nil
+}
+
+// MarkPublished only marks the version that was sent; a change saved in the meantime stays
+// waiting.
+func (r *preferenceRepo) MarkPublished(ctx context.Context, userID uint, version int) error {
+	return r.db.WithContext(ctx).Model(&model.UserPreference{}).
+		Where("user_id = ? AND version = ?", userID, version).
+		Update("published", true).Error
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type SuspensionRepository interface {
+	Create(ctx context.Context, s *model.Suspension) error
+	Update(ctx context.Context, s *model.Suspension) error
+	GetActive(ctx context.Context, userID uint) (*model.Suspension, error)
+	ListByUser(ctx context.Context, userID uint) ([]model.Suspension, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Suspension, error)
+}
+
+type suspensionRepo struct{ db *gorm.DB }
+
+func NewSuspensionRepository(db *gorm.DB) SuspensionRepository {
+	return &suspensionRepo{db: db}
+}
+
+func (r *suspensionRepo) Create(ctx context.Context, s *model.Suspension) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *suspensionRepo) Update(ctx context.Context, s *model.Suspension) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
+
+// GetActive returns nil when the user is not suspended.
+func (r *suspensionRepo) GetActive(ctx context.Context, userID uint) (*model.Suspension, error) {
+	var s model.Suspension
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND lifted_at IS NULL", userID).
+		Order("id DESC").
+		First(&s).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return &s, nil
+}
+
+// ListByUser returns the suspension history of the user, latest first.
+func (r *suspensionRepo) ListByUser(ctx context.Context, userID uint) ([]model.Suspension, error) {
+	var suspensions []model.Suspension
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&suspensions).Error; err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return suspensions, nil
+}
+
+// ListExpired returns suspensions still in force whose end is past, oldest end first.
+func (r *suspensionRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Suspension, error) {
+	var suspensions []model.Suspension
+	err := r.db.WithContext(ctx).
+		Where("lifted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&suspensions).Error
+	if err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return suspensions, nil
+}
@@ -3,7 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 	"user-service/internal/constant"
+	"user-service/internal/dto"
 	"user-service/internal/model"
 
 	"gorm.io/gorm"
@@ -16,6 +21,9 @@ type UserRepository interface {
 	Update(ctx context.Context, user *model.User) error
 	GetByID(ctx context.Context, id uint) (*model.User, error)
 	GetUserList(ctx context.Context, offset, limit int) ([]model.User, int64, error)
+	Search(ctx context.Context, filter dto.UserSearchFilter) ([]model.User, error)
+	MarkVerified(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
 }
 
 type userRepo struct{ db *gorm.DB }
@@ -89,3 +97,92 @@ func (r *userRepo) GetUserList(ctx context.Context, offset, limit int) ([]model.
 
 	return users, total, nil
 }
+
+// searchColumns maps the sort keys of the search to their columns.
+var searchColumns = map[string]string{
+	"email":       "email",
+	"name":        "name",
+	"role":        "role",
+	"is_active":   "is_active",
+	"is_verified": "is_verified",
+	"created_at":  "created_at",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Search returns up to filter.Limit users ordered by the sort column, then by ID, starting after
+// filter.After. Seeking past the last row instead of skipping an offset keeps deep pages as cheap
+// as the first and stable while users sign up.
+func (r *userRepo) Search(ctx context.Context, filter dto.UserSearchFilter) ([]model.User, error) {
+	column, ok := searchColumns[filter.Sort]
+	if !ok {
+		return nil, errors.New(constant.ErrInvalidSearchParam)
+	}
+
+	q := r.db.WithContext(ctx).Model(&model.User{})
+	if filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Query) + "%"
+		q = q.Where("email LIKE ? OR name LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		q = q.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		q = q.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.IsVerified != nil {
+		q = q.Where("is_verified = ?", *filter.IsVerified)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		q = q.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		q = q.Where("created_at < ?", filter.CreatedTo)
+	}
+
+	cmp, order := ">", "ASC"
+	if filter.Desc {
+		cmp, order = "<", "DESC"
+	}
+	if filter.After != nil {
+		value, err := cursorValue(filter.Sort, filter.After.Value)
+		if err != nil {
+			return nil, errors.New(constant.ErrInvalidCursor)
+		}
+		q = q.Where(fmt.Sprintf("(%[1]s %[2]s ?) OR (%[1]s = ? AND id %[2]s ?)", column, cmp), value, value, filter.After.ID)
+	}
+
+	users := []model.User{}
+	if err := q.Order(column + " " + order).Order("id " + order).Limit(filter.Limit).Find(&users).Error; err != nil {
+		return nil, errors.New(constant.ErrDatabase)
+	}
+	return users, nil
+}
+
+// cursorValue converts the sort value stored in a cursor back to the type of its column.
+func cursorValue(sort, value string) (interface{}, error) {
+	switch sort {
+	case "is_active", "is_verified":
+		return strconv.ParseBool(value)
+	case "created_at":
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		return value, nil
+	}
+}
+
+// MarkVerified records that the user verified their email in auth-service.
+func (r *userRepo) MarkVerified(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("is_verified", true).Error; err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
+
+// Delete removes the row for good so the email can be registered again.
+func (r *userRepo) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Unscoped().Delete(&model.User{}, id).Error; err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"packages/servicetoken"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+)
+
+type VenueClient interface {
+	// GetSpaces returns the spaces that still exist among ids, in no particular order.
+	GetSpaces(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error)
+}
+
+type venueClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewVenueClient calls venue-service, authenticated with a service token.
+func NewVenueClient(baseURL string, issuer *servicetoken.Issuer) VenueClient {
+	return &venueClient{
+		baseURL: baseURL,
+		http:    issuer.Client(servicetoken.VenueService, 5*time.Second),
+	}
+}
+
+// GetSpaces asks for constant.MaxSpaceBatch spaces at a time, the most venue-service takes.
+func (c *venueClient) GetSpaces(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error) {
+	var spaces []dto.SpaceSummary
+	for chunk := range slices.Chunk(ids, constant.MaxSpaceBatch) {
+		found, err := c.getSpaces(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		spaces = append(spaces, found...)
+	}
+	return spaces, nil
+}
+
+func (c *venueClient) getSpaces(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error) {
+	fullURL, err := url.JoinPath(c.baseURL, constant.GetSpacesURL)
+	if err != nil {
+		return nil, errors.New(constant.ErrCreateHTTPRequest)
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	fullURL += "?" + url.Values{"ids": {strings.Join(parts, ",")}}.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrCreateHTTPRequest)
+	}
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, errors.New(constant.ErrSendHTTPRequest)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	var body struct {
+		Data []dto.SpaceSummary `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.New(constant.ErrUnmarshalResponse)
+	}
+	return body.Data, nil
+}
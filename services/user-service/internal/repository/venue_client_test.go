@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"packages/servicetoken"
+	"strconv"
+	"strings"
+	"testing"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVenueClient_GetSpaces_SendsBatches(t *testing.T) {
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		batches = append(batches, len(ids))
+		var spaces []dto.SpaceSummary
+		for _, id := range ids {
+			n, _ := strconv.ParseUint(id, 10, 64)
+			spaces = append(spaces, dto.SpaceSummary{ID: uint(n)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": spaces})
+	}))
+	defer srv.Close()
+
+	ids := make([]uint, constant.MaxSpaceBatch*2+5)
+	for i := range ids {
+		ids[i] = uint(i + 1)
+	}
+	client := NewVenueClient(srv.URL, servicetoken.NewIssuer(servicetoken.UserService, []byte("secret")))
+
+	spaces, err := client.GetSpaces(context.Background(), ids)
+
+	require.NoError(t, err)
+	assert.Len(t, spaces, len(ids))
+	assert.Equal(t, []int{constant.MaxSpaceBatch, constant.MaxSpaceBatch, 5}, batches)
+}
+
+func TestVenueClient_GetSpaces_NoIDs(t *testing.T) {
+	client := NewVenueClient("http://unused", servicetoken.NewIssuer(servicetoken.UserService, []byte("secret")))
+
+	spaces, err := client.GetSpaces(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, spaces)
+}
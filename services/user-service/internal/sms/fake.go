@@ -0,0 +1,60 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ConsoleProvider prints messages to the log instead of sending them.
+type ConsoleProvider struct{}
+
+func NewConsoleProvider() *ConsoleProvider {
+	return &ConsoleProvider{}
+}
+
+func (p *ConsoleProvider) Send(_ context.Context, to, body string) error {
+	log.Printf("sms to %s: %s", to, body)
+	return nil
+}
+
+// FileProvider appends messages to a file, one JSON object per line, for tests and local runs
+// that need to read the codes back.
+type FileProvider struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// Message is a line written by FileProvider.
+type Message struct {
+	To     string    `json:"to"`
+	Body   string    `json:"body"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path, now: time.Now}
+}
+
+func (p *FileProvider) Send(_ context.Context, to, body string) error {
+	line, err := json.Marshal(Message{To: to, Body: body, SentAt: p.now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, err := os.OpenFile(p.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
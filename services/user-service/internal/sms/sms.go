@@ -0,0 +1,49 @@
+// Package sms sends text messages through a provider chosen at startup: Twilio, or for local
+// runs a fake that prints messages to the log or appends them to a file.
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Provider sends body to a phone number in E.164 format.
+type Provider interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+const (
+	EnvProvider = "SMS_PROVIDER" // "console" (default), "file" or "twilio"
+	EnvFilePath = "SMS_FILE_PATH"
+
+	EnvTwilioAccountSID = "TWILIO_ACCOUNT_SID"
+	EnvTwilioAuthToken  = "TWILIO_AUTH_TOKEN"
+	EnvTwilioFrom       = "TWILIO_FROM" // sender number or messaging service SID
+	EnvTwilioBaseURL    = "TWILIO_BASE_URL"
+
+	DefaultFilePath = "sms.log"
+)
+
+// NewFromEnv builds the provider configured by SMS_PROVIDER.
+func NewFromEnv() (Provider, error) {
+	switch provider := os.Getenv(EnvProvider); provider {
+	case "", "console":
+		return NewConsoleProvider(), nil
+	case "file":
+		path := os.Getenv(EnvFilePath)
+		if path == "" {
+			path = DefaultFilePath
+		}
+		return NewFileProvider(path), nil
+	case "twilio":
+		return NewTwilioProvider(TwilioConfig{
+			AccountSID: os.Getenv(EnvTwilioAccountSID),
+			AuthToken:  os.Getenv(EnvTwilioAuthToken),
+			From:       os.Getenv(EnvTwilioFrom),
+			BaseURL:    os.Getenv(EnvTwilioBaseURL),
+		})
+	default:
+		return nil, fmt.Errorf("unknown %s %q", EnvProvider, provider)
+	}
+}
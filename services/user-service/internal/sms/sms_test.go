@@ -0,0 +1,75 @@
+package sms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileProvider_AppendsMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.log")
+	p := NewFileProvider(path)
+
+	require.NoError(t, p.Send(context.Background(), "+84912345678", "first"))
+	require.NoError(t, p.Send(context.Background(), "+84912345678", "second"))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var got []Message
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m Message
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m))
+		got = append(got, m)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, "+84912345678", got[0].To)
+	assert.Equal(t, "second", got[1].Body)
+}
+
+func TestTwilioProvider_Send(t *testing.T) {
+	var form map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC1" || pass != "secret" || r.URL.Path != "/2010-04-01/Accounts/AC1/Messages.json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p, err := NewTwilioProvider(TwilioConfig{AccountSID: "AC1", AuthToken: "secret", From: "MG42", BaseURL: srv.URL})
+	require.NoError(t, err)
+
+	require.NoError(t, p.Send(context.Background(), "+84912345678", "code 123456"))
+	assert.Equal(t, []string{"+84912345678"}, form["To"])
+	assert.Equal(t, []string{"code 123456"}, form["Body"])
+	assert.Equal(t, []string{"MG42"}, form["MessagingServiceSid"])
+	assert.Empty(t, form["From"])
+}
+
+func TestTwilioProvider_ReportsRejection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21211,"message":"Invalid 'To' Phone Number"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewTwilioProvider(TwilioConfig{AccountSID: "AC1", AuthToken: "secret", From: "+15005550006", BaseURL: srv.URL})
+	require.NoError(t, err)
+
+	err = p.Send(context.Background(), "+84912345678", "hi")
+	assert.ErrorContains(t, err, "21211")
+}
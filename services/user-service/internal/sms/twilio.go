@@ -0,0 +1,68 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultTwilioBaseURL = "https://api.twilio.com"
+
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the sender number, or a messaging service SID starting with "MG"
+	From string
+	// BaseURL overrides the API endpoint, for tests
+	BaseURL string
+}
+
+// TwilioProvider sends messages with the Twilio Programmable Messaging REST API.
+type TwilioProvider struct {
+	cfg  TwilioConfig
+	http *http.Client
+}
+
+func NewTwilioProvider(cfg TwilioConfig) (*TwilioProvider, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" || cfg.From == "" {
+		return nil, errors.New("twilio sms needs an account SID, auth token and sender")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTwilioBaseURL
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &TwilioProvider{cfg: cfg, http: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (p *TwilioProvider) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "Body": {body}}
+	if strings.HasPrefix(p.cfg.From, "MG") {
+		form.Set("MessagingServiceSid", p.cfg.From)
+	} else {
+		form.Set("From", p.cfg.From)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.cfg.BaseURL, url.PathEscape(p.cfg.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(p.cfg.AccountSID, p.cfg.AuthToken)
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("twilio: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/utils"
+)
+
+// UploadAvatar replaces the avatar of the user. Every upload gets new keys, so cached copies of
+// the previous picture are never served for the new one; the old files are removed afterwards.
+func (u *userUsecase) UploadAvatar(ctx context.Context, email string, data []byte) (*dto.AvatarResponse, error) {
+	user, err := currentUser(ctx, u.repo, email)
+	if err != nil {
+		return nil, err
+	}
+
+	avatar, err := utils.ProcessAvatar(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrImageTooLarge):
+			return nil, errors.New(constant.ErrImageTooLarge)
+		case errors.Is(err, utils.ErrUnsupportedImage):
+			return nil, errors.New(constant.ErrUnsupportedImage)
+		case errors.Is(err, utils.ErrInvalidImage):
+			return nil, errors.New(constant.ErrInvalidImage)
+		}
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.New(constant.ErrInternalServer)
+	}
+	base := fmt.Sprintf("avatars/%d/%s", user.ID, hex.EncodeToString(random))
+	key, thumbKey := base+avatar.Ext, base+"_thumb"+avatar.Ext
+
+	if err := u.storage.Put(ctx, key, avatar.ContentType, avatar.Image); err != nil {
+		return nil, errors.New(constant.ErrStoreAvatar)
+	}
+	if err := u.storage.Put(ctx, thumbKey, avatar.ContentType, avatar.Thumb); err != nil {
+		u.deleteObjects(ctx, key)
+		return nil, errors.New(constant.ErrStoreAvatar)
+	}
+
+	oldKey, oldThumbKey := user.AvatarKey, user.AvatarThumbKey
+	user.AvatarKey, user.AvatarThumbKey = key, thumbKey
+	if err := u.repo.Update(ctx, user); err != nil {
+		u.deleteObjects(ctx, key, thumbKey)
+		return nil, errors.New(constant.ErrUpdateFailed)
+	}
+	u.deleteObjects(ctx, oldKey, oldThumbKey)
+
+	avatarURL, thumbURL, err := u.avatarURLs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AvatarResponse{AvatarURL: avatarURL, AvatarThumbURL: thumbURL}, nil
+}
+
+// avatarURLs returns empty URLs for users without an avatar.
+func (u *userUsecase) avatarURLs(ctx context.Context, user *model.User) (string, string, error) {
+	if user.AvatarKey == "" {
+		return "", "", nil
+	}
+	avatarURL, err := u.storage.URL(ctx, user.AvatarKey)
+	if err != nil {
+		return "", "", errors.New(constant.ErrInternalServer)
+	}
+	thumbURL, err := u.storage.URL(ctx, user.AvatarThumbKey)
+	if err != nil {
+		return "", "", errors.New(constant.ErrInternalServer)
+	}
+	return avatarURL, thumbURL, nil
+}
+
+// deleteObjects removes files that are no longer referenced. Failures only leave garbage behind.
+func (u *userUsecase) deleteObjects(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := u.storage.Delete(ctx, key); err != nil {
+			log.Printf("deleting stored object %s failed: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+)
+
+type FavoriteUsecase interface {
+	AddSpace(ctx context.Context, email string, spaceID uint) (*dto.FavoriteSpaceResponse, error)
+	RemoveSpace(ctx context.Context, email string, spaceID uint) error
+	ListSpaces(ctx context.Context, email string) ([]dto.FavoriteSpaceResponse, error)
+	ExportFavorites(ctx context.Context, userID uint) ([]dto.FavoriteSpaceExport, error)
+	EraseFavorites(ctx context.Context, userID uint) error
+}
+
+type favoriteUsecase struct {
+	userRepo    repository.UserRepository
+	favRepo     repository.FavoriteRepository
+	venueClient repository.VenueClient
+}
+
+func NewFavoriteUsecase(userRepo repository.UserRepository, favRepo repository.FavoriteRepository, venueClient repository.VenueClient) FavoriteUsecase {
+	return &favoriteUsecase{userRepo: userRepo, favRepo: favRepo, venueClient: venueClient}
+}
+
+// AddSpace saves the space as a favourite of the user. Saving it again changes nothing.
+func (u *favoriteUsecase) AddSpace(ctx context.Context, email string, spaceID uint) (*dto.FavoriteSpaceResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	spaces, err := u.venueClient.GetSpaces(ctx, []uint{spaceID})
+	if err != nil {
+		log.Printf("look up space %d failed: %v", spaceID, err)
+		return nil, errors.New(constant.ErrVenueService)
+	}
+	if len(spaces) == 0 || !listable(spaces[0]) {
+		return nil, errors.New(constant.ErrSpaceNotFound)
+	}
+
+	fav := &model.FavoriteSpace{UserID: user.ID, SpaceID: spaceID}
+	if err := u.favRepo.AddSpace(ctx, fav, constant.MaxFavoriteSpaces); err != nil {
+		return nil, err
+	}
+	return &dto.FavoriteSpaceResponse{SpaceSummary: spaces[0], FavoritedAt: fav.CreatedAt}, nil
+}
+
+// RemoveSpace is a no-op when the space is not a favourite.
+func (u *favoriteUsecase) RemoveSpace(ctx context.Context, email string, spaceID uint) error {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return err
+	}
+	return u.favRepo.RemoveSpaces(ctx, user.ID, spaceID)
+}
+
+// ListSpaces returns the favourites of the user with the current data of their space, latest
+// first. Favourites whose space is gone or whose venue was blocked are left out and removed.
+func (u *favoriteUsecase) ListSpaces(ctx context.Context, email string) ([]dto.FavoriteSpaceResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	favs, err := u.favRepo.ListSpaces(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := []dto.FavoriteSpaceResponse{}
+	if len(favs) == 0 {
+		return res, nil
+	}
+
+	ids := make([]uint, len(favs))
+	for i, fav := range favs {
+		ids[i] = fav.SpaceID
+	}
+	spaces, err := u.venueClient.GetSpaces(ctx, ids)
+	if err != nil {
+		log.Printf("look up favourite spaces of user %d failed: %v", user.ID, err)
+		return nil, errors.New(constant.ErrVenueService)
+	}
+	byID := make(map[uint]dto.SpaceSummary, len(spaces))
+	for _, s := range spaces {
+		byID[s.ID] = s
+	}
+
+	var stale []uint
+	for _, fav := range favs {
+		space, ok := byID[fav.SpaceID]
+		if !ok || !listable(space) {
+			stale = append(stale, fav.SpaceID)
+			continue
+		}
+		res = append(res, dto.FavoriteSpaceResponse{SpaceSummary: space, FavoritedAt: fav.CreatedAt})
+	}
+	// the list is right either way; a failed clean-up is done again next time
+	if err := u.favRepo.RemoveSpaces(ctx, user.ID, stale...); err != nil {
+		log.Printf("remove stale favourites of user %d failed: %v", user.ID, err)
+	}
+	return res, nil
+}
+
+func (u *favoriteUsecase) ExportFavorites(ctx context.Context, userID uint) ([]dto.FavoriteSpaceExport, error) {
+	favs, err := u.favRepo.ListSpaces(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.FavoriteSpaceExport, 0, len(favs))
+	for _, fav := range favs {
+		res = append(res, dto.FavoriteSpaceExport{SpaceID: fav.SpaceID, FavoritedAt: fav.CreatedAt})
+	}
+	return res, nil
+}
+
+func (u *favoriteUsecase) EraseFavorites(ctx context.Context, userID uint) error {
+	return u.favRepo.DeleteByUser(ctx, userID)
+}
+
+// listable reports whether a space can be kept as a favourite. A venue waiting for approval
+// keeps its spaces, shown as not bookable; a blocked one does not.
+func listable(space dto.SpaceSummary) bool {
+	return space.VenueStatus != constant.VenueStatusBlocked
+}
@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock FavoriteRepo =====
+type mockFavoriteRepo struct{ mock.Mock }
+
+func (m *mockFavoriteRepo) AddSpace(ctx context.Context, fav *model.FavoriteSpace, max int) error {
+	return m.Called(ctx, fav, max).Error(0)
+}
+func (m *mockFavoriteRepo) RemoveSpaces(ctx context.Context, userID uint, spaceIDs ...uint) error {
+	return m.Called(ctx, userID, spaceIDs).Error(0)
+}
+func (m *mockFavoriteRepo) ListSpaces(ctx context.Context, userID uint) ([]model.FavoriteSpace, error) {
+	args := m.Called(ctx, userID)
+	if f, ok := args.Get(0).([]model.FavoriteSpace); ok {
+		return f, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockFavoriteRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+// ===== Mock VenueClient =====
+type mockVenueClient struct{ mock.Mock }
+
+func (m *mockVenueClient) GetSpaces(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error) {
+	args := m.Called(ctx, ids)
+	if s, ok := args.Get(0).([]dto.SpaceSummary); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupFavoriteUsecase() (*mockFavoriteRepo, *mockVenueClient, usecase.FavoriteUsecase) {
+	repo, favRepo, venues := new(mockUserRepo), new(mockFavoriteRepo), new(mockVenueClient)
+	repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}}, nil)
+	return favRepo, venues, usecase.NewFavoriteUsecase(repo, favRepo, venues)
+}
+
+func TestAddFavoriteSpace(t *testing.T) {
+	favRepo, venues, uc := setupFavoriteUsecase()
+	venues.On("GetSpaces", mock.Anything, []uint{12}).Return([]dto.SpaceSummary{
+		{ID: 12, Name: "Room A", VenueStatus: "approved", Bookable: true},
+	}, nil)
+	favRepo.On("AddSpace", mock.Anything, &model.FavoriteSpace{UserID: 7, SpaceID: 12}, constant.MaxFavoriteSpaces).Return(nil)
+
+	res, err := uc.AddSpace(context.Background(), "a@b.com", 12)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Room A", res.Name)
+	assert.True(t, res.Bookable)
+}
+
+func TestAddFavoriteSpace_Rejects(t *testing.T) {
+	cases := map[string]struct {
+		spaces []dto.SpaceSummary
+		want   string
+	}{
+		"unknown space": {spaces: []dto.SpaceSummary{}, want: constant.ErrSpaceNotFound},
+		"blocked venue": {spaces: []dto.SpaceSummary{{ID: 12, VenueStatus: constant.VenueStatusBlocked}}, want: constant.ErrSpaceNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			favRepo, venues, uc := setupFavoriteUsecase()
+			venues.On("GetSpaces", mock.Anything, []uint{12}).Return(tc.spaces, nil)
+
+			_, err := uc.AddSpace(context.Background(), "a@b.com", 12)
+
+			assert.EqualError(t, err, tc.want)
+			favRepo.AssertNotCalled(t, "AddSpace", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddFavoriteSpace_TooManySaved(t *testing.T) {
+	favRepo, venues, uc := setupFavoriteUsecase()
+	venues.On("GetSpaces", mock.Anything, []uint{12}).Return([]dto.SpaceSummary{{ID: 12, VenueStatus: "approved"}}, nil)
+	favRepo.On("AddSpace", mock.Anything, mock.Anything, constant.MaxFavoriteSpaces).Return(errors.New(constant.ErrTooManyFavorites))
+
+	_, err := uc.AddSpace(context.Background(), "a@b.com", 12)
+
+	assert.EqualError(t, err, constant.ErrTooManyFavorites)
+}
+
+func TestListFavoriteSpaces_HidesAndRemovesStale(t *testing.T) {
+	favRepo, venues, uc := setupFavoriteUsecase()
+	now := time.Now()
+	favRepo.On("ListSpaces", mock.Anything, uint(7)).Return([]model.FavoriteSpace{
+		{UserID: 7, SpaceID: 3, CreatedAt: now},
+		{UserID: 7, SpaceID: 2, CreatedAt: now.Add(-time.Hour)},
+		{UserID: 7, SpaceID: 1, CreatedAt: now.Add(-2 * time.Hour)},
+		{UserID: 7, SpaceID: 4, CreatedAt: now.Add(-3 * time.Hour)},
+	}, nil)
+	venues.On("GetSpaces", mock.Anything, []uint{3, 2, 1, 4}).Return([]dto.SpaceSummary{
+		{ID: 1, Name: "Desk", VenueStatus: "approved", Bookable: true},
+		{ID: 2, Name: "Loft", VenueStatus: constant.VenueStatusBlocked},
+		{ID: 4, Name: "New", VenueStatus: "pending"},
+	}, nil)
+	favRepo.On("RemoveSpaces", mock.Anything, uint(7), []uint{3, 2}).Return(nil)
+
+	res, err := uc.ListSpaces(context.Background(), "a@b.com")
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, uint(1), res[0].ID)
+	assert.True(t, res[0].Bookable)
+	assert.Equal(t, uint(4), res[1].ID)
+	assert.False(t, res[1].Bookable)
+	favRepo.AssertExpectations(t)
+}
+
+func TestListFavoriteSpaces_VenueServiceDown(t *testing.T) {
+	favRepo, venues, uc := setupFavoriteUsecase()
+	favRepo.On("ListSpaces", mock.Anything, uint(7)).Return([]model.FavoriteSpace{{UserID: 7, SpaceID: 1}}, nil)
+	venues.On("GetSpaces", mock.Anything, []uint{1}).Return(nil, errors.New("timeout"))
+
+	_, err := uc.ListSpaces(context.Background(), "a@b.com")
+
+	assert.EqualError(t, err, constant.ErrVenueService)
+	favRepo.AssertNotCalled(t, "RemoveSpaces", mock.Anything, mock.Anything, mock.Anything)
+}
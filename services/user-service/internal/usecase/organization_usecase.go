@@ -0,0 +1,530 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/mail"
+	"strings"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/kafka"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+)
+
+type OrganizationUsecase interface {
+	CreateOrganization(ctx context.Context, email string, req dto.CreateOrganizationRequest) (*dto.OrganizationResponse, error)
+	ListOrganizations(ctx context.Context, email string) ([]dto.OrganizationResponse, error)
+	GetOrganization(ctx context.Context, email string, orgID uint) (*dto.OrganizationResponse, error)
+	UpdateOrganization(ctx context.Context, email string, orgID uint, req dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error)
+	ListMembers(ctx context.Context, email string, orgID uint) ([]dto.MemberResponse, error)
+	InviteMember(ctx context.Context, email string, orgID uint, req dto.InviteMemberRequest) (*dto.MemberResponse, error)
+	UpdateMemberRole(ctx context.Context, email string, orgID, memberID uint, role string) (*dto.MemberResponse, error)
+	UpdateMemberQuota(ctx context.Context, email string, orgID, memberID uint, req dto.MemberQuotaRequest) (*dto.MemberResponse, error)
+	RemoveMember(ctx context.Context, email string, orgID, memberID uint) error
+	ListInvitations(ctx context.Context, email string) ([]dto.InvitationResponse, error)
+	AcceptInvitation(ctx context.Context, email string, invitationID uint) (*dto.MemberResponse, error)
+	DeclineInvitation(ctx context.Context, email string, invitationID uint) error
+	GetMembership(ctx context.Context, orgID, userID uint) (*dto.MemberResponse, error)
+	ExportMemberships(ctx context.Context, userID uint) ([]dto.MemberResponse, error)
+	EraseMemberships(ctx context.Context, userID uint) error
+}
+
+type organizationUsecase struct {
+	userRepo repository.UserRepository
+	orgRepo  repository.OrganizationRepository
+	mail     kafka.Producer
+}
+
+// NewOrganizationUsecase mails invitations through mailProducer, which writes to the mail topic.
+func NewOrganizationUsecase(userRepo repository.UserRepository, orgRepo repository.OrganizationRepository, mailProducer kafka.Producer) OrganizationUsecase {
+	return &organizationUsecase{userRepo: userRepo, orgRepo: orgRepo, mail: mailProducer}
+}
+
+// CreateOrganization makes the user the owner of a new organization.
+func (u *organizationUsecase) CreateOrganization(ctx context.Context, email string, req dto.CreateOrganizationRequest) (*dto.OrganizationResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New(constant.ErrNameRequired)
+	}
+	billingEmail, err := normalizeEmail(req.BillingEmail, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &model.Organization{Name: name, BillingEmail: billingEmail, CreatedBy: user.ID}
+	owner := &model.OrganizationMember{
+		Email:  user.Email,
+		UserID: user.ID,
+		Role:   constant.OrgRoleOwner,
+		Status: constant.MemberStatusActive,
+	}
+	if err := u.orgRepo.Create(ctx, org, owner); err != nil {
+		return nil, err
+	}
+	return organizationResponse(org, owner.Role), nil
+}
+
+func (u *organizationUsecase) ListOrganizations(ctx context.Context, email string) ([]dto.OrganizationResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	members, err := u.orgRepo.ListMemberships(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.OrganizationResponse, 0, len(members))
+	for _, m := range members {
+		if m.Organization != nil {
+			res = append(res, *organizationResponse(m.Organization, m.Role))
+		}
+	}
+	return res, nil
+}
+
+func (u *organizationUsecase) GetOrganization(ctx context.Context, email string, orgID uint) (*dto.OrganizationResponse, error) {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return nil, err
+	}
+	org, err := u.organization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return organizationResponse(org, actor.Role), nil
+}
+
+// UpdateOrganization changes the name and billing address; only owners may.
+func (u *organizationUsecase) UpdateOrganization(ctx context.Context, email string, orgID uint, req dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error) {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != constant.OrgRoleOwner {
+		return nil, errors.New(constant.ErrOrganizationForbidden)
+	}
+	org, err := u.organization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New(constant.ErrNameRequired)
+		}
+		org.Name = name
+	}
+	if req.BillingEmail != nil {
+		billingEmail, err := normalizeEmail(*req.BillingEmail, actor.Email)
+		if err != nil {
+			return nil, err
+		}
+		org.BillingEmail = billingEmail
+	}
+	if err := u.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return organizationResponse(org, actor.Role), nil
+}
+
+// ListMembers returns the members and open invitations; any member may see them.
+func (u *organizationUsecase) ListMembers(ctx context.Context, email string, orgID uint) ([]dto.MemberResponse, error) {
+	if _, err := u.actor(ctx, email, orgID); err != nil {
+		return nil, err
+	}
+	members, err := u.orgRepo.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.MemberResponse, 0, len(members))
+	for i := range members {
+		res = append(res, *memberResponse(&members[i]))
+	}
+	return res, nil
+}
+
+// InviteMember invites an address, which need not have an account yet: the invitation waits
+// for the user who signs up with it. Admins may only invite members.
+func (u *organizationUsecase) InviteMember(ctx context.Context, email string, orgID uint, req dto.InviteMemberRequest) (*dto.MemberResponse, error) {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return nil, err
+	}
+	role := req.Role
+	if role == "" {
+		role = constant.OrgRoleMember
+	}
+	if !validOrgRole(role) {
+		return nil, errors.New(constant.ErrInvalidOrgRole)
+	}
+	if !canManage(actor, role) {
+		return nil, errors.New(constant.ErrOrganizationForbidden)
+	}
+	invitee, err := normalizeEmail(req.Email, "")
+	if err != nil || invitee == "" {
+		return nil, errors.New(constant.ErrInvalidInput)
+	}
+
+	existing, err := u.orgRepo.GetMemberByEmail(ctx, orgID, invitee)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New(constant.ErrAlreadyMember)
+	}
+	// members are kept under the address they joined with, which they may have changed since
+	if user, err := u.userRepo.GetByEmail(ctx, invitee); err != nil {
+		return nil, err
+	} else if user != nil {
+		existing, err := u.orgRepo.GetMemberByUser(ctx, orgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New(constant.ErrAlreadyMember)
+		}
+	}
+	org, err := u.organization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &model.OrganizationMember{
+		OrganizationID: orgID,
+		Email:          invitee,
+		Role:           role,
+		Status:         constant.MemberStatusInvited,
+		InvitedBy:      actor.UserID,
+	}
+	if err := u.orgRepo.CreateMember(ctx, member); err != nil {
+		return nil, err
+	}
+	u.sendInvitation(ctx, org, member, actor.Email)
+	return memberResponse(member), nil
+}
+
+// sendInvitation only logs a failure: the invitation is listed in the app either way.
+func (u *organizationUsecase) sendInvitation(ctx context.Context, org *model.Organization, member *model.OrganizationMember, inviter string) {
+	payload, err := json.Marshal(dto.MailEvent{
+		Email: member.Email,
+		Type:  constant.EventTypeOrgInvitation,
+		Data: map[string]string{
+			"organization": org.Name,
+			"inviter":      inviter,
+			"role":         member.Role,
+		},
+	})
+	if err != nil {
+		log.Println("marshal organization invitation failed:", err)
+		return
+	}
+	if err := u.mail.Publish(ctx, []byte(member.Email), payload); err != nil {
+		log.Printf("publish invitation to organization %d failed: %v", org.ID, err)
+	}
+}
+
+// UpdateMemberRole is for owners only. The last owner cannot step down.
+func (u *organizationUsecase) UpdateMemberRole(ctx context.Context, email string, orgID, memberID uint, role string) (*dto.MemberResponse, error) {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != constant.OrgRoleOwner {
+		return nil, errors.New(constant.ErrOrganizationForbidden)
+	}
+	if !validOrgRole(role) {
+		return nil, errors.New(constant.ErrInvalidOrgRole)
+	}
+	member, err := u.member(ctx, orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != constant.OrgRoleOwner {
+		if err := u.keepAnOwner(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+	member.Role = role
+	if err := u.orgRepo.UpdateMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return memberResponse(member), nil
+}
+
+// UpdateMemberQuota replaces the monthly booking quotas of a member, a nil quota lifting the
+// limit. booking-service enforces them on the bookings billed to the organization.
+func (u *organizationUsecase) UpdateMemberQuota(ctx context.Context, email string, orgID, memberID uint, req dto.MemberQuotaRequest) (*dto.MemberResponse, error) {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := u.member(ctx, orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(actor, member.Role) {
+		return nil, errors.New(constant.ErrOrganizationForbidden)
+	}
+	if (req.MonthlyHours != nil && *req.MonthlyHours < 0) || (req.MonthlySpend != nil && *req.MonthlySpend < 0) {
+		return nil, errors.New(constant.ErrInvalidQuota)
+	}
+
+	member.MonthlyHourQuota = req.MonthlyHours
+	member.MonthlySpendQuota = req.MonthlySpend
+	if err := u.orgRepo.UpdateMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return memberResponse(member), nil
+}
+
+// RemoveMember removes a member or withdraws an invitation. Members may always remove
+// themselves, that is leave, unless they are the last owner.
+func (u *organizationUsecase) RemoveMember(ctx context.Context, email string, orgID, memberID uint) error {
+	actor, err := u.actor(ctx, email, orgID)
+	if err != nil {
+		return err
+	}
+	member, err := u.member(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.ID != actor.ID && !canManage(actor, member.Role) {
+		return errors.New(constant.ErrOrganizationForbidden)
+	}
+	if err := u.keepAnOwner(ctx, member); err != nil {
+		return err
+	}
+	return u.orgRepo.DeleteMember(ctx, member.ID)
+}
+
+func (u *organizationUsecase) ListInvitations(ctx context.Context, email string) ([]dto.InvitationResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	invitations, err := u.orgRepo.ListInvitations(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.InvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		if inv.Organization == nil {
+			continue
+		}
+		res = append(res, dto.InvitationResponse{
+			ID:               inv.ID,
+			OrganizationID:   inv.OrganizationID,
+			OrganizationName: inv.Organization.Name,
+			Role:             inv.Role,
+			CreatedAt:        inv.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// AcceptInvitation makes the user a member. Only the user signed in with the invited address
+// may accept, which proves they own it.
+func (u *organizationUsecase) AcceptInvitation(ctx context.Context, email string, invitationID uint) (*dto.MemberResponse, error) {
+	user, inv, err := u.invitation(ctx, email, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := u.orgRepo.GetMemberByUser(ctx, inv.OrganizationID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New(constant.ErrAlreadyMember)
+	}
+
+	inv.UserID = user.ID
+	inv.Status = constant.MemberStatusActive
+	if err := u.orgRepo.UpdateMember(ctx, inv); err != nil {
+		return nil, err
+	}
+	return memberResponse(inv), nil
+}
+
+func (u *organizationUsecase) DeclineInvitation(ctx context.Context, email string, invitationID uint) error {
+	_, inv, err := u.invitation(ctx, email, invitationID)
+	if err != nil {
+		return err
+	}
+	return u.orgRepo.DeleteMember(ctx, inv.ID)
+}
+
+// GetMembership returns the active membership of the user, for booking-service.
+func (u *organizationUsecase) GetMembership(ctx context.Context, orgID, userID uint) (*dto.MemberResponse, error) {
+	member, err := u.orgRepo.GetMemberByUser(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.Status != constant.MemberStatusActive {
+		return nil, errors.New(constant.ErrMemberNotFound)
+	}
+	return memberResponse(member), nil
+}
+
+// ExportMemberships returns the organizations of the user for their personal data export.
+func (u *organizationUsecase) ExportMemberships(ctx context.Context, userID uint) ([]dto.MemberResponse, error) {
+	members, err := u.orgRepo.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.MemberResponse, 0, len(members))
+	for i := range members {
+		res = append(res, *memberResponse(&members[i]))
+	}
+	return res, nil
+}
+
+// EraseMemberships takes a deleted account out of its organizations and drops the invitations
+// to its address. It runs before the profile is anonymised, while the address is still known.
+// An organization losing its only owner this way keeps its other members and bookings.
+func (u *organizationUsecase) EraseMemberships(ctx context.Context, userID uint) error {
+	email := ""
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil && err.Error() != constant.ErrUserNotFound {
+		return err
+	}
+	if user != nil {
+		email = user.Email
+	}
+	return u.orgRepo.DeleteByUser(ctx, userID, email)
+}
+
+// actor returns the membership of the user in the organization. Organizations the user is not
+// an active member of are reported as not found.
+func (u *organizationUsecase) actor(ctx context.Context, email string, orgID uint) (*model.OrganizationMember, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	member, err := u.orgRepo.GetMemberByUser(ctx, orgID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.Status != constant.MemberStatusActive {
+		return nil, errors.New(constant.ErrOrganizationNotFound)
+	}
+	return member, nil
+}
+
+func (u *organizationUsecase) organization(ctx context.Context, orgID uint) (*model.Organization, error) {
+	org, err := u.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, errors.New(constant.ErrOrganizationNotFound)
+	}
+	return org, nil
+}
+
+func (u *organizationUsecase) member(ctx context.Context, orgID, memberID uint) (*model.OrganizationMember, error) {
+	member, err := u.orgRepo.GetMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.OrganizationID != orgID {
+		return nil, errors.New(constant.ErrMemberNotFound)
+	}
+	return member, nil
+}
+
+// invitation returns the open invitation sent to the address of the user.
+func (u *organizationUsecase) invitation(ctx context.Context, email string, invitationID uint) (*model.User, *model.OrganizationMember, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	inv, err := u.orgRepo.GetMember(ctx, invitationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inv == nil || inv.Status != constant.MemberStatusInvited || inv.Email != user.Email {
+		return nil, nil, errors.New(constant.ErrInvitationNotFound)
+	}
+	return user, inv, nil
+}
+
+// keepAnOwner refuses to demote or remove member when it is the last active owner.
+func (u *organizationUsecase) keepAnOwner(ctx context.Context, member *model.OrganizationMember) error {
+	if member.Role != constant.OrgRoleOwner || member.Status != constant.MemberStatusActive {
+		return nil
+	}
+	owners, err := u.orgRepo.CountOwners(ctx, member.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return errors.New(constant.ErrLastOwner)
+	}
+	return nil
+}
+
+func validOrgRole(role string) bool {
+	return role == constant.OrgRoleOwner || role == constant.OrgRoleAdmin || role == constant.OrgRoleMember
+}
+
+// canManage tells whether actor may invite, change or remove a member with role: owners manage
+// everyone, admins only members.
+func canManage(actor *model.OrganizationMember, role string) bool {
+	switch actor.Role {
+	case constant.OrgRoleOwner:
+		return true
+	case constant.OrgRoleAdmin:
+		return role == constant.OrgRoleMember
+	default:
+		return false
+	}
+}
+
+// normalizeEmail lowercases an address and checks it, falling back to def when it is empty.
+func normalizeEmail(email, def string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return def, nil
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", errors.New(constant.ErrInvalidInput)
+	}
+	return email, nil
+}
+
+func organizationResponse(org *model.Organization, role string) *dto.OrganizationResponse {
+	return &dto.OrganizationResponse{
+		ID:           org.ID,
+		Name:         org.Name,
+		BillingEmail: org.BillingEmail,
+		Role:         role,
+		CreatedAt:    org.CreatedAt,
+	}
+}
+
+func memberResponse(m *model.OrganizationMember) *dto.MemberResponse {
+	return &dto.MemberResponse{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		UserID:         m.UserID,
+		Email:          m.Email,
+		Role:           m.Role,
+		Status:         m.Status,
+		MonthlyHours:   m.MonthlyHourQuota,
+		MonthlySpend:   m.MonthlySpendQuota,
+		InvitedBy:      m.InvitedBy,
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -0,0 +1,323 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock OrganizationRepo =====
+type mockOrgRepo struct{ mock.Mock }
+
+func (m *mockOrgRepo) Create(ctx context.Context, org *model.Organization, owner *model.OrganizationMember) error {
+	return m.Called(ctx, org, owner).Error(0)
+}
+func (m *mockOrgRepo) GetByID(ctx context.Context, id uint) (*model.Organization, error) {
+	args := m.Called(ctx, id)
+	if o, ok := args.Get(0).(*model.Organization); ok {
+		return o, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockOrgRepo) Update(ctx context.Context, org *model.Organization) error {
+	return m.Called(ctx, org).Error(0)
+}
+func (m *mockOrgRepo) ListMemberships(ctx context.Context, userID uint) ([]model.OrganizationMember, error) {
+	args := m.Called(ctx, userID)
+	if ms, ok := args.Get(0).([]model.OrganizationMember); ok {
+		return ms, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockOrgRepo) ListInvitations(ctx context.Context, email string) ([]model.OrganizationMember, error) {
+	args := m.Called(ctx, email)
+	if ms, ok := args.Get(0).([]model.OrganizationMember); ok {
+		return ms, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockOrgRepo) GetMember(ctx context.Context, id uint) (*model.OrganizationMember, error) {
+	return memberResult(m.Called(ctx, id))
+}
+func (m *mockOrgRepo) GetMemberByUser(ctx context.Context, orgID, userID uint) (*model.OrganizationMember, error) {
+	return memberResult(m.Called(ctx, orgID, userID))
+}
+func (m *mockOrgRepo) GetMemberByEmail(ctx context.Context, orgID uint, email string) (*model.OrganizationMember, error) {
+	return memberResult(m.Called(ctx, orgID, email))
+}
+func (m *mockOrgRepo) ListMembers(ctx context.Context, orgID uint) ([]model.OrganizationMember, error) {
+	args := m.Called(ctx, orgID)
+	if ms, ok := args.Get(0).([]model.OrganizationMember); ok {
+		return ms, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockOrgRepo) CountOwners(ctx context.Context, orgID uint) (int64, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *mockOrgRepo) CreateMember(ctx context.Context, member *model.OrganizationMember) error {
+	return m.Called(ctx, member).Error(0)
+}
+func (m *mockOrgRepo) UpdateMember(ctx context.Context, member *model.OrganizationMember) error {
+	return m.Called(ctx, member).Error(0)
+}
+func (m *mockOrgRepo) DeleteMember(ctx context.Context, id uint) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockOrgRepo) DeleteByUser(ctx context.Context, userID uint, email string) error {
+	return m.Called(ctx, userID, email).Error(0)
+}
+
+func memberResult(args mock.Arguments) (*model.OrganizationMember, error) {
+	if mb, ok := args.Get(0).(*model.OrganizationMember); ok {
+		return mb, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func setupOrgUsecase() (*mockUserRepo, *mockOrgRepo, *mockProducer, usecase.OrganizationUsecase) {
+	repo, orgRepo, mail := new(mockUserRepo), new(mockOrgRepo), new(mockProducer)
+	return repo, orgRepo, mail, usecase.NewOrganizationUsecase(repo, orgRepo, mail)
+}
+
+// signedIn makes email the current user, with the given role in organization 1; an empty role
+// means no membership.
+func signedIn(repo *mockUserRepo, orgRepo *mockOrgRepo, userID uint, email, role string) *model.OrganizationMember {
+	repo.On("GetByEmail", mock.Anything, email).Return(&model.User{Model: gorm.Model{ID: userID}, Email: email}, nil)
+	if role == "" {
+		orgRepo.On("GetMemberByUser", mock.Anything, uint(1), userID).Return(nil, nil)
+		return nil
+	}
+	member := &model.OrganizationMember{
+		Model: gorm.Model{ID: userID * 10}, OrganizationID: 1, UserID: userID, Email: email,
+		Role: role, Status: constant.MemberStatusActive,
+	}
+	orgRepo.On("GetMemberByUser", mock.Anything, uint(1), userID).Return(member, nil)
+	return member
+}
+
+func TestCreateOrganization_OwnerIsCreator(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}, nil)
+	orgRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		org := args.Get(1).(*model.Organization)
+		owner := args.Get(2).(*model.OrganizationMember)
+		assert.Equal(t, "Acme", org.Name)
+		assert.Equal(t, "a@b.com", org.BillingEmail)
+		assert.Equal(t, uint(7), owner.UserID)
+		assert.Equal(t, constant.OrgRoleOwner, owner.Role)
+		assert.Equal(t, constant.MemberStatusActive, owner.Status)
+	})
+
+	res, err := uc.CreateOrganization(context.Background(), "a@b.com", dto.CreateOrganizationRequest{Name: " Acme "})
+
+	require.NoError(t, err)
+	assert.Equal(t, constant.OrgRoleOwner, res.Role)
+}
+
+func TestCreateOrganization_InvalidBillingEmail(t *testing.T) {
+	repo, _, _, uc := setupOrgUsecase()
+	repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}, nil)
+
+	_, err := uc.CreateOrganization(context.Background(), "a@b.com", dto.CreateOrganizationRequest{Name: "Acme", BillingEmail: "billing"})
+
+	assert.EqualError(t, err, constant.ErrInvalidInput)
+}
+
+func TestGetOrganization_NonMemberNotFound(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", "")
+
+	_, err := uc.GetOrganization(context.Background(), "a@b.com", 1)
+
+	assert.EqualError(t, err, constant.ErrOrganizationNotFound)
+}
+
+func TestInviteMember_CreatesInvitationAndMails(t *testing.T) {
+	repo, orgRepo, mail, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "owner@b.com", constant.OrgRoleAdmin)
+	orgRepo.On("GetMemberByEmail", mock.Anything, uint(1), "new@b.com").Return(nil, nil)
+	repo.On("GetByEmail", mock.Anything, "new@b.com").Return(nil, nil)
+	orgRepo.On("GetByID", mock.Anything, uint(1)).Return(&model.Organization{Model: gorm.Model{ID: 1}, Name: "Acme"}, nil)
+	orgRepo.On("CreateMember", mock.Anything, mock.AnythingOfType("*model.OrganizationMember")).Return(nil)
+	mail.On("Publish", mock.Anything, "new@b.com", mock.Anything).Return(nil)
+
+	res, err := uc.InviteMember(context.Background(), "owner@b.com", 1, dto.InviteMemberRequest{Email: " New@B.com "})
+
+	require.NoError(t, err)
+	assert.Equal(t, "new@b.com", res.Email)
+	assert.Equal(t, constant.OrgRoleMember, res.Role)
+	assert.Equal(t, constant.MemberStatusInvited, res.Status)
+	assert.Equal(t, uint(7), res.InvitedBy)
+
+	var event dto.MailEvent
+	require.NoError(t, json.Unmarshal(mail.Calls[0].Arguments.Get(2).([]byte), &event))
+	assert.Equal(t, constant.EventTypeOrgInvitation, event.Type)
+	assert.Equal(t, "Acme", event.Data["organization"])
+}
+
+func TestInviteMember_MailFailureStillInvites(t *testing.T) {
+	repo, orgRepo, mail, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "owner@b.com", constant.OrgRoleOwner)
+	orgRepo.On("GetMemberByEmail", mock.Anything, uint(1), "new@b.com").Return(nil, nil)
+	repo.On("GetByEmail", mock.Anything, "new@b.com").Return(nil, nil)
+	orgRepo.On("GetByID", mock.Anything, uint(1)).Return(&model.Organization{Model: gorm.Model{ID: 1}, Name: "Acme"}, nil)
+	orgRepo.On("CreateMember", mock.Anything, mock.Anything).Return(nil)
+	mail.On("Publish", mock.Anything, "new@b.com", mock.Anything).Return(errors.New("kafka down"))
+
+	_, err := uc.InviteMember(context.Background(), "owner@b.com", 1, dto.InviteMemberRequest{Email: "new@b.com", Role: constant.OrgRoleAdmin})
+
+	assert.NoError(t, err)
+}
+
+func TestInviteMember_Rejected(t *testing.T) {
+	cases := map[string]struct {
+		actorRole, role, want string
+		existing              *model.OrganizationMember
+	}{
+		"plain member":            {constant.OrgRoleMember, "", constant.ErrOrganizationForbidden, nil},
+		"admin inviting an admin": {constant.OrgRoleAdmin, constant.OrgRoleAdmin, constant.ErrOrganizationForbidden, nil},
+		"unknown role":            {constant.OrgRoleOwner, "billing", constant.ErrInvalidOrgRole, nil},
+		"already invited":         {constant.OrgRoleOwner, "", constant.ErrAlreadyMember, &model.OrganizationMember{}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo, orgRepo, mail, uc := setupOrgUsecase()
+			signedIn(repo, orgRepo, 7, "a@b.com", tc.actorRole)
+			orgRepo.On("GetMemberByEmail", mock.Anything, uint(1), "new@b.com").Return(tc.existing, nil)
+
+			_, err := uc.InviteMember(context.Background(), "a@b.com", 1, dto.InviteMemberRequest{Email: "new@b.com", Role: tc.role})
+
+			assert.EqualError(t, err, tc.want)
+			orgRepo.AssertNotCalled(t, "CreateMember", mock.Anything, mock.Anything)
+			mail.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateMemberRole_LastOwnerCannotStepDown(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	owner := signedIn(repo, orgRepo, 7, "a@b.com", constant.OrgRoleOwner)
+	orgRepo.On("GetMember", mock.Anything, owner.ID).Return(owner, nil)
+	orgRepo.On("CountOwners", mock.Anything, uint(1)).Return(int64(1), nil)
+
+	_, err := uc.UpdateMemberRole(context.Background(), "a@b.com", 1, owner.ID, constant.OrgRoleMember)
+
+	assert.EqualError(t, err, constant.ErrLastOwner)
+	orgRepo.AssertNotCalled(t, "UpdateMember", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMemberQuota(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", constant.OrgRoleAdmin)
+	orgRepo.On("GetMember", mock.Anything, uint(30)).Return(&model.OrganizationMember{
+		Model: gorm.Model{ID: 30}, OrganizationID: 1, UserID: 3, Role: constant.OrgRoleMember,
+		Status: constant.MemberStatusActive, MonthlySpendQuota: floatPtr(500),
+	}, nil)
+	orgRepo.On("UpdateMember", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := uc.UpdateMemberQuota(context.Background(), "a@b.com", 1, 30, dto.MemberQuotaRequest{MonthlyHours: floatPtr(40)})
+
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, *res.MonthlyHours)
+	assert.Nil(t, res.MonthlySpend, "a quota left out is lifted")
+
+	_, err = uc.UpdateMemberQuota(context.Background(), "a@b.com", 1, 30, dto.MemberQuotaRequest{MonthlyHours: floatPtr(-1)})
+	assert.EqualError(t, err, constant.ErrInvalidQuota)
+}
+
+func TestUpdateMemberQuota_AdminCannotChangeAdmins(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", constant.OrgRoleAdmin)
+	orgRepo.On("GetMember", mock.Anything, uint(30)).Return(&model.OrganizationMember{
+		Model: gorm.Model{ID: 30}, OrganizationID: 1, Role: constant.OrgRoleAdmin, Status: constant.MemberStatusActive,
+	}, nil)
+
+	_, err := uc.UpdateMemberQuota(context.Background(), "a@b.com", 1, 30, dto.MemberQuotaRequest{})
+
+	assert.EqualError(t, err, constant.ErrOrganizationForbidden)
+}
+
+func TestRemoveMember_MemberMayLeave(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	me := signedIn(repo, orgRepo, 7, "a@b.com", constant.OrgRoleMember)
+	orgRepo.On("GetMember", mock.Anything, me.ID).Return(me, nil)
+	orgRepo.On("DeleteMember", mock.Anything, me.ID).Return(nil)
+
+	assert.NoError(t, uc.RemoveMember(context.Background(), "a@b.com", 1, me.ID))
+}
+
+func TestRemoveMember_OtherOrganizationNotFound(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", constant.OrgRoleOwner)
+	orgRepo.On("GetMember", mock.Anything, uint(99)).Return(&model.OrganizationMember{
+		Model: gorm.Model{ID: 99}, OrganizationID: 2, Role: constant.OrgRoleMember,
+	}, nil)
+
+	err := uc.RemoveMember(context.Background(), "a@b.com", 1, 99)
+
+	assert.EqualError(t, err, constant.ErrMemberNotFound)
+	orgRepo.AssertNotCalled(t, "DeleteMember", mock.Anything, mock.Anything)
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", "")
+	orgRepo.On("GetMember", mock.Anything, uint(5)).Return(&model.OrganizationMember{
+		Model: gorm.Model{ID: 5}, OrganizationID: 1, Email: "a@b.com", Role: constant.OrgRoleMember,
+		Status: constant.MemberStatusInvited,
+	}, nil)
+	orgRepo.On("UpdateMember", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := uc.AcceptInvitation(context.Background(), "a@b.com", 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), res.UserID)
+	assert.Equal(t, constant.MemberStatusActive, res.Status)
+}
+
+func TestAcceptInvitation_SentToSomeoneElse(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	signedIn(repo, orgRepo, 7, "a@b.com", "")
+	orgRepo.On("GetMember", mock.Anything, uint(5)).Return(&model.OrganizationMember{
+		Model: gorm.Model{ID: 5}, OrganizationID: 1, Email: "other@b.com", Status: constant.MemberStatusInvited,
+	}, nil)
+
+	_, err := uc.AcceptInvitation(context.Background(), "a@b.com", 5)
+
+	assert.EqualError(t, err, constant.ErrInvitationNotFound)
+	orgRepo.AssertNotCalled(t, "UpdateMember", mock.Anything, mock.Anything)
+}
+
+func TestGetMembership_InvitedIsNotAMember(t *testing.T) {
+	_, orgRepo, _, uc := setupOrgUsecase()
+	orgRepo.On("GetMemberByUser", mock.Anything, uint(1), uint(7)).Return(&model.OrganizationMember{
+		OrganizationID: 1, UserID: 7, Status: constant.MemberStatusInvited,
+	}, nil)
+
+	_, err := uc.GetMembership(context.Background(), 1, 7)
+
+	assert.EqualError(t, err, constant.ErrMemberNotFound)
+}
+
+func TestEraseMemberships_DropsInvitationsToAddress(t *testing.T) {
+	repo, orgRepo, _, uc := setupOrgUsecase()
+	repo.On("GetByID", mock.Anything, uint(7)).Return(&model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}, nil)
+	orgRepo.On("DeleteByUser", mock.Anything, uint(7), "a@b.com").Return(nil)
+
+	assert.NoError(t, uc.EraseMemberships(context.Background(), 7))
+	orgRepo.AssertExpectations(t)
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"packages/preferences"
+	"slices"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+	"user-service/internal/sms"
+	"user-service/internal/utils"
+)
+
+type PhoneUsecase interface {
+	SendVerificationCode(ctx context.Context, email, phone string) (*dto.PhoneCodeResponse, error)
+	VerifyPhone(ctx context.Context, email, code string) (*dto.PhoneVerifiedResponse, error)
+	SyncSMSPhone(ctx context.Context, email string) error
+	ErasePhoneVerification(ctx context.Context, userID uint) error
+}
+
+type phoneUsecase struct {
+	userRepo repository.UserRepository
+	prefRepo repository.PreferenceRepository
+	codeRepo repository.PhoneVerificationRepository
+	sms      sms.Provider
+	now      func() time.Time
+}
+
+func NewPhoneUsecase(userRepo repository.UserRepository, prefRepo repository.PreferenceRepository, codeRepo repository.PhoneVerificationRepository, provider sms.Provider) PhoneUsecase {
+	return &phoneUsecase{userRepo: userRepo, prefRepo: prefRepo, codeRepo: codeRepo, sms: provider, now: time.Now}
+}
+
+// otpMessages are the texts of the code, by language. They stay in plain ASCII, which fits
+// more characters in one SMS than Unicode.
+var otpMessages = map[string]string{
+	preferences.LanguageEnglish:    "%s is your Co-working Booking verification code. It expires in %d minutes.",
+	preferences.LanguageVietnamese: "%s la ma xac thuc Co-working Booking cua ban. Ma het han sau %d phut.",
+}
+
+// SendVerificationCode texts a one-time code to phone, or to the phone of the profile when it is
+// empty. Codes can be resent once a minute and a few times a day; a new code replaces the last.
+func (u *phoneUsecase) SendVerificationCode(ctx context.Context, email, phone string) (*dto.PhoneCodeResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	if phone == "" {
+		phone = user.Phone
+	}
+	phone, err = utils.NormalizePhone(phone)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidPhoneNumber)
+	}
+	if !utils.IsMobilePhone(phone) {
+		return nil, errors.New(constant.ErrPhoneNotMobile)
+	}
+	if user.PhoneVerified && user.Phone == phone {
+		return nil, errors.New(constant.ErrPhoneAlreadyVerified)
+	}
+
+	code, err := newOTP()
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	// the send is stored, and counted, before the code is texted: parallel requests cannot all
+	// pass the limits, and a text always carries a code that can be checked
+	now := u.now()
+	var sent model.PhoneVerification
+	err = u.codeRepo.ClaimSend(ctx, user.ID, func(v *model.PhoneVerification) error {
+		if v.WindowStart.IsZero() {
+			v.WindowStart = now
+		}
+		if now.Before(v.SentAt.Add(constant.PhoneOTPResendInterval)) {
+			return errors.New(constant.ErrOTPResendTooSoon)
+		}
+		if now.Sub(v.WindowStart) >= 24*time.Hour {
+			v.WindowStart, v.SendCount = now, 0
+		}
+		if v.SendCount >= constant.PhoneOTPMaxSendsPerDay {
+			return errors.New(constant.ErrOTPDailyLimit)
+		}
+		v.Phone = phone
+		v.Salt = hex.EncodeToString(salt)
+		v.CodeHash = hashOTP(v.Salt, code)
+		v.ExpiresAt = now.Add(constant.PhoneOTPTTL)
+		v.Attempts = 0
+		v.SentAt = now
+		v.SendCount++
+		sent = *v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.sms.Send(ctx, phone, u.otpMessage(ctx, user.ID, code)); err != nil {
+		log.Printf("send verification code to user %d failed: %v", user.ID, err)
+		return nil, errors.New(constant.ErrSendSMS)
+	}
+	return &dto.PhoneCodeResponse{
+		Phone:       phone,
+		ExpiresAt:   sent.ExpiresAt,
+		ResendAfter: now.Add(constant.PhoneOTPResendInterval),
+	}, nil
+}
+
+// VerifyPhone checks the code last sent to the user. Once it matches, the number it was sent to
+// becomes the verified phone of the profile and the number SMS notifications go to. A code
+// takes a few wrong guesses at most, then a new one has to be sent.
+func (u *phoneUsecase) VerifyPhone(ctx context.Context, email, code string) (*dto.PhoneVerifiedResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	v, err := u.codeRepo.Get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || v.CodeHash == "" {
+		return nil, errors.New(constant.ErrInvalidOTP)
+	}
+	if v.Attempts >= constant.PhoneOTPMaxAttempts {
+		return nil, errors.New(constant.ErrOTPTooManyAttempts)
+	}
+	if u.now().After(v.ExpiresAt) {
+		return nil, errors.New(constant.ErrOTPExpired)
+	}
+
+	// every guess is counted before the code is compared, so parallel requests share the limit
+	counted, err := u.codeRepo.CountAttempt(ctx, user.ID, v.CodeHash, constant.PhoneOTPMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !counted {
+		return nil, errors.New(constant.ErrOTPTooManyAttempts)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashOTP(v.Salt, code)), []byte(v.CodeHash)) != 1 {
+		if v.Attempts+1 >= constant.PhoneOTPMaxAttempts {
+			return nil, errors.New(constant.ErrOTPTooManyAttempts)
+		}
+		return nil, errors.New(constant.ErrInvalidOTP)
+	}
+
+	// the row stays, without a code, so the daily count of codes sent survives
+	cleared, err := u.codeRepo.ClearCode(ctx, user.ID, v.CodeHash)
+	if err != nil {
+		return nil, err
+	}
+	if !cleared {
+		return nil, errors.New(constant.ErrInvalidOTP)
+	}
+	user.Phone = v.Phone
+	user.PhoneVerified = true
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := u.setSMSPhone(ctx, user.ID, user.Phone); err != nil {
+		return nil, err
+	}
+	return &dto.PhoneVerifiedResponse{Phone: user.Phone, PhoneVerified: true}, nil
+}
+
+// SyncSMSPhone brings the number SMS notifications go to in line with the profile, after it
+// changed: an unverified number gets no SMS.
+func (u *phoneUsecase) SyncSMSPhone(ctx context.Context, email string) error {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return err
+	}
+	phone := ""
+	if user.PhoneVerified {
+		phone = user.Phone
+	}
+	return u.setSMSPhone(ctx, user.ID, phone)
+}
+
+func (u *phoneUsecase) ErasePhoneVerification(ctx context.Context, userID uint) error {
+	return u.codeRepo.Delete(ctx, userID)
+}
+
+// setSMSPhone saves phone as the number of the preferences, which publishes it. Without a
+// number, SMS is taken off the channels too, so they show what is delivered.
+func (u *phoneUsecase) setSMSPhone(ctx context.Context, userID uint, phone string) error {
+	pref, err := loadPreference(ctx, u.prefRepo, userID)
+	if err != nil {
+		return err
+	}
+	if pref.Phone == phone {
+		return nil
+	}
+	pref.Phone = phone
+	if phone == "" {
+		for event, channels := range pref.Channels {
+			pref.Channels[event] = slices.DeleteFunc(channels, func(c string) bool { return c == preferences.ChannelSMS })
+		}
+	}
+	return u.prefRepo.Save(ctx, pref)
+}
+
+func (u *phoneUsecase) otpMessage(ctx context.Context, userID uint, code string) string {
+	language := preferences.DefaultLanguage
+	if pref, err := u.prefRepo.Get(ctx, userID); err == nil && pref != nil {
+		language = pref.Language
+	}
+	msg, ok := otpMessages[language]
+	if !ok {
+		msg = otpMessages[preferences.DefaultLanguage]
+	}
+	return fmt.Sprintf(msg, code, int(constant.PhoneOTPTTL/time.Minute))
+}
+
+// newOTP returns a random code of PhoneOTPLength digits.
+func newOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < constant.PhoneOTPLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", constant.PhoneOTPLength, n), nil
+}
+
+func hashOTP(salt, code string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
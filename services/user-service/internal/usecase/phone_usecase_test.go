@@ -0,0 +1,266 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"packages/preferences"
+	"regexp"
+	"testing"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/model"
+	"user-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock PhoneVerificationRepo =====
+type mockPhoneRepo struct {
+	mock.Mock
+	claimed *model.PhoneVerification // the row stored by the last ClaimSend that went through
+}
+
+func (m *mockPhoneRepo) Get(ctx context.Context, userID uint) (*model.PhoneVerification, error) {
+	args := m.Called(ctx, userID)
+	if fn, ok := args.Get(0).(func() *model.PhoneVerification); ok {
+		return fn(), args.Error(1)
+	}
+	if v, ok := args.Get(0).(*model.PhoneVerification); ok {
+		return v, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ClaimSend applies claim to a copy of the row returned by the expectation, like the locked
+// row of the transaction, and keeps it only when claim accepts it.
+func (m *mockPhoneRepo) ClaimSend(ctx context.Context, userID uint, claim func(v *model.PhoneVerification) error) error {
+	args := m.Called(ctx, userID)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	v := &model.PhoneVerification{UserID: userID}
+	if last, ok := args.Get(0).(*model.PhoneVerification); ok && last != nil {
+		copied := *last
+		v = &copied
+	}
+	if err := claim(v); err != nil {
+		return err
+	}
+	m.claimed = v
+	return nil
+}
+func (m *mockPhoneRepo) Delete(ctx context.Context, userID uint) error {
+	return m.Called(ctx, userID).Error(0)
+}
+func (m *mockPhoneRepo) CountAttempt(ctx context.Context, userID uint, codeHash string, max int) (bool, error) {
+	args := m.Called(ctx, userID, codeHash, max)
+	if fn, ok := args.Get(0).(func(context.Context, uint, string, int) bool); ok {
+		return fn(ctx, userID, codeHash, max), args.Error(1)
+	}
+	return args.Bool(0), args.Error(1)
+}
+func (m *mockPhoneRepo) ClearCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	if fn, ok := args.Get(0).(func(context.Context, uint, string) bool); ok {
+		return fn(ctx, userID, codeHash), args.Error(1)
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+// countAttempts makes CountAttempt and ClearCode act on v like the SQL statements do.
+func countAttempts(phoneRepo *mockPhoneRepo, v func() *model.PhoneVerification) {
+	phoneRepo.On("CountAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ uint, codeHash string, max int) bool {
+			if v().CodeHash != codeHash || v().Attempts >= max {
+				return false
+			}
+			v().Attempts++
+			return true
+		}, nil)
+	phoneRepo.On("ClearCode", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ uint, codeHash string) bool {
+			if v().CodeHash != codeHash {
+				return false
+			}
+			v().CodeHash = ""
+			return true
+		}, nil)
+}
+
+// ===== Fake SMS provider =====
+type fakeSMS struct {
+	to, body string
+	err      error
+}
+
+func (f *fakeSMS) Send(_ context.Context, to, body string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.to, f.body = to, body
+	return nil
+}
+
+var otpPattern = regexp.MustCompile(`\d{6}`)
+
+func (f *fakeSMS) code() string { return otpPattern.FindString(f.body) }
+
+func setupPhoneUsecase(user *model.User) (*mockUserRepo, *mockPreferenceRepo, *mockPhoneRepo, *fakeSMS, usecase.PhoneUsecase) {
+	repo, prefRepo, phoneRepo, provider := new(mockUserRepo), new(mockPreferenceRepo), new(mockPhoneRepo), new(fakeSMS)
+	repo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	return repo, prefRepo, phoneRepo, provider, usecase.NewPhoneUsecase(repo, prefRepo, phoneRepo, provider)
+}
+
+func TestPhoneVerification_SendThenVerify(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com", Phone: "0912345678"}
+	repo, prefRepo, phoneRepo, provider, uc := setupPhoneUsecase(user)
+	prefRepo.On("Get", mock.Anything, uint(7)).Return(&model.UserPreference{UserID: 7, Language: "vi"}, nil)
+	phoneRepo.On("ClaimSend", mock.Anything, uint(7)).Return(nil, nil).Once()
+
+	sent, err := uc.SendVerificationCode(context.Background(), "a@b.com", "")
+	stored := phoneRepo.claimed
+
+	require.NoError(t, err)
+	assert.Equal(t, "+84912345678", sent.Phone)
+	assert.Equal(t, "+84912345678", provider.to)
+	assert.Contains(t, provider.body, "ma xac thuc")
+	require.NotNil(t, stored)
+	assert.NotContains(t, stored.CodeHash, provider.code())
+
+	phoneRepo.On("Get", mock.Anything, uint(7)).Return(stored, nil)
+	countAttempts(phoneRepo, func() *model.PhoneVerification { return stored })
+	repo.On("Update", mock.Anything, user).Return(nil)
+	prefRepo.On("Save", mock.Anything, mock.AnythingOfType("*model.UserPreference")).Return(nil)
+
+	res, err := uc.VerifyPhone(context.Background(), "a@b.com", provider.code())
+
+	require.NoError(t, err)
+	assert.True(t, res.PhoneVerified)
+	assert.True(t, user.PhoneVerified)
+	assert.Equal(t, "+84912345678", user.Phone)
+	assert.Empty(t, stored.CodeHash)
+	pref := prefRepo.Calls[len(prefRepo.Calls)-1].Arguments.Get(1).(*model.UserPreference)
+	assert.Equal(t, "+84912345678", pref.Phone)
+}
+
+func TestSendVerificationCode_Rejects(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		user  model.User
+		phone string
+		last  *model.PhoneVerification
+		want  string
+	}{
+		"invalid number":   {phone: "12345", want: constant.ErrInvalidPhoneNumber},
+		"landline":         {phone: "02838123456", want: constant.ErrPhoneNotMobile},
+		"already verified": {user: model.User{Phone: "+84912345678", PhoneVerified: true}, phone: "0912345678", want: constant.ErrPhoneAlreadyVerified},
+		"resent too soon":  {phone: "0912345678", last: &model.PhoneVerification{SentAt: now.Add(-10 * time.Second), WindowStart: now}, want: constant.ErrOTPResendTooSoon},
+		"daily limit": {phone: "0912345678", last: &model.PhoneVerification{
+			SentAt: now.Add(-time.Hour), WindowStart: now.Add(-2 * time.Hour), SendCount: constant.PhoneOTPMaxSendsPerDay}, want: constant.ErrOTPDailyLimit},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			user := tc.user
+			user.ID, user.Email = 7, "a@b.com"
+			_, _, phoneRepo, provider, uc := setupPhoneUsecase(&user)
+			phoneRepo.On("ClaimSend", mock.Anything, uint(7)).Return(tc.last, nil)
+
+			_, err := uc.SendVerificationCode(context.Background(), "a@b.com", tc.phone)
+
+			assert.EqualError(t, err, tc.want)
+			assert.Empty(t, provider.to)
+			assert.Nil(t, phoneRepo.claimed)
+		})
+	}
+}
+
+func TestSendVerificationCode_ProviderFailureStillCountsTheSend(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}
+	_, prefRepo, phoneRepo, provider, uc := setupPhoneUsecase(user)
+	provider.err = errors.New("carrier down")
+	prefRepo.On("Get", mock.Anything, uint(7)).Return(nil, nil)
+	phoneRepo.On("ClaimSend", mock.Anything, uint(7)).Return(nil, nil)
+
+	_, err := uc.SendVerificationCode(context.Background(), "a@b.com", "+84912345678")
+
+	assert.EqualError(t, err, constant.ErrSendSMS)
+	require.NotNil(t, phoneRepo.claimed)
+	assert.Equal(t, 1, phoneRepo.claimed.SendCount)
+}
+
+func TestSendVerificationCode_NoTextWhenTheClaimFails(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}
+	_, _, phoneRepo, provider, uc := setupPhoneUsecase(user)
+	phoneRepo.On("ClaimSend", mock.Anything, uint(7)).Return(nil, errors.New(constant.ErrDatabase))
+
+	_, err := uc.SendVerificationCode(context.Background(), "a@b.com", "+84912345678")
+
+	assert.EqualError(t, err, constant.ErrDatabase)
+	assert.Empty(t, provider.to)
+}
+
+func TestVerifyPhone_WrongCodesUseUpAttempts(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}
+	repo, _, phoneRepo, _, uc := setupPhoneUsecase(user)
+	v := &model.PhoneVerification{UserID: 7, Phone: "+84912345678", Salt: "s", CodeHash: "not-a-hash",
+		ExpiresAt: time.Now().Add(time.Minute), Attempts: constant.PhoneOTPMaxAttempts - 2}
+	// a copy on every read, as from the database
+	phoneRepo.On("Get", mock.Anything, uint(7)).Return(func() *model.PhoneVerification { copied := *v; return &copied }, nil)
+	countAttempts(phoneRepo, func() *model.PhoneVerification { return v })
+
+	_, err := uc.VerifyPhone(context.Background(), "a@b.com", "000000")
+	assert.EqualError(t, err, constant.ErrInvalidOTP)
+	_, err = uc.VerifyPhone(context.Background(), "a@b.com", "000000")
+	assert.EqualError(t, err, constant.ErrOTPTooManyAttempts)
+	_, err = uc.VerifyPhone(context.Background(), "a@b.com", "000000")
+	assert.EqualError(t, err, constant.ErrOTPTooManyAttempts)
+
+	assert.Equal(t, constant.PhoneOTPMaxAttempts, v.Attempts)
+	assert.False(t, user.PhoneVerified)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	phoneRepo.AssertNotCalled(t, "ClaimSend", mock.Anything, mock.Anything)
+}
+
+func TestVerifyPhone_AttemptsUsedUpMeanwhile(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}
+	repo, _, phoneRepo, _, uc := setupPhoneUsecase(user)
+	// read before parallel guesses used up the last attempts
+	phoneRepo.On("Get", mock.Anything, uint(7)).Return(&model.PhoneVerification{UserID: 7, Phone: "+84912345678",
+		Salt: "s", CodeHash: "h", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	phoneRepo.On("CountAttempt", mock.Anything, uint(7), "h", constant.PhoneOTPMaxAttempts).Return(false, nil)
+
+	_, err := uc.VerifyPhone(context.Background(), "a@b.com", "123456")
+
+	assert.EqualError(t, err, constant.ErrOTPTooManyAttempts)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestVerifyPhone_Expired(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com"}
+	_, _, phoneRepo, _, uc := setupPhoneUsecase(user)
+	phoneRepo.On("Get", mock.Anything, uint(7)).Return(&model.PhoneVerification{
+		UserID: 7, Phone: "+84912345678", Salt: "s", CodeHash: "h", ExpiresAt: time.Now().Add(-time.Second)}, nil)
+
+	_, err := uc.VerifyPhone(context.Background(), "a@b.com", "123456")
+
+	assert.EqualError(t, err, constant.ErrOTPExpired)
+}
+
+func TestSyncSMSPhone_DropsSMSForUnverifiedNumber(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@b.com", Phone: "+84987654321"}
+	_, prefRepo, _, _, uc := setupPhoneUsecase(user)
+	prefRepo.On("Get", mock.Anything, uint(7)).Return(&model.UserPreference{
+		UserID: 7, Phone: "+84912345678",
+		Channels: map[string][]string{preferences.EventBookingCreated: {preferences.ChannelEmail, preferences.ChannelSMS}},
+	}, nil)
+	prefRepo.On("Save", mock.Anything, mock.AnythingOfType("*model.UserPreference")).Return(nil)
+
+	require.NoError(t, uc.SyncSMSPhone(context.Background(), "a@b.com"))
+
+	saved := prefRepo.Calls[1].Arguments.Get(1).(*model.UserPreference)
+	assert.Empty(t, saved.Phone)
+	assert.Equal(t, []string{preferences.ChannelEmail}, saved.Channels[preferences.EventBookingCreated])
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/kafka"
+	"user-service/internal/repository"
+)
+
+// PreferenceRelay publishes saved preferences that Kafka did not get yet, keyed by user ID.
+type PreferenceRelay struct {
+	prefRepo repository.PreferenceRepository
+	producer kafka.Producer
+}
+
+func NewPreferenceRelay(prefRepo repository.PreferenceRepository, producer kafka.Producer) *PreferenceRelay {
+	return &PreferenceRelay{prefRepo: prefRepo, producer: producer}
+}
+
+// Run polls for unpublished preferences until ctx is cancelled.
+func (r *PreferenceRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(constant.PreferencePollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.PublishPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending sends one batch and returns how many were published. It stops at the first
+// failure, leaving the rest for the next round.
+func (r *PreferenceRelay) PublishPending(ctx context.Context) int {
+	prefs, err := r.prefRepo.ListUnpublished(ctx, constant.PreferenceBatchSize)
+	if err != nil {
+		log.Println("list unpublished preferences failed:", err)
+		return 0
+	}
+
+	sent := 0
+	for i := range prefs {
+		pref := &prefs[i]
+		payload, err := json.Marshal(publishedPreferences(pref))
+		if err != nil {
+			log.Println("marshal preferences failed:", err)
+			continue
+		}
+		if err := r.producer.Publish(ctx, []byte(strconv.FormatUint(uint64(pref.UserID), 10)), payload); err != nil {
+			log.Println("publish preferences failed:", err)
+			return sent
+		}
+		if err := r.prefRepo.MarkPublished(ctx, pref.UserID, pref.Version); err != nil {
+			log.Println("mark preferences published failed:", err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"packages/preferences"
+	"slices"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+)
+
+type PreferenceUsecase interface {
+	GetPreferences(ctx context.Context, email string) (*dto.PreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, email string, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error)
+	ExportPreferences(ctx context.Context, userID uint) (*dto.PreferencesResponse, error)
+	ResetPreferences(ctx context.Context, userID uint) error
+}
+
+type preferenceUsecase struct {
+	userRepo repository.UserRepository
+	prefRepo repository.PreferenceRepository
+}
+
+func NewPreferenceUsecase(userRepo repository.UserRepository, prefRepo repository.PreferenceRepository) PreferenceUsecase {
+	return &preferenceUsecase{userRepo: userRepo, prefRepo: prefRepo}
+}
+
+func (u *preferenceUsecase) GetPreferences(ctx context.Context, email string) (*dto.PreferencesResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	return u.ExportPreferences(ctx, user.ID)
+}
+
+// UpdatePreferences validates and saves the fields set in req. The change reaches the other
+// services through the preference relay.
+func (u *preferenceUsecase) UpdatePreferences(ctx context.Context, email string, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	user, err := currentUser(ctx, u.userRepo, email)
+	if err != nil {
+		return nil, err
+	}
+	pref, err := u.load(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Language != nil {
+		if !slices.Contains(preferences.Languages, *req.Language) {
+			return nil, errors.New(constant.ErrInvalidLanguage)
+		}
+		pref.Language = *req.Language
+	}
+	if req.Timezone != nil {
+		// "Local" would mean the zone of whichever host reads it
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			return nil, errors.New(constant.ErrInvalidTimezone)
+		}
+		pref.Timezone = *req.Timezone
+	}
+	if req.Currency != nil {
+		if !slices.Contains(preferences.Currencies, *req.Currency) {
+			return nil, errors.New(constant.ErrInvalidCurrency)
+		}
+		pref.Currency = *req.Currency
+	}
+	for event, channels := range req.Channels {
+		if !slices.Contains(preferences.Events, event) {
+			return nil, errors.New(constant.ErrInvalidChannels)
+		}
+		chosen := make([]string, 0, len(channels))
+		for _, channel := range channels {
+			if !slices.Contains(preferences.Channels, channel) {
+				return nil, errors.New(constant.ErrInvalidChannels)
+			}
+			if !slices.Contains(chosen, channel) {
+				chosen = append(chosen, channel)
+			}
+		}
+		if slices.Contains(chosen, preferences.ChannelSMS) && pref.Phone == "" {
+			return nil, errors.New(constant.ErrPhoneNotVerified)
+		}
+		pref.Channels[event] = chosen
+	}
+
+	if err := u.prefRepo.Save(ctx, pref); err != nil {
+		return nil, err
+	}
+	return preferencesResponse(pref), nil
+}
+
+func (u *preferenceUsecase) ExportPreferences(ctx context.Context, userID uint) (*dto.PreferencesResponse, error) {
+	pref, err := u.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferencesResponse(pref), nil
+}
+
+// ResetPreferences puts back the defaults once the account is deleted, publishing them so no
+// service keeps the old choices. Users who never saved any are left alone.
+func (u *preferenceUsecase) ResetPreferences(ctx context.Context, userID uint) error {
+	pref, err := u.prefRepo.Get(ctx, userID)
+	if err != nil || pref == nil {
+		return err
+	}
+	return u.prefRepo.Save(ctx, defaultPreference(userID))
+}
+
+func (u *preferenceUsecase) load(ctx context.Context, userID uint) (*model.UserPreference, error) {
+	return loadPreference(ctx, u.prefRepo, userID)
+}
+
+// loadPreference returns the saved preferences of the user, or the defaults.
+func loadPreference(ctx context.Context, prefRepo repository.PreferenceRepository, userID uint) (*model.UserPreference, error) {
+	pref, err := prefRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pref == nil {
+		return defaultPreference(userID), nil
+	}
+	if pref.Channels == nil {
+		pref.Channels = map[string][]string{}
+	}
+	return pref, nil
+}
+
+func defaultPreference(userID uint) *model.UserPreference {
+	def := preferences.Default(userID)
+	return &model.UserPreference{
+		UserID:   userID,
+		Language: def.Language,
+		Timezone: def.Timezone,
+		Currency: def.Currency,
+		Channels: def.Channels,
+	}
+}
+
+// publishedPreferences is the message the relay publishes for pref.
+func publishedPreferences(pref *model.UserPreference) preferences.Preferences {
+	return preferences.Preferences{
+		UserID:    pref.UserID,
+		Language:  pref.Language,
+		Timezone:  pref.Timezone,
+		Currency:  pref.Currency,
+		Channels:  pref.Channels,
+		Phone:     pref.Phone,
+		UpdatedAt: pref.UpdatedAt,
+	}
+}
+
+func preferencesResponse(pref *model.UserPreference) *dto.PreferencesResponse {
+	published := publishedPreferences(pref)
+	channels := make(map[string][]string, len(preferences.Events))
+	for _, event := range preferences.Events {
+		channels[event] = []string{}
+		for _, channel := range preferences.Channels {
+			if published.Wants(event, channel) {
+				channels[event] = append(channels[event], channel)
+			}
+		}
+	}
+	return &dto.PreferencesResponse{
+		Language: pref.Language,
+		Timezone: pref.Timezone,
+		Currency: pref.Currency,
+		Channels: channels,
+	}
+}
@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"packages/preferences"
+	"testing"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock PreferenceRepo =====
+type mockPreferenceRepo struct{ mock.Mock }
+
+func (m *mockPreferenceRepo) Get(ctx context.Context, userID uint) (*model.UserPreference, error) {
+	args := m.Called(ctx, userID)
+	if p, ok := args.Get(0).(*model.UserPreference); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockPreferenceRepo) Save(ctx context.Context, pref *model.UserPreference) error {
+	return m.Called(ctx, pref).Error(0)
+}
+func (m *mockPreferenceRepo) ListUnpublished(ctx context.Context, limit int) ([]model.UserPreference, error) {
+	args := m.Called(ctx, limit)
+	if p, ok := args.Get(0).([]model.UserPreference); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockPreferenceRepo) MarkPublished(ctx context.Context, userID uint, version int) error {
+	return m.Called(ctx, userID, version).Error(0)
+}
+
+// ===== Mock Producer =====
+type mockProducer struct{ mock.Mock }
+
+func (m *mockProducer) Publish(ctx context.Context, key, value []byte) error {
+	return m.Called(ctx, string(key), value).Error(0)
+}
+func (m *mockProducer) Close() error { return nil }
+
+func strPtr(s string) *string { return &s }
+
+func TestGetPreferences_Defaults(t *testing.T) {
+	repo, prefRepo := new(mockUserRepo), new(mockPreferenceRepo)
+	uc := usecase.NewPreferenceUsecase(repo, prefRepo)
+	repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}}, nil)
+	prefRepo.On("Get", mock.Anything, uint(7)).Return(nil, nil)
+
+	res, err := uc.GetPreferences(context.Background(), "a@b.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, preferences.DefaultLanguage, res.Language)
+	assert.Equal(t, preferences.DefaultTimezone, res.Timezone)
+	assert.Equal(t, preferences.DefaultChannels, res.Channels[preferences.EventBookingCreated])
+}
+
+func TestUpdatePreferences_ChangesOnlyWhatIsSent(t *testing.T) {
+	repo, prefRepo := new(mockUserRepo), new(mockPreferenceRepo)
+	uc := usecase.NewPreferenceUsecase(repo, prefRepo)
+	repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}}, nil)
+	prefRepo.On("Get", mock.Anything, uint(7)).Return(&model.UserPreference{
+		UserID: 7, Language: "vi", Timezone: "UTC", Currency: "VND", Phone: "+84912345678",
+		Channels: map[string][]string{preferences.EventBookingCreated: {preferences.ChannelEmail}},
+	}, nil)
+	prefRepo.On("Save", mock.Anything, mock.AnythingOfType("*model.UserPreference")).Return(nil)
+
+	res, err := uc.UpdatePreferences(context.Background(), "a@b.com", dto.UpdatePreferencesRequest{
+		Timezone: strPtr("Asia/Ho_Chi_Minh"),
+		Channels: map[string][]string{preferences.EventBookingStatusUpdated: {"sms", "sms"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "vi", res.Language)
+	assert.Equal(t, "Asia/Ho_Chi_Minh", res.Timezone)
+	assert.Equal(t, []string{preferences.ChannelEmail}, res.Channels[preferences.EventBookingCreated])
+	assert.Equal(t, []string{preferences.ChannelSMS}, res.Channels[preferences.EventBookingStatusUpdated])
+	saved := prefRepo.Calls[1].Arguments.Get(1).(*model.UserPreference)
+	assert.Equal(t, "Asia/Ho_Chi_Minh", saved.Timezone)
+}
+
+func TestUpdatePreferences_RejectsBadInput(t *testing.T) {
+	cases := map[string]struct {
+		req  dto.UpdatePreferencesRequest
+		want string
+	}{
+		"language":      {dto.UpdatePreferencesRequest{Language: strPtr("fr")}, constant.ErrInvalidLanguage},
+		"timezone":      {dto.UpdatePreferencesRequest{Timezone: strPtr("Saigon/Center")}, constant.ErrInvalidTimezone},
+		"local":         {dto.UpdatePreferencesRequest{Timezone: strPtr("Local")}, constant.ErrInvalidTimezone},
+		"currency":      {dto.UpdatePreferencesRequest{Currency: strPtr("BTC")}, constant.ErrInvalidCurrency},
+		"unknown event": {dto.UpdatePreferencesRequest{Channels: map[string][]string{"NEWSLETTER": {"email"}}}, constant.ErrInvalidChannels},
+		"unknown channel": {dto.UpdatePreferencesRequest{
+			Channels: map[string][]string{preferences.EventBookingCreated: {"pigeon"}}}, constant.ErrInvalidChannels},
+		"sms without verified phone": {dto.UpdatePreferencesRequest{
+			Channels: map[string][]string{preferences.EventBookingCreated: {"sms"}}}, constant.ErrPhoneNotVerified},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo, prefRepo := new(mockUserRepo), new(mockPreferenceRepo)
+			uc := usecase.NewPreferenceUsecase(repo, prefRepo)
+			repo.On("GetByEmail", mock.Anything, "a@b.com").Return(&model.User{Model: gorm.Model{ID: 7}}, nil)
+			prefRepo.On("Get", mock.Anything, uint(7)).Return(nil, nil)
+
+			_, err := uc.UpdatePreferences(context.Background(), "a@b.com", tc.req)
+
+			assert.EqualError(t, err, tc.want)
+			prefRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPreferenceRelay_PublishesAndStopsOnFailure(t *testing.T) {
+	prefRepo, producer := new(mockPreferenceRepo), new(mockProducer)
+	relay := usecase.NewPreferenceRelay(prefRepo, producer)
+	prefRepo.On("ListUnpublished", mock.Anything, constant.PreferenceBatchSize).Return([]model.UserPreference{
+		{UserID: 7, Language: "vi", Timezone: "Asia/Ho_Chi_Minh", Currency: "VND", Version: 3},
+		{UserID: 8, Language: "en", Timezone: "UTC", Currency: "USD", Version: 1},
+		{UserID: 9, Language: "en", Timezone: "UTC", Currency: "USD", Version: 2},
+	}, nil)
+	producer.On("Publish", mock.Anything, "7", mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, "8", mock.Anything).Return(errors.New("broker down"))
+	prefRepo.On("MarkPublished", mock.Anything, uint(7), 3).Return(nil)
+
+	assert.Equal(t, 1, relay.PublishPending(context.Background()))
+
+	var published preferences.Preferences
+	require.NoError(t, json.Unmarshal(producer.Calls[0].Arguments.Get(2).([]byte), &published))
+	assert.Equal(t, uint(7), published.UserID)
+	assert.Equal(t, "Asia/Ho_Chi_Minh", published.Timezone)
+	producer.AssertNotCalled(t, "Publish", mock.Anything, "9", mock.Anything)
+	prefRepo.AssertNotCalled(t, "MarkPublished", mock.Anything, uint(8), mock.Anything)
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"user-service/internal/constant"
+)
+
+// SuspensionExpirer lifts suspensions once they reach their end.
+type SuspensionExpirer struct {
+	suspensions SuspensionUsecase
+}
+
+func NewSuspensionExpirer(suspensions SuspensionUsecase) *SuspensionExpirer {
+	return &SuspensionExpirer{suspensions: suspensions}
+}
+
+// Run lifts expired suspensions until ctx is cancelled.
+func (e *SuspensionExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(constant.SuspensionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		e.suspensions.LiftExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
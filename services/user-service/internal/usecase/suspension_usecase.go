@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"packages/userdata"
+	"slices"
+	"strconv"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/kafka"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+)
+
+type SuspensionUsecase interface {
+	SuspendUser(ctx context.Context, actorEmail string, userID uint, req dto.SuspendUserRequest) (*dto.SuspensionResponse, error)
+	LiftSuspension(ctx context.Context, actorEmail string, userID uint, req dto.LiftSuspensionRequest) (*dto.SuspensionResponse, error)
+	ListSuspensions(ctx context.Context, userID uint) ([]dto.SuspensionResponse, error)
+	IsSuspended(ctx context.Context, userID uint) (bool, error)
+	LiftExpired(ctx context.Context) int
+	EraseSuspensions(ctx context.Context, userID uint) error
+}
+
+type suspensionUsecase struct {
+	userRepo       repository.UserRepository
+	suspensionRepo repository.SuspensionRepository
+	authClient     repository.AuthClient
+	mail           kafka.Producer
+	now            func() time.Time
+}
+
+// NewSuspensionUsecase tells suspended users through mailProducer, which writes to the mail topic.
+func NewSuspensionUsecase(userRepo repository.UserRepository, suspensionRepo repository.SuspensionRepository, authClient repository.AuthClient, mailProducer kafka.Producer) SuspensionUsecase {
+	return &suspensionUsecase{userRepo: userRepo, suspensionRepo: suspensionRepo, authClient: authClient, mail: mailProducer, now: time.Now}
+}
+
+// SuspendUser deactivates the user, which signs them out everywhere, and records why and by whom.
+// The record is written last, so a failure half way can simply be retried.
+func (u *suspensionUsecase) SuspendUser(ctx context.Context, actorEmail string, userID uint, req dto.SuspendUserRequest) (*dto.SuspensionResponse, error) {
+	actor, err := u.userRepo.GetByEmail(ctx, actorEmail)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New(constant.ErrUnauthorized)
+	}
+	if actor.ID == userID {
+		return nil, errors.New(constant.ErrCannotSuspendSelf)
+	}
+	now := u.now()
+	if !slices.Contains(constant.SuspensionReasons, req.Reason) || (req.ExpiresAt != nil && !req.ExpiresAt.After(now)) {
+		return nil, errors.New(constant.ErrInvalidSuspension)
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	current, err := u.suspensionRepo.GetActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, errors.New(constant.ErrAlreadySuspended)
+	}
+
+	if err := u.setActive(ctx, user, false); err != nil {
+		return nil, err
+	}
+	s := &model.Suspension{
+		UserID:      userID,
+		Reason:      req.Reason,
+		Note:        req.Note,
+		SuspendedBy: actor.ID,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if err := u.suspensionRepo.Create(ctx, s); err != nil {
+		return nil, errors.New(constant.ErrUpdateFailed)
+	}
+
+	data := map[string]string{"reason": s.Reason}
+	if s.ExpiresAt != nil {
+		data["suspendedUntil"] = s.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	u.notify(ctx, user, constant.EventTypeAccountSuspended, data)
+	res := toSuspensionResponse(s)
+	return &res, nil
+}
+
+// LiftSuspension ends the suspension of the user before it expires.
+func (u *suspensionUsecase) LiftSuspension(ctx context.Context, actorEmail string, userID uint, req dto.LiftSuspensionRequest) (*dto.SuspensionResponse, error) {
+	actor, err := u.userRepo.GetByEmail(ctx, actorEmail)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New(constant.ErrUnauthorized)
+	}
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	s, err := u.suspensionRepo.GetActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, errors.New(constant.ErrNotSuspended)
+	}
+
+	s.LiftedBy = actor.ID
+	s.LiftNote = req.Note
+	if err := u.lift(ctx, s); err != nil {
+		return nil, err
+	}
+	res := toSuspensionResponse(s)
+	return &res, nil
+}
+
+func (u *suspensionUsecase) ListSuspensions(ctx context.Context, userID uint) ([]dto.SuspensionResponse, error) {
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	suspensions, err := u.suspensionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.SuspensionResponse, 0, len(suspensions))
+	for i := range suspensions {
+		res = append(res, toSuspensionResponse(&suspensions[i]))
+	}
+	return res, nil
+}
+
+func (u *suspensionUsecase) IsSuspended(ctx context.Context, userID uint) (bool, error) {
+	s, err := u.suspensionRepo.GetActive(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return s != nil, nil
+}
+
+// LiftExpired lifts one batch of suspensions that reached their end and returns how many were
+// lifted. One that fails stays in force and is tried again on the next round.
+func (u *suspensionUsecase) LiftExpired(ctx context.Context) int {
+	expired, err := u.suspensionRepo.ListExpired(ctx, u.now(), constant.SuspensionBatchSize)
+	if err != nil {
+		log.Println("list expired suspensions failed:", err)
+		return 0
+	}
+
+	lifted := 0
+	for i := range expired {
+		if err := u.lift(ctx, &expired[i]); err != nil {
+			log.Printf("lift suspension %d failed: %v", expired[i].ID, err)
+			continue
+		}
+		lifted++
+	}
+	return lifted
+}
+
+// EraseSuspensions closes the suspension in force when the account of the user is deleted, so
+// that neither an admin nor its expiry reactivates the account later. The history stays.
+func (u *suspensionUsecase) EraseSuspensions(ctx context.Context, userID uint) error {
+	s, err := u.suspensionRepo.GetActive(ctx, userID)
+	if err != nil || s == nil {
+		return err
+	}
+	return u.close(ctx, s, constant.SuspensionNoteAccountDeleted)
+}
+
+// lift reactivates the user before closing the suspension, so that it stays in force when
+// reactivating fails. The suspension of a deleted account is closed without reactivating it.
+func (u *suspensionUsecase) lift(ctx context.Context, s *model.Suspension) error {
+	user, err := u.userRepo.GetByID(ctx, s.UserID)
+	if err != nil {
+		return errors.New(constant.ErrUserNotFound)
+	}
+	if user.Email == userdata.DeletedEmail(user.ID) {
+		return u.close(ctx, s, constant.SuspensionNoteAccountDeleted)
+	}
+	if err := u.setActive(ctx, user, true); err != nil {
+		return err
+	}
+	if err := u.close(ctx, s, s.LiftNote); err != nil {
+		return err
+	}
+	u.notify(ctx, user, constant.EventTypeAccountReinstated, nil)
+	return nil
+}
+
+func (u *suspensionUsecase) close(ctx context.Context, s *model.Suspension, note string) error {
+	now := u.now()
+	s.LiftedAt = &now
+	s.LiftNote = note
+	if err := u.suspensionRepo.Update(ctx, s); err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+// setActive saves the status in both services; auth-service revokes the sessions of a user
+// turned inactive.
+func (u *suspensionUsecase) setActive(ctx context.Context, user *model.User, active bool) error {
+	user.IsActive = active
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	if err := u.authClient.UpdateUser(ctx, dto.UpdateAuthUserRequest{UserID: user.ID, IsActive: &active}); err != nil {
+		return errors.New(constant.ErrUpdateFailed)
+	}
+	return nil
+}
+
+// notify only logs a failure: the suspension holds whether the user was told or not.
+func (u *suspensionUsecase) notify(ctx context.Context, user *model.User, eventType string, data map[string]string) {
+	payload, err := json.Marshal(dto.MailEvent{UserID: user.ID, Email: user.Email, Type: eventType, Data: data})
+	if err != nil {
+		log.Println("marshal suspension mail failed:", err)
+		return
+	}
+	if err := u.mail.Publish(ctx, []byte(strconv.FormatUint(uint64(user.ID), 10)), payload); err != nil {
+		log.Printf("publish %s mail to user %d failed: %v", eventType, user.ID, err)
+	}
+}
+
+func toSuspensionResponse(s *model.Suspension) dto.SuspensionResponse {
+	return dto.SuspensionResponse{
+		ID:          s.ID,
+		UserID:      s.UserID,
+		Reason:      s.Reason,
+		Note:        s.Note,
+		SuspendedBy: s.SuspendedBy,
+		SuspendedAt: s.CreatedAt,
+		ExpiresAt:   s.ExpiresAt,
+		Active:      s.Active(),
+		LiftedAt:    s.LiftedAt,
+		LiftedBy:    s.LiftedBy,
+		LiftNote:    s.LiftNote,
+	}
+}
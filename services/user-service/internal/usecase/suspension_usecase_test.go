@@ -0,0 +1,235 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"packages/userdata"
+	"testing"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+	"user-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock SuspensionRepo =====
+type mockSuspensionRepo struct{ mock.Mock }
+
+func (m *mockSuspensionRepo) Create(ctx context.Context, s *model.Suspension) error {
+	return m.Called(ctx, s).Error(0)
+}
+func (m *mockSuspensionRepo) Update(ctx context.Context, s *model.Suspension) error {
+	return m.Called(ctx, s).Error(0)
+}
+func (m *mockSuspensionRepo) GetActive(ctx context.Context, userID uint) (*model.Suspension, error) {
+	args := m.Called(ctx, userID)
+	if s, ok := args.Get(0).(*model.Suspension); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockSuspensionRepo) ListByUser(ctx context.Context, userID uint) ([]model.Suspension, error) {
+	args := m.Called(ctx, userID)
+	if s, ok := args.Get(0).([]model.Suspension); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockSuspensionRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Suspension, error) {
+	args := m.Called(ctx, now, limit)
+	if s, ok := args.Get(0).([]model.Suspension); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type suspensionMocks struct {
+	users       *mockUserRepo
+	suspensions *mockSuspensionRepo
+	auth        *mockAuthClient
+	mail        *mockProducer
+}
+
+func setupSuspensionUsecase() (suspensionMocks, usecase.SuspensionUsecase) {
+	m := suspensionMocks{new(mockUserRepo), new(mockSuspensionRepo), new(mockAuthClient), new(mockProducer)}
+	m.users.On("GetByEmail", mock.Anything, "admin@b.com").Return(&model.User{Model: gorm.Model{ID: 1}, Email: "admin@b.com"}, nil)
+	return m, usecase.NewSuspensionUsecase(m.users, m.suspensions, m.auth, m.mail)
+}
+
+func inactive(req dto.UpdateAuthUserRequest) bool { return req.IsActive != nil && !*req.IsActive }
+func active(req dto.UpdateAuthUserRequest) bool   { return req.IsActive != nil && *req.IsActive }
+
+func TestSuspendUser_DeactivatesRecordsAndNotifies(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "u@b.com", IsActive: true}
+	until := time.Now().Add(72 * time.Hour)
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(user, nil)
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(nil, nil)
+	m.users.On("Update", mock.Anything, user).Return(nil)
+	m.auth.On("UpdateUser", mock.Anything, mock.MatchedBy(inactive)).Return(nil)
+	m.suspensions.On("Create", mock.Anything, mock.AnythingOfType("*model.Suspension")).Return(nil)
+	m.mail.On("Publish", mock.Anything, "7", mock.Anything).Return(nil)
+
+	res, err := uc.SuspendUser(context.Background(), "admin@b.com", 7, dto.SuspendUserRequest{
+		Reason: constant.SuspensionReasonSpam, Note: "bulk messages to hosts", ExpiresAt: &until,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.Active)
+	assert.Equal(t, uint(1), res.SuspendedBy)
+	assert.False(t, user.IsActive)
+	saved := m.suspensions.Calls[1].Arguments.Get(1).(*model.Suspension)
+	assert.Equal(t, "bulk messages to hosts", saved.Note)
+
+	var event dto.MailEvent
+	require.NoError(t, json.Unmarshal(m.mail.Calls[0].Arguments.Get(2).([]byte), &event))
+	assert.Equal(t, constant.EventTypeAccountSuspended, event.Type)
+	assert.Equal(t, constant.SuspensionReasonSpam, event.Data["reason"])
+	assert.NotContains(t, event.Data, "note")
+	assert.Equal(t, until.UTC().Format(time.RFC3339), event.Data["suspendedUntil"])
+}
+
+func TestSuspendUser_Rejects(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cases := map[string]struct {
+		userID  uint
+		req     dto.SuspendUserRequest
+		current *model.Suspension
+		want    string
+	}{
+		"self":              {userID: 1, req: dto.SuspendUserRequest{Reason: constant.SuspensionReasonSpam}, want: constant.ErrCannotSuspendSelf},
+		"unknown reason":    {userID: 7, req: dto.SuspendUserRequest{Reason: "bored"}, want: constant.ErrInvalidSuspension},
+		"expiry in past":    {userID: 7, req: dto.SuspendUserRequest{Reason: constant.SuspensionReasonFraud, ExpiresAt: &past}, want: constant.ErrInvalidSuspension},
+		"already suspended": {userID: 7, req: dto.SuspendUserRequest{Reason: constant.SuspensionReasonFraud}, current: &model.Suspension{UserID: 7}, want: constant.ErrAlreadySuspended},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, uc := setupSuspensionUsecase()
+			m.users.On("GetByID", mock.Anything, uint(7)).Return(&model.User{Model: gorm.Model{ID: 7}, IsActive: true}, nil)
+			m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(tc.current, nil)
+
+			_, err := uc.SuspendUser(context.Background(), "admin@b.com", tc.userID, tc.req)
+
+			assert.EqualError(t, err, tc.want)
+			m.auth.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+			m.suspensions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSuspendUser_AuthFailureRecordsNothing(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	user := &model.User{Model: gorm.Model{ID: 7}, IsActive: true}
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(user, nil)
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(nil, nil)
+	m.users.On("Update", mock.Anything, user).Return(nil)
+	m.auth.On("UpdateUser", mock.Anything, mock.Anything).Return(errors.New("auth down"))
+
+	_, err := uc.SuspendUser(context.Background(), "admin@b.com", 7, dto.SuspendUserRequest{Reason: constant.SuspensionReasonOther})
+
+	assert.EqualError(t, err, constant.ErrUpdateFailed)
+	m.suspensions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestLiftSuspension_ReactivatesAndRecordsActor(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "u@b.com"}
+	current := &model.Suspension{Model: gorm.Model{ID: 3}, UserID: 7, Reason: constant.SuspensionReasonFraud, SuspendedBy: 1}
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(user, nil)
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(current, nil)
+	m.users.On("Update", mock.Anything, user).Return(nil)
+	m.auth.On("UpdateUser", mock.Anything, mock.MatchedBy(active)).Return(nil)
+	m.suspensions.On("Update", mock.Anything, current).Return(nil)
+	m.mail.On("Publish", mock.Anything, "7", mock.Anything).Return(nil)
+
+	res, err := uc.LiftSuspension(context.Background(), "admin@b.com", 7, dto.LiftSuspensionRequest{Note: "appeal accepted"})
+
+	require.NoError(t, err)
+	assert.False(t, res.Active)
+	assert.Equal(t, uint(1), res.LiftedBy)
+	assert.Equal(t, "appeal accepted", res.LiftNote)
+	assert.True(t, user.IsActive)
+}
+
+func TestLiftSuspension_NotSuspended(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(&model.User{Model: gorm.Model{ID: 7}}, nil)
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(nil, nil)
+
+	_, err := uc.LiftSuspension(context.Background(), "admin@b.com", 7, dto.LiftSuspensionRequest{})
+
+	assert.EqualError(t, err, constant.ErrNotSuspended)
+}
+
+func TestLiftExpired_KeepsFailuresForNextRound(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	ok := &model.User{Model: gorm.Model{ID: 7}, Email: "u@b.com"}
+	broken := &model.User{Model: gorm.Model{ID: 8}, Email: "v@b.com"}
+	m.suspensions.On("ListExpired", mock.Anything, mock.Anything, constant.SuspensionBatchSize).Return([]model.Suspension{
+		{Model: gorm.Model{ID: 3}, UserID: 7},
+		{Model: gorm.Model{ID: 4}, UserID: 8},
+	}, nil)
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(ok, nil)
+	m.users.On("GetByID", mock.Anything, uint(8)).Return(broken, nil)
+	m.users.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.auth.On("UpdateUser", mock.Anything, mock.MatchedBy(func(r dto.UpdateAuthUserRequest) bool { return r.UserID == 7 })).Return(nil)
+	m.auth.On("UpdateUser", mock.Anything, mock.MatchedBy(func(r dto.UpdateAuthUserRequest) bool { return r.UserID == 8 })).Return(errors.New("auth down"))
+	m.suspensions.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.mail.On("Publish", mock.Anything, "7", mock.Anything).Return(nil)
+
+	assert.Equal(t, 1, uc.LiftExpired(context.Background()))
+
+	m.suspensions.AssertNumberOfCalls(t, "Update", 1)
+	lifted := m.suspensions.Calls[1].Arguments.Get(1).(*model.Suspension)
+	assert.Equal(t, uint(3), lifted.ID)
+	assert.NotNil(t, lifted.LiftedAt)
+	assert.Zero(t, lifted.LiftedBy)
+}
+
+func TestLiftExpired_DeletedAccountStaysInactive(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	erased := &model.User{Model: gorm.Model{ID: 7}, Email: userdata.DeletedEmail(7)}
+	m.suspensions.On("ListExpired", mock.Anything, mock.Anything, constant.SuspensionBatchSize).Return([]model.Suspension{
+		{Model: gorm.Model{ID: 3}, UserID: 7},
+	}, nil)
+	m.users.On("GetByID", mock.Anything, uint(7)).Return(erased, nil)
+	m.suspensions.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	assert.Equal(t, 1, uc.LiftExpired(context.Background()))
+
+	assert.False(t, erased.IsActive)
+	m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	m.auth.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	m.mail.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	closed := m.suspensions.Calls[1].Arguments.Get(1).(*model.Suspension)
+	assert.NotNil(t, closed.LiftedAt)
+	assert.Equal(t, constant.SuspensionNoteAccountDeleted, closed.LiftNote)
+}
+
+func TestEraseSuspensions_ClosesActiveWithoutReactivating(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	current := &model.Suspension{Model: gorm.Model{ID: 3}, UserID: 7, Reason: constant.SuspensionReasonFraud, SuspendedBy: 1}
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(current, nil)
+	m.suspensions.On("Update", mock.Anything, current).Return(nil)
+
+	require.NoError(t, uc.EraseSuspensions(context.Background(), 7))
+
+	assert.False(t, current.Active())
+	assert.Equal(t, constant.SuspensionNoteAccountDeleted, current.LiftNote)
+	m.users.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	m.auth.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func TestEraseSuspensions_NotSuspended(t *testing.T) {
+	m, uc := setupSuspensionUsecase()
+	m.suspensions.On("GetActive", mock.Anything, uint(7)).Return(nil, nil)
+
+	require.NoError(t, uc.EraseSuspensions(context.Background(), 7))
+	m.suspensions.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"packages/userdata"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+)
+
+// ExportUserData returns the profile of the user for their personal data export.
+func (u *userUsecase) ExportUserData(ctx context.Context, id uint) (*dto.UserDetailResponse, error) {
+	return u.GetUserByID(ctx, id)
+}
+
+// EraseUserData anonymises the profile of a deleted account. The row stays, since bookings and
+// payments elsewhere still point at the user ID. Erasing an unknown or already erased user is a
+// no-op, so auth-service can retry freely.
+func (u *userUsecase) EraseUserData(ctx context.Context, id uint) error {
+	user, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == constant.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	oldKey, oldThumbKey := user.AvatarKey, user.AvatarThumbKey
+	user.Name = userdata.DeletedName
+	user.Email = userdata.DeletedEmail(user.ID)
+	user.Phone = ""
+	user.PhoneVerified = false
+	user.IsActive = false
+	user.AvatarKey, user.AvatarThumbKey = "", ""
+	if err := u.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	u.deleteObjects(ctx, oldKey, oldThumbKey)
+	return nil
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/constant"
+	"user-service/internal/dto"
+	"user-service/internal/model"
+)
+
+const (
+	defaultSearchSort  = "created_at"
+	defaultSearchOrder = "desc"
+	defaultSearchLimit = 20
+)
+
+// SearchUsers finds users for moderators. Pages are chained with an opaque cursor holding the
+// sort value and ID of the last user returned, so it is only valid for the same sort and order.
+func (u *userUsecase) SearchUsers(ctx context.Context, req dto.SearchUsersRequest) (*dto.SearchUsersResponse, error) {
+	filter, err := newUserSearchFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	users, err := u.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SearchUsersResponse{Users: make([]dto.UserSummary, 0, min(len(users), limit))}
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[limit-1]
+		order := "asc"
+		if filter.Desc {
+			order = "desc"
+		}
+		res.NextCursor, err = encodeUserCursor(dto.UserCursor{
+			Sort:  filter.Sort,
+			Order: order,
+			Value: userSortValue(&last, filter.Sort),
+			ID:    last.ID,
+		})
+		if err != nil {
+			return nil, errors.New(constant.ErrInternalServer)
+		}
+	}
+	for _, user := range users {
+		res.Users = append(res.Users, dto.UserSummary{
+			ID:         user.ID,
+			Email:      user.Email,
+			Name:       user.Name,
+			Phone:      user.Phone,
+			Role:       user.Role,
+			IsActive:   user.IsActive,
+			IsVerified: user.IsVerified,
+			CreatedAt:  user.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// exportColumns is the header of a user export. Its email, name and role columns are those read
+// by the user import of auth-service.
+var exportColumns = []string{"id", "email", "name", "phone", "role", "is_active", "is_verified", "created_at"}
+
+// ExportUsers writes every user matching the filters of req to w as CSV, in the order of the
+// search. The limit and cursor of req are ignored; users are read in batches, walking the same
+// cursor as the search, so exports of any size use little memory.
+func (u *userUsecase) ExportUsers(ctx context.Context, req dto.SearchUsersRequest, w io.Writer) error {
+	req.Limit = constant.UserExportBatchSize
+	req.Cursor = ""
+	filter, err := newUserSearchFilter(req)
+	if err != nil {
+		return err
+	}
+
+	// nothing is written before the first batch is read, so a failing query can still be answered
+	users, err := u.repo.Search(ctx, filter)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	for {
+		for i := range users {
+			user := &users[i]
+			if err := cw.Write([]string{
+				strconv.FormatUint(uint64(user.ID), 10),
+				user.Email,
+				csvSafe(user.Name),
+				user.Phone,
+				user.Role,
+				strconv.FormatBool(user.IsActive),
+				strconv.FormatBool(user.IsVerified),
+				user.CreatedAt.UTC().Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+		}
+		if len(users) < filter.Limit {
+			break
+		}
+		last := users[len(users)-1]
+		filter.After = &dto.UserCursor{Sort: filter.Sort, Value: userSortValue(&last, filter.Sort), ID: last.ID}
+		if users, err = u.repo.Search(ctx, filter); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe keeps a spreadsheet from running a name typed by a user as a formula.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func newUserSearchFilter(req dto.SearchUsersRequest) (dto.UserSearchFilter, error) {
+	if req.Sort == "" {
+		req.Sort = defaultSearchSort
+	}
+	if req.Order == "" {
+		req.Order = defaultSearchOrder
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSearchLimit
+	}
+
+	filter := dto.UserSearchFilter{
+		Query:       req.Query,
+		Role:        req.Role,
+		IsActive:    req.IsActive,
+		IsVerified:  req.IsVerified,
+		CreatedFrom: req.CreatedFrom,
+		Sort:        req.Sort,
+		Desc:        req.Order == "desc",
+		Limit:       req.Limit,
+	}
+	// created_to names the last day included
+	if !req.CreatedTo.IsZero() {
+		filter.CreatedTo = req.CreatedTo.AddDate(0, 0, 1)
+		if !req.CreatedFrom.IsZero() && !req.CreatedFrom.Before(filter.CreatedTo) {
+			return filter, errors.New(constant.ErrInvalidSearchParam)
+		}
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeUserCursor(req.Cursor)
+		if err != nil || cursor.Sort != req.Sort || cursor.Order != req.Order {
+			return filter, errors.New(constant.ErrInvalidCursor)
+		}
+		filter.After = cursor
+	}
+	return filter, nil
+}
+
+func userSortValue(user *model.User, sort string) string {
+	switch sort {
+	case "email":
+		return user.Email
+	case "name":
+		return user.Name
+	case "role":
+		return user.Role
+	case "is_active":
+		return strconv.FormatBool(user.IsActive)
+	case "is_verified":
+		return strconv.FormatBool(user.IsVerified)
+	default:
+		return user.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+func encodeUserCursor(cursor dto.UserCursor) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeUserCursor(s string) (*dto.UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor dto.UserCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"io"
+	"packages/policy"
+	"packages/storage"
 	"user-service/internal/constant"
 	"user-service/internal/dto"
 	"user-service/internal/model"
@@ -14,16 +17,26 @@ type UserUsecase interface {
 	GetProfile(ctx context.Context, email string) (*dto.GetProfileResponse, error)
 	UpdateProfile(ctx context.Context, email string, req *dto.UpdateProfileRequest) (*dto.UpdateProfileResponse, error)
 	GetUserList(ctx context.Context, page int, limit int) (*dto.UserListResponse, error)
-	GetUserByID(ctx context.Context, id uint) (*model.User, error)
+	GetUserByID(ctx context.Context, id uint) (*dto.UserDetailResponse, error)
 	UpdateUser(ctx context.Context, req dto.UpdateUserRequest, userID uint) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*dto.CreateUserResponse, error)
+	DeleteUser(ctx context.Context, id uint) error
+	ChangeEmail(ctx context.Context, id uint, email string) error
+	SearchUsers(ctx context.Context, req dto.SearchUsersRequest) (*dto.SearchUsersResponse, error)
+	ExportUsers(ctx context.Context, req dto.SearchUsersRequest, w io.Writer) error
+	MarkVerified(ctx context.Context, id uint) error
+	UploadAvatar(ctx context.Context, email string, data []byte) (*dto.AvatarResponse, error)
+	ExportUserData(ctx context.Context, id uint) (*dto.UserDetailResponse, error)
+	EraseUserData(ctx context.Context, id uint) error
 }
 type userUsecase struct {
 	repo       repository.UserRepository
 	authClient repository.AuthClient
+	storage    storage.Storage
 }
 
-func NewUserUsecase(repo repository.UserRepository, authClient repository.AuthClient) UserUsecase {
-	return &userUsecase{repo: repo, authClient: authClient}
+func NewUserUsecase(repo repository.UserRepository, authClient repository.AuthClient, store storage.Storage) UserUsecase {
+	return &userUsecase{repo: repo, authClient: authClient, storage: store}
 }
 
 func (uc *userUsecase) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.CreateUserResponse, error) {
@@ -59,12 +72,19 @@ func (uc *userUsecase) GetProfile(ctx context.Context, email string) (*dto.GetPr
 		return nil, err
 	}
 
+	avatarURL, thumbURL, err := uc.avatarURLs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
 	return &dto.GetProfileResponse{
-		ID:       user.ID,
-		Name:     user.Name,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		IsActive: user.IsActive,
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Phone:          user.Phone,
+		PhoneVerified:  user.PhoneVerified,
+		IsActive:       user.IsActive,
+		AvatarURL:      avatarURL,
+		AvatarThumbURL: thumbURL,
 	}, nil
 }
 
@@ -75,16 +95,27 @@ func (uc *userUsecase) UpdateProfile(ctx context.Context, email string, req *dto
 	}
 
 	user.Name = req.Name
-	user.Phone = req.Phone
+	// a new number has to be verified again before SMS goes to it
+	if req.Phone != user.Phone {
+		user.Phone = req.Phone
+		user.PhoneVerified = false
+	}
 
 	if err := uc.repo.Update(ctx, user); err != nil {
 		return nil, errors.New(constant.ErrUpdateFailed)
 	}
 
+	avatarURL, thumbURL, err := uc.avatarURLs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
 	return &dto.UpdateProfileResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Phone: user.Phone,
+		ID:             user.ID,
+		Name:           user.Name,
+		Phone:          user.Phone,
+		PhoneVerified:  user.PhoneVerified,
+		AvatarURL:      avatarURL,
+		AvatarThumbURL: thumbURL,
 	}, nil
 }
 
@@ -103,12 +134,16 @@ func (uc *userUsecase) GetUserList(ctx context.Context, page int, limit int) (*d
 	}, nil
 }
 
-func (u *userUsecase) GetUserByID(ctx context.Context, id uint) (*model.User, error) {
+func (u *userUsecase) GetUserByID(ctx context.Context, id uint) (*dto.UserDetailResponse, error) {
 	user, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return user, nil
+	avatarURL, thumbURL, err := u.avatarURLs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserDetailResponse{User: *user, AvatarURL: avatarURL, AvatarThumbURL: thumbURL}, nil
 }
 
 func (u *userUsecase) UpdateUser(ctx context.Context, req dto.UpdateUserRequest, userID uint) (*model.User, error) {
@@ -122,12 +157,7 @@ func (u *userUsecase) UpdateUser(ctx context.Context, req dto.UpdateUserRequest,
 	}
 
 	if req.Role != nil {
-		validRoles := map[string]bool{
-			constant.RoleUser:      true,
-			constant.RoleModerator: true,
-			constant.RoleAdmin:     true,
-		}
-		if !validRoles[*req.Role] {
+		if !policy.IsGlobalRole(*req.Role) {
 			return nil, errors.New(constant.ErrInvalidRole)
 		}
 		user.Role = *req.Role
@@ -149,3 +179,68 @@ func (u *userUsecase) UpdateUser(ctx context.Context, req dto.UpdateUserRequest,
 
 	return user, nil
 }
+
+// GetUserByEmail lets auth-service pick up a profile left behind by an unfinished sign-up.
+func (u *userUsecase) GetUserByEmail(ctx context.Context, email string) (*dto.CreateUserResponse, error) {
+	user, err := currentUser(ctx, u.repo, email)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CreateUserResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		Name:  user.Name,
+		Role:  user.Role,
+	}, nil
+}
+
+// DeleteUser is the compensation auth-service runs when a sign-up fails after the profile was created.
+func (u *userUsecase) DeleteUser(ctx context.Context, id uint) error {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, id)
+}
+
+// ChangeEmail is called by auth-service once the user confirmed a new address, and again with
+// the old address when the change is reverted. Setting the current address again is a no-op.
+func (u *userUsecase) ChangeEmail(ctx context.Context, id uint, email string) error {
+	user, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Email == email {
+		return nil
+	}
+
+	exists, err := u.repo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return errors.New(constant.ErrDatabase)
+	}
+	if exists {
+		return errors.New(constant.ErrEmailAlreadyExists)
+	}
+
+	user.Email = email
+	return u.repo.Update(ctx, user)
+}
+
+// MarkVerified is called by auth-service once the user verified their email.
+func (u *userUsecase) MarkVerified(ctx context.Context, id uint) error {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return u.repo.MarkVerified(ctx, id)
+}
+
+// currentUser returns the user with the email, the one a request was authenticated as.
+func currentUser(ctx context.Context, users repository.UserRepository, email string) (*model.User, error) {
+	user, err := users.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(constant.ErrUserNotFound)
+	}
+	return user, nil
+}
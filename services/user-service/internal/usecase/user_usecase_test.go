@@ -1,9 +1,15 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	"image/png"
+	"packages/userdata"
+	"strings"
 	"testing"
+	"time"
 	"user-service/internal/constant"
 	"user-service/internal/dto"
 	"user-service/internal/model"
@@ -49,6 +55,20 @@ func (m *mockUserRepo) GetUserList(ctx context.Context, offset, limit int) ([]mo
 	return nil, 0, args.Error(2)
 }
 
+func (m *mockUserRepo) Delete(ctx context.Context, id uint) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockUserRepo) Search(ctx context.Context, filter dto.UserSearchFilter) ([]model.User, error) {
+	args := m.Called(ctx, filter)
+	if u, ok := args.Get(0).([]model.User); ok {
+		return u, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockUserRepo) MarkVerified(ctx context.Context, id uint) error {
+	return m.Called(ctx, id).Error(0)
+}
+
 // ===== Mock AuthClient =====
 type mockAuthClient struct{ mock.Mock }
 
@@ -56,12 +76,26 @@ func (m *mockAuthClient) UpdateUser(ctx context.Context, req dto.UpdateAuthUserR
 	return m.Called(ctx, req).Error(0)
 }
 
+// ===== Mock Storage =====
+type mockStorage struct{ mock.Mock }
+
+func (m *mockStorage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	return m.Called(ctx, key, contentType, data).Error(0)
+}
+func (m *mockStorage) Delete(ctx context.Context, key string) error {
+	return m.Called(ctx, key).Error(0)
+}
+func (m *mockStorage) URL(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
 // ====== TESTS ======
 
 func TestCreateUser_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	req := dto.CreateUserRequest{Email: "test@example.com", Name: "John", Role: constant.RoleUser}
 
@@ -78,7 +112,7 @@ func TestCreateUser_Success(t *testing.T) {
 func TestCreateUser_EmailExists(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	req := dto.CreateUserRequest{Email: "dup@example.com", Name: "Dup", Role: constant.RoleUser}
 
@@ -94,7 +128,7 @@ func TestCreateUser_EmailExists(t *testing.T) {
 func TestGetProfile_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "john@example.com", Name: "John", Phone: "12345", IsActive: true}
 	repo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
@@ -109,7 +143,7 @@ func TestGetProfile_Success(t *testing.T) {
 func TestGetProfile_NotFound(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	repo.On("GetByEmail", mock.Anything, "notfound@example.com").Return(nil, errors.New(constant.ErrUserNotFound))
 
@@ -122,7 +156,7 @@ func TestGetProfile_NotFound(t *testing.T) {
 func TestUpdateProfile_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	email := "john@example.com"
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: email, Name: "John"}
@@ -141,7 +175,7 @@ func TestUpdateProfile_Success(t *testing.T) {
 func TestUpdateProfile_UpdateFail(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	email := "john@example.com"
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: email, Name: "John"}
@@ -160,7 +194,7 @@ func TestUpdateProfile_UpdateFail(t *testing.T) {
 func TestGetUserList_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	users := []model.User{{Model: gorm.Model{ID: 1}, Email: "a@example.com"}}
 	repo.On("GetUserList", mock.Anything, 0, 10).Return(users, 1, nil)
@@ -175,7 +209,7 @@ func TestGetUserList_Success(t *testing.T) {
 func TestGetUserList_Error(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	repo.On("GetUserList", mock.Anything, 0, 10).Return(nil, 0, errors.New("db error"))
 
@@ -189,7 +223,7 @@ func TestGetUserList_Error(t *testing.T) {
 func TestGetUserByID_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com"}
 	repo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
@@ -203,7 +237,7 @@ func TestGetUserByID_Success(t *testing.T) {
 func TestGetUserByID_NotFound(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	repo.On("GetByID", mock.Anything, uint(99)).Return(nil, errors.New(constant.ErrUserNotFound))
 
@@ -216,7 +250,7 @@ func TestGetUserByID_NotFound(t *testing.T) {
 func TestUpdateUser_Success(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Role: constant.RoleUser, IsActive: true}
 	req := dto.UpdateUserRequest{Role: ptrString(constant.RoleAdmin), IsActive: ptrBool(false)}
@@ -235,7 +269,7 @@ func TestUpdateUser_Success(t *testing.T) {
 func TestUpdateUser_InvalidRole(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Role: constant.RoleUser}
 	req := dto.UpdateUserRequest{Role: ptrString("superman")}
@@ -252,7 +286,7 @@ func TestUpdateUser_InvalidRole(t *testing.T) {
 func TestUpdateUser_RepoUpdateFail(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Role: constant.RoleUser}
 	req := dto.UpdateUserRequest{Role: ptrString(constant.RoleAdmin)}
@@ -270,7 +304,7 @@ func TestUpdateUser_RepoUpdateFail(t *testing.T) {
 func TestUpdateUser_AuthUpdateFail(t *testing.T) {
 	repo := new(mockUserRepo)
 	authClient := new(mockAuthClient)
-	uc := usecase.NewUserUsecase(repo, authClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
 
 	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Role: constant.RoleUser}
 	req := dto.UpdateUserRequest{Role: ptrString(constant.RoleAdmin)}
@@ -289,3 +323,364 @@ func TestUpdateUser_AuthUpdateFail(t *testing.T) {
 // ===== Helpers =====
 func ptrString(s string) *string { return &s }
 func ptrBool(b bool) *bool       { return &b }
+
+func TestGetUserByEmail_Success(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	user := &model.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Role: constant.RoleUser}
+	repo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+
+	res, err := uc.GetUserByEmail(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), res.ID)
+}
+
+func TestGetUserByEmail_NotFound(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, nil)
+
+	res, err := uc.GetUserByEmail(context.Background(), "test@example.com")
+
+	assert.EqualError(t, err, constant.ErrUserNotFound)
+	assert.Nil(t, res)
+}
+
+func TestDeleteUser_Success(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}}, nil)
+	repo.On("Delete", mock.Anything, uint(1)).Return(nil)
+
+	err := uc.DeleteUser(context.Background(), 1)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(99)).Return(nil, errors.New(constant.ErrUserNotFound))
+
+	err := uc.DeleteUser(context.Background(), 99)
+
+	assert.EqualError(t, err, constant.ErrUserNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestChangeEmail_Success(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	user := &model.User{Model: gorm.Model{ID: 1}, Email: "old@example.com"}
+	repo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	repo.On("ExistsByEmail", mock.Anything, "new@example.com").Return(false, nil)
+	repo.On("Update", mock.Anything, user).Return(nil)
+
+	err := uc.ChangeEmail(context.Background(), 1, "new@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+	repo.AssertExpectations(t)
+}
+
+func TestChangeEmail_SameEmail_IsNoop(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Email: "new@example.com"}, nil)
+
+	err := uc.ChangeEmail(context.Background(), 1, "new@example.com")
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestChangeEmail_EmailTaken(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Email: "old@example.com"}, nil)
+	repo.On("ExistsByEmail", mock.Anything, "taken@example.com").Return(true, nil)
+
+	err := uc.ChangeEmail(context.Background(), 1, "taken@example.com")
+
+	assert.EqualError(t, err, constant.ErrEmailAlreadyExists)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSearchUsers_PagesWithCursor(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	users := []model.User{
+		{Model: gorm.Model{ID: 3}, Email: "c@example.com", Name: "Carol", IsVerified: true},
+		{Model: gorm.Model{ID: 2}, Email: "b@example.com", Name: "Bob"},
+		{Model: gorm.Model{ID: 1}, Email: "a@example.com", Name: "Alice"},
+	}
+	repo.On("Search", mock.Anything, mock.MatchedBy(func(f dto.UserSearchFilter) bool {
+		return f.After == nil && f.Sort == "name" && f.Desc && f.Limit == 3
+	})).Return(users, nil)
+
+	res, err := uc.SearchUsers(context.Background(), dto.SearchUsersRequest{Sort: "name", Order: "desc", Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Users, 2)
+	assert.Equal(t, "c@example.com", res.Users[0].Email)
+	assert.True(t, res.Users[0].IsVerified)
+	assert.NotEmpty(t, res.NextCursor)
+
+	repo.On("Search", mock.Anything, mock.MatchedBy(func(f dto.UserSearchFilter) bool {
+		return f.After != nil && f.After.Value == "Bob" && f.After.ID == 2
+	})).Return(users[2:], nil)
+
+	res, err = uc.SearchUsers(context.Background(), dto.SearchUsersRequest{Sort: "name", Order: "desc", Limit: 2, Cursor: res.NextCursor})
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Users, 1)
+	assert.Empty(t, res.NextCursor)
+	repo.AssertExpectations(t)
+}
+
+func TestSearchUsers_Defaults(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	repo.On("Search", mock.Anything, mock.MatchedBy(func(f dto.UserSearchFilter) bool {
+		return f.Sort == "created_at" && f.Desc && f.Limit == 21 && f.CreatedTo.Equal(to.AddDate(0, 0, 1))
+	})).Return([]model.User{}, nil)
+
+	res, err := uc.SearchUsers(context.Background(), dto.SearchUsersRequest{CreatedTo: to})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, res.Users)
+	assert.Empty(t, res.NextCursor)
+	repo.AssertExpectations(t)
+}
+
+func TestExportUsers_WritesEveryBatch(t *testing.T) {
+	repo := new(mockUserRepo)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), new(mockStorage))
+
+	created := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	first := make([]model.User, constant.UserExportBatchSize)
+	for i := range first {
+		first[i] = model.User{Model: gorm.Model{ID: uint(i + 10), CreatedAt: created}, Email: "u@example.com", Name: "User", Role: "user"}
+	}
+	first[0].Name = "=HYPERLINK(\"x\")"
+	repo.On("Search", mock.Anything, mock.MatchedBy(func(f dto.UserSearchFilter) bool {
+		return f.After == nil && f.Role == "user" && f.Sort == "email" && !f.Desc && f.Limit == constant.UserExportBatchSize
+	})).Return(first, nil).Once()
+	repo.On("Search", mock.Anything, mock.MatchedBy(func(f dto.UserSearchFilter) bool {
+		return f.After != nil && f.After.ID == uint(constant.UserExportBatchSize+9)
+	})).Return([]model.User{
+		{Model: gorm.Model{ID: 3, CreatedAt: created}, Email: "z@example.com", Name: "Zoe", Phone: "+84901234567", Role: "user", IsActive: true},
+	}, nil).Once()
+
+	var out bytes.Buffer
+	// the cursor and limit of a search page do not apply to an export
+	err := uc.ExportUsers(context.Background(), dto.SearchUsersRequest{Role: "user", Sort: "email", Order: "asc", Limit: 5, Cursor: "ignored"}, &out)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, constant.UserExportBatchSize+2)
+	assert.Equal(t, "id,email,name,phone,role,is_active,is_verified,created_at", lines[0])
+	assert.Equal(t, `10,u@example.com,"'=HYPERLINK(""x"")",,user,false,false,2025-03-01T08:00:00Z`, lines[1])
+	assert.Equal(t, "3,z@example.com,Zoe,+84901234567,user,true,false,2025-03-01T08:00:00Z", lines[len(lines)-1])
+	repo.AssertExpectations(t)
+}
+
+func TestExportUsers_FailingQueryWritesNothing(t *testing.T) {
+	repo := new(mockUserRepo)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), new(mockStorage))
+	repo.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New(constant.ErrDatabase))
+
+	var out bytes.Buffer
+	err := uc.ExportUsers(context.Background(), dto.SearchUsersRequest{}, &out)
+
+	assert.EqualError(t, err, constant.ErrDatabase)
+	assert.Zero(t, out.Len())
+}
+
+func TestSearchUsers_RejectsBadInput(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("Search", mock.Anything, mock.Anything).Return([]model.User{
+		{Model: gorm.Model{ID: 2}, Email: "b@example.com"},
+		{Model: gorm.Model{ID: 1}, Email: "a@example.com"},
+	}, nil).Once()
+	res, err := uc.SearchUsers(context.Background(), dto.SearchUsersRequest{Sort: "email", Limit: 1})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		req  dto.SearchUsersRequest
+		want string
+	}{
+		{"cursor of another sort", dto.SearchUsersRequest{Sort: "name", Limit: 1, Cursor: res.NextCursor}, constant.ErrInvalidCursor},
+		{"cursor of another order", dto.SearchUsersRequest{Sort: "email", Order: "asc", Limit: 1, Cursor: res.NextCursor}, constant.ErrInvalidCursor},
+		{"garbled cursor", dto.SearchUsersRequest{Cursor: "not a cursor"}, constant.ErrInvalidCursor},
+		{"empty date range", dto.SearchUsersRequest{
+			CreatedFrom: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+			CreatedTo:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		}, constant.ErrInvalidSearchParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.SearchUsers(context.Background(), tt.req)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+	repo.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestMarkVerified_NotFound(t *testing.T) {
+	repo := new(mockUserRepo)
+	authClient := new(mockAuthClient)
+	uc := usecase.NewUserUsecase(repo, authClient, new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New(constant.ErrUserNotFound))
+
+	err := uc.MarkVerified(context.Background(), 9)
+
+	assert.EqualError(t, err, constant.ErrUserNotFound)
+	repo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything)
+}
+
+func pngUpload(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 48))))
+	return buf.Bytes()
+}
+
+func TestUploadAvatar_ReplacesPrevious(t *testing.T) {
+	repo := new(mockUserRepo)
+	store := new(mockStorage)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), store)
+
+	user := &model.User{Model: gorm.Model{ID: 4}, Email: "a@example.com", AvatarKey: "avatars/4/old.png", AvatarThumbKey: "avatars/4/old_thumb.png"}
+	repo.On("GetByEmail", mock.Anything, "a@example.com").Return(user, nil)
+	isNewKey := func(suffix string) interface{} {
+		return mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "avatars/4/") && strings.HasSuffix(key, suffix) && !strings.Contains(key, "old")
+		})
+	}
+	store.On("Put", mock.Anything, isNewKey("_thumb.png"), "image/png", mock.Anything).Return(nil).Once()
+	store.On("Put", mock.Anything, isNewKey(".png"), "image/png", mock.Anything).Return(nil).Once()
+	repo.On("Update", mock.Anything, user).Return(nil)
+	store.On("Delete", mock.Anything, "avatars/4/old.png").Return(nil)
+	store.On("Delete", mock.Anything, "avatars/4/old_thumb.png").Return(nil)
+	store.On("URL", mock.Anything, mock.Anything).Return("https://cdn.example.com/x", nil)
+
+	res, err := uc.UploadAvatar(context.Background(), "a@example.com", pngUpload(t))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/x", res.AvatarURL)
+	assert.NotEqual(t, "avatars/4/old.png", user.AvatarKey)
+	assert.Equal(t, strings.TrimSuffix(user.AvatarKey, ".png")+"_thumb.png", user.AvatarThumbKey)
+	repo.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestUploadAvatar_UnsupportedType(t *testing.T) {
+	repo := new(mockUserRepo)
+	store := new(mockStorage)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), store)
+
+	repo.On("GetByEmail", mock.Anything, "a@example.com").Return(&model.User{Model: gorm.Model{ID: 4}}, nil)
+
+	_, err := uc.UploadAvatar(context.Background(), "a@example.com", []byte("%PDF-1.7"))
+
+	assert.EqualError(t, err, constant.ErrUnsupportedImage)
+	store.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadAvatar_UpdateFails_RemovesNewFiles(t *testing.T) {
+	repo := new(mockUserRepo)
+	store := new(mockStorage)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), store)
+
+	user := &model.User{Model: gorm.Model{ID: 4}, AvatarKey: "avatars/4/old.png", AvatarThumbKey: "avatars/4/old_thumb.png"}
+	repo.On("GetByEmail", mock.Anything, "a@example.com").Return(user, nil)
+	var stored []string
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = append(stored, args.String(1))
+	}).Return(nil)
+	repo.On("Update", mock.Anything, user).Return(errors.New(constant.ErrUpdateFailed))
+	store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := uc.UploadAvatar(context.Background(), "a@example.com", pngUpload(t))
+
+	assert.EqualError(t, err, constant.ErrUpdateFailed)
+	assert.Len(t, stored, 2)
+	for _, key := range stored {
+		store.AssertCalled(t, "Delete", mock.Anything, key)
+	}
+	store.AssertNotCalled(t, "Delete", mock.Anything, "avatars/4/old.png")
+}
+
+func TestGetProfile_WithAvatar(t *testing.T) {
+	repo := new(mockUserRepo)
+	store := new(mockStorage)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), store)
+
+	repo.On("GetByEmail", mock.Anything, "a@example.com").Return(&model.User{Email: "a@example.com", AvatarKey: "avatars/1/a.jpg", AvatarThumbKey: "avatars/1/a_thumb.jpg"}, nil)
+	store.On("URL", mock.Anything, "avatars/1/a.jpg").Return("/files/avatars/1/a.jpg", nil)
+	store.On("URL", mock.Anything, "avatars/1/a_thumb.jpg").Return("/files/avatars/1/a_thumb.jpg", nil)
+
+	res, err := uc.GetProfile(context.Background(), "a@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/avatars/1/a.jpg", res.AvatarURL)
+	assert.Equal(t, "/files/avatars/1/a_thumb.jpg", res.AvatarThumbURL)
+}
+
+func TestEraseUserData_AnonymisesProfile(t *testing.T) {
+	repo := new(mockUserRepo)
+	store := new(mockStorage)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), store)
+
+	user := &model.User{Model: gorm.Model{ID: 7}, Email: "a@example.com", Name: "Alice", Phone: "0901234567", IsActive: true, AvatarKey: "avatars/7/a.jpg", AvatarThumbKey: "avatars/7/a_thumb.jpg"}
+	repo.On("GetByID", mock.Anything, uint(7)).Return(user, nil)
+	repo.On("Update", mock.Anything, user).Return(nil)
+	store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	err := uc.EraseUserData(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userdata.DeletedName, user.Name)
+	assert.Equal(t, "deleted-7@deleted.invalid", user.Email)
+	assert.Empty(t, user.Phone)
+	assert.False(t, user.IsActive)
+	assert.Empty(t, user.AvatarKey)
+	store.AssertCalled(t, "Delete", mock.Anything, "avatars/7/a.jpg")
+	store.AssertCalled(t, "Delete", mock.Anything, "avatars/7/a_thumb.jpg")
+}
+
+func TestEraseUserData_UnknownUser_NoOp(t *testing.T) {
+	repo := new(mockUserRepo)
+	uc := usecase.NewUserUsecase(repo, new(mockAuthClient), new(mockStorage))
+
+	repo.On("GetByID", mock.Anything, uint(7)).Return(nil, errors.New(constant.ErrUserNotFound))
+
+	assert.NoError(t, uc.EraseUserData(context.Background(), 7))
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"packages/imaging"
+)
+
+const (
+	MaxAvatarBytes = 5 << 20
+	// decoding is refused past this many pixels, so a small file cannot claim a huge canvas
+	maxAvatarPixels = 25_000_000
+
+	AvatarSize      = 512
+	AvatarThumbSize = 128
+
+	jpegQuality = 85
+)
+
+var (
+	ErrImageTooLarge    = errors.New("image too large")
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrInvalidImage     = errors.New("invalid image")
+)
+
+// Avatar is an uploaded picture cropped to a square and re-encoded at two sizes.
+type Avatar struct {
+	ContentType string
+	Ext         string
+	Image       []byte
+	Thumb       []byte
+}
+
+// ProcessAvatar accepts JPEG and PNG pictures. It crops them to a centered square, turns them
+// upright as their EXIF orientation says, then re-encodes them. Re-encoding keeps pixels only,
+// so EXIF data such as GPS position or camera serial never leaves the upload.
+func ProcessAvatar(data []byte) (*Avatar, error) {
+	if len(data) > MaxAvatarBytes {
+		return nil, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// cropping and scaling a centered square commute with rotations, so the orientation is
+	// applied to the small result rather than the full picture
+	square := squareThumbnail(img, AvatarSize)
+	if contentType == "image/jpeg" {
+		square = imaging.Orient(square, imaging.JPEGOrientation(data))
+	}
+	thumb := squareThumbnail(square, AvatarThumbSize)
+
+	avatar := &Avatar{ContentType: contentType}
+	encode := func(m image.Image) ([]byte, error) {
+		var buf bytes.Buffer
+		var err error
+		if contentType == "image/png" {
+			err = png.Encode(&buf, m)
+		} else {
+			err = jpeg.Encode(&buf, m, &jpeg.Options{Quality: jpegQuality})
+		}
+		return buf.Bytes(), err
+	}
+	if avatar.Image, err = encode(square); err != nil {
+		return nil, err
+	}
+	if avatar.Thumb, err = encode(thumb); err != nil {
+		return nil, err
+	}
+	avatar.Ext = ".jpg"
+	if contentType == "image/png" {
+		avatar.Ext = ".png"
+	}
+	return avatar, nil
+}
+
+// squareThumbnail crops the centered square of img and scales it down to size, averaging the
+// source pixels under each target pixel. Smaller pictures are only cropped.
+func squareThumbnail(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	origin := image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2)
+
+	src := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(src, src.Bounds(), img, origin, draw.Src)
+	if side <= size {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		y0, y1 := dy*side/size, (dy+1)*side/size
+		for dx := 0; dx < size; dx++ {
+			x0, x1 := dx*side/size, (dx+1)*side/size
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride+x0*4 : y*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			o := dy*dst.Stride + dx*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[o+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"packages/imaging/imagingtest"
+	"testing"
+	"user-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessAvatar_CropsScalesAndStripsEXIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, imagingtest.Halves(1600, 800), &jpeg.Options{Quality: 95}))
+	upload := imagingtest.WithOrientation(buf.Bytes(), 6) // stored rotated, must be turned clockwise
+
+	avatar, err := utils.ProcessAvatar(upload)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", avatar.ContentType)
+	assert.Equal(t, ".jpg", avatar.Ext)
+	assert.NotContains(t, string(avatar.Image), "Exif")
+
+	img, err := jpeg.Decode(bytes.NewReader(avatar.Image))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, utils.AvatarSize, utils.AvatarSize), img.Bounds())
+	// the red left half ends up on top once turned upright
+	assert.True(t, imagingtest.IsRed(img.At(utils.AvatarSize/2, 10)))
+	assert.False(t, imagingtest.IsRed(img.At(utils.AvatarSize/2, utils.AvatarSize-10)))
+
+	thumb, err := jpeg.Decode(bytes.NewReader(avatar.Thumb))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, utils.AvatarThumbSize, utils.AvatarThumbSize), thumb.Bounds())
+}
+
+func TestProcessAvatar_KeepsSmallPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, imagingtest.Halves(60, 40)))
+
+	avatar, err := utils.ProcessAvatar(buf.Bytes())
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", avatar.ContentType)
+	img, err := png.Decode(bytes.NewReader(avatar.Image))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 40), img.Bounds(), "only cropped, never enlarged")
+}
+
+func TestProcessAvatar_Rejects(t *testing.T) {
+	_, err := utils.ProcessAvatar([]byte("GIF89a not accepted"))
+	assert.ErrorIs(t, err, utils.ErrUnsupportedImage)
+
+	_, err = utils.ProcessAvatar(append([]byte{0xFF, 0xD8, 0xFF}, make([]byte, 100)...))
+	assert.ErrorIs(t, err, utils.ErrInvalidImage)
+
+	_, err = utils.ProcessAvatar(make([]byte, utils.MaxAvatarBytes+1))
+	assert.ErrorIs(t, err, utils.ErrImageTooLarge)
+}
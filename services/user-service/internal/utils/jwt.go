@@ -4,16 +4,20 @@ import (
 	"errors"
 	"log"
 	"os"
+	"packages/policy"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type Claims struct {
-	UserID     uint   `json:"user_id"`
-	Email      string `json:"email"`
-	Role       string `json:"role"`
-	IsActive   bool   `json:"is_active"`
-	IsVerified bool   `json:"is_verified"`
+	UserID     uint          `json:"user_id"`
+	Email      string        `json:"email"`
+	Role       string        `json:"role"`
+	IsActive   bool          `json:"is_active"`
+	IsVerified bool          `json:"is_verified"`
+	TokenType  string        `json:"token_type"`
+	SessionID  string        `json:"sid,omitempty"`
+	Act        *policy.Actor `json:"act,omitempty"` // set on impersonation tokens
 	jwt.RegisteredClaims
 }
 
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+const vietnamCode = "84"
+
+// legacyMobilePrefixes maps the 11-digit Vietnamese mobile prefixes retired in 2018 to the ones
+// that replaced them, so numbers saved in the old format still reach their owner.
+var legacyMobilePrefixes = map[string]string{
+	"120": "70", "121": "79", "122": "77", "126": "76", "128": "78",
+	"123": "83", "124": "84", "125": "85", "127": "81", "129": "82",
+	"162": "32", "163": "33", "164": "34", "165": "35", "166": "36",
+	"167": "37", "168": "38", "169": "39",
+	"186": "56", "188": "58", "199": "59",
+}
+
+// NormalizePhone returns the number in E.164 format, such as +84912345678. Numbers without a
+// country code are read as Vietnamese: 0912 345 678, 84912345678 and +84 912-345-678 are the
+// same number. Spaces, dots, dashes and parentheses are ignored.
+func NormalizePhone(raw string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	international := false
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits, international = digits[1:], true
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case strings.HasPrefix(digits, "0"):
+		digits = vietnamCode + digits[1:]
+	case strings.HasPrefix(digits, vietnamCode) && len(digits) >= 11:
+		international = true
+	default:
+		return "", ErrInvalidPhone
+	}
+	if !allDigits(digits) || digits[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	if !international && !strings.HasPrefix(digits, vietnamCode) {
+		return "", ErrInvalidPhone
+	}
+
+	if national, ok := strings.CutPrefix(digits, vietnamCode); ok {
+		national, ok = vietnameseNumber(national)
+		if !ok {
+			return "", ErrInvalidPhone
+		}
+		return "+" + vietnamCode + national, nil
+	}
+	// E.164 allows up to 15 digits, country code included
+	if len(digits) < 8 || len(digits) > 15 {
+		return "", ErrInvalidPhone
+	}
+	return "+" + digits, nil
+}
+
+// vietnameseNumber checks a national number: mobiles have 9 digits after the country code,
+// landlines 10 starting with 2.
+func vietnameseNumber(national string) (string, bool) {
+	if len(national) == 10 {
+		if prefix, ok := legacyMobilePrefixes[national[:3]]; ok {
+			national = prefix + national[3:]
+		}
+	}
+	switch {
+	case len(national) == 9 && strings.ContainsRune("35789", rune(national[0])):
+		return national, true
+	case len(national) == 10 && national[0] == '2':
+		return national, true
+	default:
+		return "", false
+	}
+}
+
+// IsMobilePhone tells whether a normalised number can receive SMS. Vietnamese landlines cannot;
+// numbers of other countries are assumed to.
+func IsMobilePhone(phone string) bool {
+	national, ok := strings.CutPrefix(phone, "+"+vietnamCode)
+	if !ok {
+		return strings.HasPrefix(phone, "+")
+	}
+	return len(national) == 9
+}
+
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
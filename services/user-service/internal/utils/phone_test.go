@@ -0,0 +1,52 @@
+package utils_test
+
+import (
+	"testing"
+	"user-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"0912345678":        "+84912345678",
+		"0912 345 678":      "+84912345678",
+		"84912345678":       "+84912345678",
+		"+84 912-345-678":   "+84912345678",
+		"0084912345678":     "+84912345678",
+		"(028) 3822 1234":   "+842838221234",
+		"01689123456":       "+84389123456", // retired 11-digit prefix
+		"+84 1689 123 456":  "+84389123456",
+		"+1 (415) 555-2671": "+14155552671",
+	}
+	for raw, want := range cases {
+		got, err := utils.NormalizePhone(raw)
+		if assert.NoError(t, err, raw) {
+			assert.Equal(t, want, got, raw)
+		}
+	}
+}
+
+func TestNormalizePhone_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"999",
+		"912345678",      // no leading 0 nor country code
+		"0112345678",     // no such Vietnamese prefix
+		"091234567",      // a digit short
+		"09123456789",    // a digit too many
+		"+84 0912345678", // trunk prefix after the country code
+		"+1234567",       // too short for E.164
+		"+1234567890123456",
+		"0912abc678",
+	} {
+		_, err := utils.NormalizePhone(raw)
+		assert.ErrorIs(t, err, utils.ErrInvalidPhone, raw)
+	}
+}
+
+func TestIsMobilePhone(t *testing.T) {
+	assert.True(t, utils.IsMobilePhone("+84912345678"))
+	assert.False(t, utils.IsMobilePhone("+842838221234"))
+	assert.True(t, utils.IsMobilePhone("+14155552671"))
+}
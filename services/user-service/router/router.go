@@ -1,12 +1,23 @@
 package router
 
 import (
+	"context"
 	"log"
 	"os"
+	"packages/impersonation"
+	"packages/policy"
+	"packages/servicetoken"
+	"packages/session"
+	"packages/storage"
+	"packages/userdata"
+	"time"
 	"user-service/db"
+	"user-service/internal/constant"
 	"user-service/internal/handler"
+	"user-service/internal/kafka"
 	"user-service/internal/middleware"
 	"user-service/internal/repository"
+	"user-service/internal/sms"
 	"user-service/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -14,27 +25,122 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(r *gin.Engine) {
+// SetupRouter registers the routes and returns the relay publishing preference changes and the
+// job lifting expired suspensions, for the caller to run. mailProducer writes to the topic read
+// by mail-service.
+func SetupRouter(r *gin.Engine, producer, mailProducer kafka.Producer) (*usecase.PreferenceRelay, *usecase.SuspensionExpirer) {
 	baseURL := os.Getenv("AUTH_SERVICE_URL")
 	if baseURL == "" {
 		log.Fatal("missing env: AUTH_SERVICE_URL")
 	}
-	authClient := repository.NewAuthClient(baseURL)
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceVerifier := servicetoken.NewVerifier(servicetoken.UserService, serviceSecret)
+	venueURL := os.Getenv("VENUE_SERVICE_URL")
+	if venueURL == "" {
+		log.Fatal("missing env: VENUE_SERVICE_URL")
+	}
+	serviceIssuer := servicetoken.NewIssuer(servicetoken.UserService, serviceSecret)
+	authClient := repository.NewAuthClient(baseURL, serviceIssuer)
+	venueClient := repository.NewVenueClient(venueURL, serviceIssuer)
+
+	// refuse the tokens of sessions revoked in auth-service
+	authHTTP := serviceIssuer.Client(servicetoken.AuthService, 5*time.Second)
+	revocations := session.NewRevocationStore(session.HTTPSource(baseURL, authHTTP))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	middleware.TrackRevocations(revocations)
+	// record impersonated requests in auth-service before serving them
+	middleware.RecordImpersonations(impersonation.NewRecorder(baseURL, authHTTP))
+	store, err := storage.NewFromEnv(constant.EnvAvatarPrefix, constant.AvatarRoute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	smsProvider, err := sms.NewFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
 	userRepo := repository.NewUserRepository(db.DB)
-	userUC := usecase.NewUserUsecase(userRepo, authClient)
-	userHandler := handler.NewUserHandler(userUC)
+	prefRepo := repository.NewPreferenceRepository(db.DB)
+	userUC := usecase.NewUserUsecase(userRepo, authClient, store)
+	orgRepo := repository.NewOrganizationRepository(db.DB)
+	phoneRepo := repository.NewPhoneVerificationRepository(db.DB)
+	suspensionRepo := repository.NewSuspensionRepository(db.DB)
+	favRepo := repository.NewFavoriteRepository(db.DB)
+	prefUC := usecase.NewPreferenceUsecase(userRepo, prefRepo)
+	orgUC := usecase.NewOrganizationUsecase(userRepo, orgRepo, mailProducer)
+	phoneUC := usecase.NewPhoneUsecase(userRepo, prefRepo, phoneRepo, smsProvider)
+	suspensionUC := usecase.NewSuspensionUsecase(userRepo, suspensionRepo, authClient, mailProducer)
+	favUC := usecase.NewFavoriteUsecase(userRepo, favRepo, venueClient)
+	userHandler := handler.NewUserHandler(userUC, prefUC, orgUC, phoneUC, suspensionUC, favUC)
+	orgHandler := handler.NewOrganizationHandler(orgUC)
+	suspensionHandler := handler.NewSuspensionHandler(suspensionUC)
+	favHandler := handler.NewFavoriteHandler(favUC)
 	// User routes
 	api := r.Group("api/v1/users")
 	//admin
-	api.GET("/", middleware.RequireAuth("admin" , "moderator"), userHandler.GetUserList)
+	api.GET("/", middleware.RequireAuth(policy.UserReadAll), userHandler.GetUserList)
+	api.GET("/search", middleware.RequireAuth(policy.UserReadAll), userHandler.SearchUsers)
+	api.GET("/export", middleware.RequireAuth(policy.UserManage), userHandler.ExportUsers)
 	api.GET("/:id", userHandler.GetUserByID)
-	api.PUT("/:id", middleware.RequireAuth("admin"), userHandler.UpdateUser)
+	api.PUT("/:id", middleware.RequireAuth(policy.UserManage), userHandler.UpdateUser)
+	api.GET("/:id/suspensions", middleware.RequireAuth(policy.UserReadAll), suspensionHandler.ListSuspensions)
+	api.POST("/:id/suspensions", middleware.RequireAuth(policy.UserManage), suspensionHandler.SuspendUser)
+	api.POST("/:id/suspensions/lift", middleware.RequireAuth(policy.UserManage), suspensionHandler.LiftSuspension)
 	//user
-	api.GET("/profile", middleware.RequireAuth("user"), userHandler.GetUserProfile)
-	api.PUT("/profile", middleware.RequireAuth("user"), userHandler.UpdateUserProfile)
+	api.GET("/profile", middleware.RequireAuth(policy.ProfileManage), userHandler.GetUserProfile)
+	api.PUT("/profile", middleware.RequireAuth(policy.ProfileManage), userHandler.UpdateUserProfile)
+	api.PUT("/profile/avatar", middleware.RequireAuth(policy.ProfileManage), userHandler.UploadAvatar)
+	api.GET("/profile/preferences", middleware.RequireAuth(policy.ProfileManage), userHandler.GetPreferences)
+	api.PUT("/profile/preferences", middleware.RequireAuth(policy.ProfileManage), userHandler.UpdatePreferences)
+	api.POST("/profile/phone/verification", middleware.RequireAuth(policy.ProfileManage), userHandler.SendPhoneCode)
+	api.POST("/profile/phone/verify", middleware.RequireAuth(policy.ProfileManage), userHandler.VerifyPhone)
+	api.GET("/favorites/spaces", middleware.RequireAuth(policy.ProfileManage), favHandler.ListFavoriteSpaces)
+	api.POST("/favorites/spaces/:id", middleware.RequireAuth(policy.ProfileManage), favHandler.AddFavoriteSpace)
+	api.DELETE("/favorites/spaces/:id", middleware.RequireAuth(policy.ProfileManage), favHandler.RemoveFavoriteSpace)
+	// avatars kept on local disk are served by the service itself
+	if local, ok := store.(*storage.LocalStorage); ok {
+		r.Static(constant.AvatarRoute, local.Dir())
+	}
+
+	// Organization routes; roles within an organization are checked by the usecase
+	orgs := r.Group("api/v1/organizations", middleware.RequireAuth(policy.ProfileManage))
+	orgs.POST("", orgHandler.CreateOrganization)
+	orgs.GET("", orgHandler.ListOrganizations)
+	orgs.GET("/invitations", orgHandler.ListInvitations)
+	orgs.POST("/invitations/:id/accept", orgHandler.AcceptInvitation)
+	orgs.DELETE("/invitations/:id", orgHandler.DeclineInvitation)
+	orgs.GET("/:id", orgHandler.GetOrganization)
+	orgs.PUT("/:id", orgHandler.UpdateOrganization)
+	orgs.GET("/:id/members", orgHandler.ListMembers)
+	orgs.POST("/:id/members", orgHandler.InviteMember)
+	orgs.PUT("/:id/members/:memberID/role", orgHandler.UpdateMemberRole)
+	orgs.PUT("/:id/members/:memberID/quota", orgHandler.UpdateMemberQuota)
+	orgs.DELETE("/:id/members/:memberID", orgHandler.RemoveMember)
 
 	//auth-service
-	api.POST("/", userHandler.CreateUser)
+	authServiceRoutes(r, serviceVerifier, userHandler)
+
+	//booking-service
+	r.GET("api/v1/internal/organizations/:id/members/:userID",
+		middleware.RequireService(serviceVerifier, servicetoken.BookingService), orgHandler.GetMembership)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return usecase.NewPreferenceRelay(prefRepo, producer), usecase.NewSuspensionExpirer(suspensionUC)
+}
+
+// authServiceRoutes registers the routes only auth-service may call: creating and deleting
+// accounts and reading them by email. Each one is behind its service token.
+func authServiceRoutes(r *gin.Engine, verifier *servicetoken.Verifier, userHandler *handler.UserHandler) {
+	requireAuthService := middleware.RequireService(verifier, servicetoken.AuthService)
+	r.POST("api/v1/users/", requireAuthService, userHandler.CreateUser)
+	internal := r.Group("api/v1/internal/users", requireAuthService)
+	internal.GET("", userHandler.GetUserByEmail)
+	internal.DELETE("/:id", userHandler.DeleteUser)
+	internal.PUT("/:id/email", userHandler.ChangeEmail)
+	internal.PUT("/:id/verified", userHandler.MarkVerified)
+	r.GET(userdata.Route, requireAuthService, userHandler.ExportUserData)
+	r.DELETE(userdata.Route, requireAuthService, userHandler.EraseUserData)
 }
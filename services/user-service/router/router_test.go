@@ -0,0 +1,48 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"packages/servicetoken"
+	"testing"
+	"user-service/internal/handler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthServiceRoutes_RequireAuthServiceToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-service-secret")
+	r := gin.New()
+	// the handlers have no usecases: a request that gets past the token check panics
+	authServiceRoutes(r, servicetoken.NewVerifier(servicetoken.UserService, secret),
+		handler.NewUserHandler(nil, nil, nil, nil, nil, nil))
+
+	bookingToken, err := servicetoken.NewIssuer(servicetoken.BookingService, secret).Token(servicetoken.UserService)
+	require.NoError(t, err)
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/users/"},
+		{http.MethodGet, "/api/v1/internal/users?email=a@b.com"},
+		{http.MethodDelete, "/api/v1/internal/users/7"},
+		{http.MethodPut, "/api/v1/internal/users/7/email"},
+		{http.MethodPut, "/api/v1/internal/users/7/verified"},
+		{http.MethodGet, "/api/v1/internal/user-data/7"},
+		{http.MethodDelete, "/api/v1/internal/user-data/7"},
+	}
+	for _, route := range routes {
+		for name, token := range map[string]string{"no token": "", "other service": bookingToken} {
+			t.Run(route.method+" "+route.path+" "+name, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				if token != "" {
+					req.Header.Set(servicetoken.Header, token)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			})
+		}
+	}
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"packages/impersonation"
+	"packages/servicetoken"
+	"packages/session"
+	"packages/storage"
+	"time"
+	_ "time/tzdata"
 	"venue-service/config"
 	_ "venue-service/docs"
+	"venue-service/internal/constant"
+	"venue-service/internal/geocode"
 	"venue-service/internal/handler"
+	"venue-service/internal/middleware"
 	"venue-service/internal/repository"
 	"venue-service/internal/route"
 	"venue-service/internal/usecase"
@@ -30,19 +40,58 @@ func main() {
 	if baseURL == "" {
 		log.Fatal("missing env: BOOKING_SERVICE_URL")
 	}
-	bookingClient := repository.NewBookingClient(baseURL)
+	serviceSecret, err := servicetoken.SecretFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	issuer := servicetoken.NewIssuer(servicetoken.VenueService, serviceSecret)
+	bookingClient := repository.NewBookingClient(baseURL, issuer)
+
+	// refuse the tokens of sessions revoked in auth-service
+	authURL := os.Getenv("AUTH_SERVICE_URL")
+	if authURL == "" {
+		log.Fatal("missing env: AUTH_SERVICE_URL")
+	}
+	authClient := issuer.Client(servicetoken.AuthService, 5*time.Second)
+	revocations := session.NewRevocationStore(session.HTTPSource(authURL, authClient))
+	go revocations.Run(context.Background(), session.SyncInterval)
+	middleware.TrackRevocations(revocations)
+	// record impersonated requests in auth-service before serving them
+	middleware.RecordImpersonations(impersonation.NewRecorder(authURL, authClient))
+	store, err := storage.NewFromEnv(constant.EnvImagePrefix, constant.ImageRoute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	geocoder, err := geocode.NewFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	venueRepository := repository.NewVenueRepository(config.DB)
-	venueUsecase := usecase.NewVenueUsecase(venueRepository)
+	spaceRepository := repository.NewSpaceRepository(config.DB)
+	imageUsecase := usecase.NewImageUsecase(repository.NewImageRepository(config.DB), venueRepository, spaceRepository, store)
+	imageHandler := handler.NewImageHandler(imageUsecase)
+
+	venueUsecase := usecase.NewVenueUsecase(venueRepository, imageUsecase, geocoder)
 	venueHandler := handler.NewVenueHandler(venueUsecase)
 
-	spaceRepository := repository.NewSpaceRepository(config.DB)
-	spaceUsecase := usecase.NewSpaceUsecase(spaceRepository, venueRepository, bookingClient)
+	scheduleUsecase := usecase.NewScheduleUsecase(repository.NewScheduleRepository(config.DB), venueRepository, spaceRepository)
+	scheduleHandler := handler.NewScheduleHandler(scheduleUsecase)
+
+	spaceUsecase := usecase.NewSpaceUsecase(spaceRepository, venueRepository, bookingClient, scheduleUsecase, imageUsecase)
 	spaceHandler := handler.NewSpaceHandler(spaceUsecase)
 
 	amenityRepository := repository.NewAmenityRepository(config.DB)
 	amenityUsecase := usecase.NewAmenityUsecase(amenityRepository)
 	amenityHandler := handler.NewAmenityHandler(amenityUsecase)
-	r := route.SetupRouter(venueHandler, spaceHandler, amenityHandler)
+	catalogUsecase := usecase.NewCatalogUsecase(repository.NewCatalogRepository(config.DB), imageUsecase)
+	catalogHandler := handler.NewCatalogHandler(catalogUsecase)
+
+	serviceVerifier := servicetoken.NewVerifier(servicetoken.VenueService, serviceSecret)
+	r := route.SetupRouter(venueHandler, spaceHandler, amenityHandler, catalogHandler, imageHandler, scheduleHandler, serviceVerifier)
+	if local, ok := store.(*storage.LocalStorage); ok {
+		r.Static(constant.ImageRoute, local.Dir())
+	}
 
 	port := os.Getenv("VENUE_SERVICE_PORT")
 	if port == "" {
@@ -87,13 +87,35 @@ func ConnectDB() {
 	sqlDB.SetMaxOpenConns(100)
 
 	DB = db
-	err = db.AutoMigrate(model.Amenity{}, model.VenueAmenity{}, model.Venue{}, model.Space{})
+	if err := addVenueLocation(db); err != nil {
+		log.Fatalf("❌ Adding venue location failed: %v", err)
+	}
+	err = db.AutoMigrate(model.Amenity{}, model.VenueAmenity{}, model.Venue{}, model.Space{}, model.VenueMember{}, model.Image{}, model.OpeningHours{}, model.Closure{}, model.Holiday{})
 	if err != nil {
 		log.Fatalf("❌ AutoMigrate failed: %v", err)
 	}
 	log.Println("✅ Connected to MySQL successfully!")
 }
 
+// addVenueLocation adds the spatial column to a venues table made before it existed. MySQL only
+// indexes NOT NULL spatial columns and has no default to fill them with, so it takes three steps.
+func addVenueLocation(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&model.Venue{}) || m.HasColumn(&model.Venue{}, "Location") {
+		return nil
+	}
+	for _, stmt := range []string{
+		"ALTER TABLE venues ADD COLUMN location POINT SRID 4326 NULL",
+		"UPDATE venues SET location = ST_GeomFromText('POINT(0 0)', 4326)",
+		"ALTER TABLE venues MODIFY location POINT SRID 4326 NOT NULL",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getEnvAsInt(name string, defaultVal int) int {
 	if valStr := os.Getenv(name); valStr != "" {
 		if val, err := strconv.Atoi(valStr); err == nil {
@@ -9,6 +9,7 @@ require (
 	github.com/stretchr/testify v1.11.1
 	gorm.io/driver/mysql v1.6.0
 	gorm.io/gorm v1.30.1
+	packages v0.0.0
 )
 
 require (
@@ -81,3 +82,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	sigs.k8s.io/yaml v1.6.0 // indirect
 )
+
+replace packages => ../../packages
@@ -17,6 +17,15 @@ var (
 	ErrAmenityNotFound     = errors.New("amenity not found")
 	ErrVenueNotFound       = errors.New("venue not found")
 	ErrInvalidSpaceType    = errors.New("invalid space type")
+	ErrInvalidMemberRole   = errors.New("invalid member role")
+	ErrImageNotFound       = errors.New("image not found")
+	ErrImageTooLarge       = errors.New("image too large")
+	ErrUnsupportedImage    = errors.New("unsupported image type")
+	ErrInvalidImage        = errors.New("invalid image")
+	ErrTooManyImages       = errors.New("too many images")
+	ErrInvalidSchedule     = errors.New("invalid opening hours")
+	ErrInvalidTimezone     = errors.New("invalid timezone")
+	ErrHolidayExists       = errors.New("holiday already exists")
 )
 
 const (
@@ -25,6 +34,59 @@ const (
 	BLOCKED  = "blocked"
 )
 
+// MaxSpaceBatch caps the spaces other services can look up at once.
+const MaxSpaceBatch = 100
+
+const (
+	DefaultCatalogLimit = 20
+	MaxCatalogLimit     = 100
+)
+
+const (
+	DefaultSearchRadiusKm = 10
+	MaxSearchRadiusKm     = 100
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Orders of a space search. Relevance needs a query and distance a point; without either the
+// newest spaces come first.
+const (
+	SortRelevance    = "relevance"
+	SortPriceAsc     = "price"
+	SortPriceDesc    = "-price"
+	SortCapacityAsc  = "capacity"
+	SortCapacityDesc = "-capacity"
+	SortDistance     = "distance"
+	SortNewest       = "newest"
+)
+
+// PriceBands are the lower bounds of the price bands faceted by search, after the one from 0.
+var PriceBands = []float64{100000, 200000, 500000, 1000000}
+
+// DefaultTimezone is the zone of the opening hours of a venue until it sets its own.
+const DefaultTimezone = "Asia/Ho_Chi_Minh"
+
+// Where a schedule comes from, see dto.Schedule.
+const (
+	ScheduleSpace = "space"
+	ScheduleVenue = "venue"
+	ScheduleDaily = "daily"
+	ScheduleNone  = "none"
+)
+
+const (
+	MaxImagesPerUpload = 10
+	MaxGalleryImages   = 30
+	MaxCaptionLength   = 255
+
+	// photos are stored as configured by IMAGE_STORAGE, IMAGE_LOCAL_DIR and IMAGE_PUBLIC_URL
+	EnvImagePrefix = "IMAGE"
+	// where venue-service serves the local photo directory
+	ImageRoute = "/api/v1/images"
+)
+
 const (
 	PRIVATE_OFFICE = "private_office"
 	MEETING_ROOM   = "meeting_room"
@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// CatalogFilter holds the query of the public venue catalogue. Amenities are IDs, a venue having
+// to offer all of them; the space filters match venues with at least one space meeting them all.
+type CatalogFilter struct {
+	Page        int     `form:"page" binding:"omitempty,min=1"`
+	Limit       int     `form:"limit" binding:"omitempty,min=1,max=100"`
+	City        string  `form:"city" binding:"omitempty,max=100"`
+	Query       string  `form:"q" binding:"omitempty,max=100"`
+	SpaceType   string  `form:"space_type" binding:"omitempty,oneof=private_office meeting_room desk"`
+	MinCapacity int     `form:"min_capacity" binding:"omitempty,min=1"`
+	MaxPrice    float64 `form:"max_price" binding:"omitempty,gt=0"`
+
+	AmenityIDs []uint   `form:"-"`
+	Fields     []string `form:"-"` // empty means every field
+}
+
+// CatalogVenue is an approved venue as the catalogue shows it. Its JSON keys are the names
+// accepted by the fields parameter.
+type CatalogVenue struct {
+	ID          uint             `json:"id"`
+	Name        string           `json:"name"`
+	Address     string           `json:"address"`
+	City        string           `json:"city"`
+	Description string           `json:"description"`
+	Status      string           `json:"status"`
+	Latitude    *float64         `json:"latitude"`
+	Longitude   *float64         `json:"longitude"`
+	Spaces      []CatalogSpace   `json:"spaces"`
+	Amenities   []CatalogAmenity `json:"amenities"`
+	Stats       CatalogStats     `json:"stats"`
+	CoverImage  *Image           `json:"cover_image"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type CatalogSpace struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Capacity    int     `json:"capacity"`
+	Price       float64 `json:"price"`
+	Description string  `json:"description"`
+	OpenHour    string  `json:"open_hour"`
+	CloseHour   string  `json:"close_hour"`
+	CoverImage  *Image  `json:"cover_image"`
+}
+
+type CatalogAmenity struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// CatalogStats sums up the spaces of a venue; prices and capacity are 0 when it has none.
+type CatalogStats struct {
+	SpaceCount    int      `json:"space_count"`
+	MinPrice      float64  `json:"min_price"`
+	MaxPrice      float64  `json:"max_price"`
+	MaxCapacity   int      `json:"max_capacity"`
+	TotalCapacity int      `json:"total_capacity"`
+	SpaceTypes    []string `json:"space_types"`
+	AmenityCount  int      `json:"amenity_count"`
+}
+
+type Pagination struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+type CatalogPage struct {
+	Venues     []CatalogVenue
+	Pagination Pagination
+}
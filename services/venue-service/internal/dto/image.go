@@ -0,0 +1,31 @@
+package dto
+
+// Image is a gallery photo with the URL of each of its renditions: large, medium and thumb.
+type Image struct {
+	ID       uint              `json:"id"`
+	VenueID  uint              `json:"venue_id"`
+	SpaceID  uint              `json:"space_id,omitempty"`
+	Caption  string            `json:"caption"`
+	Position int               `json:"position"`
+	IsCover  bool              `json:"is_cover"`
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	URLs     map[string]string `json:"urls"`
+}
+
+// ImageUpload is one file of a gallery upload.
+type ImageUpload struct {
+	Data    []byte
+	Caption string
+}
+
+type UpdateImageRequest struct {
+	Caption *string `json:"caption" binding:"omitempty,max=255"`
+	// Cover makes the photo the cover of its gallery; the previous cover stays as a plain photo.
+	Cover bool `json:"cover"`
+}
+
+// ReorderImagesRequest lists every photo of the gallery in the new order.
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1"`
+}
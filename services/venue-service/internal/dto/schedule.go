@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// OpeningInterval is a time a venue or space opens on a weekday, 0 being Sunday. Closes is
+// after Opens on the same day; "24:00" closes at midnight.
+type OpeningInterval struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"`
+	Opens   string `json:"opens" binding:"required"`
+	Closes  string `json:"closes" binding:"required"`
+}
+
+// VenueScheduleRequest replaces the opening hours of a venue along with the timezone they are
+// in and the public holidays it closes on.
+type VenueScheduleRequest struct {
+	Timezone        string            `json:"timezone" binding:"required,max=64"`
+	HolidayCalendar string            `json:"holiday_calendar" binding:"omitempty,alphanum,max=16"`
+	Hours           []OpeningInterval `json:"hours" binding:"required,min=1,dive"`
+}
+
+// SpaceScheduleRequest gives a space opening hours of its own instead of those of its venue.
+type SpaceScheduleRequest struct {
+	Hours []OpeningInterval `json:"hours" binding:"required,min=1,dive"`
+}
+
+// Schedule is the weekly opening hours of a venue or space. Source tells where they come from:
+// the space itself, its venue, the daily open_hour and close_hour of the space, or none when
+// open around the clock.
+type Schedule struct {
+	VenueID         uint              `json:"venue_id"`
+	SpaceID         uint              `json:"space_id,omitempty"`
+	Timezone        string            `json:"timezone"`
+	HolidayCalendar string            `json:"holiday_calendar"`
+	Source          string            `json:"source"`
+	Hours           []OpeningInterval `json:"hours"`
+}
+
+// ClosureRequest closes the venue, or only its space SpaceID, from StartsAt to EndsAt.
+type ClosureRequest struct {
+	SpaceID  uint      `json:"space_id"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason" binding:"max=255"`
+}
+
+type Closure struct {
+	ID       uint      `json:"id"`
+	VenueID  uint      `json:"venue_id"`
+	SpaceID  uint      `json:"space_id,omitempty"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type HolidayRequest struct {
+	Calendar string `json:"calendar" binding:"required,alphanum,max=16"`
+	Date     string `json:"date" binding:"required,datetime=2006-01-02"`
+	Name     string `json:"name" binding:"required,max=100"`
+}
+
+type Holiday struct {
+	ID       uint   `json:"id"`
+	Calendar string `json:"calendar"`
+	Date     string `json:"date"`
+	Name     string `json:"name"`
+}
+
+// OpeningCheck tells whether a space is open over a whole period, and if not why.
+type OpeningCheck struct {
+	Open   bool   `json:"open"`
+	Reason string `json:"reason,omitempty"`
+}
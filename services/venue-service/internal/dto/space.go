@@ -1,5 +1,107 @@
 package dto
 
+import (
+	"time"
+	"venue-service/internal/model"
+)
+
+// SpaceSearchFilter is the query of a space search. Query is matched in full text against the
+// name and description of spaces and of their venue. Times, when set, keep the spaces free over
+// that period. Lat and Lng, when set, keep the spaces within RadiusKm of the point. AmenityIDs
+// keep the spaces whose venue offers all of them, and OpenAt ("15:04") those open at that time of
+// day, local to each venue, on the day StartTime falls on or today.
+type SpaceSearchFilter struct {
+	Query       string    `form:"q" binding:"omitempty,max=100"`
+	City        string    `form:"city" binding:"omitempty,max=100"`
+	Address     string    `form:"address" binding:"omitempty,max=255"`
+	Type        string    `form:"type" binding:"omitempty,oneof=private_office meeting_room desk"`
+	StartTime   time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime     time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	Lat         *float64  `form:"lat"`
+	Lng         *float64  `form:"lng"`
+	RadiusKm    float64   `form:"radius_km"`
+	MinCapacity int       `form:"min_capacity"`
+	MinPrice    float64   `form:"min_price"`
+	MaxPrice    float64   `form:"max_price"`
+	OpenAt      string    `form:"open_at"`
+	Sort        string    `form:"sort"` // one of the constant.SortX orders
+	Page        int       `form:"page"`
+	Limit       int       `form:"limit"`
+
+	AmenityIDs []uint `form:"-"`
+	ExcludeIDs []uint `form:"-"` // spaces booked or closed over the time window, or closed at OpenAt
+}
+
+// SpaceHit is a space found by a search with the venue details results show. Distance is in
+// meters from the point of a radius search, nil otherwise.
+type SpaceHit struct {
+	model.Space
+	VenueName      string
+	VenueAddress   string
+	VenueCity      string
+	VenueLatitude  *float64
+	VenueLongitude *float64
+	Distance       *float64
+}
+
+// SpaceSearchResult is a space found by search, with its cover photo or else the one of its
+// venue, and its distance from the point of a radius search.
+type SpaceSearchResult struct {
+	ID          uint             `json:"id"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Capacity    int              `json:"capacity"`
+	Price       float64          `json:"price"`
+	Description string           `json:"description"`
+	OpenHour    string           `json:"open_hour"`
+	CloseHour   string           `json:"close_hour"`
+	Venue       SpaceSearchVenue `json:"venue"`
+	CoverImage  *Image           `json:"cover_image"`
+	DistanceKm  *float64         `json:"distance_km,omitempty"`
+}
+
+type SpaceSearchVenue struct {
+	ID        uint     `json:"id"`
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// SpaceFacets count the spaces matching a search by each value the results can be narrowed to.
+type SpaceFacets struct {
+	Types      []FacetCount     `json:"types"`
+	Cities     []FacetCount     `json:"cities"`
+	Amenities  []AmenityFacet   `json:"amenities"`
+	PriceBands []PriceBandFacet `json:"price_bands"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AmenityFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// PriceBandFacet counts the spaces priced from Min up to, but excluding, Max. The last band has
+// no Max.
+type PriceBandFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+type SpaceSearchPage struct {
+	Spaces     []SpaceSearchResult
+	Facets     SpaceFacets
+	Pagination Pagination
+}
+
 type CreateSpaceRequest struct {
 	Name        string  `json:"name" binding:"required"`
 	Type        string  `json:"type" binding:"required,oneof=private_office meeting_room desk"`
@@ -23,3 +125,21 @@ type UpdateSpaceRequest struct {
 type UpdateManagerRequest struct {
 	ManagerID uint `json:"manager_id" binding:"required"`
 }
+
+// SpaceSummary is a space with the venue it belongs to, for other services. Bookable is false
+// while the venue is not approved.
+type SpaceSummary struct {
+	ID          uint    `json:"id"`
+	VenueID     uint    `json:"venue_id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Capacity    int     `json:"capacity"`
+	Price       float64 `json:"price"`
+	OpenHour    string  `json:"open_hour"`
+	CloseHour   string  `json:"close_hour"`
+	VenueName   string  `json:"venue_name"`
+	City        string  `json:"city"`
+	Address     string  `json:"address"`
+	VenueStatus string  `json:"venue_status"`
+	Bookable    bool    `json:"bookable"`
+}
@@ -5,6 +5,9 @@ type CreateVenueRequest struct {
 	Address     string `json:"address" binding:"required"`
 	City        string `json:"city"`
 	Description string `json:"description"`
+	// Latitude and Longitude go together; without them the address is geocoded.
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
 }
 
 type UpdateVenueRequest struct {
@@ -12,8 +15,31 @@ type UpdateVenueRequest struct {
 	Address     string `json:"address" binding:"required"`
 	City        string `json:"city"`
 	Description string `json:"description"`
+	// Latitude and Longitude go together; without them a changed address is geocoded again.
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
 }
 
 type FilterVenueRequest struct {
 	Status string `form:"status"` // pending / approved / blocked
 }
+
+type AddMemberRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=manager staff"`
+}
+
+// VenueAccess is what a user holds on a venue and its spaces, for other services to authorise
+// venue-scoped requests with policy.Subject.Can.
+type VenueAccess struct {
+	VenueID  uint          `json:"venue_id"`
+	SpaceIDs []uint        `json:"space_ids"`
+	Grants   []ScopedGrant `json:"grants"`
+}
+
+// ScopedGrant is a policy.Grant: Role held on the venue or space ID.
+type ScopedGrant struct {
+	Role  string `json:"role"`
+	Scope string `json:"scope"` // venue, space
+	ID    uint   `json:"id"`
+}
@@ -0,0 +1,51 @@
+// Package geocode turns venue addresses into coordinates through a provider chosen at startup:
+// Google Maps or OpenStreetMap Nominatim. Without one, coordinates are only entered by hand.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrNoMatch is returned when the provider knows no place at the address.
+var ErrNoMatch = errors.New("address not found")
+
+type Coordinates struct {
+	Lat float64
+	Lng float64
+}
+
+// Geocoder looks up the coordinates of a free-form address.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Coordinates, error)
+}
+
+const (
+	EnvProvider = "GEOCODER" // "none" (default), "google" or "nominatim"
+
+	EnvGoogleAPIKey  = "GOOGLE_MAPS_API_KEY"
+	EnvGoogleBaseURL = "GOOGLE_MAPS_BASE_URL"
+
+	EnvNominatimBaseURL = "NOMINATIM_BASE_URL"
+	// Nominatim asks every application to identify itself
+	EnvNominatimUserAgent = "NOMINATIM_USER_AGENT"
+
+	requestTimeout = 5 * time.Second
+)
+
+// NewFromEnv builds the geocoder configured by GEOCODER. It returns nil when geocoding is off.
+func NewFromEnv() (Geocoder, error) {
+	switch provider := os.Getenv(EnvProvider); provider {
+	case "", "none":
+		return nil, nil
+	case "google":
+		return NewGoogleGeocoder(os.Getenv(EnvGoogleAPIKey), os.Getenv(EnvGoogleBaseURL))
+	case "nominatim":
+		return NewNominatimGeocoder(os.Getenv(EnvNominatimUserAgent), os.Getenv(EnvNominatimBaseURL))
+	default:
+		return nil, fmt.Errorf("unknown %s %q", EnvProvider, provider)
+	}
+}
@@ -0,0 +1,67 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleGeocoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/maps/api/geocode/json" || r.URL.Query().Get("key") != "k" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Query().Get("address") {
+		case "12 Ly Tu Trong, HCM":
+			w.Write([]byte(`{"status":"OK","results":[{"geometry":{"location":{"lat":10.7769,"lng":106.7009}}}]}`))
+		case "nowhere":
+			w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+		default:
+			w.Write([]byte(`{"status":"REQUEST_DENIED","error_message":"bad key"}`))
+		}
+	}))
+	defer srv.Close()
+	g, err := NewGoogleGeocoder("k", srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	got, err := g.Geocode(ctx, "12 Ly Tu Trong, HCM")
+	require.NoError(t, err)
+	assert.Equal(t, Coordinates{Lat: 10.7769, Lng: 106.7009}, got)
+
+	_, err = g.Geocode(ctx, "nowhere")
+	assert.ErrorIs(t, err, ErrNoMatch)
+
+	_, err = g.Geocode(ctx, "other")
+	assert.ErrorContains(t, err, "REQUEST_DENIED bad key")
+}
+
+func TestNominatimGeocoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.Header.Get("User-Agent") != "coworking-test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("q") == "nowhere" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat":"21.0285","lon":"105.8542"}]`))
+	}))
+	defer srv.Close()
+	g, err := NewNominatimGeocoder("coworking-test", srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	got, err := g.Geocode(ctx, "Hoan Kiem, Hanoi")
+	require.NoError(t, err)
+	assert.Equal(t, Coordinates{Lat: 21.0285, Lng: 105.8542}, got)
+
+	_, err = g.Geocode(ctx, "nowhere")
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
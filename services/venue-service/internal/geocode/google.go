@@ -0,0 +1,75 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultGoogleBaseURL = "https://maps.googleapis.com"
+
+// GoogleGeocoder uses the Google Maps Geocoding API.
+type GoogleGeocoder struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+// NewGoogleGeocoder needs an API key; baseURL overrides the API endpoint, for tests.
+func NewGoogleGeocoder(apiKey, baseURL string) (*GoogleGeocoder, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("google geocoding needs %s", EnvGoogleAPIKey)
+	}
+	if baseURL == "" {
+		baseURL = defaultGoogleBaseURL
+	}
+	return &GoogleGeocoder{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) (Coordinates, error) {
+	query := url.Values{"address": {address}, "key": {g.apiKey}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/maps/api/geocode/json?"+query.Encode(), nil)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Coordinates{}, fmt.Errorf("google geocoding: %s", resp.Status)
+	}
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			Geometry struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Coordinates{}, err
+	}
+	switch {
+	case result.Status == "ZERO_RESULTS", result.Status == "OK" && len(result.Results) == 0:
+		return Coordinates{}, ErrNoMatch
+	case result.Status != "OK":
+		return Coordinates{}, errors.New("google geocoding: " + strings.TrimSpace(result.Status+" "+result.ErrorMessage))
+	}
+	loc := result.Results[0].Geometry.Location
+	return Coordinates{Lat: loc.Lat, Lng: loc.Lng}, nil
+}
@@ -0,0 +1,74 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultNominatimBaseURL = "https://nominatim.openstreetmap.org"
+
+// NominatimGeocoder uses the search API of OpenStreetMap Nominatim, or of a self-hosted copy.
+type NominatimGeocoder struct {
+	userAgent string
+	baseURL   string
+	http      *http.Client
+}
+
+// NewNominatimGeocoder needs a user agent naming the application, as the public instance
+// requires; baseURL points at a self-hosted instance.
+func NewNominatimGeocoder(userAgent, baseURL string) (*NominatimGeocoder, error) {
+	if userAgent == "" {
+		return nil, fmt.Errorf("nominatim geocoding needs %s", EnvNominatimUserAgent)
+	}
+	if baseURL == "" {
+		baseURL = defaultNominatimBaseURL
+	}
+	return &NominatimGeocoder{
+		userAgent: userAgent,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		http:      &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (Coordinates, error) {
+	query := url.Values{"q": {address}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Coordinates{}, fmt.Errorf("nominatim: %s", resp.Status)
+	}
+
+	// coordinates come as strings
+	var places []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return Coordinates{}, err
+	}
+	if len(places) == 0 {
+		return Coordinates{}, ErrNoMatch
+	}
+	lat, err := strconv.ParseFloat(places[0].Lat, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("nominatim: bad latitude %q", places[0].Lat)
+	}
+	lng, err := strconv.ParseFloat(places[0].Lon, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("nominatim: bad longitude %q", places[0].Lon)
+	}
+	return Coordinates{Lat: lat, Lng: lng}, nil
+}
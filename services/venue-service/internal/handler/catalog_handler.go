@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogHandler struct {
+	uc usecase.CatalogUsecase
+}
+
+func NewCatalogHandler(uc usecase.CatalogUsecase) *CatalogHandler {
+	return &CatalogHandler{uc}
+}
+
+// @Summary Public venue catalogue
+// @Description List approved venues with their spaces, amenities and stats, without signing in.
+// @Description fields picks the fields returned (id always is): id, name, address, city, description, status,
+// @Description spaces, amenities, stats, updated_at.
+// @Tags Catalog
+// @Produce json
+// @Param page query int false "Page, from 1"
+// @Param limit query int false "Venues per page, 20 by default, at most 100"
+// @Param city query string false "City contains"
+// @Param q query string false "Name or description contains"
+// @Param space_type query string false "Has a space of this type (private_office, meeting_room, desk)"
+// @Param min_capacity query int false "Has a space for at least this many people"
+// @Param max_price query number false "Has a space at most this price"
+// @Param amenities query string false "Comma separated amenity IDs, all required"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /catalog/venues [get]
+func (h *CatalogHandler) ListVenues(c *gin.Context) {
+	var filter dto.CatalogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	amenityIDs, err := parseIDList(c.Query("amenities"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	filter.AmenityIDs = amenityIDs
+	filter.Fields = splitList(c.Query("fields"))
+
+	page, err := h.uc.ListVenues(c.Request.Context(), filter)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	data, err := selectFields(page.Venues, filter.Fields)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "success",
+		"data":       data,
+		"pagination": page.Pagination,
+	})
+}
+
+// @Summary Public venue details
+// @Description Get an approved venue with its spaces, amenities and stats, without signing in.
+// @Tags Catalog
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /catalog/venues/{id} [get]
+func (h *CatalogHandler) GetVenue(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+
+	venue, err := h.uc.GetVenue(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": venue})
+}
+
+// splitList splits a comma separated query value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIDList parses a comma separated list of IDs.
+func parseIDList(value string) ([]uint, error) {
+	var ids []uint
+	for _, part := range splitList(value) {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || id == 0 {
+			return nil, constant.ErrInvalidID
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// selectFields keeps only fields, plus id, of each venue. Without fields the venues are returned
+// as they are.
+func selectFields(venues []dto.CatalogVenue, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return venues, nil
+	}
+	raw, err := json.Marshal(venues)
+	if err != nil {
+		return nil, err
+	}
+	var all []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+	selected := make([]map[string]json.RawMessage, len(all))
+	for i, venue := range all {
+		selected[i] = map[string]json.RawMessage{"id": venue["id"]}
+		for _, field := range fields {
+			selected[i][field] = venue[field]
+		}
+	}
+	return selected, nil
+}
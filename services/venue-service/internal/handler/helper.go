@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"packages/policy"
+	"venue-service/internal/constant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentSubject builds the policy subject from the values set by middleware.RequireAuth.
+func currentSubject(c *gin.Context) (policy.Subject, bool) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
+		return policy.Subject{}, false
+	}
+	id, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
+		return policy.Subject{}, false
+	}
+	sub := policy.Subject{UserID: id, Role: c.GetString("role")}
+	if actor, ok := c.Get("actor"); ok {
+		sub.Actor, _ = actor.(*policy.Actor)
+	}
+	return sub, true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, constant.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrNotFound), errors.Is(err, constant.ErrVenueNotFound), errors.Is(err, constant.ErrImageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrInvalidSpaceType), errors.Is(err, constant.ErrInvalidMemberRole), errors.Is(err, constant.ErrBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrUnsupportedImage), errors.Is(err, constant.ErrInvalidImage), errors.Is(err, constant.ErrTooManyImages):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrInvalidSchedule), errors.Is(err, constant.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrHolidayExists):
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, constant.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/usecase"
+	"venue-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	uc usecase.ImageUsecase
+}
+
+func NewImageHandler(uc usecase.ImageUsecase) *ImageHandler {
+	return &ImageHandler{uc}
+}
+
+// @Summary List venue photos
+// @Description List the photos of a venue in gallery order, with the URL of each rendition (large, medium, thumb)
+// @Tags Venue Image
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/images [get]
+func (h *ImageHandler) ListVenueImages(c *gin.Context) {
+	h.list(c, venueGallery)
+}
+
+// @Summary Upload venue photos
+// @Description Add up to 10 JPEG or PNG photos of up to 10 MB each, sent as "images" form fields, at the end of
+// @Description the gallery. Optional "captions" fields match the photos in order. A gallery holds at most 30 photos.
+// @Tags Venue Image
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param images formData file true "Photos"
+// @Param captions formData string false "Captions, in the order of the photos"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /venues/{id}/images [post]
+func (h *ImageHandler) UploadVenueImages(c *gin.Context) {
+	h.upload(c, venueGallery)
+}
+
+// @Summary Update a venue photo
+// @Description Change the caption of a photo, or make it the cover of the gallery
+// @Tags Venue Image
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param imageId path int true "Image ID"
+// @Param body body dto.UpdateImageRequest true "UpdateImageRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/images/{imageId} [put]
+func (h *ImageHandler) UpdateVenueImage(c *gin.Context) {
+	h.update(c, venueGallery)
+}
+
+// @Summary Reorder venue photos
+// @Description Put the gallery in the given order, which must list each of its photos once
+// @Tags Venue Image
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param body body dto.ReorderImagesRequest true "ReorderImagesRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /venues/{id}/images/order [put]
+func (h *ImageHandler) ReorderVenueImages(c *gin.Context) {
+	h.reorder(c, venueGallery)
+}
+
+// @Summary Delete a venue photo
+// @Description Delete a photo and its files; when it was the cover, the next photo takes over
+// @Tags Venue Image
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/images/{imageId} [delete]
+func (h *ImageHandler) DeleteVenueImage(c *gin.Context) {
+	h.delete(c, venueGallery)
+}
+
+// @Summary List space photos
+// @Description List the photos of a space in gallery order, with the URL of each rendition (large, medium, thumb)
+// @Tags Space Image
+// @Produce json
+// @Param id path int true "Space ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/images [get]
+func (h *ImageHandler) ListSpaceImages(c *gin.Context) {
+	h.list(c, spaceGallery)
+}
+
+// @Summary Upload space photos
+// @Description Add up to 10 JPEG or PNG photos of up to 10 MB each, sent as "images" form fields, at the end of
+// @Description the gallery. Optional "captions" fields match the photos in order. A gallery holds at most 30 photos.
+// @Tags Space Image
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param images formData file true "Photos"
+// @Param captions formData string false "Captions, in the order of the photos"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /spaces/{id}/images [post]
+func (h *ImageHandler) UploadSpaceImages(c *gin.Context) {
+	h.upload(c, spaceGallery)
+}
+
+// @Summary Update a space photo
+// @Description Change the caption of a photo, or make it the cover of the gallery
+// @Tags Space Image
+// @Accept json
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param imageId path int true "Image ID"
+// @Param body body dto.UpdateImageRequest true "UpdateImageRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/images/{imageId} [put]
+func (h *ImageHandler) UpdateSpaceImage(c *gin.Context) {
+	h.update(c, spaceGallery)
+}
+
+// @Summary Reorder space photos
+// @Description Put the gallery in the given order, which must list each of its photos once
+// @Tags Space Image
+// @Accept json
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param body body dto.ReorderImagesRequest true "ReorderImagesRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /spaces/{id}/images/order [put]
+func (h *ImageHandler) ReorderSpaceImages(c *gin.Context) {
+	h.reorder(c, spaceGallery)
+}
+
+// @Summary Delete a space photo
+// @Description Delete a photo and its files; when it was the cover, the next photo takes over
+// @Tags Space Image
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/images/{imageId} [delete]
+func (h *ImageHandler) DeleteSpaceImage(c *gin.Context) {
+	h.delete(c, spaceGallery)
+}
+
+// galleryParam reads the gallery from the path, answering 400 when it cannot.
+type galleryParam func(c *gin.Context) (usecase.Gallery, bool)
+
+func venueGallery(c *gin.Context) (usecase.Gallery, bool) {
+	id, ok := idParam(c, "id")
+	return usecase.VenueGallery(id), ok
+}
+
+func spaceGallery(c *gin.Context) (usecase.Gallery, bool) {
+	id, ok := idParam(c, "id")
+	return usecase.SpaceGallery(id), ok
+}
+
+func idParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *ImageHandler) list(c *gin.Context, gallery galleryParam) {
+	g, ok := gallery(c)
+	if !ok {
+		return
+	}
+	images, err := h.uc.List(c.Request.Context(), g)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": images})
+}
+
+func (h *ImageHandler) upload(c *gin.Context, gallery galleryParam) {
+	g, ok := gallery(c)
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	// leave room for the multipart envelope around the photos
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constant.MaxImagesPerUpload*utils.MaxImageBytes+1<<20)
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(c, constant.ErrImageTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	files, captions := form.File["images"], form.Value["captions"]
+	if len(files) > constant.MaxImagesPerUpload {
+		writeError(c, constant.ErrTooManyImages)
+		return
+	}
+
+	uploads := make([]dto.ImageUpload, len(files))
+	for i, fileHeader := range files {
+		if fileHeader.Size > utils.MaxImageBytes {
+			writeError(c, constant.ErrImageTooLarge)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+			return
+		}
+		uploads[i].Data, err = io.ReadAll(io.LimitReader(file, utils.MaxImageBytes+1))
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+			return
+		}
+		if i < len(captions) {
+			uploads[i].Caption = captions[i]
+		}
+	}
+
+	images, err := h.uc.Upload(c.Request.Context(), sub, g, uploads)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "images uploaded", "data": images})
+}
+
+func (h *ImageHandler) update(c *gin.Context, gallery galleryParam) {
+	var req dto.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	g, ok := gallery(c)
+	if !ok {
+		return
+	}
+	imageID, ok := idParam(c, "imageId")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	image, err := h.uc.Update(c.Request.Context(), sub, g, imageID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "image updated", "data": image})
+}
+
+func (h *ImageHandler) reorder(c *gin.Context, gallery galleryParam) {
+	var req dto.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	g, ok := gallery(c)
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	images, err := h.uc.Reorder(c.Request.Context(), sub, g, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "images reordered", "data": images})
+}
+
+func (h *ImageHandler) delete(c *gin.Context, gallery galleryParam) {
+	g, ok := gallery(c)
+	if !ok {
+		return
+	}
+	imageID, ok := idParam(c, "imageId")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Delete(c.Request.Context(), sub, g, imageID); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "image deleted"})
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	uc usecase.ScheduleUsecase
+}
+
+func NewScheduleHandler(uc usecase.ScheduleUsecase) *ScheduleHandler {
+	return &ScheduleHandler{uc}
+}
+
+// @Summary Get venue opening hours
+// @Description Weekly opening hours of a venue, which its spaces without hours of their own follow, with its timezone and holiday calendar
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/schedule [get]
+func (h *ScheduleHandler) GetVenueSchedule(c *gin.Context) {
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	schedule, err := h.uc.GetVenueSchedule(c.Request.Context(), venueID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": schedule})
+}
+
+// @Summary Set venue opening hours
+// @Description Replace the weekly opening hours of a venue and set the IANA timezone they are in, such as
+// @Description "Asia/Ho_Chi_Minh", and the public holiday calendar it closes on. A day holds up to 6 intervals
+// @Description that must not overlap; "24:00" closes at midnight.
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param body body dto.VenueScheduleRequest true "VenueScheduleRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/schedule [put]
+func (h *ScheduleHandler) SetVenueSchedule(c *gin.Context) {
+	var req dto.VenueScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.uc.SetVenueSchedule(c.Request.Context(), sub, venueID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "schedule updated", "data": schedule})
+}
+
+// @Summary Get space opening hours
+// @Description Weekly opening hours of a space: its own, else those of its venue, else its daily open and close hours
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Space ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/schedule [get]
+func (h *ScheduleHandler) GetSpaceSchedule(c *gin.Context) {
+	spaceID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	schedule, err := h.uc.GetSpaceSchedule(c.Request.Context(), spaceID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": schedule})
+}
+
+// @Summary Set space opening hours
+// @Description Give a space weekly opening hours of its own instead of those of its venue, in the timezone of the venue
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param body body dto.SpaceScheduleRequest true "SpaceScheduleRequest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/schedule [put]
+func (h *ScheduleHandler) SetSpaceSchedule(c *gin.Context) {
+	var req dto.SpaceScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	spaceID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.uc.SetSpaceSchedule(c.Request.Context(), sub, spaceID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "schedule updated", "data": schedule})
+}
+
+// @Summary Clear space opening hours
+// @Description Remove the own opening hours of a space, which then follows its venue
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Space ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /spaces/{id}/schedule [delete]
+func (h *ScheduleHandler) ClearSpaceSchedule(c *gin.Context) {
+	spaceID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.uc.ClearSpaceSchedule(c.Request.Context(), sub, spaceID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "schedule cleared", "data": schedule})
+}
+
+// @Summary List venue closures
+// @Description Closures of a venue and its spaces that are not over yet, soonest first
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/closures [get]
+func (h *ScheduleHandler) ListClosures(c *gin.Context) {
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	closures, err := h.uc.ListClosures(c.Request.Context(), venueID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": closures})
+}
+
+// @Summary Add a closure
+// @Description Close the venue, or only its space space_id, from starts_at to ends_at (RFC3339)
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param body body dto.ClosureRequest true "ClosureRequest"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/closures [post]
+func (h *ScheduleHandler) AddClosure(c *gin.Context) {
+	var req dto.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.uc.AddClosure(c.Request.Context(), sub, venueID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "closure added", "data": closure})
+}
+
+// @Summary Delete a closure
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param closureId path int true "Closure ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/closures/{closureId} [delete]
+func (h *ScheduleHandler) DeleteClosure(c *gin.Context) {
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	closureID, ok := idParam(c, "closureId")
+	if !ok {
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteClosure(c.Request.Context(), sub, venueID, closureID); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "closure deleted"})
+}
+
+// @Summary List public holidays
+// @Description Public holidays of a calendar such as "VN" in a year, the current one by default
+// @Tags Schedule
+// @Produce json
+// @Param calendar query string true "Holiday calendar"
+// @Param year query int false "Year"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /holidays [get]
+func (h *ScheduleHandler) ListHolidays(c *gin.Context) {
+	calendar := c.Query("calendar")
+	if calendar == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	year := time.Now().Year()
+	if raw := c.Query("year"); raw != "" {
+		y, err := strconv.Atoi(raw)
+		if err != nil || y < 1 || y > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+			return
+		}
+		year = y
+	}
+
+	holidays, err := h.uc.ListHolidays(c.Request.Context(), calendar, year)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success", "data": holidays})
+}
+
+// @Summary Add a public holiday
+// @Description Add a holiday, dated "2006-01-02", to a calendar; venues following it are closed all day
+// @Tags Admin Holiday
+// @Accept json
+// @Produce json
+// @Param body body dto.HolidayRequest true "HolidayRequest"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/holidays [post]
+func (h *ScheduleHandler) AddHoliday(c *gin.Context) {
+	var req dto.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	holiday, err := h.uc.AddHoliday(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "holiday added", "data": holiday})
+}
+
+// @Summary Delete a public holiday
+// @Tags Admin Holiday
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/holidays/{id} [delete]
+func (h *ScheduleHandler) DeleteHoliday(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.uc.DeleteHoliday(c.Request.Context(), id); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "holiday deleted"})
+}
+
+// @Summary Check a space is open (internal)
+// @Description Tells booking-service whether a space is open from start to end (RFC3339), and if not why
+// @Tags Internal
+// @Produce json
+// @Param id path int true "Space ID"
+// @Param start query string true "Start time"
+// @Param end query string true "End time"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /internal/spaces/{id}/opening [get]
+func (h *ScheduleHandler) CheckOpening(c *gin.Context) {
+	spaceID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	end, err := time.Parse(time.RFC3339, c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+
+	check, err := h.uc.CheckOpen(c.Request.Context(), spaceID, start, end)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": check})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 	"venue-service/internal/constant"
 	"venue-service/internal/dto"
@@ -39,14 +40,8 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 		return
 	}
 
-	uid, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
-		return
-	}
-	userID, ok := uid.(uint)
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
 
@@ -57,9 +52,9 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	space, err := h.uc.Create(ctx, userID, uint(venueID), req)
+	space, err := h.uc.Create(ctx, sub, uint(venueID), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -96,7 +91,7 @@ func (h *SpaceHandler) GetSpace(c *gin.Context) {
 }
 
 // @Summary Update a space
-// @Description Update information of a space (user must be authenticated)
+// @Description Update information of a space (venue owner, space manager, or a role with space:update)
 // @Tags Space
 // @Accept json
 // @Produce json
@@ -114,14 +109,8 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 		return
 	}
 
-	uid, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
-		return
-	}
-	userID, ok := uid.(uint)
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
 
@@ -132,9 +121,9 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	space, err := h.uc.Update(ctx, userID, uint(spaceID), req)
+	space, err := h.uc.Update(ctx, sub, uint(spaceID), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -145,7 +134,7 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 }
 
 // @Summary Delete a space
-// @Description Delete a space by ID (user must be authenticated)
+// @Description Delete a space by ID (venue owner, space manager, or a role with space:delete)
 // @Tags Space
 // @Produce json
 // @Param id path int true "Space ID"
@@ -155,14 +144,8 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /spaces/{id} [delete]
 func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
-	uid, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
-		return
-	}
-	userID, ok := uid.(uint)
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
 
@@ -173,8 +156,8 @@ func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	if err := h.uc.Delete(ctx, userID, uint(spaceID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	if err := h.uc.Delete(ctx, sub, uint(spaceID)); err != nil {
+		writeError(c, err)
 		return
 	}
 
@@ -200,14 +183,8 @@ func (h *SpaceHandler) UpdateManager(c *gin.Context) {
 		return
 	}
 
-	uid, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
-		return
-	}
-	userID, ok := uid.(uint)
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
 
@@ -218,8 +195,8 @@ func (h *SpaceHandler) UpdateManager(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	if err := h.uc.UpdateManager(ctx, userID, uint(spaceID), req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	if err := h.uc.UpdateManager(ctx, sub, uint(spaceID), req); err != nil {
+		writeError(c, err)
 		return
 	}
 
@@ -227,55 +204,101 @@ func (h *SpaceHandler) UpdateManager(c *gin.Context) {
 }
 
 // @Summary Search spaces
-// @Description Search spaces with filters and availability in a time range
+// @Description Search spaces with filters and availability in a time range. Results are paged and come with facet counts over every match.
 // @Tags Space
 // @Produce json
-// @Param name query string false "Space name"
+// @Param q query string false "Words to find in the name or description of the space or its venue"
+// @Param name query string false "Deprecated, same as q"
 // @Param city query string false "City"
 // @Param address query string false "Address"
 // @Param type query string false "Space type (private_office, meeting_room, desk)"
-// @Param start_time query string true "Start time (RFC3339 format)"
-// @Param end_time query string true "End time (RFC3339 format)"
+// @Param start_time query string false "Start time (RFC3339 format), to keep the spaces free until end_time"
+// @Param end_time query string false "End time (RFC3339 format)"
+// @Param amenity_ids query string false "Comma separated amenity IDs, all offered by the venue"
+// @Param min_capacity query int false "Minimum capacity"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param open_at query string false "Time of day the space must be open at (HH:MM), local to its venue, on the day of start_time or today"
+// @Param lat query number false "Latitude of the search center"
+// @Param lng query number false "Longitude of the search center"
+// @Param radius_km query number false "Search radius in kilometers (default 10, max 100)"
+// @Param sort query string false "relevance, price, -price, capacity, -capacity, distance or newest; defaults to relevance with q, else distance with lat/lng, else newest"
+// @Param page query int false "Page, from 1"
+// @Param limit query int false "Spaces per page (default 20, max 100)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /spaces/search [get]
 func (h *SpaceHandler) SearchSpaces(c *gin.Context) {
-	name := c.Query("name")
-	city := c.Query("city")
-	address := c.Query("address")
-	spaceType := c.Query("type")
-
-	loc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-
-	startTime, err := time.Parse(time.RFC3339, c.Query("start_time"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid start_time"})
+	var filter dto.SpaceSearchFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
 		return
 	}
-	startTime = startTime.In(loc)
-
-	endTime, err := time.Parse(time.RFC3339, c.Query("end_time"))
+	if filter.Query == "" {
+		filter.Query = c.Query("name")
+	}
+	amenityIDs, err := parseIDList(c.Query("amenity_ids"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid end_time"})
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
 		return
 	}
-	endTime = endTime.In(loc)
+	filter.AmenityIDs = amenityIDs
 
-	if !startTime.Before(endTime) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "start_time must be before end_time",
-		})
+	if filter.StartTime.IsZero() != filter.EndTime.IsZero() {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "start_time and end_time must be given together"})
 		return
 	}
-	spaces, err := h.uc.SearchSpaces(c.Request.Context(), name, city, address, spaceType, startTime, endTime)
+	if !filter.StartTime.IsZero() {
+		loc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
+		filter.StartTime, filter.EndTime = filter.StartTime.In(loc), filter.EndTime.In(loc)
+		if !filter.StartTime.Before(filter.EndTime) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "start_time must be before end_time",
+			})
+			return
+		}
+	}
+
+	page, err := h.uc.SearchSpaces(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		writeError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "success",
-		"data":    spaces,
+		"message":    "success",
+		"data":       page.Spaces,
+		"facets":     page.Facets,
+		"pagination": page.Pagination,
 	})
 }
+
+// @Summary Look up spaces (internal)
+// @Description Returns the spaces with the given IDs and their venue, for other services. Deleted spaces are left out.
+// @Tags Internal
+// @Produce json
+// @Param ids query string true "Comma separated space IDs, at most 100"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /internal/spaces [get]
+func (h *SpaceHandler) GetSpaceSummaries(c *gin.Context) {
+	var ids []uint
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+
+	spaces, err := h.uc.GetSummaries(c.Request.Context(), ids)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": spaces})
+}
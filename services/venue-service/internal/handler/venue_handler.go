@@ -102,7 +102,7 @@ func (h *VenueHandler) GetVenueByID(c *gin.Context) {
 }
 
 // @Summary Update a venue
-// @Description Update a venue by ID (user must be authenticated)
+// @Description Update a venue by ID (owner, or a role with venue:update)
 // @Tags Venue
 // @Accept json
 // @Produce json
@@ -124,14 +124,13 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
 		return
 	}
-	userID, ok := c.Get("userID")
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
-	venue, err := h.uc.Update(c.Request.Context(), userID.(uint), uint(id), req)
+	venue, err := h.uc.Update(c.Request.Context(), sub, uint(id), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -141,7 +140,7 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 }
 
 // @Summary Delete a venue
-// @Description Delete a venue by ID (user must be authenticated)
+// @Description Delete a venue by ID (owner, or a role with venue:delete)
 // @Tags Venue
 // @Produce json
 // @Param id path int true "Venue ID"
@@ -156,13 +155,12 @@ func (h *VenueHandler) DeleteVenue(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
 		return
 	}
-	userID, ok := c.Get("userID")
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
-	if err := h.uc.Delete(c.Request.Context(), userID.(uint), uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	if err := h.uc.Delete(c.Request.Context(), sub, uint(id)); err != nil {
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "venue deleted"})
@@ -191,13 +189,12 @@ func (h *VenueHandler) AddAmenity(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
 		return
 	}
-	userID, ok := c.Get("userID")
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
-	if err := h.uc.AddAmenity(c.Request.Context(), userID.(uint), uint(id), req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	if err := h.uc.AddAmenity(c.Request.Context(), sub, uint(id), req); err != nil {
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"message": "amenity added"})
@@ -207,6 +204,7 @@ func (h *VenueHandler) AddAmenity(c *gin.Context) {
 // @Description Remove a specific amenity from a venue (user must be authenticated)
 // @Tags Venue Amenity
 // @Produce json
+// @Param id path int true "Venue ID"
 // @Param venueAmenityId path int true "Venue Amenity ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
@@ -214,18 +212,22 @@ func (h *VenueHandler) AddAmenity(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /venues/{id}/amenities/{venueAmenityId} [delete]
 func (h *VenueHandler) RemoveAmenity(c *gin.Context) {
+	venueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
 	venueAmenityID, err := strconv.Atoi(c.Param("venueAmenityId"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
 		return
 	}
-	userID, ok := c.Get("userID")
+	sub, ok := currentSubject(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": constant.ErrUnauthorized.Error()})
 		return
 	}
-	if err := h.uc.RemoveAmenity(c.Request.Context(), userID.(uint), uint(venueAmenityID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	if err := h.uc.RemoveAmenity(c.Request.Context(), sub, uint(venueID), uint(venueAmenityID)); err != nil {
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "amenity removed"})
@@ -303,3 +305,131 @@ func (h *VenueHandler) BlockVenue(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "venue blocked"})
 }
+
+// @Summary List venue members
+// @Description List the manager and staff grants of a venue (owner, or a role with venue:manage_members)
+// @Tags Venue Member
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/members [get]
+func (h *VenueHandler) ListMembers(c *gin.Context) {
+	venueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+	members, err := h.uc.ListMembers(c.Request.Context(), sub, uint(venueID))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// @Summary Add a venue member
+// @Description Grant a manager or staff role on a venue
+// @Tags Venue Member
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param body body dto.AddMemberRequest true "AddMemberRequest"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/members [post]
+func (h *VenueHandler) AddMember(c *gin.Context) {
+	venueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	var req dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrBadRequest.Error()})
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+	member, err := h.uc.AddMember(c.Request.Context(), sub, uint(venueID), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "member added",
+		"data":    member,
+	})
+}
+
+// @Summary Remove a venue member
+// @Description Revoke the manager or staff role of a user on a venue
+// @Tags Venue Member
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /venues/{id}/members/{userId} [delete]
+func (h *VenueHandler) RemoveMember(c *gin.Context) {
+	venueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.RemoveMember(c.Request.Context(), sub, uint(venueID), uint(userID)); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// @Summary Grants of a user on a venue (internal)
+// @Description The roles a user holds on a venue and its spaces, and the IDs of its spaces, for booking-service
+// @Tags Internal
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param user_id query int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /internal/venues/{id}/access [get]
+func (h *VenueHandler) GetVenueAccess(c *gin.Context) {
+	venueID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": constant.ErrInvalidID.Error()})
+		return
+	}
+	access, err := h.uc.GetAccess(c.Request.Context(), venueID, uint(userID))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": access})
+}
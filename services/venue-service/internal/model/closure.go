@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Closure shuts a venue, or one of its spaces, over a period such as works or a private event.
+type Closure struct {
+	gorm.Model
+	VenueID  uint      `gorm:"not null;index"`
+	SpaceID  uint      `gorm:"not null;default:0"` // 0 closes the whole venue
+	StartsAt time.Time `gorm:"not null;index"`
+	EndsAt   time.Time `gorm:"not null"`
+	Reason   string    `gorm:"type:varchar(255)"`
+}
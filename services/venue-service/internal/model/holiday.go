@@ -0,0 +1,12 @@
+package model
+
+import "gorm.io/gorm"
+
+// Holiday is a public holiday of a calendar such as "VN". Venues following the calendar are
+// closed all day.
+type Holiday struct {
+	gorm.Model
+	Calendar string `gorm:"type:varchar(16);not null;uniqueIndex:idx_holidays_calendar_date,priority:1"`
+	Date     string `gorm:"type:char(10);not null;uniqueIndex:idx_holidays_calendar_date,priority:2"` // "2006-01-02"
+	Name     string `gorm:"type:varchar(100);not null"`
+}
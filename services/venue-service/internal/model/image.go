@@ -0,0 +1,18 @@
+package model
+
+import "gorm.io/gorm"
+
+// Image is a photo in the gallery of a venue, or of one of its spaces. Its renditions are
+// stored under Key + "_" + rendition + Ext.
+type Image struct {
+	gorm.Model
+	VenueID  uint   `gorm:"not null;index"`
+	SpaceID  uint   `gorm:"not null;default:0;index"` // 0 for photos of the venue itself
+	Key      string `gorm:"type:varchar(255);not null"`
+	Ext      string `gorm:"size:8;not null"`
+	Width    int
+	Height   int
+	Caption  string `gorm:"type:varchar(255)"`
+	Position int    `gorm:"not null"`
+	IsCover  bool   `gorm:"not null;default:false"`
+}
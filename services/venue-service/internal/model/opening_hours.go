@@ -0,0 +1,14 @@
+package model
+
+import "gorm.io/gorm"
+
+// OpeningHours is a time a venue, or one of its spaces, opens on a weekday. A space without any
+// follows its venue.
+type OpeningHours struct {
+	gorm.Model
+	VenueID uint   `gorm:"not null;index:idx_opening_hours_owner,priority:1"`
+	SpaceID uint   `gorm:"not null;default:0;index:idx_opening_hours_owner,priority:2"` // 0 for the venue itself
+	Weekday int    `gorm:"not null"`                                                    // 0 is Sunday
+	Opens   string `gorm:"size:5;not null"`                                             // "09:00"
+	Closes  string `gorm:"size:5;not null"`                                             // "18:00", "24:00" for midnight
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SRID of WGS 84 latitude and longitude. In this SRID MySQL reads WKT points latitude first.
+const SRID = 4326
+
+// Point is written to a MySQL POINT column with SRID 4326.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// WKT is the point as well-known text, latitude first as SRID 4326 expects.
+func (p Point) WKT() string {
+	return fmt.Sprintf("POINT(%f %f)", p.Lat, p.Lng)
+}
+
+func (p Point) GormValue(_ context.Context, _ *gorm.DB) clause.Expr {
+	return clause.Expr{SQL: "ST_GeomFromText(?, ?)", Vars: []interface{}{p.WKT(), SRID}}
+}
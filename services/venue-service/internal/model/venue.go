@@ -4,13 +4,36 @@ import "gorm.io/gorm"
 
 type Venue struct {
 	gorm.Model
-	UserID      uint   `gorm:"not null;index"`
-	Name        string `gorm:"type:varchar(255);not null"`
-	Address     string `gorm:"type:varchar(512);not null"`
-	City        string `gorm:"type:varchar(100);index"`
-	Description string `gorm:"type:text"`
-	Status      string `gorm:"type:varchar(50);default:'pending';index"` // pending, approved, blocked
+	UserID      uint     `gorm:"not null;index"`
+	Name        string   `gorm:"type:varchar(255);not null;index:idx_venues_fulltext,class:FULLTEXT"`
+	Address     string   `gorm:"type:varchar(512);not null"`
+	City        string   `gorm:"type:varchar(100);index"`
+	Description string   `gorm:"type:text;index:idx_venues_fulltext,class:FULLTEXT"`
+	Status      string   `gorm:"type:varchar(50);default:'pending';index"` // pending, approved, blocked
+	Latitude    *float64 // nil until geocoded or entered by hand
+	Longitude   *float64
+	// Timezone is the IANA zone the opening hours are in
+	Timezone string `gorm:"type:varchar(64);not null;default:'Asia/Ho_Chi_Minh'"`
+	// HolidayCalendar names the public holidays the venue closes on, none when empty
+	HolidayCalendar string `gorm:"type:varchar(16)"`
+	// Location mirrors the coordinates for the spatial index, POINT(0 0) while there are none.
+	// MySQL only indexes NOT NULL spatial columns. It is written on save and never read.
+	Location Point `gorm:"type:POINT SRID 4326;not null;index:,class:SPATIAL;->:false;<-" json:"-"`
 
 	Spaces    []Space        `gorm:"foreignKey:VenueID;constraint:OnDelete:CASCADE"`
 	Amenities []VenueAmenity `gorm:"foreignKey:VenueID;constraint:OnDelete:CASCADE"`
 }
+
+// HasLocation reports whether the venue has coordinates.
+func (v *Venue) HasLocation() bool {
+	return v.Latitude != nil && v.Longitude != nil
+}
+
+// BeforeSave keeps Location in step with the coordinates.
+func (v *Venue) BeforeSave(*gorm.DB) error {
+	v.Location = Point{}
+	if v.HasLocation() {
+		v.Location = Point{Lat: *v.Latitude, Lng: *v.Longitude}
+	}
+	return nil
+}
@@ -4,7 +4,7 @@ import "gorm.io/gorm"
 
 type VenueAmenity struct {
 	gorm.Model
-	VenueID   uint    `gorm:"not null;index"`
-	AmenityID uint    `gorm:"not null;index"`
+	VenueID   uint    `gorm:"not null;index;index:idx_venue_amenities_amenity_venue,priority:2"`
+	AmenityID uint    `gorm:"not null;index:idx_venue_amenities_amenity_venue,priority:1"`
 	Amenity   Amenity `gorm:"foreignKey:AmenityID;constraint:OnDelete:CASCADE"`
 }
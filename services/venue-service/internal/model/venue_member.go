@@ -0,0 +1,11 @@
+package model
+
+import "gorm.io/gorm"
+
+// VenueMember grants a scoped role (manager, staff) on a venue. The owner is implied by Venue.UserID.
+type VenueMember struct {
+	gorm.Model
+	VenueID uint   `gorm:"not null;uniqueIndex:idx_venue_member"`
+	UserID  uint   `gorm:"not null;uniqueIndex:idx_venue_member;index"`
+	Role    string `gorm:"type:varchar(20);not null"` // manager, staff
+}
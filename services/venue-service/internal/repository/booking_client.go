@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"packages/servicetoken"
 	"time"
 )
 
@@ -18,12 +19,11 @@ type bookingClient struct {
 	httpClient *http.Client
 }
 
-func NewBookingClient(baseURL string) BookingClient {
+// NewBookingClient calls the internal routes of booking-service, authenticated with a service token.
+func NewBookingClient(baseURL string, issuer *servicetoken.Issuer) BookingClient {
 	return &bookingClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		baseURL:    baseURL,
+		httpClient: issuer.Client(servicetoken.BookingService, 5*time.Second),
 	}
 }
 
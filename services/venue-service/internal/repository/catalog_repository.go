@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// CatalogRepository reads the approved venues shown to everyone.
+type CatalogRepository interface {
+	ListApproved(ctx context.Context, filter dto.CatalogFilter, withSpaces, withAmenities bool) ([]model.Venue, int64, error)
+	FindApproved(ctx context.Context, id uint) (*model.Venue, error)
+}
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) CatalogRepository {
+	return &catalogRepository{db}
+}
+
+// ListApproved returns one page of the venues matching filter, oldest first, and how many match
+// in all. Page and Limit must be set.
+func (r *catalogRepository) ListApproved(ctx context.Context, filter dto.CatalogFilter, withSpaces, withAmenities bool) ([]model.Venue, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Venue{}).Where("status = ?", constant.APPROVED)
+	if filter.City != "" {
+		query = query.Where("city LIKE ?", "%"+filter.City+"%")
+	}
+	if filter.Query != "" {
+		query = query.Where("(name LIKE ? OR description LIKE ?)", "%"+filter.Query+"%", "%"+filter.Query+"%")
+	}
+	if filter.SpaceType != "" || filter.MinCapacity > 0 || filter.MaxPrice > 0 {
+		spaces := r.db.Model(&model.Space{}).Select("venue_id")
+		if filter.SpaceType != "" {
+			spaces = spaces.Where("type = ?", filter.SpaceType)
+		}
+		if filter.MinCapacity > 0 {
+			spaces = spaces.Where("capacity >= ?", filter.MinCapacity)
+		}
+		if filter.MaxPrice > 0 {
+			spaces = spaces.Where("price <= ?", filter.MaxPrice)
+		}
+		query = query.Where("id IN (?)", spaces)
+	}
+	for _, amenityID := range filter.AmenityIDs {
+		query = query.Where("id IN (?)", r.db.Model(&model.VenueAmenity{}).Select("venue_id").Where("amenity_id = ?", amenityID))
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var venues []model.Venue
+	if total == 0 {
+		return venues, 0, nil
+	}
+	page := query.Order("id").Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit)
+	if withSpaces {
+		page = page.Preload("Spaces", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+	}
+	if withAmenities {
+		page = page.Preload("Amenities.Amenity")
+	}
+	if err := page.Find(&venues).Error; err != nil {
+		return nil, 0, err
+	}
+	return venues, total, nil
+}
+
+func (r *catalogRepository) FindApproved(ctx context.Context, id uint) (*model.Venue, error) {
+	var venue model.Venue
+	err := r.db.WithContext(ctx).
+		Preload("Spaces", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Amenities.Amenity").
+		Where("status = ?", constant.APPROVED).
+		First(&venue, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"venue-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ImageRepository interface {
+	Create(ctx context.Context, images []model.Image) error
+	// ListGallery returns the photos of the venue, or of its space when spaceID is not 0, in
+	// gallery order.
+	ListGallery(ctx context.Context, venueID, spaceID uint) ([]model.Image, error)
+	// ListByVenue returns the photos of the venue and of all its spaces.
+	ListByVenue(ctx context.Context, venueID uint) ([]model.Image, error)
+	// ListCovers returns the covers of the venues' own galleries and of the spaces' galleries.
+	ListCovers(ctx context.Context, venueIDs, spaceIDs []uint) ([]model.Image, error)
+	SaveAll(ctx context.Context, images []model.Image) error
+	Delete(ctx context.Context, ids []uint) error
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db}
+}
+
+func (r *imageRepository) Create(ctx context.Context, images []model.Image) error {
+	return r.db.WithContext(ctx).Create(&images).Error
+}
+
+func (r *imageRepository) ListGallery(ctx context.Context, venueID, spaceID uint) ([]model.Image, error) {
+	var images []model.Image
+	err := r.db.WithContext(ctx).
+		Where("venue_id = ? AND space_id = ?", venueID, spaceID).
+		Order("position, id").
+		Find(&images).Error
+	return images, err
+}
+
+func (r *imageRepository) ListByVenue(ctx context.Context, venueID uint) ([]model.Image, error) {
+	var images []model.Image
+	err := r.db.WithContext(ctx).Where("venue_id = ?", venueID).Find(&images).Error
+	return images, err
+}
+
+func (r *imageRepository) ListCovers(ctx context.Context, venueIDs, spaceIDs []uint) ([]model.Image, error) {
+	var images []model.Image
+	if len(venueIDs) == 0 && len(spaceIDs) == 0 {
+		return images, nil
+	}
+	// IN over an empty list is not valid SQL everywhere, 0 is never an ID
+	venueIDs, spaceIDs = append(slices.Clip(venueIDs), 0), append(slices.Clip(spaceIDs), 0)
+	err := r.db.WithContext(ctx).
+		Where("is_cover = ?", true).
+		Where("(space_id = 0 AND venue_id IN ?) OR (space_id <> 0 AND space_id IN ?)", venueIDs, spaceIDs).
+		Find(&images).Error
+	return images, err
+}
+
+// SaveAll saves the photos of a gallery together, so the order and the cover never end up
+// half changed.
+func (r *imageRepository) SaveAll(ctx context.Context, images []model.Image) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range images {
+			if err := tx.Save(&images[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete removes the rows for good; their files are gone with them.
+func (r *imageRepository) Delete(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.Image{}, ids).Error
+}
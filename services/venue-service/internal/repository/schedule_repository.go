@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"venue-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleRepository stores the opening hours, closures and public holidays that decide when
+// spaces are open.
+type ScheduleRepository interface {
+	ListHours(ctx context.Context, venueIDs []uint) ([]model.OpeningHours, error)
+	SaveVenueSchedule(ctx context.Context, venue *model.Venue, hours []model.OpeningHours) error
+	ReplaceHours(ctx context.Context, venueID, spaceID uint, hours []model.OpeningHours) error
+
+	ListClosures(ctx context.Context, venueID uint, from time.Time) ([]model.Closure, error)
+	ClosuresBetween(ctx context.Context, venueIDs []uint, start, end time.Time) ([]model.Closure, error)
+	CreateClosure(ctx context.Context, closure *model.Closure) error
+	FindClosure(ctx context.Context, venueID, closureID uint) (*model.Closure, error)
+	DeleteClosure(ctx context.Context, closure *model.Closure) error
+
+	ListHolidays(ctx context.Context, calendar string, year int) ([]model.Holiday, error)
+	HolidaysBetween(ctx context.Context, calendars []string, from, to string) ([]model.Holiday, error)
+	HolidayExists(ctx context.Context, calendar, date string) (bool, error)
+	CreateHoliday(ctx context.Context, holiday *model.Holiday) error
+	DeleteHoliday(ctx context.Context, id uint) error
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db}
+}
+
+// ListHours returns the opening hours of the venues and of their spaces.
+func (r *scheduleRepository) ListHours(ctx context.Context, venueIDs []uint) ([]model.OpeningHours, error) {
+	var hours []model.OpeningHours
+	err := r.db.WithContext(ctx).
+		Where("venue_id IN ?", venueIDs).
+		Order("weekday, opens").
+		Find(&hours).Error
+	return hours, err
+}
+
+// SaveVenueSchedule saves the timezone and holiday calendar of the venue and replaces its own
+// opening hours, leaving those of its spaces.
+func (r *scheduleRepository) SaveVenueSchedule(ctx context.Context, venue *model.Venue, hours []model.OpeningHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(venue).Select("timezone", "holiday_calendar").Updates(venue).Error
+		if err != nil {
+			return err
+		}
+		return replaceHours(tx, venue.ID, 0, hours)
+	})
+}
+
+// ReplaceHours replaces the opening hours of the space spaceID of the venue, or of the venue
+// itself when spaceID is 0. No hours leave the space to follow its venue.
+func (r *scheduleRepository) ReplaceHours(ctx context.Context, venueID, spaceID uint, hours []model.OpeningHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceHours(tx, venueID, spaceID, hours)
+	})
+}
+
+func replaceHours(tx *gorm.DB, venueID, spaceID uint, hours []model.OpeningHours) error {
+	err := tx.Unscoped().
+		Where("venue_id = ? AND space_id = ?", venueID, spaceID).
+		Delete(&model.OpeningHours{}).Error
+	if err != nil || len(hours) == 0 {
+		return err
+	}
+	return tx.Create(&hours).Error
+}
+
+// ListClosures returns the closures of the venue and its spaces that end after from, soonest
+// first.
+func (r *scheduleRepository) ListClosures(ctx context.Context, venueID uint, from time.Time) ([]model.Closure, error) {
+	var closures []model.Closure
+	err := r.db.WithContext(ctx).
+		Where("venue_id = ? AND ends_at > ?", venueID, from).
+		Order("starts_at").
+		Find(&closures).Error
+	return closures, err
+}
+
+// ClosuresBetween returns the closures of the venues and their spaces overlapping [start, end).
+func (r *scheduleRepository) ClosuresBetween(ctx context.Context, venueIDs []uint, start, end time.Time) ([]model.Closure, error) {
+	var closures []model.Closure
+	err := r.db.WithContext(ctx).
+		Where("venue_id IN ? AND starts_at < ? AND ends_at > ?", venueIDs, end, start).
+		Find(&closures).Error
+	return closures, err
+}
+
+func (r *scheduleRepository) CreateClosure(ctx context.Context, closure *model.Closure) error {
+	return r.db.WithContext(ctx).Create(closure).Error
+}
+
+func (r *scheduleRepository) FindClosure(ctx context.Context, venueID, closureID uint) (*model.Closure, error) {
+	var closure model.Closure
+	err := r.db.WithContext(ctx).
+		Where("venue_id = ?", venueID).
+		First(&closure, closureID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &closure, nil
+}
+
+func (r *scheduleRepository) DeleteClosure(ctx context.Context, closure *model.Closure) error {
+	return r.db.WithContext(ctx).Delete(closure).Error
+}
+
+// ListHolidays returns the holidays of the calendar in the year, by date.
+func (r *scheduleRepository) ListHolidays(ctx context.Context, calendar string, year int) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	err := r.db.WithContext(ctx).
+		Where("calendar = ? AND date LIKE ?", calendar, fmt.Sprintf("%04d-%%", year)).
+		Order("date").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// HolidaysBetween returns the holidays of the calendars dated from from to to, both included.
+func (r *scheduleRepository) HolidaysBetween(ctx context.Context, calendars []string, from, to string) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	err := r.db.WithContext(ctx).
+		Where("calendar IN ? AND date BETWEEN ? AND ?", calendars, from, to).
+		Find(&holidays).Error
+	return holidays, err
+}
+
+func (r *scheduleRepository) HolidayExists(ctx context.Context, calendar, date string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Holiday{}).
+		Where("calendar = ? AND date = ?", calendar, date).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *scheduleRepository) CreateHoliday(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
+}
+
+// DeleteHoliday removes the holiday for good, so that its date can be added again. It returns
+// gorm.ErrRecordNotFound when there is no such holiday.
+func (r *scheduleRepository) DeleteHoliday(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Unscoped().Delete(&model.Holiday{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,7 +2,13 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
 	"venue-service/internal/constant"
+	"venue-service/internal/dto"
 	"venue-service/internal/model"
 
 	"gorm.io/gorm"
@@ -13,7 +19,10 @@ type SpaceRepository interface {
 	GetByID(ctx context.Context, id uint) (*model.Space, error)
 	Update(ctx context.Context, space *model.Space) error
 	Delete(ctx context.Context, space *model.Space) error
-	FilterSpaces(ctx context.Context, name, city, address, spaceType string) ([]model.Space, error)
+	FilterSpaces(ctx context.Context, filter dto.SpaceSearchFilter) ([]dto.SpaceHit, int64, error)
+	MatchingIDs(ctx context.Context, filter dto.SpaceSearchFilter) ([]uint, error)
+	Facets(ctx context.Context, filter dto.SpaceSearchFilter) (*dto.SpaceFacets, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]model.Space, error)
 }
 
 type spaceRepository struct {
@@ -44,30 +53,255 @@ func (r *spaceRepository) Delete(ctx context.Context, space *model.Space) error
 	return r.db.WithContext(ctx).Delete(space).Error
 }
 
-func (r *spaceRepository) FilterSpaces(ctx context.Context, name, city, address, spaceType string) ([]model.Space, error) {
-	var spaces []model.Space
+// FilterSpaces returns one page of the spaces of approved venues matching filter, in the order
+// of filter.Sort, and how many match in all. Sort, Page and Limit must be set.
+func (r *spaceRepository) FilterSpaces(ctx context.Context, filter dto.SpaceSearchFilter) ([]dto.SpaceHit, int64, error) {
+	query := r.matching(ctx, filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var hits []dto.SpaceHit
+	if total == 0 {
+		return hits, 0, nil
+	}
+
+	columns := []string{
+		"spaces.*",
+		"venues.name AS venue_name",
+		"venues.address AS venue_address",
+		"venues.city AS venue_city",
+		"venues.latitude AS venue_latitude",
+		"venues.longitude AS venue_longitude",
+	}
+	var args []any
+	if p, ok := searchPoint(filter); ok {
+		columns = append(columns, distanceSQL+" AS distance")
+		args = append(args, p.WKT(), model.SRID)
+	}
+	if terms := fulltextTerms(filter.Query); terms != "" {
+		columns = append(columns, relevanceSQL+" AS relevance")
+		args = append(args, terms, terms)
+	}
+
+	page := query.Select(strings.Join(columns, ", "), args...)
+	switch filter.Sort {
+	case constant.SortRelevance:
+		page = page.Order("relevance DESC")
+	case constant.SortPriceAsc:
+		page = page.Order("spaces.price")
+	case constant.SortPriceDesc:
+		page = page.Order("spaces.price DESC")
+	case constant.SortCapacityAsc:
+		page = page.Order("spaces.capacity")
+	case constant.SortCapacityDesc:
+		page = page.Order("spaces.capacity DESC")
+	case constant.SortDistance:
+		page = page.Order("distance")
+	}
+	err := page.Order("spaces.id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// MatchingIDs returns the IDs of every space matching filter, ignoring its order and page.
+func (r *spaceRepository) MatchingIDs(ctx context.Context, filter dto.SpaceSearchFilter) ([]uint, error) {
+	var ids []uint
+	if err := r.matching(ctx, filter).Pluck("spaces.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Facets counts the spaces matching filter by type, city, venue amenity and price band. Every
+// price band is listed, the other facets only have the values found.
+func (r *spaceRepository) Facets(ctx context.Context, filter dto.SpaceSearchFilter) (*dto.SpaceFacets, error) {
+	query := r.matching(ctx, filter)
+	facets := dto.SpaceFacets{
+		Types:      []dto.FacetCount{},
+		Cities:     []dto.FacetCount{},
+		Amenities:  []dto.AmenityFacet{},
+		PriceBands: make([]dto.PriceBandFacet, 0, len(constant.PriceBands)+1),
+	}
+
+	err := query.Select("spaces.type AS value, COUNT(*) AS count").
+		Group("spaces.type").
+		Order("count DESC, value").
+		Scan(&facets.Types).Error
+	if err != nil {
+		return nil, err
+	}
+	err = query.Select("venues.city AS value, COUNT(*) AS count").
+		Where("venues.city <> ''").
+		Group("venues.city").
+		Order("count DESC, value").
+		Scan(&facets.Cities).Error
+	if err != nil {
+		return nil, err
+	}
+	err = query.Select("amenities.id, amenities.name, COUNT(DISTINCT spaces.id) AS count").
+		Joins("JOIN venue_amenities ON venue_amenities.venue_id = spaces.venue_id AND venue_amenities.deleted_at IS NULL").
+		Joins("JOIN amenities ON amenities.id = venue_amenities.amenity_id AND amenities.deleted_at IS NULL").
+		Group("amenities.id, amenities.name").
+		Order("count DESC, amenities.name").
+		Scan(&facets.Amenities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// the bounds are constants, written into the SQL so MySQL can group by the band
+	band := "CASE"
+	for i, bound := range constant.PriceBands {
+		band += fmt.Sprintf(" WHEN spaces.price < %s THEN %d", strconv.FormatFloat(bound, 'f', -1, 64), i)
+	}
+	band += fmt.Sprintf(" ELSE %d END", len(constant.PriceBands))
+	var bandCounts []struct {
+		Band  int
+		Count int64
+	}
+	err = query.Select(band + " AS band, COUNT(*) AS count").
+		Group("band").
+		Scan(&bandCounts).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i <= len(constant.PriceBands); i++ {
+		facet := dto.PriceBandFacet{}
+		if i > 0 {
+			facet.Min = constant.PriceBands[i-1]
+		}
+		if i < len(constant.PriceBands) {
+			facet.Max = &constant.PriceBands[i]
+		}
+		facets.PriceBands = append(facets.PriceBands, facet)
+	}
+	for _, c := range bandCounts {
+		facets.PriceBands[c.Band].Count = c.Count
+	}
+	return &facets, nil
+}
 
+const (
+	// distanceSQL is the distance in meters from the venue to a point and its SRID
+	distanceSQL = "ST_Distance_Sphere(venues.location, ST_GeomFromText(?, ?))"
+	// relevanceSQL ranks a full-text match, one in the space counting twice one in its venue
+	relevanceSQL = "MATCH(spaces.name, spaces.description) AGAINST (? IN BOOLEAN MODE) * 2 + " +
+		"MATCH(venues.name, venues.description) AGAINST (? IN BOOLEAN MODE)"
+)
+
+// matching builds the query of the spaces of approved venues matching filter. Its session can be
+// reused for counts, facets and pages. AmenityIDs must not repeat. Times and OpenAt depend on
+// schedules and bookings, which the caller turns into ExcludeIDs.
+func (r *spaceRepository) matching(ctx context.Context, filter dto.SpaceSearchFilter) *gorm.DB {
 	query := r.db.WithContext(ctx).
-		Preload("Venue").
-		Joins("JOIN venues ON venues.id = spaces.venue_id").
+		Model(&model.Space{}).
+		Joins("JOIN venues ON venues.id = spaces.venue_id AND venues.deleted_at IS NULL").
 		Where("venues.status = ?", constant.APPROVED)
 
-	if name != "" {
-		query = query.Where("spaces.name LIKE ? OR venues.name LIKE ?", "%"+name+"%", "%"+name+"%")
+	if terms := fulltextTerms(filter.Query); terms != "" {
+		query = query.Where("(MATCH(spaces.name, spaces.description) AGAINST (? IN BOOLEAN MODE) OR "+
+			"MATCH(venues.name, venues.description) AGAINST (? IN BOOLEAN MODE))", terms, terms)
+	}
+	if filter.City != "" {
+		query = query.Where("venues.city LIKE ?", "%"+filter.City+"%")
 	}
-	if city != "" {
-		query = query.Where("venues.city LIKE ?", "%"+city+"%")
+	if filter.Address != "" {
+		query = query.Where("venues.address LIKE ?", "%"+filter.Address+"%")
 	}
-	if address != "" {
-		query = query.Where("venues.address LIKE ?", "%"+address+"%")
+	if filter.Type != "" {
+		query = query.Where("spaces.type = ?", filter.Type)
 	}
-	if spaceType != "" {
-		query = query.Where("spaces.type = ?", spaceType)
+	if filter.MinCapacity > 0 {
+		query = query.Where("spaces.capacity >= ?", filter.MinCapacity)
 	}
+	if filter.MinPrice > 0 {
+		query = query.Where("spaces.price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("spaces.price <= ?", filter.MaxPrice)
+	}
+	if len(filter.AmenityIDs) > 0 {
+		// venues offering every amenity, read from the (amenity_id, venue_id) index
+		offering := r.db.Model(&model.VenueAmenity{}).
+			Select("venue_id").
+			Where("amenity_id IN ?", filter.AmenityIDs).
+			Group("venue_id").
+			Having("COUNT(DISTINCT amenity_id) = ?", len(filter.AmenityIDs))
+		query = query.Joins("JOIN (?) AS offering ON offering.venue_id = spaces.venue_id", offering)
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		query = query.Where("spaces.id NOT IN ?", filter.ExcludeIDs)
+	}
+	if p, ok := searchPoint(filter); ok {
+		// the bounding box lets MySQL use the spatial index before measuring the exact distance
+		query = query.
+			Where("venues.latitude IS NOT NULL").
+			Where(distanceSQL+" <= ?", p.WKT(), model.SRID, filter.RadiusKm*1000)
+		if box, ok := boundingBox(p, filter.RadiusKm); ok {
+			query = query.Where("MBRContains(ST_GeomFromText(?, ?), venues.location)", box, model.SRID)
+		}
+	}
+	return query.Session(&gorm.Session{})
+}
 
-	if err := query.Find(&spaces).Error; err != nil {
-		return nil, err
+func searchPoint(filter dto.SpaceSearchFilter) (model.Point, bool) {
+	if filter.Lat == nil || filter.Lng == nil {
+		return model.Point{}, false
 	}
+	return model.Point{Lat: *filter.Lat, Lng: *filter.Lng}, true
+}
+
+// fulltextTerms turns a free-text query into a boolean-mode search for any of its words, each
+// also matching as a prefix. Operators typed by the user are dropped.
+func fulltextTerms(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + "*"
+	}
+	return strings.Join(words, " ")
+}
+
+// boundingBox returns the polygon around the circle of radiusKm centered on p, latitude first as
+// SRID 4326 expects. Circles over a pole or the antimeridian get none.
+func boundingBox(p model.Point, radiusKm float64) (string, bool) {
+	const kmPerDegree = 111.32
+	dLat := radiusKm / kmPerDegree
+	minLat, maxLat := p.Lat-dLat, p.Lat+dLat
+	if minLat < -90 || maxLat > 90 {
+		return "", false
+	}
+	dLng := radiusKm / (kmPerDegree * math.Cos(maxAbs(minLat, maxLat)*math.Pi/180))
+	minLng, maxLng := p.Lng-dLng, p.Lng+dLng
+	if minLng < -180 || maxLng > 180 {
+		return "", false
+	}
+	return fmt.Sprintf("POLYGON((%[1]f %[3]f, %[1]f %[4]f, %[2]f %[4]f, %[2]f %[3]f, %[1]f %[3]f))",
+		minLat, maxLat, minLng, maxLng), true
+}
+
+func maxAbs(a, b float64) float64 {
+	return math.Max(math.Abs(a), math.Abs(b))
+}
 
+// GetByIDs returns the spaces with their venue. Spaces that were deleted, or whose venue was,
+// are left out.
+func (r *spaceRepository) GetByIDs(ctx context.Context, ids []uint) ([]model.Space, error) {
+	var spaces []model.Space
+	err := r.db.WithContext(ctx).
+		Preload("Venue").
+		Joins("JOIN venues ON venues.id = spaces.venue_id AND venues.deleted_at IS NULL").
+		Where("spaces.id IN ?", ids).
+		Find(&spaces).Error
+	if err != nil {
+		return nil, err
+	}
 	return spaces, nil
 }
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"venue-service/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VenueRepository interface {
@@ -12,11 +14,16 @@ type VenueRepository interface {
 	FindAll(ctx context.Context, userID uint, city, name string) ([]model.Venue, error)
 	FindByID(ctx context.Context, id uint) (*model.Venue, error)
 	Update(ctx context.Context, venue *model.Venue) error
-	Delete(ctx context.Context, id uint, userID uint) error
+	Delete(ctx context.Context, id uint) error
 	AddAmenity(ctx context.Context, venueAmenity *model.VenueAmenity) error
-	RemoveAmenity(ctx context.Context, id uint, userID uint) error
+	RemoveAmenity(ctx context.Context, venueID, venueAmenityID uint) error
 	ListByStatus(ctx context.Context, status string) ([]model.Venue, error)
 
+	FindMember(ctx context.Context, venueID, userID uint) (*model.VenueMember, error)
+	ListMembers(ctx context.Context, venueID uint) ([]model.VenueMember, error)
+	SaveMember(ctx context.Context, member *model.VenueMember) error
+	RemoveMember(ctx context.Context, venueID, userID uint) error
+
 	CheckAmenityExists(ctx context.Context, amenityID uint) (bool, error)
 	CheckVenueAmenityExists(ctx context.Context, venueID, amenityID uint) (bool, error)
 }
@@ -59,16 +66,16 @@ func (r *venueRepository) Update(ctx context.Context, venue *model.Venue) error
 	return r.db.WithContext(ctx).Save(venue).Error
 }
 
-func (r *venueRepository) Delete(ctx context.Context, id uint, userID uint) error {
-	return r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.Venue{}).Error
+func (r *venueRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Venue{}, id).Error
 }
 
 func (r *venueRepository) AddAmenity(ctx context.Context, venueAmenity *model.VenueAmenity) error {
 	return r.db.WithContext(ctx).Create(venueAmenity).Error
 }
 
-func (r *venueRepository) RemoveAmenity(ctx context.Context, id uint, userID uint) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.VenueAmenity{}).Error
+func (r *venueRepository) RemoveAmenity(ctx context.Context, venueID, venueAmenityID uint) error {
+	return r.db.WithContext(ctx).Where("id = ? AND venue_id = ?", venueAmenityID, venueID).Delete(&model.VenueAmenity{}).Error
 }
 
 func (r *venueRepository) ListByStatus(ctx context.Context, status string) ([]model.Venue, error) {
@@ -102,3 +109,34 @@ func (r *venueRepository) CheckVenueAmenityExists(ctx context.Context, venueID,
 	}
 	return count > 0, nil
 }
+
+// FindMember returns nil without error when the user holds no role on the venue.
+func (r *venueRepository) FindMember(ctx context.Context, venueID, userID uint) (*model.VenueMember, error) {
+	var member model.VenueMember
+	err := r.db.WithContext(ctx).Where("venue_id = ? AND user_id = ?", venueID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *venueRepository) ListMembers(ctx context.Context, venueID uint) ([]model.VenueMember, error) {
+	var members []model.VenueMember
+	err := r.db.WithContext(ctx).Where("venue_id = ?", venueID).Order("id").Find(&members).Error
+	return members, err
+}
+
+// SaveMember inserts the grant or changes the role of an existing one.
+func (r *venueRepository) SaveMember(ctx context.Context, member *model.VenueMember) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "venue_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(member).Error
+}
+
+func (r *venueRepository) RemoveMember(ctx context.Context, venueID, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("venue_id = ? AND user_id = ?", venueID, userID).Delete(&model.VenueMember{}).Error
+}
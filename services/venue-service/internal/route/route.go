@@ -1,6 +1,8 @@
 package route
 
 import (
+	"packages/policy"
+	"packages/servicetoken"
 	"venue-service/internal/handler"
 	"venue-service/internal/middleware"
 
@@ -10,57 +12,111 @@ import (
 )
 
 
-func SetupRouter(venueHandler *handler.VenueHandler, spaceHandler *handler.SpaceHandler, amenityHandler *handler.AmenityHandler) *gin.Engine {
+func SetupRouter(venueHandler *handler.VenueHandler, spaceHandler *handler.SpaceHandler, amenityHandler *handler.AmenityHandler, catalogHandler *handler.CatalogHandler, imageHandler *handler.ImageHandler, scheduleHandler *handler.ScheduleHandler, serviceVerifier *servicetoken.Verifier) *gin.Engine {
 	r := gin.Default()
 	v := r.Group("/api/v1/venues")
 	{
-		v.POST("", middleware.RequireAuth("user"), venueHandler.CreateVenue)
-		v.GET("", middleware.RequireAuth("user"), venueHandler.GetVenues)
-		v.GET("/:id", middleware.RequireAuth("user"), venueHandler.GetVenueByID)
-		v.PUT("/:id", middleware.RequireAuth("user"), venueHandler.UpdateVenue)
-		v.DELETE("/:id", middleware.RequireAuth("user"), venueHandler.DeleteVenue)
+		v.POST("", middleware.RequireAuth(policy.VenueCreate), venueHandler.CreateVenue)
+		v.GET("", middleware.RequireAuth(), venueHandler.GetVenues)
+		v.GET("/:id", middleware.RequireAuth(), venueHandler.GetVenueByID)
+		v.PUT("/:id", middleware.RequireAuth(), venueHandler.UpdateVenue)
+		v.DELETE("/:id", middleware.RequireAuth(), venueHandler.DeleteVenue)
 
 		// Amenities in venue
-		v.POST("/:id/amenities", middleware.RequireAuth("user"), venueHandler.AddAmenity)
-		v.DELETE("/:id/amenities/:venueAmenityId", middleware.RequireAuth("user"), venueHandler.RemoveAmenity)
+		v.POST("/:id/amenities", middleware.RequireAuth(), venueHandler.AddAmenity)
+		v.DELETE("/:id/amenities/:venueAmenityId", middleware.RequireAuth(), venueHandler.RemoveAmenity)
 
 		// Spaces under venue
-		v.POST("/:id/spaces", middleware.RequireAuth("user"), spaceHandler.CreateSpace)
+		v.POST("/:id/spaces", middleware.RequireAuth(), spaceHandler.CreateSpace)
+
+		// Scoped grants (manager/staff) on venue
+		v.GET("/:id/members", middleware.RequireAuth(), venueHandler.ListMembers)
+		v.POST("/:id/members", middleware.RequireAuth(), venueHandler.AddMember)
+		v.DELETE("/:id/members/:userId", middleware.RequireAuth(), venueHandler.RemoveMember)
+
+		// Photo gallery of venue
+		v.GET("/:id/images", imageHandler.ListVenueImages)
+		v.POST("/:id/images", middleware.RequireAuth(), imageHandler.UploadVenueImages)
+		v.PUT("/:id/images/order", middleware.RequireAuth(), imageHandler.ReorderVenueImages)
+		v.PUT("/:id/images/:imageId", middleware.RequireAuth(), imageHandler.UpdateVenueImage)
+		v.DELETE("/:id/images/:imageId", middleware.RequireAuth(), imageHandler.DeleteVenueImage)
+
+		// Opening hours and closures of venue
+		v.GET("/:id/schedule", scheduleHandler.GetVenueSchedule)
+		v.PUT("/:id/schedule", middleware.RequireAuth(), scheduleHandler.SetVenueSchedule)
+		v.GET("/:id/closures", scheduleHandler.ListClosures)
+		v.POST("/:id/closures", middleware.RequireAuth(), scheduleHandler.AddClosure)
+		v.DELETE("/:id/closures/:closureId", middleware.RequireAuth(), scheduleHandler.DeleteClosure)
+	}
+
+	// public catalogue of approved venues, no sign-in needed
+	catalog := r.Group("/api/v1/catalog")
+	{
+		catalog.GET("/venues", catalogHandler.ListVenues)
+		catalog.GET("/venues/:id", catalogHandler.GetVenue)
 	}
 
 	s := r.Group("/api/v1/spaces")
 	{
 		s.GET("/:id", spaceHandler.GetSpace)
 		s.GET("/search", spaceHandler.SearchSpaces)
-		s.PUT("/:id", middleware.RequireAuth("user"), spaceHandler.UpdateSpace)
-		s.DELETE("/:id", middleware.RequireAuth("user"), spaceHandler.DeleteSpace)
+		s.PUT("/:id", middleware.RequireAuth(), spaceHandler.UpdateSpace)
+		s.DELETE("/:id", middleware.RequireAuth(), spaceHandler.DeleteSpace)
 
 		// manager update
-		s.PUT("/:id/manager", middleware.RequireAuth("user"), spaceHandler.UpdateManager)
+		s.PUT("/:id/manager", middleware.RequireAuth(), spaceHandler.UpdateManager)
+
+		// photo gallery of space
+		s.GET("/:id/images", imageHandler.ListSpaceImages)
+		s.POST("/:id/images", middleware.RequireAuth(), imageHandler.UploadSpaceImages)
+		s.PUT("/:id/images/order", middleware.RequireAuth(), imageHandler.ReorderSpaceImages)
+		s.PUT("/:id/images/:imageId", middleware.RequireAuth(), imageHandler.UpdateSpaceImage)
+		s.DELETE("/:id/images/:imageId", middleware.RequireAuth(), imageHandler.DeleteSpaceImage)
+
+		// opening hours of space
+		s.GET("/:id/schedule", scheduleHandler.GetSpaceSchedule)
+		s.PUT("/:id/schedule", middleware.RequireAuth(), scheduleHandler.SetSpaceSchedule)
+		s.DELETE("/:id/schedule", middleware.RequireAuth(), scheduleHandler.ClearSpaceSchedule)
 	}
 
+	// public holidays venues close on
+	r.GET("/api/v1/holidays", scheduleHandler.ListHolidays)
+
 	//admin
 	a := r.Group("/api/v1/admin/amenities")
 	{
-		a.POST("", middleware.RequireAuth("admin", "moderator"), amenityHandler.CreateAmenity)
-		a.GET("", middleware.RequireAuth("admin", "moderator"), amenityHandler.GetAllAmenities)
-		a.GET("/:id", middleware.RequireAuth("admin", "moderator"), amenityHandler.GetAmenity)
-		a.PUT("/:id", middleware.RequireAuth("admin", "moderator"), amenityHandler.UpdateAmenity)
-		a.DELETE("/:id", middleware.RequireAuth("admin", "moderator"), amenityHandler.DeleteAmenity)
+		a.POST("", middleware.RequireAuth(policy.AmenityManage), amenityHandler.CreateAmenity)
+		a.GET("", middleware.RequireAuth(policy.AmenityManage), amenityHandler.GetAllAmenities)
+		a.GET("/:id", middleware.RequireAuth(policy.AmenityManage), amenityHandler.GetAmenity)
+		a.PUT("/:id", middleware.RequireAuth(policy.AmenityManage), amenityHandler.UpdateAmenity)
+		a.DELETE("/:id", middleware.RequireAuth(policy.AmenityManage), amenityHandler.DeleteAmenity)
+	}
+
+	holidays := r.Group("/api/v1/admin/holidays")
+	{
+		holidays.POST("", middleware.RequireAuth(policy.HolidayManage), scheduleHandler.AddHoliday)
+		holidays.DELETE("/:id", middleware.RequireAuth(policy.HolidayManage), scheduleHandler.DeleteHoliday)
 	}
 
 	admin := r.Group("/api/v1/admin/venues")
 	{
 		// GET /admin/venues?status=pending
-		admin.GET("", middleware.RequireAuth("admin", "moderator"), venueHandler.ListVenues)
+		admin.GET("", middleware.RequireAuth(policy.VenueReadAll), venueHandler.ListVenues)
 
 		// PUT /admin/venues/:id/approve
-		admin.PUT("/:id/approve", middleware.RequireAuth("admin", "moderator"), venueHandler.ApproveVenue)
+		admin.PUT("/:id/approve", middleware.RequireAuth(policy.VenueApprove), venueHandler.ApproveVenue)
 
 		// PUT /admin/venues/:id/block
-		admin.PUT("/:id/block", middleware.RequireAuth("admin", "moderator"), venueHandler.BlockVenue)
+		admin.PUT("/:id/block", middleware.RequireAuth(policy.VenueApprove), venueHandler.BlockVenue)
 	}
 
+	//user-service
+	r.GET("/api/v1/internal/spaces", middleware.RequireService(serviceVerifier, servicetoken.UserService), spaceHandler.GetSpaceSummaries)
+
+	//booking-service
+	r.GET("/api/v1/internal/spaces/:id/opening", middleware.RequireService(serviceVerifier, servicetoken.BookingService), scheduleHandler.CheckOpening)
+	r.GET("/api/v1/internal/venues/:id/access", middleware.RequireService(serviceVerifier, servicetoken.BookingService), venueHandler.GetVenueAccess)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	return r
 }
@@ -0,0 +1,188 @@
+// Package schedule decides whether a space is open over a period, from its weekly opening hours
+// in the timezone of its venue, the public holidays its venue follows and its closures.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrOutsideHours    = errors.New("outside opening hours")
+	ErrHoliday         = errors.New("closed for a public holiday")
+	ErrClosed          = errors.New("temporarily closed")
+	ErrInvalidClock    = errors.New("invalid time of day")
+	ErrInvalidSchedule = errors.New("invalid opening hours")
+)
+
+const (
+	MinutesPerDay      = 24 * 60
+	MaxIntervalsPerDay = 6
+	// DateLayout is the layout of holiday dates, local to the venue
+	DateLayout = "2006-01-02"
+)
+
+// ParseClock parses a "15:04" time of day into minutes after midnight. "24:00" is the end of
+// the day.
+func ParseClock(s string) (int, error) {
+	if s == "24:00" {
+		return MinutesPerDay, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrInvalidClock
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock formats minutes after midnight as "15:04".
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Interval is a time a space opens on a weekday, in minutes after midnight.
+type Interval struct {
+	Weekday time.Weekday
+	Opens   int
+	Closes  int
+}
+
+// Validate checks that every interval lies within its day and ends after it starts, and that
+// the intervals of a day do not overlap. Hours past midnight belong to the next day.
+func Validate(intervals []Interval) error {
+	var days [7][]Interval
+	for _, iv := range intervals {
+		if iv.Weekday < time.Sunday || iv.Weekday > time.Saturday ||
+			iv.Opens < 0 || iv.Closes > MinutesPerDay || iv.Opens >= iv.Closes {
+			return ErrInvalidSchedule
+		}
+		days[iv.Weekday] = append(days[iv.Weekday], iv)
+	}
+	for _, day := range days {
+		if len(day) > MaxIntervalsPerDay {
+			return ErrInvalidSchedule
+		}
+		sortByOpening(day)
+		for i := 1; i < len(day); i++ {
+			if day[i].Opens < day[i-1].Closes {
+				return ErrInvalidSchedule
+			}
+		}
+	}
+	return nil
+}
+
+// Daily returns the intervals of a space open from opens to closes every day. A space closing
+// before it opens is open over midnight.
+func Daily(opens, closes string) ([]Interval, error) {
+	o, err := ParseClock(opens)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseClock(closes)
+	if err != nil {
+		return nil, err
+	}
+	if o == c || o == MinutesPerDay {
+		return nil, ErrInvalidSchedule
+	}
+	var intervals []Interval
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if o < c {
+			intervals = append(intervals, Interval{Weekday: day, Opens: o, Closes: c})
+			continue
+		}
+		intervals = append(intervals, Interval{Weekday: day, Opens: o, Closes: MinutesPerDay})
+		if c > 0 {
+			intervals = append(intervals, Interval{Weekday: day, Opens: 0, Closes: c})
+		}
+	}
+	return intervals, nil
+}
+
+// Period is a span of time, such as a closure.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Calendar holds what decides whether a space is open.
+type Calendar struct {
+	Location *time.Location // nil means UTC
+	// Week is the weekly opening hours; nil means open around the clock
+	Week []Interval
+	// Holidays are the local dates, as DateLayout, the space is closed all day
+	Holidays map[string]bool
+	Closures []Period
+}
+
+// Check returns nil when the space is open throughout [start, end), or else why it is not:
+// ErrClosed, ErrHoliday or ErrOutsideHours.
+func (c Calendar) Check(start, end time.Time) error {
+	for _, p := range c.Closures {
+		if p.Start.Before(end) && start.Before(p.End) {
+			return ErrClosed
+		}
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	start, end = start.In(loc), end.In(loc)
+	for day := midnight(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if c.Holidays[day.Format(DateLayout)] {
+			return ErrHoliday
+		}
+		if c.Week == nil {
+			continue
+		}
+		from, to := 0, MinutesPerDay
+		if start.After(day) {
+			from = start.Hour()*60 + start.Minute()
+		}
+		if next := day.AddDate(0, 0, 1); end.Before(next) {
+			to = end.Hour()*60 + end.Minute()
+			if end.Second() > 0 || end.Nanosecond() > 0 {
+				to++
+			}
+		}
+		if !covers(c.Week, day.Weekday(), from, to) {
+			return ErrOutsideHours
+		}
+	}
+	return nil
+}
+
+// covers reports whether the intervals of weekday, joined where one ends as the next opens,
+// include [from, to).
+func covers(week []Interval, weekday time.Weekday, from, to int) bool {
+	var day []Interval
+	for _, iv := range week {
+		if iv.Weekday == weekday {
+			day = append(day, iv)
+		}
+	}
+	sortByOpening(day)
+	for i := 0; i < len(day); i++ {
+		opens, closes := day[i].Opens, day[i].Closes
+		for i+1 < len(day) && day[i+1].Opens <= closes {
+			i++
+			closes = max(closes, day[i].Closes)
+		}
+		if opens <= from && to <= closes {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByOpening(intervals []Interval) {
+	slices.SortFunc(intervals, func(a, b Interval) int { return a.Opens - b.Opens })
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,126 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+	"venue-service/internal/schedule"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var saigon, _ = time.LoadLocation("Asia/Ho_Chi_Minh")
+
+// at returns the time in Saigon; 2026-03-02 is a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, saigon)
+}
+
+func weekdays(opens, closes int) []schedule.Interval {
+	var week []schedule.Interval
+	for day := time.Monday; day <= time.Friday; day++ {
+		week = append(week, schedule.Interval{Weekday: day, Opens: opens, Closes: closes})
+	}
+	return week
+}
+
+func TestParseClock(t *testing.T) {
+	for s, want := range map[string]int{"00:00": 0, "09:30": 570, "9:30": 570, "24:00": 1440} {
+		got, err := schedule.ParseClock(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"", "25:00", "09:60", "9h30", "24:01"} {
+		_, err := schedule.ParseClock(s)
+		assert.ErrorIs(t, err, schedule.ErrInvalidClock, s)
+	}
+	assert.Equal(t, "09:05", schedule.FormatClock(545))
+	assert.Equal(t, "24:00", schedule.FormatClock(1440))
+}
+
+func TestValidate(t *testing.T) {
+	ok := []schedule.Interval{
+		{Weekday: time.Monday, Opens: 480, Closes: 720},
+		{Weekday: time.Monday, Opens: 720, Closes: 1440},
+		{Weekday: time.Tuesday, Opens: 0, Closes: 120},
+	}
+	assert.NoError(t, schedule.Validate(ok))
+
+	for _, bad := range [][]schedule.Interval{
+		{{Weekday: 7, Opens: 480, Closes: 720}},
+		{{Weekday: time.Monday, Opens: 720, Closes: 720}},
+		{{Weekday: time.Monday, Opens: 1320, Closes: 120}},
+		{{Weekday: time.Monday, Opens: 480, Closes: 1441}},
+		{{Weekday: time.Monday, Opens: 480, Closes: 720}, {Weekday: time.Monday, Opens: 600, Closes: 900}},
+	} {
+		assert.ErrorIs(t, schedule.Validate(bad), schedule.ErrInvalidSchedule, "%v", bad)
+	}
+
+	var crowded []schedule.Interval
+	for i := 0; i <= schedule.MaxIntervalsPerDay; i++ {
+		crowded = append(crowded, schedule.Interval{Weekday: time.Friday, Opens: i * 60, Closes: i*60 + 30})
+	}
+	assert.ErrorIs(t, schedule.Validate(crowded), schedule.ErrInvalidSchedule)
+}
+
+func TestDaily_OverMidnight(t *testing.T) {
+	week, err := schedule.Daily("22:00", "02:00")
+	require.NoError(t, err)
+	assert.Len(t, week, 14)
+	assert.NoError(t, schedule.Validate(week))
+
+	cal := schedule.Calendar{Location: saigon, Week: week}
+	assert.NoError(t, cal.Check(at(2, 23, 0), at(3, 1, 30)))
+	assert.ErrorIs(t, cal.Check(at(3, 1, 0), at(3, 3, 0)), schedule.ErrOutsideHours)
+
+	_, err = schedule.Daily("09:00", "09:00")
+	assert.ErrorIs(t, err, schedule.ErrInvalidSchedule)
+	_, err = schedule.Daily("", "18:00")
+	assert.ErrorIs(t, err, schedule.ErrInvalidClock)
+}
+
+func TestCheck_WeeklyHours(t *testing.T) {
+	week := append(weekdays(8*60, 12*60), weekdays(12*60, 18*60)...)
+	cal := schedule.Calendar{Location: saigon, Week: week}
+
+	assert.NoError(t, cal.Check(at(2, 9, 0), at(2, 17, 0)), "intervals meeting at noon join")
+	assert.NoError(t, cal.Check(at(2, 17, 0), at(2, 18, 0)))
+	assert.ErrorIs(t, cal.Check(at(2, 17, 0), at(2, 18, 1)), schedule.ErrOutsideHours)
+	assert.ErrorIs(t, cal.Check(at(2, 7, 59), at(2, 9, 0)), schedule.ErrOutsideHours)
+	assert.ErrorIs(t, cal.Check(at(7, 9, 0), at(7, 10, 0)), schedule.ErrOutsideHours, "closed on Saturday")
+	assert.ErrorIs(t, cal.Check(at(2, 17, 0), at(3, 9, 0)), schedule.ErrOutsideHours, "closed overnight")
+}
+
+func TestCheck_Timezone(t *testing.T) {
+	cal := schedule.Calendar{Location: saigon, Week: weekdays(8*60, 18*60)}
+
+	// 02:00 UTC is 09:00 in Saigon
+	assert.NoError(t, cal.Check(time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC)))
+	assert.ErrorIs(t, cal.Check(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)), schedule.ErrOutsideHours)
+}
+
+func TestCheck_UntilMidnight(t *testing.T) {
+	week := []schedule.Interval{
+		{Weekday: time.Monday, Opens: 20 * 60, Closes: schedule.MinutesPerDay},
+		{Weekday: time.Tuesday, Opens: 0, Closes: 2 * 60},
+	}
+	cal := schedule.Calendar{Location: saigon, Week: week}
+
+	assert.NoError(t, cal.Check(at(2, 21, 0), at(3, 0, 0)))
+	assert.NoError(t, cal.Check(at(2, 21, 0), at(3, 2, 0)))
+	assert.ErrorIs(t, cal.Check(at(2, 21, 0), at(3, 2, 30)), schedule.ErrOutsideHours)
+}
+
+func TestCheck_HolidaysAndClosures(t *testing.T) {
+	cal := schedule.Calendar{
+		Location: saigon,
+		Holidays: map[string]bool{"2026-03-04": true},
+		Closures: []schedule.Period{{Start: at(5, 12, 0), End: at(5, 14, 0)}},
+	}
+
+	assert.NoError(t, cal.Check(at(3, 1, 0), at(3, 23, 0)), "no weekly hours means open around the clock")
+	assert.ErrorIs(t, cal.Check(at(4, 10, 0), at(4, 11, 0)), schedule.ErrHoliday)
+	assert.ErrorIs(t, cal.Check(at(3, 22, 0), at(4, 1, 0)), schedule.ErrHoliday)
+	assert.ErrorIs(t, cal.Check(at(5, 13, 0), at(5, 15, 0)), schedule.ErrClosed)
+	assert.NoError(t, cal.Check(at(5, 14, 0), at(5, 15, 0)))
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// CatalogFields are the fields a catalogue request can select; id is always returned.
+var CatalogFields = []string{"id", "name", "address", "city", "description", "status", "latitude", "longitude", "spaces", "amenities", "stats", "cover_image", "updated_at"}
+
+// CatalogUsecase serves the public catalogue: approved venues only, to anyone.
+type CatalogUsecase interface {
+	ListVenues(ctx context.Context, filter dto.CatalogFilter) (*dto.CatalogPage, error)
+	GetVenue(ctx context.Context, id uint) (*dto.CatalogVenue, error)
+}
+
+type catalogUsecase struct {
+	repo      repository.CatalogRepository
+	galleries Galleries
+}
+
+func NewCatalogUsecase(r repository.CatalogRepository, galleries Galleries) CatalogUsecase {
+	return &catalogUsecase{repo: r, galleries: galleries}
+}
+
+// ListVenues returns one page of approved venues. Spaces and amenities are only loaded when a
+// selected field needs them.
+func (u *catalogUsecase) ListVenues(ctx context.Context, filter dto.CatalogFilter) (*dto.CatalogPage, error) {
+	for _, field := range filter.Fields {
+		if !slices.Contains(CatalogFields, field) {
+			return nil, constant.ErrBadRequest
+		}
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = constant.DefaultCatalogLimit
+	}
+	if filter.Limit > constant.MaxCatalogLimit {
+		return nil, constant.ErrBadRequest
+	}
+
+	selected := func(field string) bool {
+		return len(filter.Fields) == 0 || slices.Contains(filter.Fields, field)
+	}
+	withSpaces := selected("spaces") || selected("stats")
+	withAmenities := selected("amenities") || selected("stats")
+
+	venues, total, err := u.repo.ListApproved(ctx, filter, withSpaces, withAmenities)
+	if err != nil {
+		return nil, err
+	}
+	page := &dto.CatalogPage{
+		Venues:     make([]dto.CatalogVenue, 0, len(venues)),
+		Pagination: dto.Pagination{Page: filter.Page, Limit: filter.Limit, Total: total},
+	}
+	for i := range venues {
+		page.Venues = append(page.Venues, toCatalogVenue(&venues[i]))
+	}
+	if selected("cover_image") || selected("spaces") {
+		if err := u.addCovers(ctx, page.Venues); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (u *catalogUsecase) GetVenue(ctx context.Context, id uint) (*dto.CatalogVenue, error) {
+	venue, err := u.repo.FindApproved(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, constant.ErrVenueNotFound
+		}
+		return nil, err
+	}
+	res := []dto.CatalogVenue{toCatalogVenue(venue)}
+	if err := u.addCovers(ctx, res); err != nil {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+// addCovers sets the cover photo of the venues and of their spaces; a space without one shows
+// the cover of its venue.
+func (u *catalogUsecase) addCovers(ctx context.Context, venues []dto.CatalogVenue) error {
+	var venueIDs, spaceIDs []uint
+	for _, v := range venues {
+		venueIDs = append(venueIDs, v.ID)
+		for _, s := range v.Spaces {
+			spaceIDs = append(spaceIDs, s.ID)
+		}
+	}
+	venueCovers, spaceCovers, err := u.galleries.Covers(ctx, venueIDs, spaceIDs)
+	if err != nil {
+		return err
+	}
+	for i := range venues {
+		v := &venues[i]
+		if img, ok := venueCovers[v.ID]; ok {
+			v.CoverImage = &img
+		}
+		for j := range v.Spaces {
+			v.Spaces[j].CoverImage = coverOf(spaceCovers, venueCovers, v.Spaces[j].ID, v.ID)
+		}
+	}
+	return nil
+}
+
+func toCatalogVenue(v *model.Venue) dto.CatalogVenue {
+	res := dto.CatalogVenue{
+		ID:          v.ID,
+		Name:        v.Name,
+		Address:     v.Address,
+		City:        v.City,
+		Description: v.Description,
+		Status:      v.Status,
+		Latitude:    v.Latitude,
+		Longitude:   v.Longitude,
+		Spaces:      make([]dto.CatalogSpace, 0, len(v.Spaces)),
+		Amenities:   make([]dto.CatalogAmenity, 0, len(v.Amenities)),
+		Stats:       dto.CatalogStats{SpaceTypes: []string{}},
+		UpdatedAt:   v.UpdatedAt,
+	}
+	for i, s := range v.Spaces {
+		res.Spaces = append(res.Spaces, dto.CatalogSpace{
+			ID:          s.ID,
+			Name:        s.Name,
+			Type:        s.Type,
+			Capacity:    s.Capacity,
+			Price:       s.Price,
+			Description: s.Description,
+			OpenHour:    s.OpenHour,
+			CloseHour:   s.CloseHour,
+		})
+
+		stats := &res.Stats
+		if i == 0 || s.Price < stats.MinPrice {
+			stats.MinPrice = s.Price
+		}
+		stats.MaxPrice = max(stats.MaxPrice, s.Price)
+		stats.MaxCapacity = max(stats.MaxCapacity, s.Capacity)
+		stats.TotalCapacity += s.Capacity
+		if !slices.Contains(stats.SpaceTypes, s.Type) {
+			stats.SpaceTypes = append(stats.SpaceTypes, s.Type)
+		}
+	}
+	for _, a := range v.Amenities {
+		res.Amenities = append(res.Amenities, dto.CatalogAmenity{ID: a.AmenityID, Name: a.Amenity.Name})
+	}
+	res.Stats.SpaceCount = len(res.Spaces)
+	res.Stats.AmenityCount = len(res.Amenities)
+	slices.Sort(res.Stats.SpaceTypes)
+	return res
+}
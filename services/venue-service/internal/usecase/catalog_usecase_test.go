@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock CatalogRepository =====
+type mockCatalogRepo struct{ mock.Mock }
+
+func (m *mockCatalogRepo) ListApproved(ctx context.Context, filter dto.CatalogFilter, withSpaces, withAmenities bool) ([]model.Venue, int64, error) {
+	args := m.Called(ctx, filter, withSpaces, withAmenities)
+	if v, ok := args.Get(0).([]model.Venue); ok {
+		return v, int64(args.Int(1)), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+func (m *mockCatalogRepo) FindApproved(ctx context.Context, id uint) (*model.Venue, error) {
+	args := m.Called(ctx, id)
+	if v, ok := args.Get(0).(*model.Venue); ok {
+		return v, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestListCatalogVenues_DefaultsAndStats(t *testing.T) {
+	repo := new(mockCatalogRepo)
+	galleries := new(mockGalleries)
+	uc := usecase.NewCatalogUsecase(repo, galleries)
+	venue := model.Venue{
+		Model:  gorm.Model{ID: 4},
+		Name:   "Hub",
+		Status: constant.APPROVED,
+		Spaces: []model.Space{
+			{Model: gorm.Model{ID: 1}, Type: "meeting_room", Capacity: 8, Price: 40},
+			{Model: gorm.Model{ID: 2}, Type: "desk", Capacity: 1, Price: 10},
+			{Model: gorm.Model{ID: 3}, Type: "desk", Capacity: 1, Price: 12},
+		},
+		Amenities: []model.VenueAmenity{{VenueID: 4, AmenityID: 9, Amenity: model.Amenity{Name: "Wifi"}}},
+	}
+	want := dto.CatalogFilter{Page: 1, Limit: constant.DefaultCatalogLimit}
+	repo.On("ListApproved", mock.Anything, want, true, true).Return([]model.Venue{venue}, 41, nil)
+	galleries.On("Covers", mock.Anything, []uint{4}, []uint{1, 2, 3}).Return(
+		map[uint]dto.Image{4: {ID: 50, VenueID: 4}},
+		map[uint]dto.Image{2: {ID: 51, VenueID: 4, SpaceID: 2}},
+		nil,
+	)
+
+	page, err := uc.ListVenues(context.Background(), dto.CatalogFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.Pagination{Page: 1, Limit: constant.DefaultCatalogLimit, Total: 41}, page.Pagination)
+	require.Len(t, page.Venues, 1)
+	assert.Equal(t, dto.CatalogStats{
+		SpaceCount:    3,
+		MinPrice:      10,
+		MaxPrice:      40,
+		MaxCapacity:   8,
+		TotalCapacity: 10,
+		SpaceTypes:    []string{"desk", "meeting_room"},
+		AmenityCount:  1,
+	}, page.Venues[0].Stats)
+	assert.Equal(t, []dto.CatalogAmenity{{ID: 9, Name: "Wifi"}}, page.Venues[0].Amenities)
+	assert.Equal(t, uint(50), page.Venues[0].CoverImage.ID)
+	assert.Equal(t, uint(50), page.Venues[0].Spaces[0].CoverImage.ID, "falls back to the venue cover")
+	assert.Equal(t, uint(51), page.Venues[0].Spaces[1].CoverImage.ID)
+}
+
+func TestListCatalogVenues_LoadsOnlySelectedRelations(t *testing.T) {
+	repo := new(mockCatalogRepo)
+	uc := usecase.NewCatalogUsecase(repo, new(mockGalleries))
+	filter := dto.CatalogFilter{Page: 2, Limit: 10, Fields: []string{"name", "city"}}
+	repo.On("ListApproved", mock.Anything, filter, false, false).Return([]model.Venue{}, 0, nil)
+
+	page, err := uc.ListVenues(context.Background(), filter)
+
+	require.NoError(t, err)
+	assert.Empty(t, page.Venues)
+	repo.AssertExpectations(t)
+}
+
+func TestListCatalogVenues_RejectsUnknownField(t *testing.T) {
+	repo := new(mockCatalogRepo)
+	uc := usecase.NewCatalogUsecase(repo, new(mockGalleries))
+
+	_, err := uc.ListVenues(context.Background(), dto.CatalogFilter{Fields: []string{"name", "user_id"}})
+
+	assert.ErrorIs(t, err, constant.ErrBadRequest)
+	repo.AssertNotCalled(t, "ListApproved", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetCatalogVenue_NotApproved(t *testing.T) {
+	repo := new(mockCatalogRepo)
+	uc := usecase.NewCatalogUsecase(repo, new(mockGalleries))
+	repo.On("FindApproved", mock.Anything, uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := uc.GetVenue(context.Background(), 5)
+
+	assert.ErrorIs(t, err, constant.ErrVenueNotFound)
+}
@@ -0,0 +1,409 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"packages/policy"
+	"packages/storage"
+	"slices"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/repository"
+	"venue-service/internal/utils"
+)
+
+// Gallery names the photos of a venue, or of one of its spaces when SpaceID is set.
+type Gallery struct {
+	VenueID uint
+	SpaceID uint
+}
+
+func VenueGallery(venueID uint) Gallery {
+	return Gallery{VenueID: venueID}
+}
+
+// SpaceGallery names the photos of a space; its venue is looked up.
+func SpaceGallery(spaceID uint) Gallery {
+	return Gallery{SpaceID: spaceID}
+}
+
+// Galleries is what venues, spaces and the catalogue need from the photo galleries.
+type Galleries interface {
+	// Covers returns the cover of each venue's own gallery and of each space's gallery.
+	Covers(ctx context.Context, venueIDs, spaceIDs []uint) (venues, spaces map[uint]dto.Image, err error)
+	// RemoveVenueImages removes the photos of the venue and of all its spaces.
+	RemoveVenueImages(ctx context.Context, venueID uint) error
+	RemoveSpaceImages(ctx context.Context, venueID, spaceID uint) error
+}
+
+type ImageUsecase interface {
+	List(ctx context.Context, g Gallery) ([]dto.Image, error)
+	Upload(ctx context.Context, sub policy.Subject, g Gallery, uploads []dto.ImageUpload) ([]dto.Image, error)
+	Update(ctx context.Context, sub policy.Subject, g Gallery, imageID uint, req dto.UpdateImageRequest) (*dto.Image, error)
+	Reorder(ctx context.Context, sub policy.Subject, g Gallery, req dto.ReorderImagesRequest) ([]dto.Image, error)
+	Delete(ctx context.Context, sub policy.Subject, g Gallery, imageID uint) error
+	Galleries
+}
+
+type imageUsecase struct {
+	repo      repository.ImageRepository
+	venueRepo repository.VenueRepository
+	spaceRepo repository.SpaceRepository
+	storage   storage.Storage
+}
+
+func NewImageUsecase(r repository.ImageRepository, v repository.VenueRepository, s repository.SpaceRepository, store storage.Storage) ImageUsecase {
+	return &imageUsecase{repo: r, venueRepo: v, spaceRepo: s, storage: store}
+}
+
+// List is public, like venues and spaces themselves, except for blocked venues.
+func (u *imageUsecase) List(ctx context.Context, g Gallery) ([]dto.Image, error) {
+	g, venue, _, err := u.resolve(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	if venue.Status == constant.BLOCKED {
+		return nil, constant.ErrVenueNotFound
+	}
+	images, err := u.repo.ListGallery(ctx, g.VenueID, g.SpaceID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	return u.toImages(ctx, images)
+}
+
+// Upload adds the photos at the end of the gallery, in the order given. Every file is checked
+// before any is stored, so an upload is added whole or not at all. The first photo of an empty
+// gallery becomes its cover.
+func (u *imageUsecase) Upload(ctx context.Context, sub policy.Subject, g Gallery, uploads []dto.ImageUpload) ([]dto.Image, error) {
+	if len(uploads) == 0 {
+		return nil, constant.ErrBadRequest
+	}
+	if len(uploads) > constant.MaxImagesPerUpload {
+		return nil, constant.ErrTooManyImages
+	}
+	for _, up := range uploads {
+		if len(up.Caption) > constant.MaxCaptionLength {
+			return nil, constant.ErrBadRequest
+		}
+	}
+	g, err := u.authorized(ctx, sub, g)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := u.repo.ListGallery(ctx, g.VenueID, g.SpaceID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	if len(existing)+len(uploads) > constant.MaxGalleryImages {
+		return nil, constant.ErrTooManyImages
+	}
+
+	photos := make([]*utils.Photo, len(uploads))
+	for i, up := range uploads {
+		if photos[i], err = utils.ProcessPhoto(up.Data); err != nil {
+			return nil, photoError(err)
+		}
+	}
+
+	position := 0
+	if len(existing) > 0 {
+		position = existing[len(existing)-1].Position
+	}
+	hasCover := slices.ContainsFunc(existing, func(img model.Image) bool { return img.IsCover })
+	images := make([]model.Image, 0, len(photos))
+	var stored []string
+	for i, photo := range photos {
+		img := model.Image{
+			VenueID:  g.VenueID,
+			SpaceID:  g.SpaceID,
+			Key:      galleryPrefix(g) + randomToken(),
+			Ext:      photo.Ext,
+			Width:    photo.Width,
+			Height:   photo.Height,
+			Caption:  uploads[i].Caption,
+			Position: position + i + 1,
+			IsCover:  !hasCover && i == 0,
+		}
+		for _, r := range utils.Renditions {
+			key := renditionKey(&img, r.Name)
+			if err := u.storage.Put(ctx, key, photo.ContentType, photo.Renditions[r.Name]); err != nil {
+				log.Printf("store image %s failed: %v", key, err)
+				u.removeFiles(ctx, stored)
+				return nil, constant.ErrCreateFailed
+			}
+			stored = append(stored, key)
+		}
+		images = append(images, img)
+	}
+	if err := u.repo.Create(ctx, images); err != nil {
+		u.removeFiles(ctx, stored)
+		return nil, constant.ErrCreateFailed
+	}
+	return u.toImages(ctx, images)
+}
+
+func (u *imageUsecase) Update(ctx context.Context, sub policy.Subject, g Gallery, imageID uint, req dto.UpdateImageRequest) (*dto.Image, error) {
+	g, err := u.authorized(ctx, sub, g)
+	if err != nil {
+		return nil, err
+	}
+	images, err := u.repo.ListGallery(ctx, g.VenueID, g.SpaceID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	i := slices.IndexFunc(images, func(img model.Image) bool { return img.ID == imageID })
+	if i < 0 {
+		return nil, constant.ErrImageNotFound
+	}
+
+	if req.Caption != nil {
+		images[i].Caption = *req.Caption
+	}
+	if req.Cover {
+		for j := range images {
+			images[j].IsCover = j == i
+		}
+	}
+	if err := u.repo.SaveAll(ctx, images); err != nil {
+		return nil, constant.ErrUpdateFailed
+	}
+	res, err := u.toImage(ctx, &images[i])
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Reorder puts the gallery in the order of req, which must list each of its photos once.
+func (u *imageUsecase) Reorder(ctx context.Context, sub policy.Subject, g Gallery, req dto.ReorderImagesRequest) ([]dto.Image, error) {
+	g, err := u.authorized(ctx, sub, g)
+	if err != nil {
+		return nil, err
+	}
+	images, err := u.repo.ListGallery(ctx, g.VenueID, g.SpaceID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	if len(req.ImageIDs) != len(images) {
+		return nil, constant.ErrBadRequest
+	}
+	positions := make(map[uint]int, len(req.ImageIDs))
+	for i, id := range req.ImageIDs {
+		positions[id] = i + 1
+	}
+	for j := range images {
+		position, ok := positions[images[j].ID]
+		if !ok {
+			return nil, constant.ErrBadRequest
+		}
+		images[j].Position = position
+	}
+
+	if err := u.repo.SaveAll(ctx, images); err != nil {
+		return nil, constant.ErrUpdateFailed
+	}
+	slices.SortFunc(images, func(a, b model.Image) int { return a.Position - b.Position })
+	return u.toImages(ctx, images)
+}
+
+// Delete removes the photo and its files. When it was the cover, the next photo in the gallery
+// takes over.
+func (u *imageUsecase) Delete(ctx context.Context, sub policy.Subject, g Gallery, imageID uint) error {
+	g, err := u.authorized(ctx, sub, g)
+	if err != nil {
+		return err
+	}
+	images, err := u.repo.ListGallery(ctx, g.VenueID, g.SpaceID)
+	if err != nil {
+		return constant.ErrInternalServerError
+	}
+	i := slices.IndexFunc(images, func(img model.Image) bool { return img.ID == imageID })
+	if i < 0 {
+		return constant.ErrImageNotFound
+	}
+	removed := images[i]
+	if err := u.repo.Delete(ctx, []uint{removed.ID}); err != nil {
+		return constant.ErrDeleteFailed
+	}
+
+	rest := slices.Delete(images, i, i+1)
+	if removed.IsCover && len(rest) > 0 {
+		rest[0].IsCover = true
+		if err := u.repo.SaveAll(ctx, rest[:1]); err != nil {
+			log.Printf("make image %d the cover failed: %v", rest[0].ID, err)
+		}
+	}
+	u.removeFiles(ctx, renditionKeys(&removed))
+	return nil
+}
+
+func (u *imageUsecase) Covers(ctx context.Context, venueIDs, spaceIDs []uint) (map[uint]dto.Image, map[uint]dto.Image, error) {
+	images, err := u.repo.ListCovers(ctx, venueIDs, spaceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	venues, spaces := make(map[uint]dto.Image), make(map[uint]dto.Image)
+	for i := range images {
+		img, err := u.toImage(ctx, &images[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if img.SpaceID == 0 {
+			venues[img.VenueID] = img
+		} else {
+			spaces[img.SpaceID] = img
+		}
+	}
+	return venues, spaces, nil
+}
+
+func (u *imageUsecase) RemoveVenueImages(ctx context.Context, venueID uint) error {
+	images, err := u.repo.ListByVenue(ctx, venueID)
+	if err != nil {
+		return err
+	}
+	return u.remove(ctx, images)
+}
+
+func (u *imageUsecase) RemoveSpaceImages(ctx context.Context, venueID, spaceID uint) error {
+	images, err := u.repo.ListGallery(ctx, venueID, spaceID)
+	if err != nil {
+		return err
+	}
+	return u.remove(ctx, images)
+}
+
+func (u *imageUsecase) remove(ctx context.Context, images []model.Image) error {
+	ids := make([]uint, len(images))
+	var keys []string
+	for i := range images {
+		ids[i] = images[i].ID
+		keys = append(keys, renditionKeys(&images[i])...)
+	}
+	if err := u.repo.Delete(ctx, ids); err != nil {
+		return err
+	}
+	u.removeFiles(ctx, keys)
+	return nil
+}
+
+// resolve loads the venue of the gallery, and its space for a space gallery, and fills in
+// g.VenueID.
+func (u *imageUsecase) resolve(ctx context.Context, g Gallery) (Gallery, *model.Venue, *model.Space, error) {
+	var space *model.Space
+	if g.SpaceID != 0 {
+		s, err := u.spaceRepo.GetByID(ctx, g.SpaceID)
+		if err != nil {
+			return g, nil, nil, constant.ErrNotFound
+		}
+		space, g.VenueID = s, s.VenueID
+	}
+	venue, err := u.venueRepo.FindByID(ctx, g.VenueID)
+	if err != nil {
+		return g, nil, nil, constant.ErrVenueNotFound
+	}
+	return g, venue, space, nil
+}
+
+// authorized resolves g and checks the caller may edit it: venue:update for the photos of the
+// venue, space:update for those of a space.
+func (u *imageUsecase) authorized(ctx context.Context, sub policy.Subject, g Gallery) (Gallery, error) {
+	g, venue, space, err := u.resolve(ctx, g)
+	if err != nil {
+		return g, err
+	}
+	perm := policy.VenueUpdate
+	if space != nil {
+		perm = policy.SpaceUpdate
+	}
+	return g, authorize(ctx, u.venueRepo, sub, perm, venue, space)
+}
+
+func (u *imageUsecase) toImages(ctx context.Context, images []model.Image) ([]dto.Image, error) {
+	res := make([]dto.Image, 0, len(images))
+	for i := range images {
+		img, err := u.toImage(ctx, &images[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, img)
+	}
+	return res, nil
+}
+
+func (u *imageUsecase) toImage(ctx context.Context, img *model.Image) (dto.Image, error) {
+	res := dto.Image{
+		ID:       img.ID,
+		VenueID:  img.VenueID,
+		SpaceID:  img.SpaceID,
+		Caption:  img.Caption,
+		Position: img.Position,
+		IsCover:  img.IsCover,
+		Width:    img.Width,
+		Height:   img.Height,
+		URLs:     make(map[string]string, len(utils.Renditions)),
+	}
+	for _, r := range utils.Renditions {
+		url, err := u.storage.URL(ctx, renditionKey(img, r.Name))
+		if err != nil {
+			return dto.Image{}, err
+		}
+		res.URLs[r.Name] = url
+	}
+	return res, nil
+}
+
+// removeFiles deletes stored files. The photos are already gone, so a failure only leaves an
+// unreachable file behind and is logged.
+func (u *imageUsecase) removeFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			log.Printf("remove image file %s failed: %v", key, err)
+		}
+	}
+}
+
+func galleryPrefix(g Gallery) string {
+	if g.SpaceID != 0 {
+		return fmt.Sprintf("venues/%d/spaces/%d/", g.VenueID, g.SpaceID)
+	}
+	return fmt.Sprintf("venues/%d/", g.VenueID)
+}
+
+func renditionKey(img *model.Image, rendition string) string {
+	return img.Key + "_" + rendition + img.Ext
+}
+
+func renditionKeys(img *model.Image) []string {
+	keys := make([]string, 0, len(utils.Renditions))
+	for _, r := range utils.Renditions {
+		keys = append(keys, renditionKey(img, r.Name))
+	}
+	return keys
+}
+
+// randomToken names stored files, so their URLs cannot be guessed from the photo ID.
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func photoError(err error) error {
+	switch {
+	case errors.Is(err, utils.ErrImageTooLarge):
+		return constant.ErrImageTooLarge
+	case errors.Is(err, utils.ErrUnsupportedImage):
+		return constant.ErrUnsupportedImage
+	case errors.Is(err, utils.ErrInvalidImage):
+		return constant.ErrInvalidImage
+	default:
+		return err
+	}
+}
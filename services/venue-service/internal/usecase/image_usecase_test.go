@@ -0,0 +1,306 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"strings"
+	"sync"
+	"testing"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock ImageRepository =====
+type mockImageRepo struct{ mock.Mock }
+
+func (m *mockImageRepo) Create(ctx context.Context, images []model.Image) error {
+	return m.Called(ctx, images).Error(0)
+}
+func (m *mockImageRepo) ListGallery(ctx context.Context, venueID, spaceID uint) ([]model.Image, error) {
+	args := m.Called(ctx, venueID, spaceID)
+	if imgs, ok := args.Get(0).([]model.Image); ok {
+		return imgs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImageRepo) ListByVenue(ctx context.Context, venueID uint) ([]model.Image, error) {
+	args := m.Called(ctx, venueID)
+	if imgs, ok := args.Get(0).([]model.Image); ok {
+		return imgs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImageRepo) ListCovers(ctx context.Context, venueIDs, spaceIDs []uint) ([]model.Image, error) {
+	args := m.Called(ctx, venueIDs, spaceIDs)
+	if imgs, ok := args.Get(0).([]model.Image); ok {
+		return imgs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImageRepo) SaveAll(ctx context.Context, images []model.Image) error {
+	return m.Called(ctx, images).Error(0)
+}
+func (m *mockImageRepo) Delete(ctx context.Context, ids []uint) error {
+	return m.Called(ctx, ids).Error(0)
+}
+
+// ===== Mock Galleries =====
+type mockGalleries struct{ mock.Mock }
+
+func (m *mockGalleries) Covers(ctx context.Context, venueIDs, spaceIDs []uint) (map[uint]dto.Image, map[uint]dto.Image, error) {
+	args := m.Called(ctx, venueIDs, spaceIDs)
+	venues, _ := args.Get(0).(map[uint]dto.Image)
+	spaces, _ := args.Get(1).(map[uint]dto.Image)
+	return venues, spaces, args.Error(2)
+}
+func (m *mockGalleries) RemoveVenueImages(ctx context.Context, venueID uint) error {
+	return m.Called(ctx, venueID).Error(0)
+}
+func (m *mockGalleries) RemoveSpaceImages(ctx context.Context, venueID, spaceID uint) error {
+	return m.Called(ctx, venueID, spaceID).Error(0)
+}
+
+// memStorage keeps objects in memory.
+type memStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{objects: make(map[string][]byte)}
+}
+
+func (s *memStorage) Put(_ context.Context, key, _ string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	return nil
+}
+func (s *memStorage) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+func (s *memStorage) URL(_ context.Context, key string) (string, error) {
+	return "/files/" + key, nil
+}
+
+func jpegBytes(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil))
+	return buf.Bytes()
+}
+
+type imageMocks struct {
+	images *mockImageRepo
+	venues *mockVenueRepo
+	spaces *mockSpaceRepo
+	store  *memStorage
+}
+
+func setupImageUsecase() (imageMocks, usecase.ImageUsecase) {
+	m := imageMocks{new(mockImageRepo), new(mockVenueRepo), new(mockSpaceRepo), newMemStorage()}
+	m.venues.On("FindByID", mock.Anything, uint(4)).Return(&model.Venue{Model: gorm.Model{ID: 4}, UserID: 10, Status: constant.APPROVED}, nil)
+	return m, usecase.NewImageUsecase(m.images, m.venues, m.spaces, m.store)
+}
+
+func TestUploadImages_FirstBecomesCover(t *testing.T) {
+	m, uc := setupImageUsecase()
+	m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return([]model.Image{}, nil)
+	m.images.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := uc.Upload(context.Background(), asUser(10), usecase.VenueGallery(4), []dto.ImageUpload{
+		{Data: jpegBytes(t, 2000, 1000), Caption: "Lobby"},
+		{Data: jpegBytes(t, 300, 200)},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.True(t, res[0].IsCover)
+	assert.False(t, res[1].IsCover)
+	assert.Equal(t, []int{1, 2}, []int{res[0].Position, res[1].Position})
+	assert.Equal(t, "Lobby", res[0].Caption)
+	assert.Equal(t, 1600, res[0].Width)
+	assert.Equal(t, 800, res[0].Height)
+	assert.Len(t, m.store.objects, 6, "three renditions each")
+	assert.True(t, strings.HasPrefix(res[0].URLs["thumb"], "/files/venues/4/"))
+	assert.True(t, strings.HasSuffix(res[0].URLs["thumb"], "_thumb.jpg"))
+}
+
+func TestUploadImages_SpaceGalleryAppends(t *testing.T) {
+	m, uc := setupImageUsecase()
+	m.spaces.On("GetByID", mock.Anything, uint(8)).Return(&model.Space{Model: gorm.Model{ID: 8}, VenueID: 4, ManagerID: 5}, nil)
+	m.images.On("ListGallery", mock.Anything, uint(4), uint(8)).Return([]model.Image{
+		{Model: gorm.Model{ID: 1}, VenueID: 4, SpaceID: 8, Position: 3, IsCover: true},
+	}, nil)
+	m.images.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := uc.Upload(context.Background(), asUser(5), usecase.SpaceGallery(8), []dto.ImageUpload{{Data: jpegBytes(t, 100, 100)}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, res[0].Position)
+	assert.False(t, res[0].IsCover)
+	assert.Equal(t, uint(8), res[0].SpaceID)
+	assert.True(t, strings.HasPrefix(res[0].URLs["large"], "/files/venues/4/spaces/8/"))
+}
+
+func TestUploadImages_Rejects(t *testing.T) {
+	full := make([]model.Image, constant.MaxGalleryImages)
+	cases := map[string]struct {
+		sub     uint
+		gallery []model.Image
+		data    []byte
+		want    error
+	}{
+		"not the owner": {sub: 11, gallery: []model.Image{}, data: jpegBytes(t, 10, 10), want: constant.ErrForbidden},
+		"gallery full":  {sub: 10, gallery: full, data: jpegBytes(t, 10, 10), want: constant.ErrTooManyImages},
+		"not a picture": {sub: 10, gallery: []model.Image{}, data: []byte("GIF89a"), want: constant.ErrUnsupportedImage},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, uc := setupImageUsecase()
+			m.venues.On("FindMember", mock.Anything, uint(4), tc.sub).Return(nil, nil)
+			m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return(tc.gallery, nil)
+
+			_, err := uc.Upload(context.Background(), asUser(tc.sub), usecase.VenueGallery(4), []dto.ImageUpload{{Data: tc.data}})
+
+			assert.ErrorIs(t, err, tc.want)
+			assert.Empty(t, m.store.objects)
+			m.images.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateImage_SwitchesCover(t *testing.T) {
+	m, uc := setupImageUsecase()
+	m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return([]model.Image{
+		{Model: gorm.Model{ID: 1}, VenueID: 4, Position: 1, IsCover: true},
+		{Model: gorm.Model{ID: 2}, VenueID: 4, Position: 2},
+	}, nil)
+	m.images.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	caption := "Terrace"
+
+	res, err := uc.Update(context.Background(), asUser(10), usecase.VenueGallery(4), 2, dto.UpdateImageRequest{Caption: &caption, Cover: true})
+
+	require.NoError(t, err)
+	assert.True(t, res.IsCover)
+	assert.Equal(t, "Terrace", res.Caption)
+	saved := m.images.Calls[1].Arguments.Get(1).([]model.Image)
+	assert.False(t, saved[0].IsCover)
+}
+
+func TestReorderImages(t *testing.T) {
+	gallery := func() []model.Image {
+		return []model.Image{
+			{Model: gorm.Model{ID: 1}, VenueID: 4, Position: 1},
+			{Model: gorm.Model{ID: 2}, VenueID: 4, Position: 2},
+			{Model: gorm.Model{ID: 3}, VenueID: 4, Position: 3},
+		}
+	}
+
+	t.Run("new order", func(t *testing.T) {
+		m, uc := setupImageUsecase()
+		m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return(gallery(), nil)
+		m.images.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+
+		res, err := uc.Reorder(context.Background(), asUser(10), usecase.VenueGallery(4), dto.ReorderImagesRequest{ImageIDs: []uint{3, 1, 2}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []uint{3, 1, 2}, []uint{res[0].ID, res[1].ID, res[2].ID})
+		assert.Equal(t, []int{1, 2, 3}, []int{res[0].Position, res[1].Position, res[2].Position})
+	})
+
+	for name, ids := range map[string][]uint{
+		"missing one": {3, 1},
+		"duplicate":   {3, 1, 1},
+		"unknown":     {3, 1, 9},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, uc := setupImageUsecase()
+			m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return(gallery(), nil)
+
+			_, err := uc.Reorder(context.Background(), asUser(10), usecase.VenueGallery(4), dto.ReorderImagesRequest{ImageIDs: ids})
+
+			assert.ErrorIs(t, err, constant.ErrBadRequest)
+			m.images.AssertNotCalled(t, "SaveAll", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDeleteImage_NextBecomesCover(t *testing.T) {
+	m, uc := setupImageUsecase()
+	ctx := context.Background()
+	m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return([]model.Image{
+		{Model: gorm.Model{ID: 1}, VenueID: 4, Key: "venues/4/a", Ext: ".jpg", Position: 1, IsCover: true},
+		{Model: gorm.Model{ID: 2}, VenueID: 4, Key: "venues/4/b", Ext: ".jpg", Position: 2},
+	}, nil)
+	m.images.On("Delete", mock.Anything, []uint{1}).Return(nil)
+	m.images.On("SaveAll", mock.Anything, mock.MatchedBy(func(imgs []model.Image) bool {
+		return len(imgs) == 1 && imgs[0].ID == 2 && imgs[0].IsCover
+	})).Return(nil)
+	for _, key := range []string{"venues/4/a_large.jpg", "venues/4/a_thumb.jpg", "venues/4/b_thumb.jpg"} {
+		m.store.Put(ctx, key, "image/jpeg", nil)
+	}
+
+	err := uc.Delete(ctx, asUser(10), usecase.VenueGallery(4), 1)
+
+	require.NoError(t, err)
+	m.images.AssertExpectations(t)
+	assert.Equal(t, []string{"venues/4/b_thumb.jpg"}, keys(m.store))
+}
+
+func TestDeleteImage_OtherGallery(t *testing.T) {
+	m, uc := setupImageUsecase()
+	m.images.On("ListGallery", mock.Anything, uint(4), uint(0)).Return([]model.Image{{Model: gorm.Model{ID: 1}, VenueID: 4}}, nil)
+
+	err := uc.Delete(context.Background(), asUser(10), usecase.VenueGallery(4), 7)
+
+	assert.ErrorIs(t, err, constant.ErrImageNotFound)
+	m.images.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestRemoveVenueImages_RemovesSpacePhotosToo(t *testing.T) {
+	m, uc := setupImageUsecase()
+	ctx := context.Background()
+	m.images.On("ListByVenue", ctx, uint(4)).Return([]model.Image{
+		{Model: gorm.Model{ID: 1}, VenueID: 4, Key: "venues/4/a", Ext: ".png"},
+		{Model: gorm.Model{ID: 2}, VenueID: 4, SpaceID: 8, Key: "venues/4/spaces/8/b", Ext: ".jpg"},
+	}, nil)
+	m.images.On("Delete", ctx, []uint{1, 2}).Return(nil)
+	m.store.Put(ctx, "venues/4/a_medium.png", "image/png", nil)
+	m.store.Put(ctx, "venues/4/spaces/8/b_large.jpg", "image/jpeg", nil)
+
+	require.NoError(t, uc.RemoveVenueImages(ctx, 4))
+
+	assert.Empty(t, m.store.objects)
+}
+
+func TestListImages_BlockedVenue(t *testing.T) {
+	venues := new(mockVenueRepo)
+	venues.On("FindByID", mock.Anything, uint(4)).Return(&model.Venue{Model: gorm.Model{ID: 4}, Status: constant.BLOCKED}, nil)
+	images := new(mockImageRepo)
+	uc := usecase.NewImageUsecase(images, venues, new(mockSpaceRepo), newMemStorage())
+
+	_, err := uc.List(context.Background(), usecase.VenueGallery(4))
+
+	assert.ErrorIs(t, err, constant.ErrVenueNotFound)
+	images.AssertNotCalled(t, "ListGallery", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func keys(s *memStorage) []string {
+	var res []string
+	for key := range s.objects {
+		res = append(res, key)
+	}
+	return res
+}
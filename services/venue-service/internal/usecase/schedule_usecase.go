@@ -0,0 +1,585 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"packages/policy"
+	"slices"
+	"time"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/repository"
+	"venue-service/internal/schedule"
+
+	"gorm.io/gorm"
+)
+
+// Openings tells which spaces are closed over a period or at a time of day, for the search
+// availability filters.
+type Openings interface {
+	ClosedSpaces(ctx context.Context, spaceIDs []uint, start, end time.Time) ([]uint, error)
+	ClosedAt(ctx context.Context, spaceIDs []uint, day time.Time, clock string) ([]uint, error)
+}
+
+// ScheduleUsecase manages when spaces are open: the weekly hours of venues and spaces, the
+// timezone they are in, public holidays and one-off closures.
+type ScheduleUsecase interface {
+	GetVenueSchedule(ctx context.Context, venueID uint) (*dto.Schedule, error)
+	SetVenueSchedule(ctx context.Context, sub policy.Subject, venueID uint, req dto.VenueScheduleRequest) (*dto.Schedule, error)
+	GetSpaceSchedule(ctx context.Context, spaceID uint) (*dto.Schedule, error)
+	SetSpaceSchedule(ctx context.Context, sub policy.Subject, spaceID uint, req dto.SpaceScheduleRequest) (*dto.Schedule, error)
+	ClearSpaceSchedule(ctx context.Context, sub policy.Subject, spaceID uint) (*dto.Schedule, error)
+
+	ListClosures(ctx context.Context, venueID uint) ([]dto.Closure, error)
+	AddClosure(ctx context.Context, sub policy.Subject, venueID uint, req dto.ClosureRequest) (*dto.Closure, error)
+	DeleteClosure(ctx context.Context, sub policy.Subject, venueID, closureID uint) error
+
+	ListHolidays(ctx context.Context, calendar string, year int) ([]dto.Holiday, error)
+	AddHoliday(ctx context.Context, req dto.HolidayRequest) (*dto.Holiday, error)
+	DeleteHoliday(ctx context.Context, id uint) error
+
+	CheckOpen(ctx context.Context, spaceID uint, start, end time.Time) (*dto.OpeningCheck, error)
+	Openings
+}
+
+type scheduleUsecase struct {
+	repo      repository.ScheduleRepository
+	venueRepo repository.VenueRepository
+	spaceRepo repository.SpaceRepository
+}
+
+func NewScheduleUsecase(r repository.ScheduleRepository, v repository.VenueRepository, s repository.SpaceRepository) ScheduleUsecase {
+	return &scheduleUsecase{repo: r, venueRepo: v, spaceRepo: s}
+}
+
+func (u *scheduleUsecase) GetVenueSchedule(ctx context.Context, venueID uint) (*dto.Schedule, error) {
+	venue, err := u.venueRepo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	hours, err := u.repo.ListHours(ctx, []uint{venueID})
+	if err != nil {
+		return nil, err
+	}
+	return venueSchedule(venue, hours), nil
+}
+
+// SetVenueSchedule replaces the opening hours of the venue, which its spaces without hours of
+// their own follow, and sets their timezone and holiday calendar.
+func (u *scheduleUsecase) SetVenueSchedule(ctx context.Context, sub policy.Subject, venueID uint, req dto.VenueScheduleRequest) (*dto.Schedule, error) {
+	if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+		return nil, constant.ErrInvalidTimezone
+	}
+	hours, err := toOpeningHours(venueID, 0, req.Hours)
+	if err != nil {
+		return nil, err
+	}
+	venue, err := u.venueRepo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.venueRepo, sub, policy.VenueUpdate, venue, nil); err != nil {
+		return nil, err
+	}
+
+	venue.Timezone, venue.HolidayCalendar = req.Timezone, req.HolidayCalendar
+	if err := u.repo.SaveVenueSchedule(ctx, venue, hours); err != nil {
+		return nil, constant.ErrUpdateFailed
+	}
+	return venueSchedule(venue, hours), nil
+}
+
+// GetSpaceSchedule returns the hours the space opens at: its own, else those of its venue, else
+// its daily open and close hours.
+func (u *scheduleUsecase) GetSpaceSchedule(ctx context.Context, spaceID uint) (*dto.Schedule, error) {
+	space, venue, err := u.spaceWithVenue(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return u.spaceSchedule(ctx, space, venue)
+}
+
+func (u *scheduleUsecase) SetSpaceSchedule(ctx context.Context, sub policy.Subject, spaceID uint, req dto.SpaceScheduleRequest) (*dto.Schedule, error) {
+	space, venue, err := u.spaceWithVenue(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := toOpeningHours(venue.ID, space.ID, req.Hours)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, u.venueRepo, sub, policy.SpaceUpdate, venue, space); err != nil {
+		return nil, err
+	}
+	if err := u.repo.ReplaceHours(ctx, venue.ID, space.ID, hours); err != nil {
+		return nil, constant.ErrUpdateFailed
+	}
+	return u.spaceSchedule(ctx, space, venue)
+}
+
+// ClearSpaceSchedule removes the own hours of the space, which then follows its venue.
+func (u *scheduleUsecase) ClearSpaceSchedule(ctx context.Context, sub policy.Subject, spaceID uint) (*dto.Schedule, error) {
+	space, venue, err := u.spaceWithVenue(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, u.venueRepo, sub, policy.SpaceUpdate, venue, space); err != nil {
+		return nil, err
+	}
+	if err := u.repo.ReplaceHours(ctx, venue.ID, space.ID, nil); err != nil {
+		return nil, constant.ErrDeleteFailed
+	}
+	return u.spaceSchedule(ctx, space, venue)
+}
+
+// ListClosures returns the closures of the venue and its spaces that are not over yet.
+func (u *scheduleUsecase) ListClosures(ctx context.Context, venueID uint) ([]dto.Closure, error) {
+	if _, err := u.venueRepo.FindByID(ctx, venueID); err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	closures, err := u.repo.ListClosures(ctx, venueID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.Closure, 0, len(closures))
+	for i := range closures {
+		res = append(res, toClosure(&closures[i]))
+	}
+	return res, nil
+}
+
+// AddClosure closes the venue, or one of its spaces, over a period. Closing a space takes
+// space:update on it, closing the venue venue:update.
+func (u *scheduleUsecase) AddClosure(ctx context.Context, sub policy.Subject, venueID uint, req dto.ClosureRequest) (*dto.Closure, error) {
+	if !req.StartsAt.Before(req.EndsAt) {
+		return nil, constant.ErrBadRequest
+	}
+	venue, space, err := u.closureTarget(ctx, venueID, req.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, u.venueRepo, sub, closurePermission(space), venue, space); err != nil {
+		return nil, err
+	}
+
+	closure := model.Closure{
+		VenueID:  venueID,
+		SpaceID:  req.SpaceID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	}
+	if err := u.repo.CreateClosure(ctx, &closure); err != nil {
+		return nil, constant.ErrCreateFailed
+	}
+	res := toClosure(&closure)
+	return &res, nil
+}
+
+func (u *scheduleUsecase) DeleteClosure(ctx context.Context, sub policy.Subject, venueID, closureID uint) error {
+	closure, err := u.repo.FindClosure(ctx, venueID, closureID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constant.ErrNotFound
+		}
+		return err
+	}
+	venue, space, err := u.closureTarget(ctx, venueID, closure.SpaceID)
+	if err != nil {
+		return err
+	}
+	if err := authorize(ctx, u.venueRepo, sub, closurePermission(space), venue, space); err != nil {
+		return err
+	}
+	if err := u.repo.DeleteClosure(ctx, closure); err != nil {
+		return constant.ErrDeleteFailed
+	}
+	return nil
+}
+
+func (u *scheduleUsecase) ListHolidays(ctx context.Context, calendar string, year int) ([]dto.Holiday, error) {
+	holidays, err := u.repo.ListHolidays(ctx, calendar, year)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.Holiday, 0, len(holidays))
+	for i := range holidays {
+		res = append(res, toHoliday(&holidays[i]))
+	}
+	return res, nil
+}
+
+// AddHoliday adds a public holiday to a calendar. The route checks the caller may manage them.
+func (u *scheduleUsecase) AddHoliday(ctx context.Context, req dto.HolidayRequest) (*dto.Holiday, error) {
+	exists, err := u.repo.HolidayExists(ctx, req.Calendar, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, constant.ErrHolidayExists
+	}
+	holiday := model.Holiday{Calendar: req.Calendar, Date: req.Date, Name: req.Name}
+	if err := u.repo.CreateHoliday(ctx, &holiday); err != nil {
+		return nil, constant.ErrCreateFailed
+	}
+	res := toHoliday(&holiday)
+	return &res, nil
+}
+
+func (u *scheduleUsecase) DeleteHoliday(ctx context.Context, id uint) error {
+	if err := u.repo.DeleteHoliday(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constant.ErrNotFound
+		}
+		return constant.ErrDeleteFailed
+	}
+	return nil
+}
+
+// CheckOpen tells booking-service whether the space is open throughout [start, end).
+func (u *scheduleUsecase) CheckOpen(ctx context.Context, spaceID uint, start, end time.Time) (*dto.OpeningCheck, error) {
+	if !start.Before(end) {
+		return nil, constant.ErrBadRequest
+	}
+	spaces, err := u.spaceRepo.GetByIDs(ctx, []uint{spaceID})
+	if err != nil {
+		return nil, err
+	}
+	if len(spaces) == 0 {
+		return nil, constant.ErrNotFound
+	}
+	calendars, err := u.calendars(ctx, spaces, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if err := calendars[spaceID].Check(start, end); err != nil {
+		return &dto.OpeningCheck{Open: false, Reason: err.Error()}, nil
+	}
+	return &dto.OpeningCheck{Open: true}, nil
+}
+
+// ClosedSpaces returns those of the spaces that are not open throughout [start, end).
+func (u *scheduleUsecase) ClosedSpaces(ctx context.Context, spaceIDs []uint, start, end time.Time) ([]uint, error) {
+	if len(spaceIDs) == 0 {
+		return nil, nil
+	}
+	spaces, err := u.spaceRepo.GetByIDs(ctx, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+	calendars, err := u.calendars(ctx, spaces, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var closed []uint
+	for _, s := range spaces {
+		if calendars[s.ID].Check(start, end) != nil {
+			closed = append(closed, s.ID)
+		}
+	}
+	return closed, nil
+}
+
+// ClosedAt returns those of the spaces that are not open at the time of day clock ("15:04") on
+// the date day falls on in the timezone of their venue.
+func (u *scheduleUsecase) ClosedAt(ctx context.Context, spaceIDs []uint, day time.Time, clock string) ([]uint, error) {
+	if len(spaceIDs) == 0 {
+		return nil, nil
+	}
+	at, err := time.Parse("15:04", clock)
+	if err != nil {
+		return nil, constant.ErrBadRequest
+	}
+	spaces, err := u.spaceRepo.GetByIDs(ctx, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(spaces) == 0 {
+		return nil, nil
+	}
+
+	// the same clock is a different instant in every timezone
+	instants := make(map[uint]time.Time, len(spaces))
+	var first, last time.Time
+	for _, s := range spaces {
+		loc := location(s.Venue.Timezone)
+		y, m, d := day.In(loc).Date()
+		t := time.Date(y, m, d, at.Hour(), at.Minute(), 0, 0, loc)
+		instants[s.ID] = t
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	calendars, err := u.calendars(ctx, spaces, first, last.Add(time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	var closed []uint
+	for _, s := range spaces {
+		t := instants[s.ID]
+		if calendars[s.ID].Check(t, t.Add(time.Minute)) != nil {
+			closed = append(closed, s.ID)
+		}
+	}
+	return closed, nil
+}
+
+// calendars builds the calendar of each space over [start, end). The spaces must come with
+// their venue.
+func (u *scheduleUsecase) calendars(ctx context.Context, spaces []model.Space, start, end time.Time) (map[uint]schedule.Calendar, error) {
+	var venueIDs []uint
+	var names []string
+	for _, s := range spaces {
+		venueIDs = append(venueIDs, s.VenueID)
+		if s.Venue.HolidayCalendar != "" {
+			names = append(names, s.Venue.HolidayCalendar)
+		}
+	}
+	venueIDs = slices.Compact(slices.Sorted(slices.Values(venueIDs)))
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+
+	hours, err := u.repo.ListHours(ctx, venueIDs)
+	if err != nil {
+		return nil, err
+	}
+	closures, err := u.repo.ClosuresBetween(ctx, venueIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	holidays := map[string]map[string]bool{}
+	if len(names) > 0 {
+		// a day of margin as the dates are local to each venue
+		from := start.UTC().AddDate(0, 0, -1).Format(schedule.DateLayout)
+		to := end.UTC().AddDate(0, 0, 1).Format(schedule.DateLayout)
+		found, err := u.repo.HolidaysBetween(ctx, names, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range found {
+			if holidays[h.Calendar] == nil {
+				holidays[h.Calendar] = map[string]bool{}
+			}
+			holidays[h.Calendar][h.Date] = true
+		}
+	}
+
+	res := make(map[uint]schedule.Calendar, len(spaces))
+	for i := range spaces {
+		s := &spaces[i]
+		_, week := weekOf(s, hours)
+		cal := schedule.Calendar{
+			Location: location(s.Venue.Timezone),
+			Week:     week,
+			Holidays: holidays[s.Venue.HolidayCalendar],
+		}
+		for _, c := range closures {
+			if c.VenueID == s.VenueID && (c.SpaceID == 0 || c.SpaceID == s.ID) {
+				cal.Closures = append(cal.Closures, schedule.Period{Start: c.StartsAt, End: c.EndsAt})
+			}
+		}
+		res[s.ID] = cal
+	}
+	return res, nil
+}
+
+// weekOf returns the weekly hours of the space and where they come from, taking the first of:
+// its own hours, those of its venue, its daily open and close hours. A nil week is open around
+// the clock.
+func weekOf(space *model.Space, hours []model.OpeningHours) (string, []schedule.Interval) {
+	var own, venue []schedule.Interval
+	for _, h := range hours {
+		if h.VenueID != space.VenueID || (h.SpaceID != 0 && h.SpaceID != space.ID) {
+			continue
+		}
+		iv, err := toInterval(h)
+		if err != nil {
+			log.Printf("skipping opening hours %d: %v", h.ID, err)
+			continue
+		}
+		if h.SpaceID == 0 {
+			venue = append(venue, iv)
+		} else {
+			own = append(own, iv)
+		}
+	}
+	switch {
+	case own != nil:
+		return constant.ScheduleSpace, own
+	case venue != nil:
+		return constant.ScheduleVenue, venue
+	}
+	if daily, err := schedule.Daily(space.OpenHour, space.CloseHour); err == nil {
+		return constant.ScheduleDaily, daily
+	}
+	return constant.ScheduleNone, nil
+}
+
+func (u *scheduleUsecase) spaceSchedule(ctx context.Context, space *model.Space, venue *model.Venue) (*dto.Schedule, error) {
+	hours, err := u.repo.ListHours(ctx, []uint{venue.ID})
+	if err != nil {
+		return nil, err
+	}
+	source, week := weekOf(space, hours)
+	return &dto.Schedule{
+		VenueID:         venue.ID,
+		SpaceID:         space.ID,
+		Timezone:        venue.Timezone,
+		HolidayCalendar: venue.HolidayCalendar,
+		Source:          source,
+		Hours:           toOpeningIntervals(week),
+	}, nil
+}
+
+func venueSchedule(venue *model.Venue, hours []model.OpeningHours) *dto.Schedule {
+	var week []schedule.Interval
+	for _, h := range hours {
+		if h.SpaceID != 0 {
+			continue
+		}
+		if iv, err := toInterval(h); err == nil {
+			week = append(week, iv)
+		}
+	}
+	source := constant.ScheduleVenue
+	if week == nil {
+		source = constant.ScheduleNone
+	}
+	return &dto.Schedule{
+		VenueID:         venue.ID,
+		Timezone:        venue.Timezone,
+		HolidayCalendar: venue.HolidayCalendar,
+		Source:          source,
+		Hours:           toOpeningIntervals(week),
+	}
+}
+
+func (u *scheduleUsecase) spaceWithVenue(ctx context.Context, spaceID uint) (*model.Space, *model.Venue, error) {
+	space, err := u.spaceRepo.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, nil, constant.ErrNotFound
+	}
+	venue, err := u.venueRepo.FindByID(ctx, space.VenueID)
+	if err != nil {
+		return nil, nil, constant.ErrVenueNotFound
+	}
+	return space, venue, nil
+}
+
+// closureTarget returns the venue and, for a closure of a single space, the space, which must
+// belong to the venue.
+func (u *scheduleUsecase) closureTarget(ctx context.Context, venueID, spaceID uint) (*model.Venue, *model.Space, error) {
+	venue, err := u.venueRepo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, nil, constant.ErrVenueNotFound
+	}
+	if spaceID == 0 {
+		return venue, nil, nil
+	}
+	space, err := u.spaceRepo.GetByID(ctx, spaceID)
+	if err != nil || space.VenueID != venueID {
+		return nil, nil, constant.ErrNotFound
+	}
+	return venue, space, nil
+}
+
+func closurePermission(space *model.Space) policy.Permission {
+	if space != nil {
+		return policy.SpaceUpdate
+	}
+	return policy.VenueUpdate
+}
+
+// toOpeningHours validates the intervals and turns them into rows, their times as "15:04".
+func toOpeningHours(venueID, spaceID uint, intervals []dto.OpeningInterval) ([]model.OpeningHours, error) {
+	week := make([]schedule.Interval, 0, len(intervals))
+	for _, in := range intervals {
+		opens, err := schedule.ParseClock(in.Opens)
+		if err != nil {
+			return nil, constant.ErrInvalidSchedule
+		}
+		closes, err := schedule.ParseClock(in.Closes)
+		if err != nil {
+			return nil, constant.ErrInvalidSchedule
+		}
+		week = append(week, schedule.Interval{Weekday: time.Weekday(in.Weekday), Opens: opens, Closes: closes})
+	}
+	if err := schedule.Validate(week); err != nil {
+		return nil, constant.ErrInvalidSchedule
+	}
+	hours := make([]model.OpeningHours, 0, len(week))
+	for _, iv := range week {
+		hours = append(hours, model.OpeningHours{
+			VenueID: venueID,
+			SpaceID: spaceID,
+			Weekday: int(iv.Weekday),
+			Opens:   schedule.FormatClock(iv.Opens),
+			Closes:  schedule.FormatClock(iv.Closes),
+		})
+	}
+	return hours, nil
+}
+
+func toInterval(h model.OpeningHours) (schedule.Interval, error) {
+	opens, err := schedule.ParseClock(h.Opens)
+	if err != nil {
+		return schedule.Interval{}, err
+	}
+	closes, err := schedule.ParseClock(h.Closes)
+	if err != nil {
+		return schedule.Interval{}, err
+	}
+	return schedule.Interval{Weekday: time.Weekday(h.Weekday), Opens: opens, Closes: closes}, nil
+}
+
+// toOpeningIntervals lists the week by day, from Sunday, then by time.
+func toOpeningIntervals(week []schedule.Interval) []dto.OpeningInterval {
+	week = slices.Clone(week)
+	slices.SortStableFunc(week, func(a, b schedule.Interval) int {
+		if a.Weekday != b.Weekday {
+			return int(a.Weekday - b.Weekday)
+		}
+		return a.Opens - b.Opens
+	})
+	res := make([]dto.OpeningInterval, 0, len(week))
+	for _, iv := range week {
+		res = append(res, dto.OpeningInterval{
+			Weekday: int(iv.Weekday),
+			Opens:   schedule.FormatClock(iv.Opens),
+			Closes:  schedule.FormatClock(iv.Closes),
+		})
+	}
+	return res
+}
+
+// location returns the zone of a venue, the default one for a venue from before timezones.
+func location(name string) *time.Location {
+	if name == "" {
+		name = constant.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("unknown venue timezone %q: %v", name, err)
+		return time.UTC
+	}
+	return loc
+}
+
+func toClosure(c *model.Closure) dto.Closure {
+	return dto.Closure{
+		ID:       c.ID,
+		VenueID:  c.VenueID,
+		SpaceID:  c.SpaceID,
+		StartsAt: c.StartsAt,
+		EndsAt:   c.EndsAt,
+		Reason:   c.Reason,
+	}
+}
+
+func toHoliday(h *model.Holiday) dto.Holiday {
+	return dto.Holiday{ID: h.ID, Calendar: h.Calendar, Date: h.Date, Name: h.Name}
+}
@@ -0,0 +1,302 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"venue-service/internal/constant"
+	"venue-service/internal/dto"
+	"venue-service/internal/model"
+	"venue-service/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ===== Mock ScheduleRepository =====
+type mockScheduleRepo struct{ mock.Mock }
+
+func (m *mockScheduleRepo) ListHours(ctx context.Context, venueIDs []uint) ([]model.OpeningHours, error) {
+	args := m.Called(ctx, venueIDs)
+	hours, _ := args.Get(0).([]model.OpeningHours)
+	return hours, args.Error(1)
+}
+func (m *mockScheduleRepo) SaveVenueSchedule(ctx context.Context, venue *model.Venue, hours []model.OpeningHours) error {
+	return m.Called(ctx, venue, hours).Error(0)
+}
+func (m *mockScheduleRepo) ReplaceHours(ctx context.Context, venueID, spaceID uint, hours []model.OpeningHours) error {
+	return m.Called(ctx, venueID, spaceID, hours).Error(0)
+}
+func (m *mockScheduleRepo) ListClosures(ctx context.Context, venueID uint, from time.Time) ([]model.Closure, error) {
+	args := m.Called(ctx, venueID, from)
+	closures, _ := args.Get(0).([]model.Closure)
+	return closures, args.Error(1)
+}
+func (m *mockScheduleRepo) ClosuresBetween(ctx context.Context, venueIDs []uint, start, end time.Time) ([]model.Closure, error) {
+	args := m.Called(ctx, venueIDs, start, end)
+	closures, _ := args.Get(0).([]model.Closure)
+	return closures, args.Error(1)
+}
+func (m *mockScheduleRepo) CreateClosure(ctx context.Context, closure *model.Closure) error {
+	return m.Called(ctx, closure).Error(0)
+}
+func (m *mockScheduleRepo) FindClosure(ctx context.Context, venueID, closureID uint) (*model.Closure, error) {
+	args := m.Called(ctx, venueID, closureID)
+	closure, _ := args.Get(0).(*model.Closure)
+	return closure, args.Error(1)
+}
+func (m *mockScheduleRepo) DeleteClosure(ctx context.Context, closure *model.Closure) error {
+	return m.Called(ctx, closure).Error(0)
+}
+func (m *mockScheduleRepo) ListHolidays(ctx context.Context, calendar string, year int) ([]model.Holiday, error) {
+	args := m.Called(ctx, calendar, year)
+	holidays, _ := args.Get(0).([]model.Holiday)
+	return holidays, args.Error(1)
+}
+func (m *mockScheduleRepo) HolidaysBetween(ctx context.Context, calendars []string, from, to string) ([]model.Holiday, error) {
+	args := m.Called(ctx, calendars, from, to)
+	holidays, _ := args.Get(0).([]model.Holiday)
+	return holidays, args.Error(1)
+}
+func (m *mockScheduleRepo) HolidayExists(ctx context.Context, calendar, date string) (bool, error) {
+	args := m.Called(ctx, calendar, date)
+	return args.Bool(0), args.Error(1)
+}
+func (m *mockScheduleRepo) CreateHoliday(ctx context.Context, holiday *model.Holiday) error {
+	return m.Called(ctx, holiday).Error(0)
+}
+func (m *mockScheduleRepo) DeleteHoliday(ctx context.Context, id uint) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+var saigon, _ = time.LoadLocation(constant.DefaultTimezone)
+
+// saigonAt returns the time in Saigon; 2026-03-02 is a Monday.
+func saigonAt(day, hour int) time.Time {
+	return time.Date(2026, 3, day, hour, 0, 0, 0, saigon)
+}
+
+func openingSpace(id, venueID uint) model.Space {
+	return model.Space{
+		Model:     gorm.Model{ID: id},
+		VenueID:   venueID,
+		OpenHour:  "07:00",
+		CloseHour: "22:00",
+		Venue:     model.Venue{Model: gorm.Model{ID: venueID}, Timezone: constant.DefaultTimezone, HolidayCalendar: "VN"},
+	}
+}
+
+func weekdayHours(venueID, spaceID uint, opens, closes string) []model.OpeningHours {
+	var hours []model.OpeningHours
+	for day := 1; day <= 5; day++ {
+		hours = append(hours, model.OpeningHours{VenueID: venueID, SpaceID: spaceID, Weekday: day, Opens: opens, Closes: closes})
+	}
+	return hours
+}
+
+// ===== Unit Tests =====
+
+func TestSetVenueSchedule_Normalises(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	venueRepo := new(mockVenueRepo)
+	uc := usecase.NewScheduleUsecase(repo, venueRepo, new(mockSpaceRepo))
+	ctx := context.Background()
+
+	venue := &model.Venue{Model: gorm.Model{ID: 1}, UserID: 10}
+	venueRepo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	saved := []model.OpeningHours{
+		{VenueID: 1, Weekday: 1, Opens: "08:00", Closes: "12:00"},
+		{VenueID: 1, Weekday: 1, Opens: "13:30", Closes: "24:00"},
+	}
+	repo.On("SaveVenueSchedule", ctx, venue, saved).Return(nil)
+
+	schedule, err := uc.SetVenueSchedule(ctx, asUser(10), 1, dto.VenueScheduleRequest{
+		Timezone:        "Asia/Bangkok",
+		HolidayCalendar: "VN",
+		Hours:           []dto.OpeningInterval{{Weekday: 1, Opens: "8:00", Closes: "12:00"}, {Weekday: 1, Opens: "13:30", Closes: "24:00"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Bangkok", venue.Timezone)
+	assert.Equal(t, constant.ScheduleVenue, schedule.Source)
+	assert.Equal(t, "08:00", schedule.Hours[0].Opens)
+	repo.AssertExpectations(t)
+}
+
+func TestSetVenueSchedule_Invalid(t *testing.T) {
+	uc := usecase.NewScheduleUsecase(new(mockScheduleRepo), new(mockVenueRepo), new(mockSpaceRepo))
+	ctx := context.Background()
+	hours := []dto.OpeningInterval{{Weekday: 1, Opens: "08:00", Closes: "18:00"}}
+
+	_, err := uc.SetVenueSchedule(ctx, asUser(10), 1, dto.VenueScheduleRequest{Timezone: "Mars/Olympus", Hours: hours})
+	assert.ErrorIs(t, err, constant.ErrInvalidTimezone)
+
+	for _, bad := range [][]dto.OpeningInterval{
+		{{Weekday: 1, Opens: "18:00", Closes: "08:00"}},
+		{{Weekday: 1, Opens: "08:00", Closes: "12:00"}, {Weekday: 1, Opens: "11:00", Closes: "14:00"}},
+		{{Weekday: 1, Opens: "8h", Closes: "12:00"}},
+	} {
+		_, err := uc.SetVenueSchedule(ctx, asUser(10), 1, dto.VenueScheduleRequest{Timezone: constant.DefaultTimezone, Hours: bad})
+		assert.ErrorIs(t, err, constant.ErrInvalidSchedule, "%v", bad)
+	}
+}
+
+func TestSetSpaceSchedule_Forbidden(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	venueRepo := new(mockVenueRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, venueRepo, spaceRepo)
+	ctx := context.Background()
+
+	spaceRepo.On("GetByID", ctx, uint(5)).Return(&model.Space{Model: gorm.Model{ID: 5}, VenueID: 1}, nil)
+	venueRepo.On("FindByID", ctx, uint(1)).Return(&model.Venue{Model: gorm.Model{ID: 1}, UserID: 10}, nil)
+	venueRepo.On("FindMember", ctx, uint(1), uint(99)).Return(nil, nil)
+
+	_, err := uc.SetSpaceSchedule(ctx, asUser(99), 5, dto.SpaceScheduleRequest{
+		Hours: []dto.OpeningInterval{{Weekday: 1, Opens: "08:00", Closes: "18:00"}},
+	})
+
+	assert.ErrorIs(t, err, constant.ErrForbidden)
+	repo.AssertNotCalled(t, "ReplaceHours", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckOpen_FollowsVenueHours(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), spaceRepo)
+	ctx := context.Background()
+
+	spaceRepo.On("GetByIDs", ctx, []uint{5}).Return([]model.Space{openingSpace(5, 1)}, nil)
+	repo.On("ListHours", ctx, []uint{1}).Return(weekdayHours(1, 0, "08:00", "18:00"), nil)
+	repo.On("ClosuresBetween", ctx, []uint{1}, mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("HolidaysBetween", ctx, []string{"VN"}, mock.Anything, mock.Anything).Return(nil, nil)
+
+	check, err := uc.CheckOpen(ctx, 5, saigonAt(2, 9), saigonAt(2, 17))
+	require.NoError(t, err)
+	assert.True(t, check.Open)
+
+	// the daily hours of the space give way to those of its venue
+	check, err = uc.CheckOpen(ctx, 5, saigonAt(2, 19), saigonAt(2, 21))
+	require.NoError(t, err)
+	assert.False(t, check.Open)
+	assert.Equal(t, "outside opening hours", check.Reason)
+}
+
+func TestCheckOpen_Holiday(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), spaceRepo)
+	ctx := context.Background()
+
+	spaceRepo.On("GetByIDs", ctx, []uint{5}).Return([]model.Space{openingSpace(5, 1)}, nil)
+	repo.On("ListHours", ctx, []uint{1}).Return(nil, nil)
+	repo.On("ClosuresBetween", ctx, []uint{1}, mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("HolidaysBetween", ctx, []string{"VN"}, "2026-03-01", "2026-03-03").
+		Return([]model.Holiday{{Calendar: "VN", Date: "2026-03-02", Name: "Test day"}}, nil)
+
+	check, err := uc.CheckOpen(ctx, 5, saigonAt(2, 9), saigonAt(2, 10))
+
+	require.NoError(t, err)
+	assert.False(t, check.Open)
+	assert.Equal(t, "closed for a public holiday", check.Reason)
+}
+
+func TestClosedSpaces(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), spaceRepo)
+	ctx := context.Background()
+	start, end := saigonAt(2, 19), saigonAt(2, 21)
+
+	spaces := []model.Space{openingSpace(5, 1), openingSpace(6, 1), openingSpace(7, 2)}
+	spaceRepo.On("GetByIDs", ctx, []uint{5, 6, 7}).Return(spaces, nil)
+	// space 6 opens late on its own hours while the rest of venue 1 closes at 18:00
+	hours := append(weekdayHours(1, 0, "08:00", "18:00"), weekdayHours(1, 6, "18:00", "23:00")...)
+	repo.On("ListHours", ctx, []uint{1, 2}).Return(hours, nil)
+	repo.On("ClosuresBetween", ctx, []uint{1, 2}, start, end).
+		Return([]model.Closure{{VenueID: 2, StartsAt: saigonAt(2, 20), EndsAt: saigonAt(3, 8)}}, nil)
+	repo.On("HolidaysBetween", ctx, []string{"VN"}, mock.Anything, mock.Anything).Return(nil, nil)
+
+	closed, err := uc.ClosedSpaces(ctx, []uint{5, 6, 7}, start, end)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5, 7}, closed)
+}
+
+func TestClosedAt(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), spaceRepo)
+	ctx := context.Background()
+
+	spaces := []model.Space{openingSpace(5, 1), openingSpace(6, 1), openingSpace(7, 2), openingSpace(8, 3)}
+	// venue 3 is in Tokyo, where 19:00 comes two hours before it does in Saigon
+	spaces[3].Venue.Timezone = "Asia/Tokyo"
+	spaceRepo.On("GetByIDs", ctx, []uint{5, 6, 7, 8}).Return(spaces, nil)
+	// space 6 opens late on its own hours while the rest of venue 1 closes at 18:00; venue 3
+	// keeps the legacy daily hours of its space, 07:00 to 22:00
+	hours := append(weekdayHours(1, 0, "08:00", "18:00"), weekdayHours(1, 6, "18:00", "23:00")...)
+	hours = append(hours, weekdayHours(2, 0, "08:00", "22:00")...)
+	repo.On("ListHours", ctx, []uint{1, 2, 3}).Return(hours, nil)
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	repo.On("ClosuresBetween", ctx, []uint{1, 2, 3}, time.Date(2026, 3, 2, 19, 0, 0, 0, tokyo), saigonAt(2, 19).Add(time.Minute)).
+		Return([]model.Closure{{VenueID: 2, StartsAt: saigonAt(2, 18), EndsAt: saigonAt(3, 8)}}, nil)
+	repo.On("HolidaysBetween", ctx, []string{"VN"}, mock.Anything, mock.Anything).Return(nil, nil)
+
+	// 10:00 UTC is already Monday the 2nd in both zones
+	closed, err := uc.ClosedAt(ctx, []uint{5, 6, 7, 8}, time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), "19:00")
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5, 7}, closed)
+}
+
+func TestClosedAt_Holiday(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), spaceRepo)
+	ctx := context.Background()
+
+	spaceRepo.On("GetByIDs", ctx, []uint{5}).Return([]model.Space{openingSpace(5, 1)}, nil)
+	repo.On("ListHours", ctx, []uint{1}).Return(weekdayHours(1, 0, "08:00", "18:00"), nil)
+	repo.On("ClosuresBetween", ctx, []uint{1}, mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("HolidaysBetween", ctx, []string{"VN"}, mock.Anything, mock.Anything).
+		Return([]model.Holiday{{Calendar: "VN", Date: "2026-03-02"}}, nil)
+
+	closed, err := uc.ClosedAt(ctx, []uint{5}, saigonAt(2, 0), "10:00")
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5}, closed)
+}
+
+func TestAddClosure_SpaceOfOtherVenue(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	venueRepo := new(mockVenueRepo)
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewScheduleUsecase(repo, venueRepo, spaceRepo)
+	ctx := context.Background()
+
+	venueRepo.On("FindByID", ctx, uint(1)).Return(&model.Venue{Model: gorm.Model{ID: 1}, UserID: 10}, nil)
+	spaceRepo.On("GetByID", ctx, uint(5)).Return(&model.Space{Model: gorm.Model{ID: 5}, VenueID: 2}, nil)
+
+	_, err := uc.AddClosure(ctx, asUser(10), 1, dto.ClosureRequest{SpaceID: 5, StartsAt: saigonAt(2, 9), EndsAt: saigonAt(2, 12)})
+	assert.ErrorIs(t, err, constant.ErrNotFound)
+
+	_, err = uc.AddClosure(ctx, asUser(10), 1, dto.ClosureRequest{StartsAt: saigonAt(2, 12), EndsAt: saigonAt(2, 9)})
+	assert.ErrorIs(t, err, constant.ErrBadRequest)
+	repo.AssertNotCalled(t, "CreateClosure", mock.Anything, mock.Anything)
+}
+
+func TestAddHoliday_Exists(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	uc := usecase.NewScheduleUsecase(repo, new(mockVenueRepo), new(mockSpaceRepo))
+	ctx := context.Background()
+
+	repo.On("HolidayExists", ctx, "VN", "2026-09-02").Return(true, nil)
+
+	_, err := uc.AddHoliday(ctx, dto.HolidayRequest{Calendar: "VN", Date: "2026-09-02", Name: "National Day"})
+
+	assert.ErrorIs(t, err, constant.ErrHolidayExists)
+	repo.AssertNotCalled(t, "CreateHoliday", mock.Anything, mock.Anything)
+}
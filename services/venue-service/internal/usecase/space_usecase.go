@@ -3,43 +3,51 @@ package usecase
 import (
 	"context"
 	"log"
+	"math"
+	"packages/policy"
+	"slices"
+	"strings"
 	"time"
 	"venue-service/internal/constant"
 	"venue-service/internal/dto"
 	"venue-service/internal/model"
 	"venue-service/internal/repository"
+	"venue-service/internal/schedule"
 )
 
 type SpaceUsecase interface {
-	Create(ctx context.Context, userID, venueID uint, req dto.CreateSpaceRequest) (*model.Space, error)
+	Create(ctx context.Context, sub policy.Subject, venueID uint, req dto.CreateSpaceRequest) (*model.Space, error)
 	GetByID(ctx context.Context, spaceID uint) (*model.Space, error)
-	Update(ctx context.Context, managerID, spaceID uint, req dto.UpdateSpaceRequest) (*model.Space, error)
-	Delete(ctx context.Context, managerID, spaceID uint) error
-	UpdateManager(ctx context.Context, ownerID, spaceID uint, req dto.UpdateManagerRequest) error
+	Update(ctx context.Context, sub policy.Subject, spaceID uint, req dto.UpdateSpaceRequest) (*model.Space, error)
+	Delete(ctx context.Context, sub policy.Subject, spaceID uint) error
+	UpdateManager(ctx context.Context, sub policy.Subject, spaceID uint, req dto.UpdateManagerRequest) error
 
-	SearchSpaces(ctx context.Context, name, city, address, spaceType string, startTime, endTime time.Time) ([]model.Space, error)
+	SearchSpaces(ctx context.Context, filter dto.SpaceSearchFilter) (*dto.SpaceSearchPage, error)
+	GetSummaries(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error)
 }
 type spaceUsecase struct {
 	repo          repository.SpaceRepository
 	venueRepo     repository.VenueRepository
 	bookingClient repository.BookingClient
+	openings      Openings
+	galleries     Galleries
 }
 
-func NewSpaceUsecase(r repository.SpaceRepository, v repository.VenueRepository, bookingClient repository.BookingClient) SpaceUsecase {
-	return &spaceUsecase{repo: r, venueRepo: v, bookingClient: bookingClient}
+func NewSpaceUsecase(r repository.SpaceRepository, v repository.VenueRepository, bookingClient repository.BookingClient, openings Openings, galleries Galleries) SpaceUsecase {
+	return &spaceUsecase{repo: r, venueRepo: v, bookingClient: bookingClient, openings: openings, galleries: galleries}
 }
 
 func (uc *spaceUsecase) GetByID(ctx context.Context, id uint) (*model.Space, error) {
 	return uc.repo.GetByID(ctx, id)
 }
 
-func (u *spaceUsecase) Create(ctx context.Context, userID, venueID uint, req dto.CreateSpaceRequest) (*model.Space, error) {
+func (u *spaceUsecase) Create(ctx context.Context, sub policy.Subject, venueID uint, req dto.CreateSpaceRequest) (*model.Space, error) {
 	venue, err := u.venueRepo.FindByID(ctx, venueID)
 	if err != nil {
 		return nil, constant.ErrNotFound
 	}
-	if venue.UserID != userID {
-		return nil, constant.ErrForbidden
+	if err := authorize(ctx, u.venueRepo, sub, policy.SpaceCreate, venue, nil); err != nil {
+		return nil, err
 	}
 	if req.Type != constant.PRIVATE_OFFICE && req.Type != constant.MEETING_ROOM && req.Type != constant.DESK {
 		return nil, constant.ErrInvalidSpaceType
@@ -55,19 +63,19 @@ func (u *spaceUsecase) Create(ctx context.Context, userID, venueID uint, req dto
 		OpenHour:    req.OpenHour,
 		CloseHour:   req.CloseHour,
 	}
+	if err := checkDailyHours(&space); err != nil {
+		return nil, err
+	}
 	if err := u.repo.Create(ctx, &space); err != nil {
 		return nil, constant.ErrCreateFailed
 	}
 	return &space, nil
 }
 
-func (u *spaceUsecase) Update(ctx context.Context, managerID, spaceID uint, req dto.UpdateSpaceRequest) (*model.Space, error) {
-	space, err := u.repo.GetByID(ctx, spaceID)
+func (u *spaceUsecase) Update(ctx context.Context, sub policy.Subject, spaceID uint, req dto.UpdateSpaceRequest) (*model.Space, error) {
+	space, err := u.getAuthorized(ctx, sub, policy.SpaceUpdate, spaceID)
 	if err != nil {
-		return nil, constant.ErrNotFound
-	}
-	if space.ManagerID != managerID {
-		return nil, constant.ErrForbidden
+		return nil, err
 	}
 
 	if req.Name != "" {
@@ -94,6 +102,11 @@ func (u *spaceUsecase) Update(ctx context.Context, managerID, spaceID uint, req
 	if req.CloseHour != "" {
 		space.CloseHour = req.CloseHour
 	}
+	if req.OpenHour != "" || req.CloseHour != "" {
+		if err := checkDailyHours(space); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := u.repo.Update(ctx, space); err != nil {
 		return nil, constant.ErrUpdateFailed
@@ -101,68 +114,271 @@ func (u *spaceUsecase) Update(ctx context.Context, managerID, spaceID uint, req
 	return space, nil
 }
 
-func (u *spaceUsecase) Delete(ctx context.Context, managerID, spaceID uint) error {
-	space, err := u.repo.GetByID(ctx, spaceID)
+func (u *spaceUsecase) Delete(ctx context.Context, sub policy.Subject, spaceID uint) error {
+	space, err := u.getAuthorized(ctx, sub, policy.SpaceDelete, spaceID)
 	if err != nil {
-		return constant.ErrNotFound
+		return err
+	}
+	if err := u.repo.Delete(ctx, space); err != nil {
+		return err
 	}
-	if space.ManagerID != managerID {
-		return constant.ErrForbidden
+	if err := u.galleries.RemoveSpaceImages(ctx, space.VenueID, space.ID); err != nil {
+		log.Printf("remove images of space %d failed: %v", space.ID, err)
 	}
-	return u.repo.Delete(ctx, space)
+	return nil
 }
 
-func (u *spaceUsecase) UpdateManager(ctx context.Context, ownerID, spaceID uint, req dto.UpdateManagerRequest) error {
+func (u *spaceUsecase) UpdateManager(ctx context.Context, sub policy.Subject, spaceID uint, req dto.UpdateManagerRequest) error {
+	space, err := u.getAuthorized(ctx, sub, policy.SpaceAssignManager, spaceID)
+	if err != nil {
+		return err
+	}
+	space.ManagerID = req.ManagerID
+	return u.repo.Update(ctx, space)
+}
+
+// checkDailyHours checks the open and close hours of the space, which it follows every day
+// unless it or its venue has weekly opening hours. A space closing before it opens is open
+// over midnight.
+func checkDailyHours(space *model.Space) error {
+	if space.OpenHour == "" && space.CloseHour == "" {
+		return nil
+	}
+	if _, err := schedule.Daily(space.OpenHour, space.CloseHour); err != nil {
+		return constant.ErrInvalidSchedule
+	}
+	return nil
+}
+
+// getAuthorized loads the space and checks perm against the space and its venue.
+func (u *spaceUsecase) getAuthorized(ctx context.Context, sub policy.Subject, perm policy.Permission, spaceID uint) (*model.Space, error) {
 	space, err := u.repo.GetByID(ctx, spaceID)
 	if err != nil {
-		return constant.ErrNotFound
+		return nil, constant.ErrNotFound
 	}
 	venue, err := u.venueRepo.FindByID(ctx, space.VenueID)
 	if err != nil {
-		return constant.ErrNotFound
+		return nil, constant.ErrNotFound
 	}
-	if venue.UserID != ownerID {
-		return constant.ErrForbidden
+	if err := authorize(ctx, u.venueRepo, sub, perm, venue, space); err != nil {
+		return nil, err
 	}
-	space.ManagerID = req.ManagerID
-	return u.repo.Update(ctx, space)
+	return space, nil
 }
 
-func (u *spaceUsecase) SearchSpaces(ctx context.Context, name, city, address, spaceType string, startTime, endTime time.Time) ([]model.Space, error) {
-	spaces, err := u.repo.FilterSpaces(ctx, name, city, address, spaceType)
+// SearchSpaces returns one page of the matching spaces of approved venues, with facet counts
+// over all of them. Each space comes with its cover photo or else the one of its venue, and
+// with its distance in a radius search.
+func (u *spaceUsecase) SearchSpaces(ctx context.Context, filter dto.SpaceSearchFilter) (*dto.SpaceSearchPage, error) {
+	if (filter.Lat == nil) != (filter.Lng == nil) {
+		return nil, constant.ErrBadRequest
+	}
+	if filter.Lat != nil {
+		if *filter.Lat < -90 || *filter.Lat > 90 || *filter.Lng < -180 || *filter.Lng > 180 {
+			return nil, constant.ErrBadRequest
+		}
+		if filter.RadiusKm == 0 {
+			filter.RadiusKm = constant.DefaultSearchRadiusKm
+		}
+	}
+	if filter.RadiusKm < 0 || filter.RadiusKm > constant.MaxSearchRadiusKm || (filter.Lat == nil && filter.RadiusKm != 0) {
+		return nil, constant.ErrBadRequest
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = constant.DefaultSearchLimit
+	}
+	if filter.Page < 0 || filter.Limit < 0 || filter.Limit > constant.MaxSearchLimit {
+		return nil, constant.ErrBadRequest
+	}
+	if filter.MinCapacity < 0 || filter.MinPrice < 0 || filter.MaxPrice < 0 ||
+		(filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice) {
+		return nil, constant.ErrBadRequest
+	}
+	if filter.StartTime.IsZero() != filter.EndTime.IsZero() ||
+		(!filter.StartTime.IsZero() && !filter.StartTime.Before(filter.EndTime)) {
+		return nil, constant.ErrBadRequest
+	}
+	if filter.OpenAt != "" {
+		openAt, err := time.Parse("15:04", filter.OpenAt)
+		if err != nil {
+			return nil, constant.ErrBadRequest
+		}
+		filter.OpenAt = openAt.Format("15:04")
+	}
+	filter.AmenityIDs = slices.Compact(slices.Sorted(slices.Values(filter.AmenityIDs)))
+	filter.Query = strings.TrimSpace(filter.Query)
+	switch filter.Sort {
+	case "":
+		switch {
+		case filter.Query != "":
+			filter.Sort = constant.SortRelevance
+		case filter.Lat != nil:
+			filter.Sort = constant.SortDistance
+		default:
+			filter.Sort = constant.SortNewest
+		}
+	case constant.SortRelevance:
+		if filter.Query == "" {
+			return nil, constant.ErrBadRequest
+		}
+	case constant.SortDistance:
+		if filter.Lat == nil {
+			return nil, constant.ErrBadRequest
+		}
+	case constant.SortPriceAsc, constant.SortPriceDesc, constant.SortCapacityAsc, constant.SortCapacityDesc, constant.SortNewest:
+	default:
+		return nil, constant.ErrBadRequest
+	}
+
+	// booked and closed spaces are left out before paging, so that every page is full
+	if !filter.StartTime.IsZero() || filter.OpenAt != "" {
+		spaceIDs, err := u.repo.MatchingIDs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(spaceIDs) > 0 {
+			if filter.ExcludeIDs, err = u.unavailable(ctx, spaceIDs, filter); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	hits, total, err := u.repo.FilterSpaces(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := u.repo.Facets(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if !startTime.IsZero() && !endTime.IsZero() {
-		spaceIDs := make([]uint, 0, len(spaces))
-		for _, s := range spaces {
-			spaceIDs = append(spaceIDs, s.ID)
-		}
+	venueIDs := make([]uint, 0, len(hits))
+	spaceIDs := make([]uint, 0, len(hits))
+	for _, h := range hits {
+		venueIDs = append(venueIDs, h.VenueID)
+		spaceIDs = append(spaceIDs, h.ID)
+	}
+	venueCovers, spaceCovers, err := u.galleries.Covers(ctx, venueIDs, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+	page := &dto.SpaceSearchPage{
+		Spaces:     make([]dto.SpaceSearchResult, 0, len(hits)),
+		Facets:     *facets,
+		Pagination: dto.Pagination{Page: filter.Page, Limit: filter.Limit, Total: total},
+	}
+	for _, h := range hits {
+		page.Spaces = append(page.Spaces, toSearchResult(h, coverOf(spaceCovers, venueCovers, h.ID, h.VenueID)))
+	}
+	return page, nil
+}
 
-		unavailableIDs, err := u.bookingClient.CheckAvailability(ctx, spaceIDs, startTime, endTime)
+// unavailable returns those of the spaces a search leaves out: closed at its OpenAt time, on
+// the day it starts or today, and closed or booked over its time window.
+func (u *spaceUsecase) unavailable(ctx context.Context, spaceIDs []uint, filter dto.SpaceSearchFilter) ([]uint, error) {
+	var excluded []uint
+	remaining := func() []uint {
+		return slices.DeleteFunc(slices.Clone(spaceIDs), func(id uint) bool { return slices.Contains(excluded, id) })
+	}
+	if filter.OpenAt != "" {
+		day := filter.StartTime
+		if day.IsZero() {
+			day = time.Now()
+		}
+		closedIDs, err := u.openings.ClosedAt(ctx, spaceIDs, day, filter.OpenAt)
 		if err != nil {
 			return nil, err
 		}
+		excluded = append(excluded, closedIDs...)
+	}
+	if filter.StartTime.IsZero() {
+		return excluded, nil
+	}
 
-		log.Printf("Unavailable IDs from booking-service: %v", unavailableIDs)
-		for _, s := range spaces {
-			log.Printf("Space ID %d", s.ID)
+	if openIDs := remaining(); len(openIDs) > 0 {
+		closedIDs, err := u.openings.ClosedSpaces(ctx, openIDs, filter.StartTime, filter.EndTime)
+		if err != nil {
+			return nil, err
 		}
-
-		unavailableMap := make(map[uint]struct{})
-		for _, id := range unavailableIDs {
-			unavailableMap[id] = struct{}{}
+		excluded = append(excluded, closedIDs...)
+	}
+	if openIDs := remaining(); len(openIDs) > 0 {
+		unavailableIDs, err := u.bookingClient.CheckAvailability(ctx, openIDs, filter.StartTime, filter.EndTime)
+		if err != nil {
+			return nil, err
 		}
+		excluded = append(excluded, unavailableIDs...)
+	}
+	return excluded, nil
+}
 
-		filtered := make([]model.Space, 0, len(spaces))
-		for _, s := range spaces {
-			if _, notAvailable := unavailableMap[s.ID]; !notAvailable {
-				filtered = append(filtered, s)
-			}
-		}
-		spaces = filtered
+func toSearchResult(h dto.SpaceHit, cover *dto.Image) dto.SpaceSearchResult {
+	res := dto.SpaceSearchResult{
+		ID:          h.ID,
+		Name:        h.Name,
+		Type:        h.Type,
+		Capacity:    h.Capacity,
+		Price:       h.Price,
+		Description: h.Description,
+		OpenHour:    h.OpenHour,
+		CloseHour:   h.CloseHour,
+		Venue: dto.SpaceSearchVenue{
+			ID:        h.VenueID,
+			Name:      h.VenueName,
+			Address:   h.VenueAddress,
+			City:      h.VenueCity,
+			Latitude:  h.VenueLatitude,
+			Longitude: h.VenueLongitude,
+		},
+		CoverImage: cover,
 	}
+	if h.Distance != nil {
+		km := math.Round(*h.Distance/10) / 100
+		res.DistanceKm = &km
+	}
+	return res
+}
+
+// coverOf returns the cover of the space, or else of its venue, or nil when neither has one.
+func coverOf(spaceCovers, venueCovers map[uint]dto.Image, spaceID, venueID uint) *dto.Image {
+	if img, ok := spaceCovers[spaceID]; ok {
+		return &img
+	}
+	if img, ok := venueCovers[venueID]; ok {
+		return &img
+	}
+	return nil
+}
 
-	return spaces, nil
+// GetSummaries looks up spaces for other services. Unknown and deleted spaces are left out.
+func (u *spaceUsecase) GetSummaries(ctx context.Context, ids []uint) ([]dto.SpaceSummary, error) {
+	if len(ids) == 0 || len(ids) > constant.MaxSpaceBatch {
+		return nil, constant.ErrBadRequest
+	}
+	spaces, err := u.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.SpaceSummary, 0, len(spaces))
+	for _, s := range spaces {
+		res = append(res, dto.SpaceSummary{
+			ID:          s.ID,
+			VenueID:     s.VenueID,
+			Name:        s.Name,
+			Type:        s.Type,
+			Capacity:    s.Capacity,
+			Price:       s.Price,
+			OpenHour:    s.OpenHour,
+			CloseHour:   s.CloseHour,
+			VenueName:   s.Venue.Name,
+			City:        s.Venue.City,
+			Address:     s.Venue.Address,
+			VenueStatus: s.Venue.Status,
+			Bookable:    s.Venue.Status == constant.APPROVED,
+		})
+	}
+	return res, nil
 }
@@ -2,6 +2,8 @@ package usecase_test
 
 import (
 	"context"
+	"packages/policy"
+	"slices"
 	"testing"
 	"time"
 	"venue-service/internal/constant"
@@ -36,8 +38,29 @@ func (m *mockSpaceRepo) Delete(ctx context.Context, s *model.Space) error {
 	args := m.Called(ctx, s)
 	return args.Error(0)
 }
-func (m *mockSpaceRepo) FilterSpaces(ctx context.Context, name, city, address, spaceType string) ([]model.Space, error) {
-	args := m.Called(ctx, name, city, address, spaceType)
+func (m *mockSpaceRepo) FilterSpaces(ctx context.Context, filter dto.SpaceSearchFilter) ([]dto.SpaceHit, int64, error) {
+	args := m.Called(ctx, filter)
+	if hits, ok := args.Get(0).([]dto.SpaceHit); ok {
+		return hits, int64(len(hits)), args.Error(1)
+	}
+	return nil, 0, args.Error(1)
+}
+func (m *mockSpaceRepo) MatchingIDs(ctx context.Context, filter dto.SpaceSearchFilter) ([]uint, error) {
+	args := m.Called(ctx, filter)
+	if ids, ok := args.Get(0).([]uint); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockSpaceRepo) Facets(ctx context.Context, filter dto.SpaceSearchFilter) (*dto.SpaceFacets, error) {
+	args := m.Called(ctx, filter)
+	if f, ok := args.Get(0).(*dto.SpaceFacets); ok {
+		return f, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockSpaceRepo) GetByIDs(ctx context.Context, ids []uint) ([]model.Space, error) {
+	args := m.Called(ctx, ids)
 	if sp, ok := args.Get(0).([]model.Space); ok {
 		return sp, args.Error(1)
 	}
@@ -55,13 +78,32 @@ func (m *mockBookingClient) CheckAvailability(ctx context.Context, spaceIDs []ui
 	return nil, args.Error(1)
 }
 
+// ===== Mock Openings =====
+type mockOpenings struct{ mock.Mock }
+
+func (m *mockOpenings) ClosedSpaces(ctx context.Context, spaceIDs []uint, start, end time.Time) ([]uint, error) {
+	args := m.Called(ctx, spaceIDs, start, end)
+	if ids, ok := args.Get(0).([]uint); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOpenings) ClosedAt(ctx context.Context, spaceIDs []uint, day time.Time, clock string) ([]uint, error) {
+	args := m.Called(ctx, spaceIDs, day, clock)
+	if ids, ok := args.Get(0).([]uint); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // ===== Unit Tests =====
 
 func TestCreateSpace_HappyCase(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 10}
@@ -74,7 +116,7 @@ func TestCreateSpace_HappyCase(t *testing.T) {
 		Capacity: 5,
 		Price:    100,
 	}
-	space, err := uc.Create(ctx, 10, 1, req)
+	space, err := uc.Create(ctx, asUser(10), 1, req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Room A", space.Name)
@@ -85,57 +127,137 @@ func TestCreateSpace_InvalidType(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 10}
 	venueRepo.On("FindByID", ctx, uint(1)).Return(venue, nil)
 
 	req := dto.CreateSpaceRequest{Name: "X", Type: "invalid"}
-	space, err := uc.Create(ctx, 10, 1, req)
+	space, err := uc.Create(ctx, asUser(10), 1, req)
 
 	assert.ErrorIs(t, err, constant.ErrInvalidSpaceType)
 	assert.Nil(t, space)
 }
 
+func TestCreateSpace_InvalidHours(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	venueRepo := new(mockVenueRepo)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, new(mockBookingClient), new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	venueRepo.On("FindByID", ctx, uint(1)).Return(&model.Venue{UserID: 10}, nil)
+
+	for _, hours := range [][2]string{{"8am", "18:00"}, {"09:00", "09:00"}, {"09:00", "25:00"}} {
+		req := dto.CreateSpaceRequest{Name: "X", Type: constant.DESK, OpenHour: hours[0], CloseHour: hours[1]}
+		space, err := uc.Create(ctx, asUser(10), 1, req)
+
+		assert.ErrorIs(t, err, constant.ErrInvalidSchedule, "%v", hours)
+		assert.Nil(t, space)
+	}
+	spaceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestUpdateSpace_HappyCase(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
 	ctx := context.Background()
 
-	existing := &model.Space{ManagerID: 5, Name: "Old"}
+	existing := &model.Space{VenueID: 2, ManagerID: 5, Name: "Old"}
 	spaceRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(&model.Venue{UserID: 10}, nil)
 	spaceRepo.On("Update", ctx, existing).Return(nil)
 
 	req := dto.UpdateSpaceRequest{Name: "New"}
-	space, err := uc.Update(ctx, 5, 1, req)
+	space, err := uc.Update(ctx, asUser(5), 1, req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "New", space.Name)
 }
 
+func TestUpdateSpace_VenueOwnerWithoutManagerRole(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	venueRepo := new(mockVenueRepo)
+	bookingClient := new(mockBookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	existing := &model.Space{VenueID: 2, ManagerID: 5, Name: "Old"}
+	spaceRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(&model.Venue{UserID: 10}, nil)
+	spaceRepo.On("Update", ctx, existing).Return(nil)
+
+	space, err := uc.Update(ctx, asUser(10), 1, dto.UpdateSpaceRequest{Name: "New"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", space.Name)
+}
+
+func TestUpdateSpace_VenueManagerMember(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	venueRepo := new(mockVenueRepo)
+	bookingClient := new(mockBookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	existing := &model.Space{VenueID: 2, Name: "Old"}
+	venue := &model.Venue{Model: gorm.Model{ID: 2}, UserID: 10}
+	spaceRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(venue, nil)
+	venueRepo.On("FindMember", ctx, uint(2), uint(7)).Return(&model.VenueMember{VenueID: 2, UserID: 7, Role: policy.RoleManager}, nil)
+	spaceRepo.On("Update", ctx, existing).Return(nil)
+
+	space, err := uc.Update(ctx, asUser(7), 1, dto.UpdateSpaceRequest{Name: "New"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", space.Name)
+}
+
+func TestUpdateSpace_Forbidden(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	venueRepo := new(mockVenueRepo)
+	bookingClient := new(mockBookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	existing := &model.Space{VenueID: 2, ManagerID: 5}
+	spaceRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(&model.Venue{UserID: 10}, nil)
+	venueRepo.On("FindMember", ctx, uint(0), uint(8)).Return(nil, nil)
+
+	space, err := uc.Update(ctx, asUser(8), 1, dto.UpdateSpaceRequest{Name: "New"})
+
+	assert.ErrorIs(t, err, constant.ErrForbidden)
+	assert.Nil(t, space)
+	spaceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestDeleteSpace_HappyCase(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), galleries)
 	ctx := context.Background()
 
-	existing := &model.Space{ManagerID: 5}
+	existing := &model.Space{Model: gorm.Model{ID: 1}, VenueID: 2, ManagerID: 5}
 	spaceRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(&model.Venue{UserID: 10}, nil)
 	spaceRepo.On("Delete", ctx, existing).Return(nil)
+	galleries.On("RemoveSpaceImages", ctx, uint(2), uint(1)).Return(nil)
 
-	err := uc.Delete(ctx, 5, 1)
+	err := uc.Delete(ctx, asUser(5), 1)
 	assert.NoError(t, err)
+	galleries.AssertExpectations(t)
 }
 
 func TestUpdateManager_HappyCase(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
 	ctx := context.Background()
 
 	space := &model.Space{VenueID: 2, ManagerID: 5}
@@ -146,32 +268,265 @@ func TestUpdateManager_HappyCase(t *testing.T) {
 	spaceRepo.On("Update", ctx, space).Return(nil)
 
 	req := dto.UpdateManagerRequest{ManagerID: 99}
-	err := uc.UpdateManager(ctx, 10, 1, req)
+	err := uc.UpdateManager(ctx, asUser(10), 1, req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, uint(99), space.ManagerID)
 }
 
-func TestSearchSpaces_FilterByAvailability(t *testing.T) {
+func TestUpdateManager_ManagerCannotReassign(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	venueRepo := new(mockVenueRepo)
+	bookingClient := new(mockBookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	space := &model.Space{VenueID: 2, ManagerID: 5}
+	spaceRepo.On("GetByID", ctx, uint(1)).Return(space, nil)
+	venueRepo.On("FindByID", ctx, uint(2)).Return(&model.Venue{UserID: 10}, nil)
+	venueRepo.On("FindMember", ctx, uint(0), uint(5)).Return(nil, nil)
+
+	err := uc.UpdateManager(ctx, asUser(5), 1, dto.UpdateManagerRequest{ManagerID: 6})
+
+	assert.ErrorIs(t, err, constant.ErrForbidden)
+	assert.Equal(t, uint(5), space.ManagerID)
+}
+
+func TestCreateSpace_AdminInOtherVenue(t *testing.T) {
 	spaceRepo := new(mockSpaceRepo)
 	venueRepo := new(mockVenueRepo)
 	bookingClient := new(mockBookingClient)
-	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient)
+	uc := usecase.NewSpaceUsecase(spaceRepo, venueRepo, bookingClient, new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	venueRepo.On("FindByID", ctx, uint(1)).Return(&model.Venue{UserID: 10}, nil)
+	spaceRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	req := dto.CreateSpaceRequest{Name: "Room B", Type: constant.DESK, Capacity: 1, Price: 10}
+	space, err := uc.Create(ctx, policy.Subject{UserID: 1, Role: policy.RoleAdmin}, 1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Room B", space.Name)
+}
+
+func hit(id, venueID uint) dto.SpaceHit {
+	return dto.SpaceHit{Space: model.Space{Model: gorm.Model{ID: id}, VenueID: venueID}}
+}
+
+func TestSearchSpaces_ExcludesBookedAndClosedBeforePaging(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	bookingClient := new(mockBookingClient)
+	openings := new(mockOpenings)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), bookingClient, openings, galleries)
 	ctx := context.Background()
 
 	start := time.Now()
 	end := start.Add(2 * time.Hour)
 
-	spaces := []model.Space{
-		{Model: gorm.Model{ID: 1}, Name: "Room A"},
-		{Model: gorm.Model{ID: 2}, Name: "Room B"},
-	}
-	spaceRepo.On("FilterSpaces", ctx, "Desk", "HCM", "", "").Return(spaces, nil)
+	matching := dto.SpaceSearchFilter{Query: "Desk", City: "HCM", StartTime: start, EndTime: end, Sort: constant.SortRelevance, Page: 1, Limit: constant.DefaultSearchLimit}
+	available := matching
+	available.ExcludeIDs = []uint{3, 2}
+	spaceRepo.On("MatchingIDs", ctx, matching).Return([]uint{1, 2, 3}, nil)
+	openings.On("ClosedSpaces", ctx, []uint{1, 2, 3}, start, end).Return([]uint{3}, nil)
 	bookingClient.On("CheckAvailability", ctx, []uint{1, 2}, start, end).Return([]uint{2}, nil)
+	spaceRepo.On("FilterSpaces", ctx, available).Return([]dto.SpaceHit{hit(1, 7)}, nil)
+	spaceRepo.On("Facets", ctx, available).Return(&dto.SpaceFacets{Types: []dto.FacetCount{{Value: constant.DESK, Count: 1}}}, nil)
+	galleries.On("Covers", ctx, []uint{7}, []uint{1}).Return(map[uint]dto.Image{}, map[uint]dto.Image{}, nil)
+
+	page, err := uc.SearchSpaces(ctx, dto.SpaceSearchFilter{Query: " Desk ", City: "HCM", StartTime: start, EndTime: end})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Spaces, 1)
+	assert.Equal(t, uint(1), page.Spaces[0].ID)
+	assert.Nil(t, page.Spaces[0].CoverImage)
+	assert.Equal(t, int64(1), page.Facets.Types[0].Count)
+	assert.Equal(t, dto.Pagination{Page: 1, Limit: constant.DefaultSearchLimit, Total: 1}, page.Pagination)
+}
 
-	result, err := uc.SearchSpaces(ctx, "Desk", "HCM", "", "", start, end)
+func TestSearchSpaces_CoverFallsBackToVenue(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), galleries)
+	ctx := context.Background()
+
+	spaceRepo.On("FilterSpaces", ctx, mock.Anything).Return([]dto.SpaceHit{hit(1, 7), hit(2, 7)}, nil)
+	spaceRepo.On("Facets", ctx, mock.Anything).Return(&dto.SpaceFacets{}, nil)
+	galleries.On("Covers", ctx, []uint{7, 7}, []uint{1, 2}).Return(
+		map[uint]dto.Image{7: {ID: 30, VenueID: 7}},
+		map[uint]dto.Image{2: {ID: 31, VenueID: 7, SpaceID: 2}},
+		nil,
+	)
+
+	page, err := uc.SearchSpaces(ctx, dto.SpaceSearchFilter{})
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, uint(1), result[0].ID)
+	assert.Equal(t, uint(30), page.Spaces[0].CoverImage.ID)
+	assert.Equal(t, uint(31), page.Spaces[1].CoverImage.ID)
+	spaceRepo.AssertNotCalled(t, "MatchingIDs", mock.Anything, mock.Anything)
+}
+
+func TestSearchSpaces_WithinRadius(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), galleries)
+	ctx := context.Background()
+
+	lat, lng := 10.77, 106.7
+	near, far := hit(1, 7), hit(2, 8)
+	nearM, farM := 420.0, 2567.0
+	near.Distance, far.Distance = &nearM, &farM
+	filter := dto.SpaceSearchFilter{Lat: &lat, Lng: &lng, RadiusKm: constant.DefaultSearchRadiusKm, Sort: constant.SortDistance, Page: 1, Limit: constant.DefaultSearchLimit}
+	spaceRepo.On("FilterSpaces", ctx, filter).Return([]dto.SpaceHit{near, far}, nil)
+	spaceRepo.On("Facets", ctx, filter).Return(&dto.SpaceFacets{}, nil)
+	galleries.On("Covers", ctx, []uint{7, 8}, []uint{1, 2}).Return(map[uint]dto.Image{}, map[uint]dto.Image{}, nil)
+
+	page, err := uc.SearchSpaces(ctx, dto.SpaceSearchFilter{Lat: &lat, Lng: &lng})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.42, *page.Spaces[0].DistanceKm)
+	assert.Equal(t, 2.57, *page.Spaces[1].DistanceKm)
+}
+
+func TestSearchSpaces_NormalizesAmenitiesAndOpenAt(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	openings := new(mockOpenings)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), openings, galleries)
+	ctx := context.Background()
+
+	filter := dto.SpaceSearchFilter{
+		Type: constant.MEETING_ROOM, MinCapacity: 8, MaxPrice: 200000, OpenAt: "09:05", AmenityIDs: []uint{2, 5},
+		Sort: constant.SortNewest, Page: 1, Limit: constant.DefaultSearchLimit,
+	}
+	open := filter
+	open.ExcludeIDs = []uint{2}
+	spaceRepo.On("MatchingIDs", ctx, filter).Return([]uint{1, 2}, nil)
+	openings.On("ClosedAt", ctx, []uint{1, 2}, mock.AnythingOfType("time.Time"), "09:05").Return([]uint{2}, nil)
+	spaceRepo.On("FilterSpaces", ctx, open).Return([]dto.SpaceHit{hit(1, 7)}, nil)
+	spaceRepo.On("Facets", ctx, open).Return(&dto.SpaceFacets{}, nil)
+	galleries.On("Covers", ctx, []uint{7}, []uint{1}).Return(map[uint]dto.Image{}, map[uint]dto.Image{}, nil)
+
+	page, err := uc.SearchSpaces(ctx, dto.SpaceSearchFilter{
+		Type: constant.MEETING_ROOM, MinCapacity: 8, MaxPrice: 200000, OpenAt: "9:05", AmenityIDs: []uint{5, 2, 5},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Spaces, 1)
+	spaceRepo.AssertExpectations(t)
+	openings.AssertExpectations(t)
+}
+
+func TestSearchSpaces_OpenAtOnTheDayOfTheWindow(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	bookingClient := new(mockBookingClient)
+	openings := new(mockOpenings)
+	galleries := new(mockGalleries)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), bookingClient, openings, galleries)
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	spaceRepo.On("MatchingIDs", ctx, mock.Anything).Return([]uint{1, 2, 3}, nil)
+	openings.On("ClosedAt", ctx, []uint{1, 2, 3}, start, "08:00").Return([]uint{1}, nil)
+	openings.On("ClosedSpaces", ctx, []uint{2, 3}, start, end).Return([]uint{}, nil)
+	bookingClient.On("CheckAvailability", ctx, []uint{2, 3}, start, end).Return([]uint{3}, nil)
+	excluded := mock.MatchedBy(func(f dto.SpaceSearchFilter) bool { return slices.Equal(f.ExcludeIDs, []uint{1, 3}) })
+	spaceRepo.On("FilterSpaces", ctx, excluded).Return([]dto.SpaceHit{hit(2, 7)}, nil)
+	spaceRepo.On("Facets", ctx, excluded).Return(&dto.SpaceFacets{}, nil)
+	galleries.On("Covers", ctx, []uint{7}, []uint{2}).Return(map[uint]dto.Image{}, map[uint]dto.Image{}, nil)
+
+	page, err := uc.SearchSpaces(ctx, dto.SpaceSearchFilter{StartTime: start, EndTime: end, OpenAt: "08:00"})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Spaces, 1)
+	openings.AssertExpectations(t)
+	bookingClient.AssertExpectations(t)
+}
+
+func TestSearchSpaces_DefaultSort(t *testing.T) {
+	lat, lng := 10.77, 106.7
+	for _, tc := range []struct {
+		filter dto.SpaceSearchFilter
+		sort   string
+	}{
+		{dto.SpaceSearchFilter{}, constant.SortNewest},
+		{dto.SpaceSearchFilter{Query: "quiet"}, constant.SortRelevance},
+		{dto.SpaceSearchFilter{Lat: &lat, Lng: &lng}, constant.SortDistance},
+		{dto.SpaceSearchFilter{Query: "quiet", Lat: &lat, Lng: &lng}, constant.SortRelevance},
+		{dto.SpaceSearchFilter{Query: "quiet", Sort: constant.SortPriceDesc}, constant.SortPriceDesc},
+	} {
+		spaceRepo := new(mockSpaceRepo)
+		galleries := new(mockGalleries)
+		uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), galleries)
+		ctx := context.Background()
+
+		sorted := mock.MatchedBy(func(f dto.SpaceSearchFilter) bool { return f.Sort == tc.sort })
+		spaceRepo.On("FilterSpaces", ctx, sorted).Return([]dto.SpaceHit{}, nil)
+		spaceRepo.On("Facets", ctx, sorted).Return(&dto.SpaceFacets{}, nil)
+		galleries.On("Covers", ctx, []uint{}, []uint{}).Return(map[uint]dto.Image{}, map[uint]dto.Image{}, nil)
+
+		_, err := uc.SearchSpaces(ctx, tc.filter)
+		assert.NoError(t, err)
+		spaceRepo.AssertExpectations(t)
+	}
+}
+
+func TestSearchSpaces_InvalidFilter(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	lat, lng, outside := 10.77, 106.7, 91.0
+	for _, filter := range []dto.SpaceSearchFilter{
+		{Lat: &lat},
+		{Lat: &outside, Lng: &lng},
+		{Lat: &lat, Lng: &lng, RadiusKm: constant.MaxSearchRadiusKm + 1},
+		{Lat: &lat, Lng: &lng, RadiusKm: -1},
+		{RadiusKm: 5},
+		{Sort: constant.SortRelevance},
+		{Sort: constant.SortDistance},
+		{Sort: "rating"},
+		{Limit: constant.MaxSearchLimit + 1},
+		{Page: -1},
+		{MinCapacity: -1},
+		{MinPrice: 300000, MaxPrice: 200000},
+		{OpenAt: "25:00"},
+		{StartTime: time.Now()},
+	} {
+		page, err := uc.SearchSpaces(ctx, filter)
+		assert.Nil(t, page)
+		assert.Equal(t, constant.ErrBadRequest, err)
+	}
+	spaceRepo.AssertNotCalled(t, "FilterSpaces", mock.Anything, mock.Anything)
+}
+
+func TestGetSummaries_MarksBookableVenues(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), new(mockGalleries))
+	ctx := context.Background()
+
+	spaceRepo.On("GetByIDs", ctx, []uint{1, 2, 3}).Return([]model.Space{
+		{Model: gorm.Model{ID: 1}, Name: "Room A", Venue: model.Venue{Name: "Hub", Status: constant.APPROVED}},
+		{Model: gorm.Model{ID: 2}, Name: "Desk B", Venue: model.Venue{Name: "Loft", Status: constant.BLOCKED}},
+	}, nil)
+
+	spaces, err := uc.GetSummaries(ctx, []uint{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, spaces, 2)
+	assert.True(t, spaces[0].Bookable)
+	assert.Equal(t, "Hub", spaces[0].VenueName)
+	assert.False(t, spaces[1].Bookable)
+	assert.Equal(t, constant.BLOCKED, spaces[1].VenueStatus)
+}
+
+func TestGetSummaries_RejectsOversizedBatch(t *testing.T) {
+	spaceRepo := new(mockSpaceRepo)
+	uc := usecase.NewSpaceUsecase(spaceRepo, new(mockVenueRepo), new(mockBookingClient), new(mockOpenings), new(mockGalleries))
+
+	_, err := uc.GetSummaries(context.Background(), make([]uint, constant.MaxSpaceBatch+1))
+
+	assert.ErrorIs(t, err, constant.ErrBadRequest)
+	spaceRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
 }
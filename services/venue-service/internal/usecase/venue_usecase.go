@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"context"
+	"log"
+	"packages/policy"
 	"venue-service/internal/constant"
 	"venue-service/internal/dto"
+	"venue-service/internal/geocode"
 	"venue-service/internal/model"
 	"venue-service/internal/repository"
 )
@@ -12,24 +15,35 @@ type VenueUsecase interface {
 	Create(ctx context.Context, userID uint, req dto.CreateVenueRequest) (*model.Venue, error)
 	GetAll(ctx context.Context, userID uint, city, name string) ([]model.Venue, error)
 	GetByID(ctx context.Context, id uint) (*model.Venue, error)
-	Update(ctx context.Context, userID uint, id uint, req dto.UpdateVenueRequest) (*model.Venue, error)
-	Delete(ctx context.Context, userID uint, id uint) error
-	AddAmenity(ctx context.Context, userID uint, venueID uint, req dto.AddAmenityRequest) error
-	RemoveAmenity(ctx context.Context, userID uint, venueAmenityID uint) error
+	Update(ctx context.Context, sub policy.Subject, id uint, req dto.UpdateVenueRequest) (*model.Venue, error)
+	Delete(ctx context.Context, sub policy.Subject, id uint) error
+	AddAmenity(ctx context.Context, sub policy.Subject, venueID uint, req dto.AddAmenityRequest) error
+	RemoveAmenity(ctx context.Context, sub policy.Subject, venueID, venueAmenityID uint) error
+
+	ListMembers(ctx context.Context, sub policy.Subject, venueID uint) ([]model.VenueMember, error)
+	AddMember(ctx context.Context, sub policy.Subject, venueID uint, req dto.AddMemberRequest) (*model.VenueMember, error)
+	RemoveMember(ctx context.Context, sub policy.Subject, venueID, userID uint) error
+	GetAccess(ctx context.Context, venueID, userID uint) (*dto.VenueAccess, error)
 
 	List(ctx context.Context, status string) ([]model.Venue, error)
 	UpdateStatus(ctx context.Context, venueID uint, status string) error
 }
 
 type venueUsecase struct {
-	repo repository.VenueRepository
+	repo      repository.VenueRepository
+	galleries Galleries
+	geocoder  geocode.Geocoder
 }
 
-func NewVenueUsecase(r repository.VenueRepository) VenueUsecase {
-	return &venueUsecase{r}
+// NewVenueUsecase takes a nil geocoder when coordinates are only entered by hand.
+func NewVenueUsecase(r repository.VenueRepository, galleries Galleries, geocoder geocode.Geocoder) VenueUsecase {
+	return &venueUsecase{repo: r, galleries: galleries, geocoder: geocoder}
 }
 
 func (u *venueUsecase) Create(ctx context.Context, userID uint, req dto.CreateVenueRequest) (*model.Venue, error) {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, constant.ErrBadRequest
+	}
 	venue := model.Venue{
 		UserID:      userID,
 		Name:        req.Name,
@@ -37,6 +51,12 @@ func (u *venueUsecase) Create(ctx context.Context, userID uint, req dto.CreateVe
 		City:        req.City,
 		Description: req.Description,
 		Status:      constant.PENDING,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Timezone:    constant.DefaultTimezone,
+	}
+	if !venue.HasLocation() {
+		u.locate(ctx, &venue)
 	}
 	if err := u.repo.Create(ctx, &venue); err != nil {
 		return nil, constant.ErrCreateFailed
@@ -60,35 +80,78 @@ func (u *venueUsecase) GetByID(ctx context.Context, id uint) (*model.Venue, erro
 	return venue, nil
 }
 
-func (u *venueUsecase) Update(ctx context.Context, userID uint, id uint, req dto.UpdateVenueRequest) (*model.Venue, error) {
+func (u *venueUsecase) Update(ctx context.Context, sub policy.Subject, id uint, req dto.UpdateVenueRequest) (*model.Venue, error) {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, constant.ErrBadRequest
+	}
 	venue, err := u.repo.FindByID(ctx, id)
-	if err != nil || venue.UserID != userID {
-		return nil, constant.ErrUnauthorized
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueUpdate, venue, nil); err != nil {
+		return nil, err
 	}
+	moved := venue.Address != req.Address || venue.City != req.City
 	venue.Name = req.Name
 	venue.Address = req.Address
 	venue.City = req.City
 	venue.Description = req.Description
+	switch {
+	case req.Latitude != nil:
+		venue.Latitude, venue.Longitude = req.Latitude, req.Longitude
+	case moved:
+		u.locate(ctx, venue)
+	}
 	if err := u.repo.Update(ctx, venue); err != nil {
 		return nil, constant.ErrUpdateFailed
 	}
 	return venue, nil
 }
 
-func (u *venueUsecase) Delete(ctx context.Context, userID uint, id uint) error {
-	if err := u.repo.Delete(ctx, id, userID); err != nil {
+// locate geocodes the address of the venue. A venue that cannot be located is saved without
+// coordinates, which can be entered by hand later; old ones would point at the old address.
+func (u *venueUsecase) locate(ctx context.Context, venue *model.Venue) {
+	venue.Latitude, venue.Longitude = nil, nil
+	if u.geocoder == nil {
+		return
+	}
+	address := venue.Address
+	if venue.City != "" {
+		address += ", " + venue.City
+	}
+	coords, err := u.geocoder.Geocode(ctx, address)
+	if err != nil {
+		log.Printf("geocode venue %q at %q failed: %v", venue.Name, address, err)
+		return
+	}
+	venue.Latitude, venue.Longitude = &coords.Lat, &coords.Lng
+}
+
+func (u *venueUsecase) Delete(ctx context.Context, sub policy.Subject, id uint) error {
+	venue, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueDelete, venue, nil); err != nil {
+		return err
+	}
+	if err := u.repo.Delete(ctx, id); err != nil {
 		return constant.ErrDeleteFailed
 	}
+	// the venue is gone either way; photos left behind are only unreachable
+	if err := u.galleries.RemoveVenueImages(ctx, id); err != nil {
+		log.Printf("remove images of venue %d failed: %v", id, err)
+	}
 	return nil
 }
 
-func (u *venueUsecase) AddAmenity(ctx context.Context, userID uint, venueID uint, req dto.AddAmenityRequest) error {
+func (u *venueUsecase) AddAmenity(ctx context.Context, sub policy.Subject, venueID uint, req dto.AddAmenityRequest) error {
 	venue, err := u.repo.FindByID(ctx, venueID)
 	if err != nil {
 		return constant.ErrVenueNotFound
 	}
-	if venue.UserID != userID {
-		return constant.ErrUnauthorized
+	if err := authorize(ctx, u.repo, sub, policy.VenueUpdate, venue, nil); err != nil {
+		return err
 	}
 
 	amenityExists, err := u.repo.CheckAmenityExists(ctx, req.AmenityID)
@@ -117,8 +180,15 @@ func (u *venueUsecase) AddAmenity(ctx context.Context, userID uint, venueID uint
 	return nil
 }
 
-func (u *venueUsecase) RemoveAmenity(ctx context.Context, userID uint, venueAmenityID uint) error {
-	if err := u.repo.RemoveAmenity(ctx, venueAmenityID, userID); err != nil {
+func (u *venueUsecase) RemoveAmenity(ctx context.Context, sub policy.Subject, venueID, venueAmenityID uint) error {
+	venue, err := u.repo.FindByID(ctx, venueID)
+	if err != nil {
+		return constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueUpdate, venue, nil); err != nil {
+		return err
+	}
+	if err := u.repo.RemoveAmenity(ctx, venueID, venueAmenityID); err != nil {
 		return constant.ErrDeleteFailed
 	}
 	return nil
@@ -136,3 +206,86 @@ func (u *venueUsecase) UpdateStatus(ctx context.Context, venueID uint, status st
 	venue.Status = status
 	return u.repo.Update(ctx, venue)
 }
+
+func (u *venueUsecase) ListMembers(ctx context.Context, sub policy.Subject, venueID uint) ([]model.VenueMember, error) {
+	venue, err := u.repo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueManageMembers, venue, nil); err != nil {
+		return nil, err
+	}
+	members, err := u.repo.ListMembers(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	return members, nil
+}
+
+// AddMember grants a manager or staff role on the venue, replacing any role the user already had.
+func (u *venueUsecase) AddMember(ctx context.Context, sub policy.Subject, venueID uint, req dto.AddMemberRequest) (*model.VenueMember, error) {
+	if req.Role == policy.RoleOwner || !policy.IsScopedRole(req.Role) {
+		return nil, constant.ErrInvalidMemberRole
+	}
+	venue, err := u.repo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueManageMembers, venue, nil); err != nil {
+		return nil, err
+	}
+	if req.UserID == venue.UserID {
+		return nil, constant.ErrInvalidMemberRole
+	}
+
+	member := model.VenueMember{
+		VenueID: venueID,
+		UserID:  req.UserID,
+		Role:    req.Role,
+	}
+	if err := u.repo.SaveMember(ctx, &member); err != nil {
+		return nil, constant.ErrCreateFailed
+	}
+	return &member, nil
+}
+
+func (u *venueUsecase) RemoveMember(ctx context.Context, sub policy.Subject, venueID, userID uint) error {
+	venue, err := u.repo.FindByID(ctx, venueID)
+	if err != nil {
+		return constant.ErrVenueNotFound
+	}
+	if err := authorize(ctx, u.repo, sub, policy.VenueManageMembers, venue, nil); err != nil {
+		return err
+	}
+	if err := u.repo.RemoveMember(ctx, venueID, userID); err != nil {
+		return constant.ErrDeleteFailed
+	}
+	return nil
+}
+
+// GetAccess lists the grants of the user on the venue and its spaces: owner, member role and
+// space manager.
+func (u *venueUsecase) GetAccess(ctx context.Context, venueID, userID uint) (*dto.VenueAccess, error) {
+	venue, err := u.repo.FindByID(ctx, venueID)
+	if err != nil {
+		return nil, constant.ErrVenueNotFound
+	}
+	access := &dto.VenueAccess{VenueID: venue.ID, SpaceIDs: []uint{}, Grants: []dto.ScopedGrant{}}
+	if venue.UserID == userID {
+		access.Grants = append(access.Grants, dto.ScopedGrant{Role: policy.RoleOwner, Scope: policy.ScopeVenue, ID: venue.ID})
+	}
+	member, err := u.repo.FindMember(ctx, venue.ID, userID)
+	if err != nil {
+		return nil, constant.ErrInternalServerError
+	}
+	if member != nil {
+		access.Grants = append(access.Grants, dto.ScopedGrant{Role: member.Role, Scope: policy.ScopeVenue, ID: venue.ID})
+	}
+	for _, s := range venue.Spaces {
+		access.SpaceIDs = append(access.SpaceIDs, s.ID)
+		if s.ManagerID != 0 && s.ManagerID == userID {
+			access.Grants = append(access.Grants, dto.ScopedGrant{Role: policy.RoleManager, Scope: policy.ScopeSpace, ID: s.ID})
+		}
+	}
+	return access, nil
+}
@@ -3,9 +3,11 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"packages/policy"
 	"testing"
 	"venue-service/internal/constant"
 	"venue-service/internal/dto"
+	"venue-service/internal/geocode"
 	"venue-service/internal/model"
 	"venue-service/internal/usecase"
 
@@ -34,16 +36,16 @@ func (m *mockVenueRepo) Update(ctx context.Context, venue *model.Venue) error {
 	args := m.Called(ctx, venue)
 	return args.Error(0)
 }
-func (m *mockVenueRepo) Delete(ctx context.Context, id uint, userID uint) error {
-	args := m.Called(ctx, id, userID)
+func (m *mockVenueRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 func (m *mockVenueRepo) AddAmenity(ctx context.Context, venueAmenity *model.VenueAmenity) error {
 	args := m.Called(ctx, venueAmenity)
 	return args.Error(0)
 }
-func (m *mockVenueRepo) RemoveAmenity(ctx context.Context, id uint, userID uint) error {
-	args := m.Called(ctx, id, userID)
+func (m *mockVenueRepo) RemoveAmenity(ctx context.Context, venueID, venueAmenityID uint) error {
+	args := m.Called(ctx, venueID, venueAmenityID)
 	return args.Error(0)
 }
 func (m *mockVenueRepo) ListByStatus(ctx context.Context, status string) ([]model.Venue, error) {
@@ -58,12 +60,46 @@ func (m *mockVenueRepo) CheckVenueAmenityExists(ctx context.Context, venueID, am
 	args := m.Called(ctx, venueID, amenityID)
 	return args.Bool(0), args.Error(1)
 }
+func (m *mockVenueRepo) FindMember(ctx context.Context, venueID, userID uint) (*model.VenueMember, error) {
+	args := m.Called(ctx, venueID, userID)
+	if member, ok := args.Get(0).(*model.VenueMember); ok {
+		return member, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockVenueRepo) ListMembers(ctx context.Context, venueID uint) ([]model.VenueMember, error) {
+	args := m.Called(ctx, venueID)
+	return args.Get(0).([]model.VenueMember), args.Error(1)
+}
+func (m *mockVenueRepo) SaveMember(ctx context.Context, member *model.VenueMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+func (m *mockVenueRepo) RemoveMember(ctx context.Context, venueID, userID uint) error {
+	args := m.Called(ctx, venueID, userID)
+	return args.Error(0)
+}
+
+type stubGeocoder struct {
+	coords   geocode.Coordinates
+	err      error
+	requests []string
+}
+
+func (g *stubGeocoder) Geocode(ctx context.Context, address string) (geocode.Coordinates, error) {
+	g.requests = append(g.requests, address)
+	return g.coords, g.err
+}
+
+func asUser(id uint) policy.Subject {
+	return policy.Subject{UserID: id, Role: policy.RoleUser}
+}
 
 // ===== Test Cases =====
 
 func TestCreateVenue_Success(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	req := dto.CreateVenueRequest{Name: "Test Venue", Address: "123 Street"}
@@ -77,7 +113,7 @@ func TestCreateVenue_Success(t *testing.T) {
 
 func TestCreateVenue_Fail(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	req := dto.CreateVenueRequest{Name: "Fail Venue", Address: "123 Street"}
@@ -88,9 +124,96 @@ func TestCreateVenue_Fail(t *testing.T) {
 	assert.Equal(t, constant.ErrCreateFailed, err)
 }
 
+func TestCreateVenue_GeocodesAddress(t *testing.T) {
+	repo := new(mockVenueRepo)
+	geocoder := &stubGeocoder{coords: geocode.Coordinates{Lat: 10.77, Lng: 106.7}}
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), geocoder)
+	ctx := context.Background()
+
+	repo.On("Create", ctx, mock.AnythingOfType("*model.Venue")).Return(nil)
+
+	venue, err := uc.Create(ctx, 1, dto.CreateVenueRequest{Name: "Hub", Address: "1 Le Loi", City: "HCM"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1 Le Loi, HCM"}, geocoder.requests)
+	assert.Equal(t, 10.77, *venue.Latitude)
+	assert.Equal(t, 106.7, *venue.Longitude)
+}
+
+func TestCreateVenue_GeocodeFailureKeepsVenue(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), &stubGeocoder{err: geocode.ErrNoMatch})
+	ctx := context.Background()
+
+	repo.On("Create", ctx, mock.AnythingOfType("*model.Venue")).Return(nil)
+
+	venue, err := uc.Create(ctx, 1, dto.CreateVenueRequest{Name: "Hub", Address: "nowhere"})
+	assert.NoError(t, err)
+	assert.False(t, venue.HasLocation())
+}
+
+func TestCreateVenue_ManualCoordinates(t *testing.T) {
+	repo := new(mockVenueRepo)
+	geocoder := &stubGeocoder{}
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), geocoder)
+	ctx := context.Background()
+
+	lat, lng := 21.03, 105.85
+	repo.On("Create", ctx, mock.AnythingOfType("*model.Venue")).Return(nil)
+
+	venue, err := uc.Create(ctx, 1, dto.CreateVenueRequest{Name: "Hub", Address: "1 Trang Tien", Latitude: &lat, Longitude: &lng})
+	assert.NoError(t, err)
+	assert.Empty(t, geocoder.requests)
+	assert.Equal(t, 21.03, *venue.Latitude)
+}
+
+func TestCreateVenue_HalfCoordinates(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	lat := 21.03
+
+	venue, err := uc.Create(context.Background(), 1, dto.CreateVenueRequest{Name: "Hub", Address: "x", Latitude: &lat})
+	assert.Nil(t, venue)
+	assert.Equal(t, constant.ErrBadRequest, err)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateVenue_RegeocodesOnAddressChange(t *testing.T) {
+	repo := new(mockVenueRepo)
+	geocoder := &stubGeocoder{coords: geocode.Coordinates{Lat: 16.05, Lng: 108.2}}
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), geocoder)
+	ctx := context.Background()
+
+	lat, lng := 10.77, 106.7
+	venue := &model.Venue{UserID: 1, Address: "1 Le Loi", City: "HCM", Latitude: &lat, Longitude: &lng}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("Update", ctx, venue).Return(nil)
+
+	_, err := uc.Update(ctx, asUser(1), 1, dto.UpdateVenueRequest{Name: "Hub", Address: "2 Bach Dang", City: "Da Nang"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2 Bach Dang, Da Nang"}, geocoder.requests)
+	assert.Equal(t, 16.05, *venue.Latitude)
+}
+
+func TestUpdateVenue_SameAddressKeepsCoordinates(t *testing.T) {
+	repo := new(mockVenueRepo)
+	geocoder := &stubGeocoder{}
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), geocoder)
+	ctx := context.Background()
+
+	lat, lng := 10.77, 106.7
+	venue := &model.Venue{UserID: 1, Address: "1 Le Loi", City: "HCM", Latitude: &lat, Longitude: &lng}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("Update", ctx, venue).Return(nil)
+
+	_, err := uc.Update(ctx, asUser(1), 1, dto.UpdateVenueRequest{Name: "New name", Address: "1 Le Loi", City: "HCM"})
+	assert.NoError(t, err)
+	assert.Empty(t, geocoder.requests)
+	assert.Equal(t, 10.77, *venue.Latitude)
+}
+
 func TestGetAll_Success(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	expected := []model.Venue{{Name: "Venue1"}}
@@ -103,7 +226,7 @@ func TestGetAll_Success(t *testing.T) {
 
 func TestGetAll_Fail(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	repo.On("FindAll", ctx, uint(1), "", "").Return(([]model.Venue)(nil), errors.New("db error"))
@@ -115,7 +238,7 @@ func TestGetAll_Fail(t *testing.T) {
 
 func TestUpdateVenue_Success(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 1}
@@ -123,28 +246,74 @@ func TestUpdateVenue_Success(t *testing.T) {
 	repo.On("Update", ctx, venue).Return(nil)
 
 	req := dto.UpdateVenueRequest{Name: "Updated", Address: "New Addr"}
-	v, err := uc.Update(ctx, 1, 1, req)
+	v, err := uc.Update(ctx, asUser(1), 1, req)
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated", v.Name)
 }
 
-func TestUpdateVenue_Unauthorized(t *testing.T) {
+func TestUpdateVenue_Forbidden(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 2}
 	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("FindMember", ctx, uint(0), uint(1)).Return(nil, nil)
 
 	req := dto.UpdateVenueRequest{Name: "Updated"}
-	v, err := uc.Update(ctx, 1, 1, req)
+	v, err := uc.Update(ctx, asUser(1), 1, req)
 	assert.Nil(t, v)
-	assert.Equal(t, constant.ErrUnauthorized, err)
+	assert.Equal(t, constant.ErrForbidden, err)
+}
+
+func TestUpdateVenue_StaffForbidden(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	venue := &model.Venue{UserID: 2}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("FindMember", ctx, uint(0), uint(3)).Return(&model.VenueMember{UserID: 3, Role: policy.RoleStaff}, nil)
+
+	v, err := uc.Update(ctx, asUser(3), 1, dto.UpdateVenueRequest{Name: "Updated"})
+	assert.Nil(t, v)
+	assert.Equal(t, constant.ErrForbidden, err)
+}
+
+func TestDeleteVenue_AdminAnyVenue(t *testing.T) {
+	repo := new(mockVenueRepo)
+	galleries := new(mockGalleries)
+	uc := usecase.NewVenueUsecase(repo, galleries, nil)
+	ctx := context.Background()
+
+	venue := &model.Venue{UserID: 2}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("Delete", ctx, uint(1)).Return(nil)
+	galleries.On("RemoveVenueImages", ctx, uint(1)).Return(nil)
+
+	err := uc.Delete(ctx, policy.Subject{UserID: 9, Role: policy.RoleAdmin}, 1)
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "FindMember", mock.Anything, mock.Anything, mock.Anything)
+	galleries.AssertExpectations(t)
+}
+
+func TestRemoveAmenity_Forbidden(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	venue := &model.Venue{UserID: 2}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("FindMember", ctx, uint(0), uint(1)).Return(nil, nil)
+
+	err := uc.RemoveAmenity(ctx, asUser(1), 1, 5)
+	assert.Equal(t, constant.ErrForbidden, err)
+	repo.AssertNotCalled(t, "RemoveAmenity", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestAddAmenity_Success(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 1}
@@ -155,19 +324,79 @@ func TestAddAmenity_Success(t *testing.T) {
 	repo.On("CheckVenueAmenityExists", ctx, uint(1), uint(10)).Return(false, nil)
 	repo.On("AddAmenity", ctx, mock.AnythingOfType("*model.VenueAmenity")).Return(nil)
 
-	err := uc.AddAmenity(ctx, 1, 1, req)
+	err := uc.AddAmenity(ctx, asUser(1), 1, req)
 	assert.NoError(t, err)
 }
 
 func TestAddAmenity_FailUnauthorized(t *testing.T) {
 	repo := new(mockVenueRepo)
-	uc := usecase.NewVenueUsecase(repo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
 	ctx := context.Background()
 
 	venue := &model.Venue{UserID: 2}
 	req := dto.AddAmenityRequest{AmenityID: 10}
 	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("FindMember", ctx, uint(0), uint(1)).Return(nil, nil)
+
+	err := uc.AddAmenity(ctx, asUser(1), 1, req)
+	assert.Equal(t, constant.ErrForbidden, err)
+}
+
+func TestAddMember_Success(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	venue := &model.Venue{UserID: 1}
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("SaveMember", ctx, mock.AnythingOfType("*model.VenueMember")).Return(nil)
+
+	member, err := uc.AddMember(ctx, asUser(1), 1, dto.AddMemberRequest{UserID: 4, Role: policy.RoleStaff})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), member.UserID)
+	assert.Equal(t, policy.RoleStaff, member.Role)
+}
+
+func TestAddMember_InvalidRole(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	member, err := uc.AddMember(ctx, asUser(1), 1, dto.AddMemberRequest{UserID: 4, Role: policy.RoleOwner})
+	assert.Nil(t, member)
+	assert.Equal(t, constant.ErrInvalidMemberRole, err)
+	repo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestGetAccess_StaffAndManagedSpace(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	venue := &model.Venue{UserID: 2, Spaces: []model.Space{{ManagerID: 3}, {ManagerID: 5}}}
+	venue.ID = 1
+	venue.Spaces[0].ID = 10
+	venue.Spaces[1].ID = 11
+	repo.On("FindByID", ctx, uint(1)).Return(venue, nil)
+	repo.On("FindMember", ctx, uint(1), uint(3)).Return(&model.VenueMember{UserID: 3, Role: policy.RoleStaff}, nil)
+
+	access, err := uc.GetAccess(ctx, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{10, 11}, access.SpaceIDs)
+	assert.Equal(t, []dto.ScopedGrant{
+		{Role: policy.RoleStaff, Scope: policy.ScopeVenue, ID: 1},
+		{Role: policy.RoleManager, Scope: policy.ScopeSpace, ID: 10},
+	}, access.Grants)
+}
+
+func TestGetAccess_VenueNotFound(t *testing.T) {
+	repo := new(mockVenueRepo)
+	uc := usecase.NewVenueUsecase(repo, new(mockGalleries), nil)
+	ctx := context.Background()
+
+	repo.On("FindByID", ctx, uint(1)).Return((*model.Venue)(nil), errors.New("record not found"))
 
-	err := uc.AddAmenity(ctx, 1, 1, req)
-	assert.Equal(t, constant.ErrUnauthorized, err)
+	access, err := uc.GetAccess(ctx, 1, 3)
+	assert.Nil(t, access)
+	assert.Equal(t, constant.ErrVenueNotFound, err)
 }
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"packages/imaging"
+)
+
+const (
+	MaxImageBytes = 10 << 20
+	// decoding is refused past this many pixels, so a small file cannot claim a huge canvas
+	maxImagePixels = 40_000_000
+
+	jpegQuality = 85
+)
+
+// Rendition is a standard size photos are stored at, by the longest side in pixels.
+type Rendition struct {
+	Name string
+	Size int
+}
+
+// Renditions are stored for every photo, largest first.
+var Renditions = []Rendition{
+	{Name: "large", Size: 1600},
+	{Name: "medium", Size: 800},
+	{Name: "thumb", Size: 320},
+}
+
+var (
+	ErrImageTooLarge    = errors.New("image too large")
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrInvalidImage     = errors.New("invalid image")
+)
+
+// Photo is an uploaded picture re-encoded at every rendition. Width and Height are those of
+// the largest one.
+type Photo struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Renditions  map[string][]byte
+}
+
+// ProcessPhoto accepts JPEG and PNG pictures. It turns them upright as their EXIF orientation
+// says and scales them down to fit each rendition, keeping their aspect ratio; smaller pictures
+// are never scaled up. Re-encoding keeps pixels only, so EXIF data such as GPS position never
+// leaves the upload.
+func ProcessPhoto(data []byte) (*Photo, error) {
+	if len(data) > MaxImageBytes {
+		return nil, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// fitting the longest side commutes with rotations, so the orientation is applied to the
+	// largest rendition rather than the full picture
+	large := fit(img, Renditions[0].Size)
+	if contentType == "image/jpeg" {
+		large = imaging.Orient(large, imaging.JPEGOrientation(data))
+	}
+
+	photo := &Photo{
+		ContentType: contentType,
+		Ext:         ".jpg",
+		Width:       large.Bounds().Dx(),
+		Height:      large.Bounds().Dy(),
+		Renditions:  make(map[string][]byte, len(Renditions)),
+	}
+	if contentType == "image/png" {
+		photo.Ext = ".png"
+	}
+	for _, r := range Renditions {
+		var buf bytes.Buffer
+		m := fit(large, r.Size)
+		if contentType == "image/png" {
+			err = png.Encode(&buf, m)
+		} else {
+			err = jpeg.Encode(&buf, m, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, err
+		}
+		photo.Renditions[r.Name] = buf.Bytes()
+	}
+	return photo, nil
+}
+
+// fit scales img down so its longest side is at most size, averaging the source pixels under
+// each target pixel.
+func fit(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	src, ok := img.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		src = image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	}
+	if max(w, h) <= size {
+		return src
+	}
+
+	dw, dh := max(1, w*size/max(w, h)), max(1, h*size/max(w, h))
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*h/dh, (dy+1)*h/dh
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*w/dw, (dx+1)*w/dw
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride+x0*4 : y*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			o := dy*dst.Stride + dx*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[o+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
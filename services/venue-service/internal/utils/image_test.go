@@ -0,0 +1,71 @@
+package utils_test
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"packages/imaging/imagingtest"
+	"testing"
+	"venue-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessPhoto_ScalesEveryRenditionUpright(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, imagingtest.Halves(2400, 1200), &jpeg.Options{Quality: 95}))
+	upload := imagingtest.WithOrientation(buf.Bytes(), 6) // stored rotated, must be turned clockwise
+
+	photo, err := utils.ProcessPhoto(upload)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", photo.ContentType)
+	assert.Equal(t, ".jpg", photo.Ext)
+	assert.Equal(t, 800, photo.Width)
+	assert.Equal(t, 1600, photo.Height)
+
+	sizes := map[string]image.Rectangle{
+		"large":  image.Rect(0, 0, 800, 1600),
+		"medium": image.Rect(0, 0, 400, 800),
+		"thumb":  image.Rect(0, 0, 160, 320),
+	}
+	for name, want := range sizes {
+		require.Contains(t, photo.Renditions, name)
+		assert.NotContains(t, string(photo.Renditions[name]), "Exif", name)
+		img, err := jpeg.Decode(bytes.NewReader(photo.Renditions[name]))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, img.Bounds(), name)
+		// the red left half ends up on top once turned upright
+		assert.True(t, imagingtest.IsRed(img.At(want.Dx()/2, 10)), name)
+		assert.False(t, imagingtest.IsRed(img.At(want.Dx()/2, want.Dy()-10)), name)
+	}
+}
+
+func TestProcessPhoto_NeverEnlarges(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, imagingtest.Halves(600, 400)))
+
+	photo, err := utils.ProcessPhoto(buf.Bytes())
+
+	require.NoError(t, err)
+	assert.Equal(t, ".png", photo.Ext)
+	large, err := png.Decode(bytes.NewReader(photo.Renditions["large"]))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 600, 400), large.Bounds())
+	thumb, err := png.Decode(bytes.NewReader(photo.Renditions["thumb"]))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 320, 213), thumb.Bounds())
+}
+
+func TestProcessPhoto_Rejects(t *testing.T) {
+	_, err := utils.ProcessPhoto([]byte("GIF89a not accepted"))
+	assert.ErrorIs(t, err, utils.ErrUnsupportedImage)
+
+	_, err = utils.ProcessPhoto(append([]byte{0xFF, 0xD8, 0xFF}, make([]byte, 100)...))
+	assert.ErrorIs(t, err, utils.ErrInvalidImage)
+
+	_, err = utils.ProcessPhoto(make([]byte, utils.MaxImageBytes+1))
+	assert.ErrorIs(t, err, utils.ErrImageTooLarge)
+}